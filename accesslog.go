@@ -0,0 +1,169 @@
+package huma
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// AccessLogFormat is the format of the lines written by `AccessLog`.
+type AccessLogFormat int
+
+const (
+	// AccessLogCommon writes lines in the Common Log Format, followed by the
+	// operation ID and the latency in milliseconds, like:
+	//
+	//	127.0.0.1 - - [10/Oct/2025:13:55:36 +0000] "GET /things?limit=5 HTTP/1.1" 200 2326 list-things 1.234
+	AccessLogCommon AccessLogFormat = iota
+
+	// AccessLogJSON writes one JSON object per line, like:
+	//
+	//	{"time":"2025-10-10T13:55:36Z","remoteAddr":"127.0.0.1","method":"GET","uri":"/things?limit=5","proto":"HTTP/1.1","status":200,"bytes":2326,"operationId":"list-things","latencyMs":1.234}
+	AccessLogJSON
+)
+
+// accessLogEntry is a single request in the JSON access log format.
+type accessLogEntry struct {
+	Time        time.Time `json:"time"`
+	RemoteAddr  string    `json:"remoteAddr"`
+	Method      string    `json:"method"`
+	URI         string    `json:"uri"`
+	Proto       string    `json:"proto"`
+	Status      int       `json:"status"`
+	Bytes       int64     `json:"bytes"`
+	OperationID string    `json:"operationId,omitempty"`
+	LatencyMs   float64   `json:"latencyMs"`
+}
+
+// AccessLog returns a middleware which writes a line to `w` for each request
+// once it has been handled, including the response status, body size,
+// operation ID, and latency. This works the same with every adapter, so
+// simple services don't need router-specific loggers. Lines are written with
+// a single call to `w.Write`, which is synchronized between requests.
+//
+//	api.UseMiddleware(huma.AccessLog(os.Stdout, huma.AccessLogJSON))
+func AccessLog(w io.Writer, format AccessLogFormat) func(ctx Context, next func(Context)) {
+	var mu sync.Mutex
+	return func(ctx Context, next func(Context)) {
+		start := time.Now()
+		lctx := &countingContext{humaContext: ctx}
+		next(lctx)
+
+		u := ctx.URL()
+		entry := accessLogEntry{
+			Time:       start,
+			RemoteAddr: ctx.RemoteAddr(),
+			Method:     ctx.Method(),
+			URI:        u.RequestURI(),
+			Proto:      ctx.Version().Proto,
+			Status:     ctx.Status(),
+			LatencyMs:  float64(time.Since(start).Microseconds()) / 1000,
+		}
+		if host, _, err := net.SplitHostPort(entry.RemoteAddr); err == nil {
+			entry.RemoteAddr = host
+		}
+		if entry.Status == 0 {
+			entry.Status = http.StatusOK
+		}
+		if lctx.w != nil {
+			entry.Bytes = lctx.w.n
+		}
+		if op := ctx.Operation(); op != nil {
+			entry.OperationID = op.OperationID
+		}
+
+		var line []byte
+		if format == AccessLogJSON {
+			line, _ = json.Marshal(entry)
+		} else {
+			line = entry.appendCommon(line)
+		}
+		line = append(line, '\n')
+
+		mu.Lock()
+		defer mu.Unlock()
+		w.Write(line)
+	}
+}
+
+// appendCommon appends the entry in the Common Log Format.
+func (e accessLogEntry) appendCommon(b []byte) []byte {
+	b = append(b, e.RemoteAddr...)
+	b = append(b, " - - ["...)
+	b = e.Time.AppendFormat(b, "02/Jan/2006:15:04:05 -0700")
+	b = append(b, `] "`...)
+	b = append(b, e.Method...)
+	b = append(b, ' ')
+	b = append(b, e.URI...)
+	b = append(b, ' ')
+	b = append(b, e.Proto...)
+	b = append(b, `" `...)
+	b = strconv.AppendInt(b, int64(e.Status), 10)
+	b = append(b, ' ')
+	if e.Bytes > 0 {
+		b = strconv.AppendInt(b, e.Bytes, 10)
+	} else {
+		b = append(b, '-')
+	}
+	b = append(b, ' ')
+	if e.OperationID != "" {
+		b = append(b, e.OperationID...)
+	} else {
+		b = append(b, '-')
+	}
+	b = append(b, ' ')
+	return strconv.AppendFloat(b, e.LatencyMs, 'f', 3, 64)
+}
+
+// countingContext wraps the request context to count the bytes written to
+// the response body and track write errors.
+type countingContext struct {
+	humaContext
+	w *countingWriter
+}
+
+func (c *countingContext) Unwrap() Context {
+	return c.humaContext
+}
+
+func (c *countingContext) BodyWriter() io.Writer {
+	if c.w == nil {
+		c.w = &countingWriter{w: c.humaContext.BodyWriter()}
+	}
+	return c.w
+}
+
+// countingWriter counts the bytes written through it and records the first
+// write error. It can be unwrapped so that streaming responses can still
+// flush the underlying writer.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	if err != nil && c.err == nil {
+		c.err = err
+	}
+	return n, err
+}
+
+func (c *countingWriter) Flush() {
+	if f, ok := c.w.(http.Flusher); ok {
+		f.Flush()
+	} else if rw, ok := c.w.(http.ResponseWriter); ok {
+		http.NewResponseController(rw).Flush()
+	}
+}
+
+func (c *countingWriter) Unwrap() http.ResponseWriter {
+	rw, _ := c.w.(http.ResponseWriter)
+	return rw
+}
@@ -5,6 +5,9 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
 	"strings"
 	"testing"
 
@@ -13,7 +16,10 @@ import (
 	"github.com/danielgtaylor/huma/v2/adapters/humachi"
 	"github.com/danielgtaylor/huma/v2/adapters/humaecho"
 	"github.com/danielgtaylor/huma/v2/adapters/humafiber"
+	"github.com/danielgtaylor/huma/v2/adapters/humaflow"
+	"github.com/danielgtaylor/huma/v2/adapters/humaflow/flow"
 	"github.com/danielgtaylor/huma/v2/adapters/humagin"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
 	"github.com/danielgtaylor/huma/v2/adapters/humahttprouter"
 	"github.com/danielgtaylor/huma/v2/adapters/humamux"
 	"github.com/danielgtaylor/huma/v2/humatest"
@@ -124,3 +130,135 @@ func TestAdapters(t *testing.T) {
 		})
 	}
 }
+
+func TestAdaptersMethodNotAllowed(t *testing.T) {
+	config := huma.DefaultConfig("Test", "1.0.0")
+	config.MethodNotAllowed = true
+
+	for _, adapter := range []struct {
+		name string
+		new  func() huma.API
+	}{
+		{"chi", func() huma.API { return humachi.New(chi.NewMux(), config) }},
+		{"flow", func() huma.API { return humaflow.New(flow.New(), config) }},
+		{"httprouter", func() huma.API { return humahttprouter.New(httprouter.New(), config) }},
+	} {
+		t.Run(adapter.name, func(t *testing.T) {
+			api := adapter.new()
+			huma.Get(api, "/things/{id}", func(ctx context.Context, input *struct{}) (*struct{}, error) {
+				return nil, nil
+			})
+
+			testAPI := humatest.Wrap(t, api)
+			resp := testAPI.Delete("/things/123")
+			assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+			assert.Equal(t, "GET", resp.Header().Get("Allow"))
+			assert.Equal(t, "application/problem+json", resp.Header().Get("Content-Type"))
+
+			resp = testAPI.Get("/missing")
+			assert.Equal(t, http.StatusNotFound, resp.Code)
+			assert.Equal(t, "application/problem+json", resp.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestAdaptersMethodNotAllowedKeepsHandlers(t *testing.T) {
+	config := huma.DefaultConfig("Test", "1.0.0")
+	config.MethodNotAllowed = true
+
+	notFound := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	methodNotAllowed := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+
+	for _, adapter := range []struct {
+		name string
+		new  func() huma.API
+	}{
+		{"chi", func() huma.API {
+			r := chi.NewMux()
+			r.NotFound(notFound)
+			r.MethodNotAllowed(methodNotAllowed)
+			return humachi.New(r, config)
+		}},
+		{"flow", func() huma.API {
+			r := flow.New()
+			r.NotFound = notFound
+			r.MethodNotAllowed = methodNotAllowed
+			return humaflow.New(r, config)
+		}},
+		{"httprouter", func() huma.API {
+			r := httprouter.New()
+			r.NotFound = notFound
+			r.MethodNotAllowed = methodNotAllowed
+			return humahttprouter.New(r, config)
+		}},
+	} {
+		t.Run(adapter.name, func(t *testing.T) {
+			api := adapter.new()
+			huma.Get(api, "/things/{id}", func(ctx context.Context, input *struct{}) (*struct{}, error) {
+				return nil, nil
+			})
+
+			testAPI := humatest.Wrap(t, api)
+			resp := testAPI.Delete("/things/123")
+			assert.Equal(t, http.StatusConflict, resp.Code)
+
+			resp = testAPI.Get("/missing")
+			assert.Equal(t, http.StatusTeapot, resp.Code)
+		})
+	}
+}
+
+func TestAdaptersEarlyHints(t *testing.T) {
+	config := huma.DefaultConfig("Test", "1.0.0")
+	link := "</style.css>; rel=preload; as=style"
+
+	for _, adapter := range []struct {
+		name string
+		new  func() huma.API
+	}{
+		{"chi", func() huma.API { return humachi.New(chi.NewMux(), config) }},
+		{"echo", func() huma.API { return humaecho.New(echo.New(), config) }},
+		{"gin", func() huma.API { return humagin.New(gin.New(), config) }},
+		{"go", func() huma.API { return humago.New(http.NewServeMux(), config) }},
+		{"httprouter", func() huma.API { return humahttprouter.New(httprouter.New(), config) }},
+		{"mux", func() huma.API { return humamux.New(mux.NewRouter(), config) }},
+		{"bunrouter", func() huma.API { return humabunrouter.New(bunrouter.New(), config) }},
+		{"bunroutercompat", func() huma.API { return humabunrouter.NewCompat(bunrouter.New().Compat(), config) }},
+	} {
+		t.Run(adapter.name, func(t *testing.T) {
+			api := adapter.new()
+			api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+				huma.EarlyHints(ctx, []string{link})
+				next(ctx)
+			})
+			huma.Get(api, "/hints", func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+				return &struct{ Body string }{Body: "done"}, nil
+			})
+
+			server := httptest.NewServer(api.Adapter())
+			defer server.Close()
+
+			var hints []string
+			trace := &httptrace.ClientTrace{
+				Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+					if code == http.StatusEarlyHints {
+						hints = append(hints, header.Values("Link")...)
+					}
+					return nil
+				},
+			}
+			req, _ := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), http.MethodGet, server.URL+"/hints", nil)
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, []string{link}, hints)
+			assert.Contains(t, resp.Header.Values("Link"), link)
+		})
+	}
+}
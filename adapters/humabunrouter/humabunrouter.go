@@ -87,6 +87,10 @@ func (c *bunContext) SetReadDeadline(deadline time.Time) error {
 	return huma.SetReadDeadline(c.w, deadline)
 }
 
+func (c *bunContext) EarlyHints(links []string) {
+	huma.WriteEarlyHints(c.w, c.r.Request, links)
+}
+
 func (c *bunContext) SetStatus(code int) {
 	c.status = code
 	c.w.WriteHeader(code)
@@ -100,6 +104,10 @@ func (c *bunContext) AppendHeader(name string, value string) {
 	c.w.Header().Add(name, value)
 }
 
+func (c *bunContext) SetTrailer(name, value string) {
+	c.w.Header().Set(http.TrailerPrefix+name, value)
+}
+
 func (c *bunContext) SetHeader(name string, value string) {
 	c.w.Header().Set(name, value)
 }
@@ -190,6 +198,10 @@ func (c *bunCompatContext) SetReadDeadline(deadline time.Time) error {
 	return huma.SetReadDeadline(c.w, deadline)
 }
 
+func (c *bunCompatContext) EarlyHints(links []string) {
+	huma.WriteEarlyHints(c.w, c.r, links)
+}
+
 func (c *bunCompatContext) SetStatus(code int) {
 	c.status = code
 	c.w.WriteHeader(code)
@@ -203,6 +215,10 @@ func (c *bunCompatContext) AppendHeader(name string, value string) {
 	c.w.Header().Add(name, value)
 }
 
+func (c *bunCompatContext) SetTrailer(name, value string) {
+	c.w.Header().Set(http.TrailerPrefix+name, value)
+}
+
 func (c *bunCompatContext) SetHeader(name string, value string) {
 	c.w.Header().Set(name, value)
 }
@@ -7,6 +7,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"reflect"
 	"time"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -86,6 +87,10 @@ func (c *chiContext) SetReadDeadline(deadline time.Time) error {
 	return huma.SetReadDeadline(c.w, deadline)
 }
 
+func (c *chiContext) EarlyHints(links []string) {
+	huma.WriteEarlyHints(c.w, c.r, links)
+}
+
 func (c *chiContext) SetStatus(code int) {
 	c.status = code
 	c.w.WriteHeader(code)
@@ -99,6 +104,10 @@ func (c *chiContext) AppendHeader(name string, value string) {
 	c.w.Header().Add(name, value)
 }
 
+func (c *chiContext) SetTrailer(name, value string) {
+	c.w.Header().Set(http.TrailerPrefix+name, value)
+}
+
 func (c *chiContext) SetHeader(name string, value string) {
 	c.w.Header().Set(name, value)
 }
@@ -138,6 +147,33 @@ func (a *chiAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	a.router.ServeHTTP(w, r)
 }
 
+// HandleFallback implements `huma.FallbackAdapter` using the router's not
+// found & method not allowed handlers, if the router is a `*chi.Mux`. Handlers
+// which have already been set on the router are kept.
+func (a *chiAdapter) HandleFallback(handler func(huma.Context)) {
+	m, ok := a.router.(*chi.Mux)
+	if !ok {
+		return
+	}
+	fallback := func(w http.ResponseWriter, r *http.Request) {
+		handler(&chiContext{r: r, w: w})
+	}
+	if !hasHandler(m, "notFoundHandler") {
+		m.NotFound(fallback)
+	}
+	if !hasHandler(m, "methodNotAllowedHandler") {
+		m.MethodNotAllowed(fallback)
+	}
+}
+
+// hasHandler returns whether a custom handler has been set in the named mux
+// field. Chi's getters return its defaults when none is set, so they cannot
+// be used to tell.
+func hasHandler(m *chi.Mux, field string) bool {
+	f := reflect.ValueOf(m).Elem().FieldByName(field)
+	return f.IsValid() && !f.IsNil()
+}
+
 // NewAdapter creates a new adapter for the given chi router.
 func NewAdapter(r chi.Router) huma.Adapter {
 	return &chiAdapter{router: r}
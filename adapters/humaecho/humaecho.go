@@ -1,3 +1,5 @@
+// Package humaecho provides a Huma adapter for Echo v4. For Echo v5, use the
+// `humaecho5` adapter module instead.
 package humaecho
 
 import (
@@ -84,6 +86,10 @@ func (c *echoCtx) SetReadDeadline(deadline time.Time) error {
 	return huma.SetReadDeadline(c.orig.Response(), deadline)
 }
 
+func (c *echoCtx) EarlyHints(links []string) {
+	huma.WriteEarlyHints(c.orig.Response(), c.orig.Request(), links)
+}
+
 func (c *echoCtx) SetStatus(code int) {
 	c.status = code
 	c.orig.Response().WriteHeader(code)
@@ -97,6 +103,10 @@ func (c *echoCtx) AppendHeader(name, value string) {
 	c.orig.Response().Header().Add(name, value)
 }
 
+func (c *echoCtx) SetTrailer(name, value string) {
+	c.orig.Response().Header().Set(http.TrailerPrefix+name, value)
+}
+
 func (c *echoCtx) SetHeader(name, value string) {
 	c.orig.Response().Header().Set(name, value)
 }
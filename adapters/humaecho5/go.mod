@@ -0,0 +1,10 @@
+module github.com/danielgtaylor/huma/v2/adapters/humaecho5
+
+go 1.25.0
+
+replace github.com/danielgtaylor/huma/v2 => ../../
+
+require (
+	github.com/danielgtaylor/huma/v2 v2.0.0-00010101000000-000000000000
+	github.com/labstack/echo/v5 v5.0.0
+)
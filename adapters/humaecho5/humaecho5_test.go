@@ -0,0 +1,79 @@
+package humaecho5
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/labstack/echo/v5"
+)
+
+type greetingInput struct {
+	ID   string `path:"id"`
+	Num  int    `query:"num"`
+	Body struct {
+		Suffix string `json:"suffix" maxLength:"5"`
+	}
+}
+
+type greetingOutput struct {
+	Body struct {
+		Greeting string `json:"greeting"`
+		Num      int    `json:"num"`
+	}
+}
+
+func register(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "greet",
+		Method:      http.MethodPost,
+		Path:        "/foo/{id}",
+	}, func(ctx context.Context, input *greetingInput) (*greetingOutput, error) {
+		resp := &greetingOutput{}
+		resp.Body.Greeting = "Hello, " + input.ID + input.Body.Suffix
+		resp.Body.Num = input.Num
+		return resp, nil
+	})
+}
+
+func TestEcho5(t *testing.T) {
+	r := echo.New()
+	register(New(r, huma.DefaultConfig("Test", "1.0.0")))
+
+	req := httptest.NewRequest(http.MethodPost, "/foo/123?num=5", strings.NewReader(`{"suffix": "!"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"greeting":"Hello, 123!"`) || !strings.Contains(w.Body.String(), `"num":5`) {
+		t.Fatalf("unexpected body: %s", w.Body.String())
+	}
+
+	// Validation errors are written via the v5 response.
+	req = httptest.NewRequest(http.MethodPost, "/foo/123", strings.NewReader(`{"suffix": "too long"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestEcho5Group(t *testing.T) {
+	r := echo.New()
+	register(NewWithGroup(r, r.Group("/api"), huma.DefaultConfig("Test", "1.0.0")))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/foo/123", strings.NewReader(`{"suffix": "!"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+	}
+}
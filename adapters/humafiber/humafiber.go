@@ -149,6 +149,10 @@ func (c *fiberCtx) SetReadDeadline(deadline time.Time) error {
 	return c.orig().Context().Conn().SetReadDeadline(deadline)
 }
 
+func (c *fiberCtx) EarlyHints(links []string) {
+	// Fasthttp does not support informational responses.
+}
+
 func (c *fiberCtx) SetStatus(code int) {
 	var orig = c.orig()
 	c.status = code
@@ -162,6 +166,10 @@ func (c *fiberCtx) AppendHeader(name string, value string) {
 	c.orig().Append(name, value)
 }
 
+func (c *fiberCtx) SetTrailer(name, value string) {
+	// Fiber buffers response bodies, so trailers are not supported.
+}
+
 func (c *fiberCtx) SetHeader(name string, value string) {
 	c.orig().Set(name, value)
 }
@@ -87,6 +87,10 @@ func Param(ctx context.Context, param string) string {
 	return s
 }
 
+func methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
+
 // Mux is a http.Handler which dispatches requests to different handlers.
 type Mux struct {
 	NotFound         http.Handler
@@ -99,10 +103,8 @@ type Mux struct {
 // New returns a new initialized Mux instance.
 func New() *Mux {
 	return &Mux{
-		NotFound: http.NotFoundHandler(),
-		MethodNotAllowed: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
-		}),
+		NotFound:         http.NotFoundHandler(),
+		MethodNotAllowed: http.HandlerFunc(methodNotAllowed),
 		Options: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNoContent)
 		}),
@@ -7,6 +7,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
 	"time"
 
@@ -83,6 +84,10 @@ func (c *goContext) SetReadDeadline(deadline time.Time) error {
 	return huma.SetReadDeadline(c.w, deadline)
 }
 
+func (c *goContext) EarlyHints(links []string) {
+	huma.WriteEarlyHints(c.w, c.r, links)
+}
+
 func (c *goContext) SetStatus(code int) {
 	c.status = code
 	c.w.WriteHeader(code)
@@ -96,6 +101,10 @@ func (c *goContext) AppendHeader(name string, value string) {
 	c.w.Header().Add(name, value)
 }
 
+func (c *goContext) SetTrailer(name, value string) {
+	c.w.Header().Set(http.TrailerPrefix+name, value)
+}
+
 func (c *goContext) SetHeader(name string, value string) {
 	c.w.Header().Set(name, value)
 }
@@ -141,6 +150,36 @@ func (a *goAdapter) Handle(op *huma.Operation, handler func(huma.Context)) {
 	}, op.Method)
 }
 
+// HandleFallback implements `huma.FallbackAdapter` using the router's not
+// found & method not allowed handlers, if the mux is a `*flow.Mux`. Handlers
+// which have been changed from the defaults are kept.
+func (a *goAdapter) HandleFallback(handler func(huma.Context)) {
+	m, ok := a.Mux.(*flow.Mux)
+	if !ok {
+		return
+	}
+	fallback := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(&goContext{r: r, w: w})
+	})
+	defaults := flow.New()
+	if isDefaultHandler(m.NotFound, defaults.NotFound) {
+		m.NotFound = fallback
+	}
+	if isDefaultHandler(m.MethodNotAllowed, defaults.MethodNotAllowed) {
+		m.MethodNotAllowed = fallback
+	}
+}
+
+// isDefaultHandler returns whether the handler is unset or is the same func
+// as the router's default handler.
+func isDefaultHandler(h, def http.Handler) bool {
+	if h == nil {
+		return true
+	}
+	v, d := reflect.ValueOf(h), reflect.ValueOf(def)
+	return v.Kind() == reflect.Func && d.Kind() == reflect.Func && v.Pointer() == d.Pointer()
+}
+
 // NewAdapter creates a new adapter for the given chi router.
 func NewAdapter(m Mux) huma.Adapter {
 	return &goAdapter{Mux: m}
@@ -84,6 +84,10 @@ func (c *ginCtx) SetReadDeadline(deadline time.Time) error {
 	return huma.SetReadDeadline(c.orig.Writer, deadline)
 }
 
+func (c *ginCtx) EarlyHints(links []string) {
+	huma.WriteEarlyHints(c.orig.Writer, c.orig.Request, links)
+}
+
 func (c *ginCtx) SetStatus(code int) {
 	c.status = code
 	c.orig.Status(code)
@@ -97,6 +101,10 @@ func (c *ginCtx) AppendHeader(name string, value string) {
 	c.orig.Writer.Header().Add(name, value)
 }
 
+func (c *ginCtx) SetTrailer(name, value string) {
+	c.orig.Writer.Header().Set(http.TrailerPrefix+name, value)
+}
+
 func (c *ginCtx) SetHeader(name string, value string) {
 	c.orig.Header(name, value)
 }
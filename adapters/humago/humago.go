@@ -92,6 +92,10 @@ func (c *goContext) SetReadDeadline(deadline time.Time) error {
 	return huma.SetReadDeadline(c.w, deadline)
 }
 
+func (c *goContext) EarlyHints(links []string) {
+	huma.WriteEarlyHints(c.w, c.r, links)
+}
+
 func (c *goContext) SetStatus(code int) {
 	c.status = code
 	c.w.WriteHeader(code)
@@ -105,6 +109,10 @@ func (c *goContext) AppendHeader(name string, value string) {
 	c.w.Header().Add(name, value)
 }
 
+func (c *goContext) SetTrailer(name, value string) {
+	c.w.Header().Set(http.TrailerPrefix+name, value)
+}
+
 func (c *goContext) SetHeader(name string, value string) {
 	c.w.Header().Set(name, value)
 }
@@ -87,6 +87,10 @@ func (c *httprouterContext) SetReadDeadline(deadline time.Time) error {
 	return huma.SetReadDeadline(c.w, deadline)
 }
 
+func (c *httprouterContext) EarlyHints(links []string) {
+	huma.WriteEarlyHints(c.w, c.r, links)
+}
+
 func (c *httprouterContext) SetStatus(code int) {
 	c.status = code
 	c.w.WriteHeader(code)
@@ -100,6 +104,10 @@ func (c *httprouterContext) AppendHeader(name string, value string) {
 	c.w.Header().Add(name, value)
 }
 
+func (c *httprouterContext) SetTrailer(name, value string) {
+	c.w.Header().Set(http.TrailerPrefix+name, value)
+}
+
 func (c *httprouterContext) SetHeader(name string, value string) {
 	c.w.Header().Set(name, value)
 }
@@ -138,6 +146,21 @@ func (a *httprouterAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	a.router.ServeHTTP(w, r)
 }
 
+// HandleFallback implements `huma.FallbackAdapter` using the router's not
+// found & method not allowed handlers. Handlers which have already been set on
+// the router are kept.
+func (a *httprouterAdapter) HandleFallback(handler func(huma.Context)) {
+	fallback := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(&httprouterContext{r: r, w: w})
+	})
+	if a.router.NotFound == nil {
+		a.router.NotFound = fallback
+	}
+	if a.router.MethodNotAllowed == nil {
+		a.router.MethodNotAllowed = fallback
+	}
+}
+
 func New(r *httprouter.Router, config huma.Config) huma.API {
 	return huma.NewAPI(config, &httprouterAdapter{router: r})
 }
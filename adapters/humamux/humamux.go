@@ -97,6 +97,10 @@ func (c *gmuxContext) SetReadDeadline(deadline time.Time) error {
 	return huma.SetReadDeadline(c.w, deadline)
 }
 
+func (c *gmuxContext) EarlyHints(links []string) {
+	huma.WriteEarlyHints(c.w, c.r, links)
+}
+
 func (c *gmuxContext) SetStatus(code int) {
 	c.status = code
 	c.w.WriteHeader(code)
@@ -110,6 +114,10 @@ func (c *gmuxContext) AppendHeader(name string, value string) {
 	c.w.Header().Add(name, value)
 }
 
+func (c *gmuxContext) SetTrailer(name, value string) {
+	c.w.Header().Set(http.TrailerPrefix+name, value)
+}
+
 func (c *gmuxContext) SetHeader(name string, value string) {
 	c.w.Header().Set(name, value)
 }
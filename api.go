@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"mime"
 	"mime/multipart"
 	"net/http"
 	"net/url"
@@ -14,6 +16,7 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/danielgtaylor/huma/v2/negotiation"
@@ -58,8 +61,34 @@ type Adapter interface {
 	ServeHTTP(http.ResponseWriter, *http.Request)
 }
 
+// FallbackAdapter is an optional interface which adapters can implement to
+// let Huma handle requests which do not match any registered route, e.g. via
+// the router's not found & method not allowed handlers. The context's
+// operation is nil for these requests. See `Config.MethodNotAllowed`.
+type FallbackAdapter interface {
+	HandleFallback(handler func(ctx Context))
+}
+
+// EarlyHintsContext is an optional interface which contexts can implement to
+// send `103 Early Hints` informational responses, see `EarlyHints`.
+type EarlyHintsContext interface {
+	// EarlyHints sends a `103 Early Hints` informational response with the
+	// given `Link` header values. It is a no-op if the protocol does not
+	// support it. See `WriteEarlyHints` for `net/http` based adapters.
+	EarlyHints(links []string)
+}
+
+// TrailerContext is an optional interface which contexts can implement to
+// send trailer headers after the response body, see `SetTrailer`.
+type TrailerContext interface {
+	// SetTrailer sets a trailer header which is sent after the response body.
+	SetTrailer(name, value string)
+}
+
 // Context is the current request/response context. It provides a generic
-// interface to get request information and write responses.
+// interface to get request information and write responses. Types which wrap
+// a context should implement `Unwrap() Context` returning the wrapped one, so
+// that optional interfaces like `EarlyHintsContext` can be found.
 type Context interface {
 	// Operation returns the OpenAPI operation that matched the request.
 	Operation() *Operation
@@ -143,6 +172,10 @@ func (c subContext) Context() context.Context {
 	return c.override
 }
 
+func (c subContext) Unwrap() Context {
+	return c.humaContext
+}
+
 // WithContext returns a new `huma.Context` with the underlying `context.Context`
 // replaced with the given one. This is useful for middleware that needs to
 // modify the request context.
@@ -175,17 +208,113 @@ type Config struct {
 	// `/openapi.yaml`, for example.
 	OpenAPIPath string
 
+	// OpenAPICacheControl is an optional `Cache-Control` header value to send
+	// with the OpenAPI spec, e.g. `public, max-age=300`. The spec is always
+	// sent with an `ETag` so clients can cheaply revalidate it.
+	OpenAPICacheControl string
+
+	// SpecFilter optionally returns the OpenAPI document to serve for a
+	// request, so different audiences (e.g. public vs. partner vs. internal)
+	// only see the operations and tags they're entitled to. It must not
+	// modify the passed document, see `OpenAPI.FilterOperations` for a helper.
+	// Use `SpecAudience` to cache the filtered documents.
+	//
+	//	config.SpecAudience = func(ctx huma.Context) string {
+	//		return getAudienceFromToken(ctx.Header("Authorization"))
+	//	}
+	//	config.SpecFilter = func(ctx huma.Context, doc *huma.OpenAPI) *huma.OpenAPI {
+	//		audience := config.SpecAudience(ctx)
+	//		return doc.FilterOperations(func(op *huma.Operation) bool {
+	//			return audience == "internal" || !slices.Contains(op.Tags, "Internal")
+	//		})
+	//	}
+	SpecFilter func(ctx Context, doc *OpenAPI) *OpenAPI
+
+	// SpecAudience returns the audience for a request to the OpenAPI document,
+	// like `public` or `partner`. The documents returned by `SpecFilter` are
+	// rendered once per audience and cached. Without it, `SpecFilter` is called
+	// and the document is rendered for every request.
+	SpecAudience func(ctx Context) string
+
+	// OpenAPIEncoders maps a content encoding like `gzip` to a function which
+	// creates a compressing writer. Compressed variants of the OpenAPI spec are
+	// computed once and cached until a new operation is added, then sent to
+	// clients which accept that encoding. `huma.DefaultConfig` enables `gzip`.
+	// Other encodings like Brotli can be added using third-party libraries:
+	//
+	//	config.OpenAPIEncoders["br"] = func(w io.Writer) io.WriteCloser {
+	//		return brotli.NewWriter(w)
+	//	}
+	OpenAPIEncoders map[string]func(io.Writer) io.WriteCloser
+
+	// HomePath is the path to an API home document, e.g. `/` or `/home`, which
+	// lists each operation with its URI template and hints like the allowed
+	// method and content types. This lets clients discover the API without
+	// downloading the full OpenAPI. It is served as `application/json-home`
+	// (https://datatracker.ietf.org/doc/html/draft-nottingham-json-home)
+	// with an `ETag` and the `OpenAPICacheControl` header.
+	HomePath string
+
 	// DocsPath is the path to the API documentation. If set to `/docs` it will
 	// allow clients to get `/docs` to view the documentation in a browser. If
 	// you wish to provide your own documentation renderer, you can leave this
 	// blank and attach it directly to the router or adapter.
 	DocsPath string
 
+	// DocsRenderer renders the documentation page served at `DocsPath`. If
+	// nil, Stoplight Elements is used. See `ElementsRenderer`,
+	// `SwaggerUIRenderer`, `RedocRenderer`, and `ScalarRenderer`.
+	DocsRenderer DocsRenderer
+
+	// DocsAssets optionally provides the docs renderer's static assets (e.g.
+	// JavaScript and CSS files) so they are served from `DocsPath + "/assets"`
+	// instead of being loaded from a public CDN. This is useful for air-gapped
+	// deployments. Use an `embed.FS` with a flat directory of files, e.g. via
+	// `fs.Sub(assets, "elements")`. Only top-level files are served, as the
+	// route matches a single path segment (`/assets/{file}`) to work with all
+	// routers. See the renderer's docs for the required file names.
+	DocsAssets fs.FS
+
 	// SchemasPath is the path to the API schemas. If set to `/schemas` it will
 	// allow clients to get `/schemas/{schema}` to view the schema in a browser
 	// or for use in editors like VSCode to provide autocomplete & validation.
 	SchemasPath string
 
+	// BundleSchemas makes the `SchemasPath` endpoint serve each schema as a
+	// fully self-contained document, with any referenced schemas inlined under
+	// `$defs` instead of linking to other schema files. This is useful for
+	// consumers like standalone JSON Schema validators and form generators.
+	BundleSchemas bool
+
+	// SchemaLinkBaseURL returns the scheme and host used to build absolute
+	// `$schema` links and `describedBy` link headers for the current request,
+	// e.g. `https://api.example.com`. This is useful when running behind a
+	// proxy where the request host does not match the public one. If unset,
+	// the request host is used. See also `SchemaLinkTransformer`.
+	SchemaLinkBaseURL func(ctx Context) string
+
+	// ServerVariableFunc returns the value of an OpenAPI server variable for
+	// the current request, e.g. the `region` based on the `Host`, which is
+	// used to populate input fields with a `server:"region"` tag. If unset or
+	// if it returns an empty string, the variable's default from
+	// `OpenAPI.Servers` is used.
+	ServerVariableFunc func(ctx Context, name string) string
+
+	// BasePath is a path prefix under which all operations and the built-in
+	// spec, docs, and schema routes are mounted, without being part of the
+	// documented operation paths. It may contain path params, e.g.
+	// `/t/{tenant}`, so a multi-tenant API registers each operation once
+	// rather than once per tenant. The params are available to middleware
+	// via `ctx.Param`, e.g. to store the tenant with `huma.WithValue`.
+	BasePath string
+
+	// BasePathFunc returns the public base path for the current request, e.g.
+	// `/t/acme` based on the `Host` or a header set by a gateway. It is used
+	// to build `$schema` links, `describedBy` link headers, the docs page, and
+	// the `servers` of the served OpenAPI document. If unset and `BasePath`
+	// is set, then the `BasePath` params are filled in from the request.
+	BasePathFunc func(ctx Context) string
+
 	// Formats defines the supported request/response formats by content type or
 	// extension (e.g. `json` for `application/my-format+json`).
 	Formats map[string]Format
@@ -195,9 +324,111 @@ type Config struct {
 	// chosen from the keys of `Formats`.
 	DefaultFormat string
 
+	// UseNumber makes request bodies get decoded using the format's
+	// `UnmarshalNumber` function when available, e.g. `json.Decoder.UseNumber`
+	// for JSON. This preserves the precision of large 64-bit integers during
+	// validation (e.g. `maximum` or `multipleOf` checks) and in error values.
+	// Note that untyped (`any`) body fields will contain `json.Number` values
+	// rather than `float64`.
+	UseNumber bool
+
+	// MessageFunc, if set, is used to translate built-in validation error
+	// messages like `expected length <= 10`, e.g. based on the request's
+	// `Accept-Language` header. See `MessageFunc` for details.
+	MessageFunc MessageFunc
+
+	// OnDeprecatedFields, if set, is called when a request body sets any
+	// properties which are marked as deprecated, e.g. via the
+	// `deprecated:"true"` or `removedAfter:"2025-01-01"` field tags, or when
+	// deprecated params or param aliases are sent. Use it to log usage or set
+	// a response header to help drive field migrations. The fields are
+	// locations like `body.oldName` or `query.oldName`.
+	OnDeprecatedFields func(ctx Context, fields []string)
+
+	// OnUnknownFields, if set, enables lenient reads of request bodies:
+	// properties which are not part of the schema are ignored instead of being
+	// rejected, and this is called with their locations like
+	// `body.removedField`. Use it, e.g. with a `FieldCounter`, to measure
+	// which clients still send removed or renamed fields before enforcing
+	// strict validation. The slice must not be retained after returning.
+	OnUnknownFields func(ctx Context, fields []string)
+
+	// CaseInsensitiveFields accepts request body properties whose names
+	// differ from the declared names only by case, like `UserID` for
+	// `userId`. They are renamed before validation, so the handler sees the
+	// declared names. Bodies are parsed an extra time when this is enabled.
+	CaseInsensitiveFields bool
+
+	// FieldAliases accepts the alternative names declared in a field's
+	// `altNames` tag for request body properties, like
+	// `json:"userId" altNames:"user_id"`, which helps clients migrate between
+	// naming conventions. Like `CaseInsensitiveFields`, aliases are renamed
+	// before validation. If both the declared name and an alias are sent, the
+	// declared name wins and the alias is treated as an unknown property.
+	FieldAliases bool
+
+	// BodyLimits optionally restricts the nesting depth and number of
+	// elements of JSON request bodies, which are checked before the body is
+	// unmarshaled and validated. See `BodyLimits` for details.
+	BodyLimits *BodyLimits
+
+	// Multipart optionally controls how `huma.MultipartFormFiles` request
+	// bodies are read, including the memory threshold, temporary directory,
+	// and streaming files to a sink. See `MultipartConfig` for details.
+	Multipart *MultipartConfig
+
+	// ValidationErrorStatus is the status code returned when request params
+	// or bodies fail validation, including multipart forms and errors from
+	// resolvers which don't set a status. Defaults to
+	// `422 Unprocessable Entity`. Set it to `http.StatusBadRequest` to use
+	// `400 Bad Request` for all invalid input, like for malformed bodies.
+	ValidationErrorStatus int
+
+	// ServerTiming sends a `Server-Timing` header with every response, which
+	// summarizes the time in milliseconds spent parsing the request
+	// (`parse`), validating it (`validate`), running the handler
+	// (`handler`), and transforming and marshaling the response (`marshal`).
+	// Browser dev tools display these, making it easy to see where latency
+	// goes. Responses are buffered to measure marshaling, except streaming
+	// responses which omit it. Use `Operation.ServerTiming` to enable it for
+	// individual operations instead.
+	ServerTiming bool
+
+	// DecompressRequests decompresses request bodies sent with a `gzip` or
+	// `deflate` `Content-Encoding` before they are parsed & validated.
+	// Requests with other encodings are rejected with a
+	// `415 Unsupported Media Type` error. `Operation.MaxBodyBytes` limits the
+	// compressed size, while `Operation.MaxDecompressedBodyBytes` limits the
+	// decompressed size. Multipart form bodies are not decompressed.
+	DecompressRequests bool
+
+	// NewError creates the errors written by this API's operations, like
+	// validation failures, instead of the global `huma.NewError`. This lets
+	// multiple APIs in the same process use different error models. Errors
+	// built with the default `*ErrorModel`, e.g. via `huma.Error404NotFound`,
+	// which are returned by handlers are converted with it too, keeping their
+	// status, detail, and error details. Use
+	// `Operation.NewError` to override it for individual operations.
+	NewError func(ctx Context, status int, msg string, errs ...error) StatusError
+
+	// MethodNotAllowed enables returning a `405 Method Not Allowed` error
+	// with an `Allow` header listing the documented methods when a request's
+	// path matches an operation but its method does not, rather than the
+	// router's default response. Requests which match no operation get a
+	// `404 Not Found` error. This requires the adapter to implement
+	// `FallbackAdapter`, otherwise it has no effect. Not found & method not
+	// allowed handlers which were already set on the router are kept.
+	MethodNotAllowed bool
+
 	// Transformers are a way to modify a response body before it is serialized.
 	Transformers []Transformer
 
+	// NamedTransformers are response transformers which can be referenced by
+	// name from an operation's `TransformerOrder`, allowing individual
+	// operations to run a different set of transformers in a custom order.
+	// These do not run unless referenced or also included in `Transformers`.
+	NamedTransformers map[string]Transformer
+
 	// CreateHooks is a list of functions that will be called before the API is
 	// created. This allows you to modify the configuration at creation time,
 	// for example if you need access to the path settings that may be changed
@@ -206,6 +437,11 @@ type Config struct {
 }
 
 // API represents a Huma API wrapping a specific router.
+//
+// Types may embed an API to extend it. Operations registered against such a
+// wrapper use the settings from the `Config` the embedded API was created
+// with, found via its OpenAPI document. Wrappers which return a different
+// OpenAPI document must implement `Unwrap() API` returning the embedded API.
 type API interface {
 	// Adapter returns the router adapter for this API, providing a generic
 	// interface to get request information and write responses.
@@ -222,7 +458,8 @@ type API interface {
 
 	// Transform runs the API transformers on the given value. The `status` is
 	// the key in the operation's `Responses` map that corresponds to the
-	// response being sent (e.g. "200" for a 200 OK response).
+	// response being sent (e.g. "200" for a 200 OK response). The operation's
+	// `TransformerOrder` and `Transformers` are taken into account.
 	Transform(ctx Context, status string, v any) (any, error)
 
 	// Marshal marshals the given value into the given writer. The
@@ -256,6 +493,12 @@ type Format struct {
 
 	// Unmarshal a value into `v` from the given bytes (e.g. request body).
 	Unmarshal func(data []byte, v any) error
+
+	// UnmarshalNumber optionally unmarshals a value into `v` from the given
+	// bytes while preserving the full precision of numbers, e.g. by decoding
+	// them into `json.Number` instead of `float64`. It is used instead of
+	// `Unmarshal` when `Config.UseNumber` is enabled.
+	UnmarshalNumber func(data []byte, v any) error
 }
 
 type api struct {
@@ -264,7 +507,46 @@ type api struct {
 	formats      map[string]Format
 	formatKeys   []string
 	transformers []Transformer
+	named        map[string]Transformer
 	middlewares  Middlewares
+
+	// conditional are attached to matching operations when they are
+	// registered, see `UseMiddlewareIf`.
+	conditional []conditionalMiddleware
+
+	// warmers render cached documents like the OpenAPI spec, see `Precompute`.
+	warmers []func() error
+
+	// routes are the registered operations by route and by operation ID, used
+	// to detect conflicts in `Register`.
+	routes map[string]*Operation
+}
+
+// apis maps each OpenAPI document to the API created for it by `NewAPI`, so
+// that the API can be found for wrappers which embed it without implementing
+// `Unwrap() API`.
+var apis sync.Map
+
+// apiOf returns the API created by `NewAPI` which holds the runtime settings
+// for the given API, following wrappers which implement `Unwrap() API` and
+// falling back to the API created for its OpenAPI document. It panics for
+// other implementations, as their settings and registered operations would
+// otherwise be silently ignored.
+func apiOf(a API) *api {
+	orig := a
+	for {
+		switch v := a.(type) {
+		case *api:
+			return v
+		case interface{ Unwrap() API }:
+			a = v.Unwrap()
+		default:
+			if found, ok := apis.Load(orig.OpenAPI()); ok {
+				return found.(*api)
+			}
+			panic(fmt.Errorf("unknown API implementation %T, APIs must be created with huma.NewAPI", orig))
+		}
+	}
 }
 
 func (a *api) Adapter() Adapter {
@@ -291,6 +573,9 @@ func (a *api) Unmarshal(contentType string, data []byte, v any) error {
 	if !ok {
 		return fmt.Errorf("%w: %s", ErrUnknownContentType, contentType)
 	}
+	if a.config.UseNumber && f.UnmarshalNumber != nil {
+		return f.UnmarshalNumber(data, v)
+	}
 	return f.Unmarshal(data, v)
 }
 
@@ -307,10 +592,33 @@ func (a *api) Negotiate(accept string) (string, error) {
 
 func (a *api) Transform(ctx Context, status string, v any) (any, error) {
 	var err error
-	for _, t := range a.transformers {
-		v, err = t(ctx, status, v)
-		if err != nil {
-			return nil, err
+	op := ctx.Operation()
+	if op != nil && op.TransformerOrder != nil {
+		// The operation overrides the global transformers.
+		for _, name := range op.TransformerOrder {
+			t := a.named[name]
+			if t == nil {
+				return nil, fmt.Errorf("unknown transformer %s", name)
+			}
+			v, err = t(ctx, status, v)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		for _, t := range a.transformers {
+			v, err = t(ctx, status, v)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if op != nil {
+		for _, t := range op.Transformers {
+			v, err = t(ctx, status, v)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 	return v, nil
@@ -371,6 +679,7 @@ func NewAPI(config Config, a Adapter) API {
 		adapter:      a,
 		formats:      map[string]Format{},
 		transformers: config.Transformers,
+		named:        config.NamedTransformers,
 	}
 
 	if config.OpenAPI == nil {
@@ -389,6 +698,19 @@ func NewAPI(config Config, a Adapter) API {
 		config.OpenAPI.Components.Schemas = NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
 	}
 
+	newAPI.config.OpenAPI = config.OpenAPI
+	apis.Store(config.OpenAPI, newAPI)
+
+	// Catch typos in operation transformer names at registration time rather
+	// than when the first request comes in.
+	config.OpenAPI.OnAddOperation = append(config.OpenAPI.OnAddOperation, func(oapi *OpenAPI, op *Operation) {
+		for _, name := range op.TransformerOrder {
+			if newAPI.named[name] == nil {
+				panic(fmt.Errorf("unknown transformer %s in operation %s %s", name, op.Method, op.Path))
+			}
+		}
+	})
+
 	if config.DefaultFormat == "" && config.Formats["application/json"].Marshal != nil {
 		config.DefaultFormat = "application/json"
 	}
@@ -400,101 +722,125 @@ func NewAPI(config Config, a Adapter) API {
 		newAPI.formatKeys = append(newAPI.formatKeys, k)
 	}
 
-	if config.OpenAPIPath != "" {
-		var specJSON []byte
-		a.Handle(&Operation{
-			Method: http.MethodGet,
-			Path:   config.OpenAPIPath + ".json",
-		}, func(ctx Context) {
-			ctx.SetHeader("Content-Type", "application/vnd.oai.openapi+json")
-			if specJSON == nil {
-				specJSON, _ = json.Marshal(newAPI.OpenAPI())
+	basePath := config.basePathFunc()
+
+	if fa, ok := a.(FallbackAdapter); ok && config.MethodNotAllowed {
+		fa.HandleFallback(func(ctx Context) {
+			if allowed := allowedMethods(newAPI.routes, config.BasePath, ctx.URL().Path); len(allowed) > 0 {
+				ctx.SetHeader("Allow", strings.Join(allowed, ", "))
+				WriteErr(newAPI, ctx, http.StatusMethodNotAllowed, "method "+ctx.Method()+" not allowed")
+				return
 			}
-			ctx.BodyWriter().Write(specJSON)
+			WriteErr(newAPI, ctx, http.StatusNotFound, "not found")
 		})
-		var specJSON30 []byte
-		a.Handle(&Operation{
-			Method: http.MethodGet,
-			Path:   config.OpenAPIPath + "-3.0.json",
-		}, func(ctx Context) {
-			ctx.SetHeader("Content-Type", "application/vnd.oai.openapi+json")
-			if specJSON30 == nil {
-				specJSON30, _ = newAPI.OpenAPI().Downgrade()
+	}
+
+	if config.OpenAPIPath != "" {
+		specs := newSpecDocs(config, newAPI.OpenAPI)
+		filtered := newFilteredSpecs(config, newAPI.OpenAPI)
+		for i, suffix := range specSuffixes {
+			i := i
+			a.Handle(&Operation{
+				Method: http.MethodGet,
+				Path:   config.BasePath + config.OpenAPIPath + suffix,
+			}, func(ctx Context) {
+				if docs := filtered.get(ctx); docs != nil {
+					docs[i].serve(ctx)
+					return
+				}
+				specs[i].serve(ctx)
+			})
+		}
+
+		// The cached documents are stale once a new operation gets added.
+		config.OpenAPI.OnAddOperation = append(config.OpenAPI.OnAddOperation, func(oapi *OpenAPI, op *Operation) {
+			for _, spec := range specs {
+				spec.invalidate()
 			}
-			ctx.BodyWriter().Write(specJSON30)
+			filtered.add(op)
 		})
-		var specYAML []byte
-		a.Handle(&Operation{
-			Method: http.MethodGet,
-			Path:   config.OpenAPIPath + ".yaml",
-		}, func(ctx Context) {
-			ctx.SetHeader("Content-Type", "application/vnd.oai.openapi+yaml")
-			if specYAML == nil {
-				specYAML, _ = newAPI.OpenAPI().YAML()
-			}
-			ctx.BodyWriter().Write(specYAML)
+		for _, spec := range specs {
+			newAPI.warmers = append(newAPI.warmers, spec.warm)
+		}
+	}
+
+	if config.HomePath != "" {
+		home := newSpecCache("application/json-home", config.OpenAPICacheControl, config.OpenAPIEncoders, func() ([]byte, error) {
+			return renderHome(newAPI.OpenAPI(), config.OpenAPIPath)
 		})
-		var specYAML30 []byte
 		a.Handle(&Operation{
 			Method: http.MethodGet,
-			Path:   config.OpenAPIPath + "-3.0.yaml",
-		}, func(ctx Context) {
-			ctx.SetHeader("Content-Type", "application/vnd.oai.openapi+yaml")
-			if specYAML30 == nil {
-				specYAML30, _ = newAPI.OpenAPI().DowngradeYAML()
-			}
-			ctx.BodyWriter().Write(specYAML30)
+			Path:   config.BasePath + config.HomePath,
+		}, home.serve)
+
+		config.OpenAPI.OnAddOperation = append(config.OpenAPI.OnAddOperation, func(oapi *OpenAPI, op *Operation) {
+			home.invalidate()
 		})
+		newAPI.warmers = append(newAPI.warmers, home.warm)
 	}
 
 	if config.DocsPath != "" {
+		renderer := config.DocsRenderer
+		if renderer == nil {
+			renderer = &ElementsRenderer{}
+		}
 		a.Handle(&Operation{
 			Method: http.MethodGet,
-			Path:   config.DocsPath,
+			Path:   config.BasePath + config.DocsPath,
 		}, func(ctx Context) {
-			openAPIPath := config.OpenAPIPath
-			if prefix := getAPIPrefix(newAPI.OpenAPI()); prefix != "" {
-				openAPIPath = path.Join(prefix, openAPIPath)
+			page := DocsPage{
+				Title:    "API Reference",
+				SpecPath: config.OpenAPIPath,
 			}
-			ctx.SetHeader("Content-Type", "text/html")
-			title := "Elements in HTML"
-			if config.Info != nil && config.Info.Title != "" {
-				title = config.Info.Title + " Reference"
+			if info := newAPI.OpenAPI().Info; info != nil && info.Title != "" {
+				page.Title = info.Title
 			}
-			ctx.BodyWriter().Write([]byte(`<!doctype html>
-<html lang="en">
-  <head>
-    <meta charset="utf-8" />
-    <meta name="referrer" content="same-origin" />
-    <meta name="viewport" content="width=device-width, initial-scale=1, shrink-to-fit=no" />
-    <title>` + title + `</title>
-    <!-- Embed elements Elements via Web Component -->
-    <link href="https://unpkg.com/@stoplight/elements@9.0.0/styles.min.css" rel="stylesheet" />
-    <script src="https://unpkg.com/@stoplight/elements@9.0.0/web-components.min.js" integrity="sha256-Tqvw1qE2abI+G6dPQBc5zbeHqfVwGoamETU3/TSpUw4="
-            crossorigin="anonymous"></script>
-  </head>
-  <body style="height: 100vh;">
-
-    <elements-api
-      apiDescriptionUrl="` + openAPIPath + `.yaml"
-      router="hash"
-      layout="sidebar"
-      tryItCredentialsPolicy="same-origin"
-    />
-
-  </body>
-</html>`))
+			prefix := getAPIPrefix(newAPI.OpenAPI())
+			if basePath != nil {
+				prefix = path.Join("/", prefix, basePath(ctx))
+			}
+			if prefix != "" {
+				page.SpecPath = path.Join(prefix, page.SpecPath)
+			}
+			if config.DocsAssets != nil {
+				page.AssetsPath = path.Join(prefix, config.DocsPath, "assets")
+			}
+			ctx.SetHeader("Content-Type", "text/html")
+			ctx.BodyWriter().Write(renderer.RenderDocs(page))
 		})
+
+		if config.DocsAssets != nil {
+			a.Handle(&Operation{
+				Method: http.MethodGet,
+				Path:   config.BasePath + config.DocsPath + "/assets/{file}",
+			}, func(ctx Context) {
+				file := ctx.Param("file")
+				b, err := fs.ReadFile(config.DocsAssets, file)
+				if err != nil {
+					ctx.SetStatus(http.StatusNotFound)
+					return
+				}
+				if ct := mime.TypeByExtension(path.Ext(file)); ct != "" {
+					ctx.SetHeader("Content-Type", ct)
+				}
+				ctx.BodyWriter().Write(b)
+			})
+		}
 	}
 
 	if config.SchemasPath != "" {
 		a.Handle(&Operation{
 			Method: http.MethodGet,
-			Path:   config.SchemasPath + "/{schema}",
+			Path:   config.BasePath + config.SchemasPath + "/{schema}",
 		}, func(ctx Context) {
 			// Some routers dislike a path param+suffix, so we strip it here instead.
 			schema := strings.TrimSuffix(ctx.Param("schema"), ".json")
 			ctx.SetHeader("Content-Type", "application/json")
+			if config.BundleSchemas {
+				b, _ := json.Marshal(SchemaBundle(config.OpenAPI.Components.Schemas, schema))
+				ctx.BodyWriter().Write(b)
+				return
+			}
 			b, _ := json.Marshal(config.OpenAPI.Components.Schemas.Map()[schema])
 			b = rxSchema.ReplaceAll(b, []byte(config.SchemasPath+`/$1.json`))
 			ctx.BodyWriter().Write(b)
@@ -503,3 +849,83 @@ func NewAPI(config Config, a Adapter) API {
 
 	return newAPI
 }
+
+var pathPatterns sync.Map
+
+// allowedMethods returns the methods of the registered operations, including
+// hidden ones, for the path template which best matches the concrete request
+// path, if any. Templates with fewer parameters are preferred, e.g.
+// `/items/new` over `/items/{id}`.
+func allowedMethods(routes map[string]*Operation, basePath, path string) []string {
+	templates := map[string]map[string]bool{}
+	for key, op := range routes {
+		if strings.HasPrefix(key, "id ") {
+			// Operation IDs are tracked alongside the routes.
+			continue
+		}
+		if templates[op.Path] == nil {
+			templates[op.Path] = map[string]bool{}
+		}
+		templates[op.Path][strings.ToUpper(op.Method)] = true
+	}
+
+	var best []string
+	bestParams := -1
+	for template, registered := range templates {
+		params := strings.Count(template, "{")
+		if bestParams != -1 && params >= bestParams {
+			continue
+		}
+
+		// Operations are mounted under the base path, if any.
+		if !pathPattern(basePath + template).MatchString(path) {
+			continue
+		}
+
+		methods := []string{}
+		for _, method := range []string{
+			http.MethodGet,
+			http.MethodHead,
+			http.MethodPost,
+			http.MethodPut,
+			http.MethodPatch,
+			http.MethodDelete,
+			http.MethodOptions,
+			http.MethodTrace,
+		} {
+			if registered[method] {
+				methods = append(methods, method)
+			}
+		}
+		if len(methods) > 0 {
+			best, bestParams = methods, params
+		}
+	}
+	return best
+}
+
+// pathPattern returns the cached regular expression matching concrete paths
+// for the path template.
+func pathPattern(template string) *regexp.Regexp {
+	if re, ok := pathPatterns.Load(template); ok {
+		return re.(*regexp.Regexp)
+	}
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, part := range strings.Split(template, "/")[1:] {
+		sb.WriteString("/")
+		for part != "" {
+			start := strings.Index(part, "{")
+			end := strings.Index(part, "}")
+			if start == -1 || end < start {
+				sb.WriteString(regexp.QuoteMeta(part))
+				break
+			}
+			sb.WriteString(regexp.QuoteMeta(part[:start]) + "[^/]+")
+			part = part[end+1:]
+		}
+	}
+	sb.WriteString("$")
+	re, _ := pathPatterns.LoadOrStore(template, regexp.MustCompile(sb.String()))
+	return re.(*regexp.Regexp)
+}
@@ -1,13 +1,18 @@
 package huma_test
 
 import (
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"io"
 	"net/http"
+	"slices"
 	"testing"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/humatest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBlankConfig(t *testing.T) {
@@ -87,3 +92,224 @@ func TestContextValue(t *testing.T) {
 	resp := api.Get("/test")
 	assert.Equal(t, http.StatusNoContent, resp.Code)
 }
+
+func TestOpenAPISpecCaching(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.OpenAPICacheControl = "public, max-age=60"
+	_, api := humatest.New(t, config)
+
+	resp := api.Get("/openapi.json")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "public, max-age=60", resp.Header().Get("Cache-Control"))
+	assert.Equal(t, "Accept-Encoding", resp.Header().Get("Vary"))
+	assert.Empty(t, resp.Header().Get("Content-Encoding"))
+	etag := resp.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	// The client already has the latest version.
+	resp = api.Get("/openapi.json", "If-None-Match: "+etag)
+	assert.Equal(t, http.StatusNotModified, resp.Code)
+	assert.Empty(t, resp.Body.String())
+
+	// Compressed variants have their own ETag.
+	resp = api.Get("/openapi.yaml", "Accept-Encoding: br;q=1.0, gzip;q=0.8")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "gzip", resp.Header().Get("Content-Encoding"))
+	assert.Equal(t, "application/vnd.oai.openapi+yaml", resp.Header().Get("Content-Type"))
+	gz, err := gzip.NewReader(resp.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "title: Test API")
+
+	// Adding an operation invalidates the cached spec.
+	huma.Get(api, "/new", func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+	resp = api.Get("/openapi.json", "If-None-Match: "+etag)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotEqual(t, etag, resp.Header().Get("ETag"))
+	assert.Contains(t, resp.Body.String(), `"/new"`)
+}
+
+func TestCustomJSONFormat(t *testing.T) {
+	marshaled := 0
+	unmarshaled := 0
+	format := huma.NewJSONFormat(func(v any) ([]byte, error) {
+		marshaled++
+		return json.Marshal(v)
+	}, func(data []byte, v any) error {
+		unmarshaled++
+		return json.Unmarshal(data, v)
+	})
+
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Formats = huma.WithJSONFormat(config.Formats, format)
+
+	_, api := humatest.New(t, config)
+
+	huma.Put(api, "/things", func(ctx context.Context, input *struct {
+		Body struct {
+			Name string `json:"name"`
+		}
+	}) (*struct{ Body json.RawMessage }, error) {
+		return &struct{ Body json.RawMessage }{
+			Body: json.RawMessage(`{"name":"` + input.Body.Name + `"}`),
+		}, nil
+	})
+
+	resp := api.Put("/things", map[string]any{"name": "foo"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "{\"name\":\"foo\"}\n", resp.Body.String())
+	assert.Positive(t, unmarshaled)
+
+	// Raw messages are passed through without calling the marshaler.
+	assert.Equal(t, 0, marshaled)
+
+	resp = api.Put("/things", map[string]any{"name": 1})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Equal(t, 1, marshaled)
+}
+
+func TestSpecFilter(t *testing.T) {
+	calls := 0
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.SpecAudience = func(ctx huma.Context) string {
+		if ctx.Header("Authorization") == "internal" {
+			return "internal"
+		}
+		return "public"
+	}
+	config.SpecFilter = func(ctx huma.Context, doc *huma.OpenAPI) *huma.OpenAPI {
+		calls++
+		if config.SpecAudience(ctx) == "internal" {
+			return doc
+		}
+		return doc.FilterOperations(func(op *huma.Operation) bool {
+			return !slices.Contains(op.Tags, "Internal")
+		})
+	}
+	config.Tags = []*huma.Tag{{Name: "Internal"}, {Name: "Things"}}
+	_, api := humatest.New(t, config)
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/things",
+		Tags:   []string{"Things"},
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+	huma.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/admin",
+		Tags:   []string{"Internal"},
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	resp := api.Get("/openapi.json")
+	assert.Contains(t, resp.Body.String(), `"/things"`)
+	assert.NotContains(t, resp.Body.String(), `"/admin"`)
+	assert.NotContains(t, resp.Body.String(), `"Internal"`)
+
+	resp = api.Get("/openapi.json", "Authorization: internal")
+	assert.Contains(t, resp.Body.String(), `"/admin"`)
+
+	// Each audience's document is cached.
+	api.Get("/openapi.json")
+	api.Get("/openapi.json", "Authorization: internal")
+	assert.Equal(t, 2, calls)
+
+	// The full document is not modified.
+	assert.NotNil(t, api.OpenAPI().Paths["/admin"])
+	assert.Len(t, api.OpenAPI().Tags, 2)
+}
+
+func TestHomeDocument(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.HomePath = "/home"
+	_, api := humatest.New(t, config)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-things",
+		Method:      http.MethodGet,
+		Path:        "/things",
+	}, func(ctx context.Context, input *struct {
+		Cursor string `query:"cursor"`
+		Limit  int    `query:"limit"`
+	}) (*struct{ Body []string }, error) {
+		return nil, nil
+	})
+	huma.Register(api, huma.Operation{
+		OperationID: "create-thing",
+		Method:      http.MethodPost,
+		Path:        "/things",
+		Deprecated:  true,
+	}, func(ctx context.Context, input *struct {
+		Body struct {
+			Name string `json:"name"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{ Body string }, error) {
+		return nil, nil
+	})
+	huma.Register(api, huma.Operation{
+		OperationID: "hidden",
+		Method:      http.MethodGet,
+		Path:        "/hidden",
+		Hidden:      true,
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	resp := api.Get("/home")
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json-home", resp.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"api": {
+			"title": "Test API",
+			"links": {"describedBy": "/openapi.json"}
+		},
+		"resources": {
+			"list-things": {
+				"hrefTemplate": "/things{?cursor,limit}",
+				"hrefVars": {"cursor": "param/cursor", "limit": "param/limit"},
+				"hints": {"allow": ["GET"], "formats": {"application/json": {}}}
+			},
+			"create-thing": {
+				"href": "/things",
+				"hints": {"allow": ["POST"], "acceptPost": ["application/json"], "status": "deprecated"}
+			},
+			"get-thing": {
+				"hrefTemplate": "/things/{id}",
+				"hrefVars": {"id": "param/id"},
+				"hints": {"allow": ["GET"], "formats": {"application/json": {}}}
+			}
+		}
+	}`, resp.Body.String())
+
+	// Clients can revalidate the cached document.
+	resp = api.Get("/home", "If-None-Match: "+resp.Header().Get("ETag"))
+	assert.Equal(t, http.StatusNotModified, resp.Code)
+
+	// New operations invalidate the cache.
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-thing",
+		Method:      http.MethodDelete,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+	resp = api.Get("/home")
+	assert.Contains(t, resp.Body.String(), `"delete-thing"`)
+}
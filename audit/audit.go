@@ -0,0 +1,216 @@
+// Package audit provides a middleware which emits structured audit events for
+// operations marked with `huma.Audited`, recording who called the operation,
+// what they sent, and the outcome. Events are sent to a pluggable sink, e.g.
+// for writing to a log or an external compliance system.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Redacted replaces the values of redacted fields in audit events.
+var Redacted = "[REDACTED]"
+
+// DefaultRedact lists the parameter and body field names which are always
+// redacted, compared case-insensitively.
+var DefaultRedact = []string{"authorization", "cookie", "password", "secret", "token"}
+
+// MaxBodyBytes is the maximum number of request body bytes which are included
+// in an event. Larger bodies are omitted.
+var MaxBodyBytes = 64 * 1024
+
+// Event is a single audit event describing a request to an audited operation.
+type Event struct {
+	// Time is when the request started.
+	Time time.Time `json:"time"`
+
+	// Action is the name given via `huma.Audited`, like `resource.update`.
+	Action string `json:"action"`
+
+	// Actor identifies the caller, as returned by `Config.Actor`.
+	Actor string `json:"actor,omitempty"`
+
+	OperationID string `json:"operationId"`
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+
+	// Input summarizes the request input with sensitive values redacted. Keys
+	// are parameter locations like `path.id` or `query.q`, and `body` holds
+	// the decoded JSON body.
+	Input map[string]any `json:"input,omitempty"`
+
+	// Status is the response status code.
+	Status int `json:"status"`
+
+	// Duration is how long the request took to handle.
+	Duration time.Duration `json:"duration"`
+}
+
+// Sink receives audit events after each request to an audited operation. It
+// is called synchronously, so slow sinks should buffer events.
+type Sink func(ctx context.Context, event *Event)
+
+// Config configures the audit middleware.
+type Config struct {
+	// Sink receives the events and is required.
+	Sink Sink
+
+	// Actor returns an identifier for the caller, e.g. from a value set on the
+	// context by an auth middleware. Optional.
+	Actor func(ctx huma.Context) string
+
+	// Redact lists additional parameter and body field names to redact, which
+	// are compared case-insensitively. See also `DefaultRedact`.
+	Redact []string
+}
+
+type humaContext = huma.Context
+
+// bodyRecorder wraps a context to keep a copy of the request body as it is
+// read by the operation handler.
+type bodyRecorder struct {
+	humaContext
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (r *bodyRecorder) Unwrap() huma.Context {
+	return r.humaContext
+}
+
+func (r *bodyRecorder) BodyReader() io.Reader {
+	return io.TeeReader(r.humaContext.BodyReader(), r)
+}
+
+// Write implements `io.Writer`, keeping at most `MaxBodyBytes` of the body.
+func (r *bodyRecorder) Write(p []byte) (int, error) {
+	if r.body.Len()+len(p) > MaxBodyBytes {
+		r.truncated = true
+	}
+	if !r.truncated {
+		r.body.Write(p)
+	}
+	return len(p), nil
+}
+
+// Middleware returns a middleware which sends an event to the configured sink
+// after each request to an operation marked with `huma.Audited`. Other
+// operations are passed through untouched.
+//
+//	api.UseMiddleware(audit.Middleware(audit.Config{
+//		Actor: func(ctx huma.Context) string {
+//			return getUserFromToken(ctx.Header("Authorization"))
+//		},
+//		Sink: func(ctx context.Context, event *audit.Event) {
+//			slog.InfoContext(ctx, "audit", "event", event)
+//		},
+//	}))
+func Middleware(config Config) func(ctx huma.Context, next func(huma.Context)) {
+	redact := map[string]bool{}
+	for _, name := range append(DefaultRedact, config.Redact...) {
+		redact[strings.ToLower(name)] = true
+	}
+
+	return func(ctx huma.Context, next func(huma.Context)) {
+		op := ctx.Operation()
+		if op == nil {
+			next(ctx)
+			return
+		}
+		action, ok := op.Metadata["audit"].(string)
+		if !ok {
+			next(ctx)
+			return
+		}
+
+		start := time.Now()
+		event := &Event{
+			Time:        start,
+			Action:      action,
+			OperationID: op.OperationID,
+			Method:      ctx.Method(),
+			Path:        ctx.URL().Path,
+		}
+		if config.Actor != nil {
+			event.Actor = config.Actor(ctx)
+		}
+
+		recorder := &bodyRecorder{humaContext: ctx}
+		next(recorder)
+
+		event.Status = ctx.Status()
+		event.Duration = time.Since(start)
+		var body []byte
+		if !recorder.truncated {
+			body = recorder.body.Bytes()
+		}
+		event.Input = summarize(ctx, op, body, redact)
+		config.Sink(ctx.Context(), event)
+	}
+}
+
+// summarize collects the request parameters and body with sensitive values
+// redacted.
+func summarize(ctx huma.Context, op *huma.Operation, body []byte, redact map[string]bool) map[string]any {
+	input := map[string]any{}
+	for _, p := range op.Parameters {
+		var value string
+		switch p.In {
+		case "path":
+			value = ctx.Param(p.Name)
+		case "query":
+			value = ctx.Query(p.Name)
+		case "header":
+			value = ctx.Header(p.Name)
+		default:
+			// Cookies are too likely to hold credentials.
+			continue
+		}
+		if value == "" {
+			continue
+		}
+		if redact[strings.ToLower(p.Name)] {
+			value = Redacted
+		}
+		input[p.In+"."+p.Name] = value
+	}
+
+	if len(body) > 0 {
+		var decoded any
+		if json.Unmarshal(body, &decoded) == nil {
+			input["body"] = redactValue(decoded, redact)
+		}
+	}
+
+	if len(input) == 0 {
+		return nil
+	}
+	return input
+}
+
+// redactValue replaces the values of redacted fields anywhere in a decoded
+// JSON value.
+func redactValue(v any, redact map[string]bool) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, item := range t {
+			if redact[strings.ToLower(k)] {
+				t[k] = Redacted
+				continue
+			}
+			t[k] = redactValue(item, redact)
+		}
+	case []any:
+		for i, item := range t {
+			t[i] = redactValue(item, redact)
+		}
+	}
+	return v
+}
@@ -0,0 +1,107 @@
+package audit_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/audit"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+type Credentials struct {
+	Name     string   `json:"name"`
+	Password string   `json:"password"`
+	Keys     []APIKey `json:"keys,omitempty"`
+}
+
+type APIKey struct {
+	Label  string `json:"label"`
+	Secret string `json:"secret"`
+}
+
+func TestAudit(t *testing.T) {
+	_, api := humatest.New(t)
+
+	events := []*audit.Event{}
+	api.UseMiddleware(audit.Middleware(audit.Config{
+		Actor: func(ctx huma.Context) string {
+			return ctx.Header("X-User")
+		},
+		Redact: []string{"X-API-Key"},
+		Sink: func(ctx context.Context, event *audit.Event) {
+			events = append(events, event)
+		},
+	}))
+
+	huma.Put(api, "/users/{id}", func(ctx context.Context, input *struct {
+		ID     string `path:"id"`
+		DryRun bool   `query:"dry-run"`
+		APIKey string `header:"X-API-Key"`
+		Body   Credentials
+	}) (*struct{}, error) {
+		if input.ID == "missing" {
+			return nil, huma.Error404NotFound("user not found")
+		}
+		return nil, nil
+	}, huma.Audited("user.update"))
+
+	huma.Get(api, "/users/{id}", func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	assert.Equal(t, "user.update", api.OpenAPI().Paths["/users/{id}"].Put.Metadata["audit"])
+
+	resp := api.Get("/users/abc")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Empty(t, events)
+
+	resp = api.Put("/users/abc?dry-run=true", "X-User: alice", "X-API-Key: abc123", map[string]any{
+		"name":     "Alice",
+		"password": "hunter2",
+		"keys":     []any{map[string]any{"label": "ci", "secret": "s3cr3t"}},
+	})
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	require.Len(t, events, 1)
+
+	event := events[0]
+	assert.Equal(t, "user.update", event.Action)
+	assert.Equal(t, "alice", event.Actor)
+	assert.Equal(t, "put-users-by-id", event.OperationID)
+	assert.Equal(t, http.MethodPut, event.Method)
+	assert.Equal(t, "/users/abc", event.Path)
+	assert.Equal(t, http.StatusNoContent, event.Status)
+	assert.False(t, event.Time.IsZero())
+	assert.Equal(t, map[string]any{
+		"path.id":          "abc",
+		"query.dry-run":    "true",
+		"header.X-API-Key": audit.Redacted,
+		"body": map[string]any{
+			"name":     "Alice",
+			"password": audit.Redacted,
+			"keys":     []any{map[string]any{"label": "ci", "secret": audit.Redacted}},
+		},
+	}, event.Input)
+
+	// Failures are audited too.
+	resp = api.Put("/users/missing", strings.NewReader(`{"name": "Bob", "password": "x"}`))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	require.Len(t, events, 2)
+	assert.Equal(t, http.StatusNotFound, events[1].Status)
+	assert.Empty(t, events[1].Actor)
+
+	// Large bodies are omitted.
+	old := audit.MaxBodyBytes
+	audit.MaxBodyBytes = 10
+	defer func() { audit.MaxBodyBytes = old }()
+	api.Put("/users/abc", strings.NewReader(`{"name": "Bob", "password": "x"}`))
+	require.Len(t, events, 3)
+	assert.Equal(t, map[string]any{"path.id": "abc"}, events[2].Input)
+}
@@ -0,0 +1,289 @@
+// Package autobatch provides a way to automatically generate a batch
+// operation which lets clients send many requests to existing operations in a
+// single HTTP round trip. Each sub-request runs through the normal request
+// pipeline, including routing, middleware, and validation.
+package autobatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// MaxRequests is the maximum number of sub-requests allowed in a single batch.
+var MaxRequests = 100
+
+// Request is a single sub-request within a batch.
+type Request struct {
+	Method  string            `json:"method" doc:"HTTP method of the operation to call"`
+	Path    string            `json:"path" doc:"URL path of the operation to call, including any query string"`
+	Headers map[string]string `json:"headers,omitempty" doc:"Additional request headers"`
+	Body    json.RawMessage   `json:"body,omitempty" doc:"Request body"`
+}
+
+// Response is the result of a single sub-request within a batch.
+type Response struct {
+	Status  int               `json:"status" doc:"HTTP status code of the response"`
+	Headers map[string]string `json:"headers,omitempty" doc:"Response headers"`
+	Body    any               `json:"body,omitempty" doc:"Response body. JSON responses are embedded as-is while other responses are included as a string."`
+}
+
+var responsesType = reflect.TypeOf([]Response{})
+
+// batchable is an operation which can be called from a batch.
+type batchable struct {
+	op      *huma.Operation
+	pattern *regexp.Regexp
+}
+
+// pathPattern converts a path template like `/things/{id}` into a regular
+// expression which matches concrete paths.
+func pathPattern(template string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, part := range strings.Split(template, "/")[1:] {
+		sb.WriteString("/")
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			sb.WriteString("[^/]+")
+			continue
+		}
+		sb.WriteString(regexp.QuoteMeta(part))
+	}
+	sb.WriteString("$")
+	return sb.String()
+}
+
+// AutoBatch registers a `POST /batch` operation which accepts an array of
+// sub-requests referencing the operations registered so far and returns an
+// array of their responses in the same order. Call it *after* registering
+// your operations. If an operation already exists at `/batch` then nothing
+// is registered.
+//
+// Headers sent with the batch request, like `Authorization`, are copied to
+// each sub-request. If you wish to prevent an operation from being called via
+// a batch, set the `autobatch` operation metadata field to `false`.
+func AutoBatch(api huma.API) {
+	oapi := api.OpenAPI()
+	if oapi.Paths["/batch"] != nil && oapi.Paths["/batch"].Post != nil {
+		return
+	}
+
+	ops := []batchable{}
+	for _, path := range oapi.Paths {
+		for _, op := range []*huma.Operation{path.Get, path.Put, path.Post, path.Delete, path.Options, path.Head, path.Patch, path.Trace} {
+			if op == nil {
+				continue
+			}
+			if op.Metadata != nil {
+				if b, ok := op.Metadata["autobatch"].(bool); ok && !b {
+					continue
+				}
+			}
+			ops = append(ops, batchable{op: op, pattern: regexp.MustCompile(pathPattern(op.Path))})
+		}
+	}
+
+	// Sort for a stable generated schema.
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].op.Path != ops[j].op.Path {
+			return ops[i].op.Path < ops[j].op.Path
+		}
+		return ops[i].op.Method < ops[j].op.Method
+	})
+
+	op := &huma.Operation{
+		OperationID: "batch",
+		Method:      http.MethodPost,
+		Path:        "/batch",
+		Summary:     "Batch",
+		Description: "Call multiple operations in a single request. Sub-requests are run in order and their responses are returned in the same order.",
+		RequestBody: &huma.RequestBody{
+			Required: true,
+			Content: map[string]*huma.MediaType{
+				"application/json": {
+					Schema: requestSchema(ops),
+				},
+			},
+		},
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "OK",
+				Content: map[string]*huma.MediaType{
+					"application/json": {
+						Schema: oapi.Components.Schemas.Schema(responsesType, true, "BatchResponses"),
+					},
+				},
+			},
+		},
+		Errors: []int{http.StatusBadRequest, http.StatusUnprocessableEntity},
+	}
+	oapi.AddOperation(op)
+
+	adapter := api.Adapter()
+	adapter.Handle(op, func(ctx huma.Context) {
+		var requests []Request
+		if err := json.NewDecoder(ctx.BodyReader()).Decode(&requests); err != nil {
+			huma.WriteErr(api, ctx, http.StatusBadRequest, "Unable to decode batch requests", err)
+			return
+		}
+
+		if len(requests) > MaxRequests {
+			huma.WriteErr(api, ctx, http.StatusUnprocessableEntity, "Too many batch requests", &huma.ErrorDetail{
+				Message:  "expected at most " + strconv.Itoa(MaxRequests) + " requests",
+				Location: "body",
+			})
+			return
+		}
+
+		errs := []error{}
+		for i, req := range requests {
+			if req.Method == "" || req.Path == "" {
+				errs = append(errs, &huma.ErrorDetail{
+					Message:  "expected method and path",
+					Location: "body[" + strconv.Itoa(i) + "]",
+				})
+			}
+		}
+		if len(errs) > 0 {
+			huma.WriteErr(api, ctx, http.StatusUnprocessableEntity, "validation failed", errs...)
+			return
+		}
+
+		responses := make([]Response, len(requests))
+		for i, req := range requests {
+			responses[i] = run(ctx, adapter, ops, req)
+		}
+
+		b, err := json.Marshal(responses)
+		if err != nil {
+			huma.WriteErr(api, ctx, http.StatusInternalServerError, "Unable to marshal batch responses", err)
+			return
+		}
+		ctx.SetHeader("Content-Type", "application/json")
+		ctx.SetStatus(http.StatusOK)
+		ctx.BodyWriter().Write(b)
+	})
+}
+
+// requestSchema composes the batch request schema from the schemas of the
+// batchable operations.
+func requestSchema(ops []batchable) *huma.Schema {
+	headers := &huma.Schema{
+		Type:                 huma.TypeObject,
+		Description:          "Additional request headers",
+		AdditionalProperties: &huma.Schema{Type: huma.TypeString},
+	}
+
+	items := make([]*huma.Schema, 0, len(ops))
+	for _, b := range ops {
+		s := &huma.Schema{
+			Type:        huma.TypeObject,
+			Title:       b.op.OperationID,
+			Description: b.op.Summary,
+			Properties: map[string]*huma.Schema{
+				"method":  {Type: huma.TypeString, Enum: []any{b.op.Method}},
+				"path":    {Type: huma.TypeString, Pattern: strings.TrimSuffix(pathPattern(b.op.Path), "$") + `(\?.*)?$`},
+				"headers": headers,
+			},
+			Required: []string{"method", "path"},
+		}
+		if rb := b.op.RequestBody; rb != nil {
+			if mt := rb.Content["application/json"]; mt != nil && mt.Schema != nil {
+				s.Properties["body"] = mt.Schema
+				if rb.Required {
+					s.Required = append(s.Required, "body")
+				}
+			}
+		}
+		items = append(items, s)
+	}
+
+	maxItems := MaxRequests
+	return &huma.Schema{
+		Type:     huma.TypeArray,
+		MaxItems: &maxItems,
+		Items:    &huma.Schema{AnyOf: items},
+	}
+}
+
+// run performs a single sub-request and captures its response.
+func run(ctx huma.Context, adapter huma.Adapter, ops []batchable, req Request) Response {
+	u, err := url.Parse(req.Path)
+	if err != nil {
+		return errorResponse(http.StatusBadRequest, "Invalid path", err)
+	}
+
+	method := strings.ToUpper(req.Method)
+	found := false
+	for _, b := range ops {
+		if b.op.Method == method && b.pattern.MatchString(u.Path) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return errorResponse(http.StatusNotFound, "No batchable operation found for "+method+" "+u.Path)
+	}
+
+	r, err := http.NewRequestWithContext(ctx.Context(), method, u.String(), bytes.NewReader(req.Body))
+	if err != nil {
+		return errorResponse(http.StatusBadRequest, "Invalid request", err)
+	}
+
+	// Copy incoming headers, e.g. for auth.
+	ctx.EachHeader(func(k, v string) {
+		switch http.CanonicalHeaderKey(k) {
+		case "Content-Type", "Content-Length", "Accept", "Accept-Encoding":
+			return
+		}
+		r.Header.Add(k, v)
+	})
+	if len(req.Body) > 0 {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	r.Header.Set("Accept", "application/json")
+	for k, v := range req.Headers {
+		r.Header.Set(k, v)
+	}
+
+	w := httptest.NewRecorder()
+	adapter.ServeHTTP(w, r)
+
+	resp := Response{Status: w.Code}
+	if len(w.Header()) > 0 {
+		resp.Headers = make(map[string]string, len(w.Header()))
+		for k := range w.Header() {
+			resp.Headers[k] = w.Header().Get(k)
+		}
+	}
+	if w.Body.Len() > 0 {
+		raw := w.Body.Bytes()
+		if json.Valid(raw) {
+			resp.Body = json.RawMessage(raw)
+		} else {
+			resp.Body = w.Body.String()
+		}
+	}
+	return resp
+}
+
+// errorResponse creates a sub-response for a request which could not be run.
+func errorResponse(status int, msg string, errs ...error) Response {
+	resp := Response{
+		Status:  status,
+		Headers: map[string]string{"Content-Type": "application/problem+json"},
+	}
+	if b, err := json.Marshal(huma.NewError(status, msg, errs...)); err == nil {
+		resp.Body = json.RawMessage(b)
+	}
+	return resp
+}
@@ -0,0 +1,146 @@
+package autobatch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+type ThingModel struct {
+	ID    string `json:"id"`
+	Price int    `json:"price" minimum:"0"`
+}
+
+func TestBatch(t *testing.T) {
+	db := map[string]*ThingModel{
+		"test": {ID: "test", Price: 1},
+	}
+
+	_, api := humatest.New(t)
+
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		if ctx.Header("Authorization") != "secret" {
+			huma.WriteErr(api, ctx, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		next(ctx)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{thing-id}",
+		Summary:     "Get a thing",
+		Errors:      []int{404},
+	}, func(ctx context.Context, input *struct {
+		ThingID string `path:"thing-id"`
+	}) (*struct{ Body *ThingModel }, error) {
+		thing := db[input.ThingID]
+		if thing == nil {
+			return nil, huma.Error404NotFound("Not found")
+		}
+		return &struct{ Body *ThingModel }{Body: thing}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "put-thing",
+		Method:        http.MethodPut,
+		Path:          "/things/{thing-id}",
+		DefaultStatus: http.StatusNoContent,
+	}, func(ctx context.Context, input *struct {
+		ThingID string `path:"thing-id"`
+		Body    ThingModel
+	}) (*struct{}, error) {
+		db[input.ThingID] = &input.Body
+		return nil, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-thing",
+		Method:      http.MethodDelete,
+		Path:        "/things/{thing-id}",
+		Metadata:    map[string]any{"autobatch": false},
+	}, func(ctx context.Context, input *struct {
+		ThingID string `path:"thing-id"`
+	}) (*struct{}, error) {
+		delete(db, input.ThingID)
+		return nil, nil
+	})
+
+	AutoBatch(api)
+
+	// The request schema is composed from the batchable operations.
+	op := api.OpenAPI().Paths["/batch"].Post
+	require.NotNil(t, op)
+	items := op.RequestBody.Content["application/json"].Schema.Items.AnyOf
+	require.Len(t, items, 2)
+	assert.Equal(t, "get-thing", items[0].Title)
+	assert.Equal(t, []any{http.MethodGet}, items[0].Properties["method"].Enum)
+	assert.Equal(t, `^/things/[^/]+(\?.*)?$`, items[0].Properties["path"].Pattern)
+	assert.Nil(t, items[0].Properties["body"])
+	assert.Equal(t, "put-thing", items[1].Title)
+	assert.Equal(t, "#/components/schemas/ThingModel", items[1].Properties["body"].Ref)
+	assert.Contains(t, items[1].Required, "body")
+
+	resp := api.Post("/batch", "Authorization: secret", []any{
+		map[string]any{"method": "PUT", "path": "/things/new", "body": map[string]any{"id": "new", "price": 5}},
+		map[string]any{"method": "GET", "path": "/things/new"},
+		map[string]any{"method": "GET", "path": "/things/missing"},
+		map[string]any{"method": "PUT", "path": "/things/bad", "body": map[string]any{"id": "bad", "price": -1}},
+		map[string]any{"method": "DELETE", "path": "/things/test"},
+		map[string]any{"method": "GET", "path": "/things/test", "headers": map[string]string{"Authorization": "wrong"}},
+	})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var responses []struct {
+		Status  int               `json:"status"`
+		Headers map[string]string `json:"headers"`
+		Body    json.RawMessage   `json:"body"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &responses))
+	require.Len(t, responses, 6)
+
+	assert.Equal(t, http.StatusNoContent, responses[0].Status)
+	assert.Equal(t, http.StatusOK, responses[1].Status)
+	assert.JSONEq(t, `{"id": "new", "price": 5}`, string(responses[1].Body))
+	assert.Equal(t, "application/json", responses[1].Headers["Content-Type"])
+	assert.Equal(t, http.StatusNotFound, responses[2].Status)
+	assert.Equal(t, http.StatusUnprocessableEntity, responses[3].Status)
+	assert.Contains(t, string(responses[3].Body), "body.price")
+	assert.Equal(t, http.StatusNotFound, responses[4].Status)
+	assert.Contains(t, string(responses[4].Body), "No batchable operation found for DELETE /things/test")
+	assert.Equal(t, http.StatusUnauthorized, responses[5].Status)
+
+	assert.NotNil(t, db["test"])
+	assert.NotNil(t, db["new"])
+}
+
+func TestBatchErrors(t *testing.T) {
+	_, api := humatest.New(t)
+	AutoBatch(api)
+
+	resp := api.Post("/batch", strings.NewReader("{"))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = api.Post("/batch", []any{map[string]any{"method": "GET"}})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "body[0]")
+
+	old := MaxRequests
+	MaxRequests = 1
+	defer func() { MaxRequests = old }()
+	resp = api.Post("/batch", []any{
+		map[string]any{"method": "GET", "path": "/a"},
+		map[string]any{"method": "GET", "path": "/b"},
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "expected at most 1 requests")
+}
@@ -0,0 +1,171 @@
+// Package autooptions provides a way to automatically generate OPTIONS
+// operations for each path in the API, which respond with an `Allow` header
+// listing the methods available for the path and optionally a document
+// describing each of them.
+package autooptions
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Options configures the generated OPTIONS operations.
+type Options struct {
+	// Document enables a JSON response body describing the operations which
+	// are available for the path. Otherwise a `204 No Content` is returned.
+	Document bool
+
+	// Visible adds the generated operations to the OpenAPI. By default they
+	// are hidden, as they are rarely useful to document.
+	Visible bool
+}
+
+// Capabilities describes the operations available for a path.
+type Capabilities struct {
+	Path    string                `json:"path" doc:"Path template"`
+	Methods map[string]Capability `json:"methods" doc:"Operations by HTTP method"`
+}
+
+// Capability describes a single operation.
+type Capability struct {
+	OperationID string `json:"operationId,omitempty" doc:"Unique operation identifier"`
+	Summary     string `json:"summary,omitempty" doc:"Short summary of the operation"`
+	Deprecated  bool   `json:"deprecated,omitempty" doc:"Whether the operation is deprecated"`
+}
+
+var capabilitiesType = reflect.TypeOf(Capabilities{})
+
+// registered tracks the paths which already have a generated handler, since
+// hidden operations are not added to the OpenAPI and can't be detected there.
+var registered sync.Map
+
+// methods returns the path's operations in a stable order.
+func methods(path *huma.PathItem) []*huma.Operation {
+	ops := []*huma.Operation{}
+	for _, op := range []*huma.Operation{path.Get, path.Head, path.Post, path.Put, path.Patch, path.Delete, path.Options, path.Trace} {
+		if op != nil {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// AutoOptions generates OPTIONS operations for any path which has no
+// pre-existing OPTIONS operation. Call it *after* registering your
+// operations. This method may be safely called multiple times.
+//
+// If you wish to disable this for a specific path, set the `autooptions`
+// operation metadata field to `false` on any of the path's operations.
+func AutoOptions(api huma.API, options ...Options) {
+	var opts Options
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	oapi := api.OpenAPI()
+Outer:
+	for _, path := range oapi.Paths {
+		if path.Options != nil {
+			continue
+		}
+		ops := methods(path)
+		if len(ops) == 0 {
+			continue
+		}
+		for _, op := range ops {
+			if b, ok := op.Metadata["autooptions"].(bool); ok && !b {
+				// Special case: explicitly disabled.
+				continue Outer
+			}
+		}
+		OptionsResource(api, path, opts)
+	}
+}
+
+// OptionsResource is called for each path which needs an OPTIONS operation
+// to be added. It registers and provides a handler for this new operation.
+// You may call this manually if you prefer to not use `AutoOptions` for all
+// of your paths and want more fine-grained control.
+func OptionsResource(api huma.API, path *huma.PathItem, opts Options) {
+	oapi := api.OpenAPI()
+	template := methods(path)[0].Path
+
+	op := &huma.Operation{
+		OperationID: huma.GenerateOperationID(http.MethodOptions, template, &struct{}{}),
+		Method:      http.MethodOptions,
+		Path:        template,
+		Summary:     "Options",
+		Description: "Get the methods which are available for this path via the `Allow` response header.",
+		Hidden:      !opts.Visible,
+		Responses: map[string]*huma.Response{
+			"204": {
+				Description: "No Content",
+				Headers: map[string]*huma.Param{
+					"Allow": {
+						Description: "Available methods",
+						Schema:      &huma.Schema{Type: huma.TypeString},
+					},
+				},
+			},
+		},
+	}
+	if opts.Document {
+		op.Responses = map[string]*huma.Response{
+			"200": {
+				Description: "OK",
+				Headers:     op.Responses["204"].Headers,
+				Content: map[string]*huma.MediaType{
+					"application/json": {
+						Schema: oapi.Components.Schemas.Schema(capabilitiesType, true, "Capabilities"),
+					},
+				},
+			},
+		}
+	}
+	if _, loaded := registered.LoadOrStore(path, true); loaded {
+		return
+	}
+	if opts.Visible {
+		oapi.AddOperation(op)
+	}
+
+	api.Adapter().Handle(op, func(ctx huma.Context) {
+		// Read the operations at request time to include any added later.
+		allow := []string{}
+		caps := Capabilities{Path: template, Methods: map[string]Capability{}}
+		for _, o := range methods(path) {
+			allow = append(allow, o.Method)
+			if o.Hidden {
+				continue
+			}
+			caps.Methods[o.Method] = Capability{
+				OperationID: o.OperationID,
+				Summary:     o.Summary,
+				Deprecated:  o.Deprecated,
+			}
+		}
+		if path.Options == nil {
+			allow = append(allow, http.MethodOptions)
+		}
+		ctx.SetHeader("Allow", strings.Join(allow, ", "))
+
+		if !opts.Document {
+			ctx.SetStatus(http.StatusNoContent)
+			return
+		}
+
+		b, err := json.Marshal(caps)
+		if err != nil {
+			huma.WriteErr(api, ctx, http.StatusInternalServerError, "Unable to marshal capabilities", err)
+			return
+		}
+		ctx.SetHeader("Content-Type", "application/json")
+		ctx.SetStatus(http.StatusOK)
+		ctx.BodyWriter().Write(b)
+	})
+}
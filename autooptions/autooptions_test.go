@@ -0,0 +1,89 @@
+package autooptions
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+func register(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{thing-id}",
+		Summary:     "Get a thing",
+	}, func(ctx context.Context, input *struct {
+		ThingID string `path:"thing-id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-thing",
+		Method:      http.MethodDelete,
+		Path:        "/things/{thing-id}",
+		Deprecated:  true,
+	}, func(ctx context.Context, input *struct {
+		ThingID string `path:"thing-id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-things",
+		Method:      http.MethodGet,
+		Path:        "/things",
+		Metadata:    map[string]any{"autooptions": false},
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+}
+
+func TestAutoOptions(t *testing.T) {
+	_, api := humatest.New(t)
+	register(api)
+
+	AutoOptions(api)
+	AutoOptions(api)
+
+	// Hidden from the OpenAPI by default.
+	assert.Nil(t, api.OpenAPI().Paths["/things/{thing-id}"].Options)
+
+	resp := api.Do(http.MethodOptions, "/things/abc")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, "GET, DELETE, OPTIONS", resp.Header().Get("Allow"))
+	assert.Empty(t, resp.Body.String())
+}
+
+func TestAutoOptionsDocument(t *testing.T) {
+	_, api := humatest.New(t)
+	register(api)
+
+	AutoOptions(api, Options{Document: true, Visible: true})
+
+	op := api.OpenAPI().Paths["/things/{thing-id}"].Options
+	require.NotNil(t, op)
+	assert.Equal(t, "options-things-by-thing-id", op.OperationID)
+	assert.NotNil(t, op.Responses["200"].Content["application/json"])
+
+	// Explicitly disabled paths are skipped.
+	assert.Nil(t, api.OpenAPI().Paths["/things"].Options)
+
+	resp := api.Do(http.MethodOptions, "/things/abc")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "GET, DELETE, OPTIONS", resp.Header().Get("Allow"))
+	assert.JSONEq(t, `{
+		"path": "/things/{thing-id}",
+		"methods": {
+			"GET": {"operationId": "get-thing", "summary": "Get a thing"},
+			"DELETE": {"operationId": "delete-thing", "deprecated": true},
+			"OPTIONS": {"operationId": "options-things-by-thing-id", "summary": "Options"}
+		}
+	}`, resp.Body.String())
+}
@@ -0,0 +1,78 @@
+package huma
+
+import (
+	"net/url"
+	"strings"
+)
+
+// basePathFunc returns the function used to get the public base path for a
+// request, see `Config.BasePathFunc`, or nil if no base path is configured.
+func (c Config) basePathFunc() func(ctx Context) string {
+	if c.BasePathFunc != nil {
+		return c.BasePathFunc
+	}
+	if c.BasePath == "" {
+		return nil
+	}
+	template := c.BasePath
+	return func(ctx Context) string {
+		return expandBasePath(template, ctx)
+	}
+}
+
+// MountPath returns the path at which the router serves the given operation
+// path, which includes `Config.BasePath` if set. When a request context is
+// given, path params in the base path like `{tenant}` are filled in from it,
+// e.g. to build an internal request for another operation of the same API.
+//
+//	adapter.Handle(&huma.Operation{
+//		Method: http.MethodGet,
+//		Path:   huma.MountPath(api, nil, "/custom"),
+//	}, handler)
+func MountPath(api API, ctx Context, path string) string {
+	base := apiOf(api).config.BasePath
+	if ctx != nil {
+		base = expandBasePath(base, ctx)
+	}
+	return base + path
+}
+
+// expandBasePath fills in the path params of a base path template like
+// `/t/{tenant}` from the request.
+func expandBasePath(template string, ctx Context) string {
+	if !strings.Contains(template, "{") {
+		return template
+	}
+	var sb strings.Builder
+	for template != "" {
+		start := strings.Index(template, "{")
+		end := strings.Index(template, "}")
+		if start == -1 || end < start {
+			sb.WriteString(template)
+			break
+		}
+		sb.WriteString(template[:start])
+		sb.WriteString(url.PathEscape(ctx.Param(template[start+1 : end])))
+		template = template[end+1:]
+	}
+	return sb.String()
+}
+
+// withBasePath returns a shallow copy of the document with the base path
+// appended to each server URL, or a single relative server if none are set.
+func withBasePath(doc *OpenAPI, base string) *OpenAPI {
+	if base == "" {
+		return doc
+	}
+	tmp := *doc
+	tmp.Servers = make([]*Server, 0, len(doc.Servers))
+	for _, server := range doc.Servers {
+		s := *server
+		s.URL = strings.TrimSuffix(s.URL, "/") + base
+		tmp.Servers = append(tmp.Servers, &s)
+	}
+	if len(tmp.Servers) == 0 {
+		tmp.Servers = append(tmp.Servers, &Server{URL: base})
+	}
+	return &tmp
+}
@@ -0,0 +1,318 @@
+package huma
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// LoadOpenAPI loads an existing OpenAPI 3.1 document from JSON, e.g. one
+// authored by hand for spec-first development. Operations in the document
+// can then be implemented via `BindOperation`. YAML documents must first be
+// converted to JSON.
+//
+//	spec, err := huma.LoadOpenAPI(data)
+//	if err != nil {
+//		panic(err)
+//	}
+//	config := huma.DefaultConfig("My API", "1.0.0")
+//	config.OpenAPI = spec
+func LoadOpenAPI(data []byte) (*OpenAPI, error) {
+	var oapi OpenAPI
+	if err := json.Unmarshal(data, &oapi); err != nil {
+		return nil, err
+	}
+	if oapi.Components == nil {
+		oapi.Components = &Components{}
+	}
+	if oapi.Components.Schemas == nil {
+		oapi.Components.Schemas = NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+	}
+	for path, item := range oapi.Paths {
+		for method, op := range pathItemOperations(item) {
+			op.Method = method
+			op.Path = path
+		}
+	}
+	return &oapi, nil
+}
+
+// pathItemOperations returns the operations of a path item by method.
+func pathItemOperations(item *PathItem) map[string]*Operation {
+	ops := map[string]*Operation{}
+	for method, op := range map[string]*Operation{
+		http.MethodGet:     item.Get,
+		http.MethodPut:     item.Put,
+		http.MethodPost:    item.Post,
+		http.MethodDelete:  item.Delete,
+		http.MethodOptions: item.Options,
+		http.MethodHead:    item.Head,
+		http.MethodPatch:   item.Patch,
+		http.MethodTrace:   item.Trace,
+	} {
+		if op != nil {
+			ops[method] = op
+		}
+	}
+	return ops
+}
+
+// boundAPI registers operations against a scratch OpenAPI document so that
+// the schemas generated from the handler's input & output structs do not
+// modify the pre-authored document.
+type boundAPI struct {
+	API
+	oapi *OpenAPI
+}
+
+func (a *boundAPI) OpenAPI() *OpenAPI {
+	return a.oapi
+}
+
+func (a *boundAPI) Unwrap() API {
+	return a.API
+}
+
+// BindOperation implements an operation from a pre-authored OpenAPI document
+// loaded via `LoadOpenAPI`, finding it by its operation ID. The document is
+// left unchanged and is served as-is, while the handler's input & output
+// structs are used to parse, validate, and write requests & responses just
+// like `Register`. It panics if the operation does not exist or if the
+// structs are not compatible with the document, e.g. due to a missing param,
+// an undocumented body property, or a mismatched type.
+//
+//	huma.BindOperation(api, "get-user", func(ctx context.Context, input *GetUserInput) (*GetUserOutput, error) {
+//		// ...
+//	})
+//
+// Options can be used to set Huma-specific operation fields, like
+// `huma.Operation.MaxBodyBytes` or `huma.Operation.Middlewares`.
+func BindOperation[I, O any](api API, operationID string, handler func(context.Context, *I) (*O, error), operationHandlers ...func(o *Operation)) {
+	oapi := api.OpenAPI()
+
+	var spec *Operation
+	for _, item := range oapi.Paths {
+		for _, op := range pathItemOperations(item) {
+			if op.OperationID == operationID {
+				spec = op
+			}
+		}
+	}
+	if spec == nil {
+		panic(fmt.Errorf("operation %q not found in the OpenAPI document", operationID))
+	}
+
+	scratch := *oapi
+	scratch.Paths = nil
+	scratch.OnAddOperation = nil
+	scratch.Components = &Components{
+		Schemas: NewMapRegistry("#/components/schemas/", DefaultSchemaNamer),
+	}
+
+	op := Operation{
+		OperationID: spec.OperationID,
+		Method:      spec.Method,
+		Path:        spec.Path,
+	}
+	// Default to the documented success status, if there is only one.
+	var success []int
+	for status := range spec.Responses {
+		if code, err := strconv.Atoi(status); err == nil && code >= 200 && code < 300 {
+			success = append(success, code)
+		}
+	}
+	if len(success) == 1 {
+		op.DefaultStatus = success[0]
+	}
+	for _, oh := range operationHandlers {
+		oh(&op)
+	}
+	Register(&boundAPI{API: api, oapi: &scratch}, op, handler)
+
+	bound := pathItemOperations(scratch.Paths[op.Path])[op.Method]
+	c := compatChecker{spec: oapi, bound: &scratch}
+	c.check(spec, bound)
+	if len(c.errs) > 0 {
+		panic(fmt.Errorf("operation %q is incompatible with the OpenAPI document:\n%w", operationID, errors.Join(c.errs...)))
+	}
+}
+
+// compatChecker compares an operation generated from Go structs with the
+// pre-authored operation it implements.
+type compatChecker struct {
+	spec  *OpenAPI
+	bound *OpenAPI
+	errs  []error
+}
+
+func (c *compatChecker) errorf(format string, args ...any) {
+	c.errs = append(c.errs, fmt.Errorf(format, args...))
+}
+
+func (c *compatChecker) check(spec, bound *Operation) {
+	// Params may be defined on the path item or refer to the components.
+	specParams := map[string]*Param{}
+	var params []*Param
+	if item := c.spec.Paths[spec.Path]; item != nil {
+		params = append(params, item.Parameters...)
+	}
+	for _, p := range append(params, spec.Parameters...) {
+		if p.Ref != "" && c.spec.Components != nil {
+			p = c.spec.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+		}
+		if p != nil {
+			specParams[p.In+" "+p.Name] = p
+		}
+	}
+
+	boundParams := map[string]bool{}
+	for _, p := range bound.Parameters {
+		boundParams[p.In+" "+p.Name] = true
+		sp := specParams[p.In+" "+p.Name]
+		if sp == nil {
+			c.errorf("%s param %s is not in the document", p.In, p.Name)
+			continue
+		}
+		c.schema(p.In+" param "+p.Name, sp.Schema, p.Schema, nil)
+	}
+	for key, sp := range specParams {
+		if sp.Required && !boundParams[key] {
+			c.errorf("required %s param %s is missing from the input", sp.In, sp.Name)
+		}
+	}
+
+	specBody := spec.RequestBody
+	if specBody != nil && specBody.Ref != "" && c.spec.Components != nil {
+		specBody = c.spec.Components.RequestBodies[strings.TrimPrefix(specBody.Ref, "#/components/requestBodies/")]
+	}
+	switch {
+	case bound.RequestBody != nil && specBody == nil:
+		c.errorf("request body is not in the document")
+	case bound.RequestBody == nil && specBody != nil && specBody.Required:
+		c.errorf("required request body is missing from the input")
+	case bound.RequestBody != nil:
+		c.schema("request body", jsonSchema(specBody.Content), jsonSchema(bound.RequestBody.Content), nil)
+	}
+
+	statuses := make([]string, 0, len(bound.Responses))
+	for status := range bound.Responses {
+		statuses = append(statuses, status)
+	}
+	slices.Sort(statuses)
+	for _, status := range statuses {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		resp := spec.Responses[status]
+		if resp == nil {
+			resp = spec.Responses["2XX"]
+		}
+		if resp != nil && resp.Ref != "" && c.spec.Components != nil {
+			resp = c.spec.Components.Responses[strings.TrimPrefix(resp.Ref, "#/components/responses/")]
+		}
+		if resp == nil {
+			c.errorf("response %s is not in the document", status)
+			continue
+		}
+		c.schema("response "+status+" body", jsonSchema(resp.Content), jsonSchema(bound.Responses[status].Content), nil)
+	}
+}
+
+// schema compares a documented schema with the generated one at the given
+// location, recursing into properties and items.
+func (c *compatChecker) schema(loc string, spec, bound *Schema, visited map[[2]string]bool) {
+	if visited == nil {
+		visited = map[[2]string]bool{}
+	}
+	if spec != nil && bound != nil && spec.Ref != "" && bound.Ref != "" {
+		key := [2]string{spec.Ref, bound.Ref}
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+	}
+	spec = resolveRef(c.spec.Components.Schemas, spec)
+	bound = resolveRef(c.bound.Components.Schemas, bound)
+
+	if spec == nil || bound == nil {
+		if spec == nil && bound != nil {
+			c.errorf("%s is not in the document", loc)
+		}
+		return
+	}
+
+	if spec.Type != "" && bound.Type != "" && spec.Type != bound.Type {
+		c.errorf("%s has type %s but the document has %s", loc, bound.Type, spec.Type)
+		return
+	}
+
+	if spec.Items != nil || bound.Items != nil {
+		c.schema(loc+"[]", spec.Items, bound.Items, visited)
+	}
+
+	if spec.Properties == nil && bound.Properties == nil {
+		return
+	}
+	names := make([]string, 0, len(spec.Properties)+len(bound.Properties))
+	for name := range spec.Properties {
+		names = append(names, name)
+	}
+	for name := range bound.Properties {
+		if _, ok := spec.Properties[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		sp, bp := spec.Properties[name], bound.Properties[name]
+		switch {
+		case sp == nil:
+			if !bp.hidden {
+				c.errorf("%s.%s is not in the document", loc, name)
+			}
+		case bp == nil:
+			c.errorf("%s.%s is missing from the struct", loc, name)
+		default:
+			c.schema(loc+"."+name, sp, bp, visited)
+		}
+	}
+	for _, name := range spec.Required {
+		if bound.Properties[name] != nil && !slices.Contains(bound.Required, name) {
+			c.errorf("%s.%s is required in the document but optional in the struct", loc, name)
+		}
+	}
+}
+
+// resolveRef returns the registry schema a schema refers to, if any.
+func resolveRef(registry Registry, s *Schema) *Schema {
+	if s != nil && s.Ref != "" {
+		if resolved := registry.SchemaFromRef(s.Ref); resolved != nil {
+			return resolved
+		}
+	}
+	return s
+}
+
+// jsonSchema returns the schema of the JSON media type in the content, if
+// any, preferring `application/json`.
+func jsonSchema(content map[string]*MediaType) *Schema {
+	if mt := content["application/json"]; mt != nil {
+		return mt.Schema
+	}
+	keys := make([]string, 0, len(content))
+	for k := range content {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		if strings.HasSuffix(k, "json") && content[k] != nil {
+			return content[k].Schema
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package huma
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// BodyLimits restricts the shape of JSON request bodies to guard against
+// adversarial inputs, like deeply nested arrays or objects with huge numbers
+// of properties, which are cheap to send but expensive to unmarshal and
+// validate. Bodies are scanned before they are unmarshaled, and a body which
+// exceeds any limit is rejected with a `413 Request Entity Too Large` error.
+// A zero limit is not enforced.
+//
+// The limits keep counters of the largest bodies seen, which can be used to
+// tune them based on real traffic, see `Stats`. Use a pointer so the
+// counters are shared.
+//
+//	limits := &huma.BodyLimits{MaxDepth: 32, MaxElements: 10000}
+//	config.BodyLimits = limits
+//
+//	// Later, e.g. from a metrics endpoint:
+//	fmt.Println(limits.Stats())
+type BodyLimits struct {
+	// MaxDepth is the maximum nesting depth of arrays and objects, where a
+	// top-level object has a depth of one.
+	MaxDepth int
+
+	// MaxElements is the maximum total number of array items and object
+	// properties in the body.
+	MaxElements int
+
+	// MaxProperties is the maximum number of properties in any one object.
+	MaxProperties int
+
+	checked       atomic.Int64
+	rejected      atomic.Int64
+	maxDepth      atomic.Int64
+	maxElements   atomic.Int64
+	maxProperties atomic.Int64
+}
+
+// BodyLimitStats are the counters of a `BodyLimits`.
+type BodyLimitStats struct {
+	// Checked is the number of bodies which were checked.
+	Checked int64 `json:"checked"`
+
+	// Rejected is the number of bodies which exceeded a limit.
+	Rejected int64 `json:"rejected"`
+
+	// MaxDepth, MaxElements, and MaxProperties are the largest values seen
+	// in any checked body, including rejected ones. Scanning stops once a
+	// limit is exceeded, so these are capped just above the limits.
+	MaxDepth      int64 `json:"maxDepth"`
+	MaxElements   int64 `json:"maxElements"`
+	MaxProperties int64 `json:"maxProperties"`
+}
+
+// Stats returns a snapshot of the counters.
+func (l *BodyLimits) Stats() BodyLimitStats {
+	return BodyLimitStats{
+		Checked:       l.checked.Load(),
+		Rejected:      l.rejected.Load(),
+		MaxDepth:      l.maxDepth.Load(),
+		MaxElements:   l.maxElements.Load(),
+		MaxProperties: l.maxProperties.Load(),
+	}
+}
+
+// storeMax sets the counter to the value if it is larger.
+func storeMax(counter *atomic.Int64, value int) {
+	v := int64(value)
+	for {
+		current := counter.Load()
+		if v <= current || counter.CompareAndSwap(current, v) {
+			return
+		}
+	}
+}
+
+// bodyShape tracks the shape of a JSON document while it is scanned.
+type bodyShape struct {
+	depth, elements, properties int
+}
+
+// check scans a JSON body and returns an error if it exceeds the limits.
+// The body is not validated as JSON, which is left to the unmarshaler.
+func (l *BodyLimits) check(body []byte) *contextError {
+	var shape bodyShape
+
+	// Each open container tracks whether it is an object, whether it has an
+	// item yet, and its number of items.
+	type container struct {
+		object  bool
+		hasItem bool
+		items   int
+	}
+	var stack []container
+	inString := false
+	escaped := false
+	var err *contextError
+
+	// addItem counts a new array item or object property.
+	addItem := func() {
+		top := &stack[len(stack)-1]
+		top.items++
+		shape.elements++
+		if l.MaxElements > 0 && shape.elements > l.MaxElements {
+			err = bodyLimitError("request body has too many elements limit=%d", l.MaxElements)
+		}
+		if top.object {
+			shape.properties = max(shape.properties, top.items)
+			if l.MaxProperties > 0 && top.items > l.MaxProperties {
+				err = bodyLimitError("request body object has too many properties limit=%d", l.MaxProperties)
+			}
+		}
+	}
+
+	for i := 0; i < len(body) && err == nil; i++ {
+		c := body[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case ' ', '\t', '\r', '\n', ':':
+			continue
+		case ']', '}':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		case ',':
+			if len(stack) > 0 {
+				addItem()
+			}
+			continue
+		}
+
+		// This starts a value, or a key in an object.
+		if len(stack) > 0 && !stack[len(stack)-1].hasItem {
+			stack[len(stack)-1].hasItem = true
+			addItem()
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '[', '{':
+			stack = append(stack, container{object: c == '{'})
+			shape.depth = max(shape.depth, len(stack))
+			if l.MaxDepth > 0 && len(stack) > l.MaxDepth {
+				err = bodyLimitError("request body is nested too deeply limit=%d", l.MaxDepth)
+			}
+		}
+	}
+
+	l.checked.Add(1)
+	storeMax(&l.maxDepth, shape.depth)
+	storeMax(&l.maxElements, shape.elements)
+	storeMax(&l.maxProperties, shape.properties)
+	if err != nil {
+		l.rejected.Add(1)
+	}
+	return err
+}
+
+func bodyLimitError(format string, limit int) *contextError {
+	msg := fmt.Sprintf(format, limit)
+	return &contextError{Code: http.StatusRequestEntityTooLarge, Msg: msg, Errs: []error{
+		&ErrorDetail{Location: "body", Message: msg},
+	}}
+}
+
+// isJSONContentType returns whether the request content type is JSON, which
+// is assumed if it is not set.
+func isJSONContentType(ct string) bool {
+	if i := strings.IndexByte(ct, ';'); i >= 0 {
+		ct = ct[:i]
+	}
+	ct = strings.TrimSpace(ct)
+	return ct == "" || ct == "application/json" || strings.HasSuffix(ct, "+json")
+}
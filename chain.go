@@ -1,5 +1,11 @@
 package huma
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
 // Middlewares is a list of middleware functions that can be attached to an
 // API and will be called for all incoming requests.
 type Middlewares []func(ctx Context, next func(Context))
@@ -32,3 +38,61 @@ func (m Middlewares) chain(endpoint func(Context)) func(Context) {
 	}
 	return w
 }
+
+// conditionalMiddleware is a middleware chain which is only attached to
+// operations matching a predicate, see `UseMiddlewareIf`.
+type conditionalMiddleware struct {
+	match       func(op *Operation) bool
+	middlewares Middlewares
+}
+
+// UseMiddlewareIf attaches middlewares to each operation registered from now
+// on for which `match` returns true, e.g. to require auth for all operations
+// with a given tag. The predicate runs once at registration time rather than
+// on every request. Matching middlewares run after the API middlewares and
+// before the operation's own `Middlewares`, in the order they were added.
+//
+// It panics if a matching operation was already registered, as that operation
+// would otherwise silently be served without the middlewares.
+//
+//	huma.UseMiddlewareIf(api, huma.OperationHasTag("admin"), requireAdmin)
+//	huma.UseMiddlewareIf(api, func(op *huma.Operation) bool {
+//		return op.Method != http.MethodGet
+//	}, audit)
+func UseMiddlewareIf(api API, match func(op *Operation) bool, middlewares ...func(ctx Context, next func(Context))) {
+	a := apiOf(api)
+	for key, op := range a.routes {
+		if !strings.HasPrefix(key, "id ") && match(op) {
+			panic(fmt.Errorf("UseMiddlewareIf must be called before registering matching operation %s %s", op.Method, op.Path))
+		}
+	}
+	a.conditional = append(a.conditional, conditionalMiddleware{
+		match:       match,
+		middlewares: middlewares,
+	})
+}
+
+// OperationHasTag returns a predicate for `UseMiddlewareIf` matching
+// operations with the given tag.
+func OperationHasTag(tag string) func(op *Operation) bool {
+	return func(op *Operation) bool {
+		return slices.Contains(op.Tags, tag)
+	}
+}
+
+// OperationPathPrefix returns a predicate for `UseMiddlewareIf` matching
+// operations whose path starts with the given prefix, like `/admin/`.
+func OperationPathPrefix(prefix string) func(op *Operation) bool {
+	return func(op *Operation) bool {
+		return strings.HasPrefix(op.Path, prefix)
+	}
+}
+
+// OperationHasMetadata returns a predicate for `UseMiddlewareIf` matching
+// operations with the given key in their `Metadata`.
+func OperationHasMetadata(key string) func(op *Operation) bool {
+	return func(op *Operation) bool {
+		_, ok := op.Metadata[key]
+		return ok
+	}
+}
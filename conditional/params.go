@@ -150,3 +150,53 @@ func (p *Params) PreconditionFailed(etag string, modified time.Time) huma.Status
 
 	return nil
 }
+
+// IfMatch requires clients to send the `If-Match` header with the ETag of the
+// resource they last read, which provides optimistic locking for writes: if
+// another client has changed the resource in the meantime, the write fails
+// instead of overwriting their changes. Embed it in the input of write
+// operations. A request without the header fails validation.
+//
+//	type PutThingInput struct {
+//		conditional.IfMatch
+//		ID   string `path:"id"`
+//		Body Thing
+//	}
+//
+//	func putThing(ctx context.Context, input *PutThingInput) (*struct{}, error) {
+//		current := db.Get(input.ID)
+//		if err := input.PreconditionFailed(current.ETag()); err != nil {
+//			return nil, err
+//		}
+//		// ...
+//	}
+type IfMatch struct {
+	IfMatch []string `header:"If-Match" required:"true" doc:"ETag of the resource the change is based on. The request fails if the resource has changed since."`
+}
+
+// PreconditionFailed returns a `412 Precondition Failed` error if none of the
+// ETags sent by the client match the resource's current ETag, otherwise nil.
+// Pass an empty ETag if the resource does not exist. The special value `*`
+// matches any existing resource.
+func (p *IfMatch) PreconditionFailed(etag string) huma.StatusError {
+	for _, match := range p.IfMatch {
+		trimmed := trimETag(match)
+		if (trimmed == "*" && etag != "") || (trimmed == etag && etag != "") {
+			return nil
+		}
+	}
+
+	foundMsg := "found no existing resource"
+	if etag != "" {
+		foundMsg = "found resource with ETag " + etag
+	}
+	return huma.NewError(
+		http.StatusPreconditionFailed,
+		http.StatusText(http.StatusPreconditionFailed),
+		&huma.ErrorDetail{
+			Message:  "If-Match precondition failed, " + foundMsg,
+			Location: "headers.If-Match",
+			Value:    p.IfMatch,
+		},
+	)
+}
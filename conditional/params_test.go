@@ -1,11 +1,13 @@
 package conditional
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/humatest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -176,3 +178,45 @@ func TestIfUnmodifiedSince(t *testing.T) {
 	require.Error(t, perr)
 	assert.Equal(t, http.StatusPreconditionFailed, perr.GetStatus())
 }
+
+func TestRequiredIfMatch(t *testing.T) {
+	p := IfMatch{IfMatch: []string{`"abc123"`, `W/"def456"`}}
+	assert.Nil(t, p.PreconditionFailed("abc123"))
+	assert.Nil(t, p.PreconditionFailed("def456"))
+
+	err := p.PreconditionFailed("bad")
+	require.Error(t, err)
+	assert.Equal(t, http.StatusPreconditionFailed, err.GetStatus())
+	assert.Equal(t, "If-Match precondition failed, found resource with ETag bad", err.(*huma.ErrorModel).Errors[0].Message)
+
+	p = IfMatch{IfMatch: []string{"*"}}
+	assert.Nil(t, p.PreconditionFailed("abc123"))
+	err = p.PreconditionFailed("")
+	require.Error(t, err)
+	assert.Equal(t, "If-Match precondition failed, found no existing resource", err.(*huma.ErrorModel).Errors[0].Message)
+
+	_, api := humatest.New(t)
+	huma.Put(api, "/things/{id}", func(ctx context.Context, input *struct {
+		IfMatch
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		if err := input.PreconditionFailed("abc123"); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+
+	param := api.OpenAPI().Paths["/things/{id}"].Put.Parameters[0]
+	assert.Equal(t, "If-Match", param.Name)
+	assert.True(t, param.Required)
+
+	resp := api.Put("/things/1", `If-Match: "abc123"`)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	resp = api.Put("/things/1", `If-Match: "old"`)
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+	assert.Contains(t, resp.Body.String(), "headers.If-Match")
+
+	resp = api.Put("/things/1")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
@@ -0,0 +1,174 @@
+package huma
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned when decoding a continuation token which is
+// malformed, has an invalid signature, has expired, or was created for a
+// different version of the cursor.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+var (
+	// ErrMalformedToken is returned by `VerifyToken` when a token is not in
+	// the format created by `SignToken`.
+	ErrMalformedToken = errors.New("malformed token")
+
+	// ErrBadSignature is returned by `VerifyToken` when a token was not signed
+	// by any of the keys, or has been modified.
+	ErrBadSignature = errors.New("bad signature")
+)
+
+// SignToken signs the payload with HMAC-SHA256 and returns an opaque token of
+// the form `payload.signature`, with both parts base64url encoded. It is used
+// for cursors and other tokens which clients must not be able to modify.
+func SignToken(key, payload []byte) string {
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + tokenSignature(key, encoded)
+}
+
+// VerifyToken returns the payload of a token created by `SignToken` if it was
+// signed by any of the keys, enabling key rotation.
+func VerifyToken(token string, keys ...[]byte) ([]byte, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrMalformedToken
+	}
+	for _, key := range keys {
+		if len(key) > 0 && hmac.Equal([]byte(sig), []byte(tokenSignature(key, encoded))) {
+			payload, err := base64.RawURLEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, ErrMalformedToken
+			}
+			return payload, nil
+		}
+	}
+	return nil, ErrBadSignature
+}
+
+func tokenSignature(key []byte, encoded string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Cursor is an opaque continuation token used to fetch the next page of a
+// list operation. Use it as the type of a query param or response field so
+// that it is documented as an opaque string, and a `CursorCodec` to build
+// and read it from a typed struct:
+//
+//	type ListInput struct {
+//		Cursor huma.Cursor `query:"cursor"`
+//	}
+type Cursor string
+
+// Schema implements `huma.SchemaProvider`.
+func (c Cursor) Schema(r Registry) *Schema {
+	return &Schema{
+		Type:        TypeString,
+		Description: "Opaque continuation token from a previous response used to fetch the next page. Clients must not parse or modify it.",
+		Extensions: map[string]any{
+			"x-opaque": true,
+		},
+	}
+}
+
+// cursorPayload is the signed content of a cursor.
+type cursorPayload struct {
+	Version int             `json:"v,omitempty"`
+	Expires int64           `json:"e,omitempty"`
+	Data    json.RawMessage `json:"d"`
+}
+
+// CursorCodec encodes typed structs like the last seen sort key into opaque
+// `Cursor` tokens and decodes them again. Tokens are signed with HMAC-SHA256
+// so clients cannot modify them, and can optionally expire.
+//
+//	codec := huma.NewCursorCodec(secret)
+//	codec.TTL = 24 * time.Hour
+//
+//	type ThingsCursor struct {
+//		LastID string `json:"id"`
+//	}
+//
+//	// Build the token for the next page.
+//	next, err := codec.Encode(ThingsCursor{LastID: things[len(things)-1].ID})
+//
+//	// Read the token sent by the client.
+//	var cursor ThingsCursor
+//	if input.Cursor != "" {
+//		if err := codec.Decode(input.Cursor, &cursor); err != nil {
+//			return nil, huma.Error400BadRequest("invalid cursor", err)
+//		}
+//	}
+type CursorCodec struct {
+	// Keys are used to sign and verify tokens. The first key signs new
+	// tokens while all keys are used to verify, enabling key rotation.
+	Keys [][]byte
+
+	// TTL is how long new tokens are valid. If zero, tokens do not expire.
+	TTL time.Duration
+
+	// Version is stored in each token, and tokens with a different version
+	// are rejected. Increment it when the cursor struct changes in an
+	// incompatible way.
+	Version int
+}
+
+// NewCursorCodec creates a new cursor codec which signs tokens with the
+// given key. Additional keys are only used to verify tokens.
+func NewCursorCodec(key []byte, verifyKeys ...[]byte) *CursorCodec {
+	return &CursorCodec{
+		Keys: append([][]byte{key}, verifyKeys...),
+	}
+}
+
+// Encode marshals the value as JSON and returns a signed token for it.
+func (c *CursorCodec) Encode(v any) (Cursor, error) {
+	if len(c.Keys) == 0 || len(c.Keys[0]) == 0 {
+		return "", errors.New("cursor codec has no signing key")
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	p := cursorPayload{Version: c.Version, Data: data}
+	if c.TTL > 0 {
+		p.Expires = time.Now().Add(c.TTL).Unix()
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return Cursor(SignToken(c.Keys[0], b)), nil
+}
+
+// Decode verifies the token and unmarshals its value into `v`. The returned
+// error wraps `ErrInvalidCursor` if the token cannot be used.
+func (c *CursorCodec) Decode(cursor Cursor, v any) error {
+	b, err := VerifyToken(string(cursor), c.Keys...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	var p cursorPayload
+	if err := json.Unmarshal(b, &p); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	if p.Version != c.Version {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidCursor, p.Version)
+	}
+	if p.Expires != 0 && time.Now().Unix() >= p.Expires {
+		return fmt.Errorf("%w: expired", ErrInvalidCursor)
+	}
+	if err := json.Unmarshal(p.Data, v); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	return nil
+}
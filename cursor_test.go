@@ -0,0 +1,130 @@
+package huma_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+type testCursor struct {
+	LastID string `json:"id"`
+	Offset int    `json:"o"`
+}
+
+func TestCursorCodec(t *testing.T) {
+	codec := huma.NewCursorCodec([]byte("secret"))
+	codec.TTL = time.Hour
+
+	token, err := codec.Encode(testCursor{LastID: "abc", Offset: 5})
+	require.NoError(t, err)
+
+	var decoded testCursor
+	require.NoError(t, codec.Decode(token, &decoded))
+	assert.Equal(t, testCursor{LastID: "abc", Offset: 5}, decoded)
+
+	// Tampering with the token invalidates the signature.
+	err = codec.Decode(token[1:], &decoded)
+	require.ErrorIs(t, err, huma.ErrInvalidCursor)
+	assert.Contains(t, err.Error(), "bad signature")
+
+	err = codec.Decode("garbage", &decoded)
+	require.ErrorIs(t, err, huma.ErrInvalidCursor)
+
+	// Rotated keys can still verify old tokens.
+	rotated := huma.NewCursorCodec([]byte("new-secret"), []byte("secret"))
+	require.NoError(t, rotated.Decode(token, &decoded))
+	err = huma.NewCursorCodec([]byte("other")).Decode(token, &decoded)
+	require.ErrorIs(t, err, huma.ErrInvalidCursor)
+
+	// Tokens from another version are rejected.
+	v2 := huma.NewCursorCodec([]byte("secret"))
+	v2.Version = 2
+	err = v2.Decode(token, &decoded)
+	require.ErrorIs(t, err, huma.ErrInvalidCursor)
+	assert.Contains(t, err.Error(), "unsupported version 0")
+
+	// Expired tokens are rejected.
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"e":1,"d":{"id":"abc"}}`))
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(payload))
+	expired := huma.Cursor(payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)))
+	err = codec.Decode(expired, &decoded)
+	require.ErrorIs(t, err, huma.ErrInvalidCursor)
+	assert.Contains(t, err.Error(), "expired")
+
+	_, err = (&huma.CursorCodec{}).Encode(testCursor{})
+	require.Error(t, err)
+}
+
+func TestSignToken(t *testing.T) {
+	token := huma.SignToken([]byte("secret"), []byte("payload"))
+
+	payload, err := huma.VerifyToken(token, []byte("other"), []byte("secret"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("payload"), payload)
+
+	_, err = huma.VerifyToken(token, []byte("other"))
+	require.ErrorIs(t, err, huma.ErrBadSignature)
+
+	_, err = huma.VerifyToken("garbage", []byte("secret"))
+	require.ErrorIs(t, err, huma.ErrMalformedToken)
+}
+
+func TestCursorParam(t *testing.T) {
+	codec := huma.NewCursorCodec([]byte("secret"))
+	_, api := humatest.New(t)
+
+	huma.Get(api, "/things", func(ctx context.Context, input *struct {
+		Cursor huma.Cursor `query:"cursor"`
+	}) (*struct {
+		Body struct {
+			Next huma.Cursor `json:"next"`
+		}
+	}, error) {
+		var cursor testCursor
+		if input.Cursor != "" {
+			if err := codec.Decode(input.Cursor, &cursor); err != nil {
+				return nil, huma.Error400BadRequest("invalid cursor", err)
+			}
+		}
+		resp := &struct {
+			Body struct {
+				Next huma.Cursor `json:"next"`
+			}
+		}{}
+		next, err := codec.Encode(testCursor{Offset: cursor.Offset + 10})
+		resp.Body.Next = next
+		return resp, err
+	})
+
+	param := api.OpenAPI().Paths["/things"].Get.Parameters[0]
+	assert.Equal(t, "string", param.Schema.Type)
+	assert.Equal(t, true, param.Schema.Extensions["x-opaque"])
+
+	next, err := codec.Encode(testCursor{Offset: 10})
+	require.NoError(t, err)
+	resp := api.Get("/things?cursor=" + string(next))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var decoded testCursor
+	var body struct {
+		Next huma.Cursor `json:"next"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.NoError(t, codec.Decode(body.Next, &decoded))
+	assert.Equal(t, 20, decoded.Offset)
+
+	resp = api.Get("/things?cursor=bad")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
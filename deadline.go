@@ -0,0 +1,72 @@
+package huma
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// grpcTimeoutUnits maps the `grpc-timeout` header units to durations.
+var grpcTimeoutUnits = map[byte]time.Duration{
+	'H': time.Hour,
+	'M': time.Minute,
+	'S': time.Second,
+	'm': time.Millisecond,
+	'u': time.Microsecond,
+	'n': time.Nanosecond,
+}
+
+// parseRequestTimeout parses an `X-Request-Timeout` header value, which is
+// either a Go duration like `1.5s` or `250ms`, or a number of seconds.
+func parseRequestTimeout(value string) (time.Duration, bool) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return d, d > 0
+	}
+	if secs, err := strconv.ParseFloat(value, 64); err == nil && secs > 0 {
+		return time.Duration(secs * float64(time.Second)), true
+	}
+	return 0, false
+}
+
+// parseGRPCTimeout parses a `grpc-timeout` header value, which is up to eight
+// digits followed by a unit, e.g. `100m` for 100 milliseconds.
+func parseGRPCTimeout(value string) (time.Duration, bool) {
+	n := len(value)
+	if n < 2 || n > 9 {
+		return 0, false
+	}
+	unit, ok := grpcTimeoutUnits[value[n-1]]
+	if !ok {
+		return 0, false
+	}
+	digits, err := strconv.ParseUint(value[:n-1], 10, 64)
+	if err != nil || digits == 0 {
+		return 0, false
+	}
+	return time.Duration(digits) * unit, true
+}
+
+// requestTimeout returns the timeout for the request, which is the timeout
+// sent by the client via `X-Request-Timeout` or `grpc-timeout`, bounded by the
+// operation's maximum. Without either header the maximum is used.
+func requestTimeout(ctx Context, max time.Duration) (time.Duration, *ErrorDetail) {
+	name, parse := "X-Request-Timeout", parseRequestTimeout
+	value := ctx.Header(name)
+	if value == "" {
+		name, parse = "grpc-timeout", parseGRPCTimeout
+		value = ctx.Header(name)
+	}
+	if value == "" {
+		return max, nil
+	}
+
+	d, ok := parse(strings.TrimSpace(value))
+	if !ok {
+		return 0, &ErrorDetail{
+			Message:  "invalid request timeout",
+			Location: "header." + name,
+			Value:    value,
+		}
+	}
+	return min(d, max), nil
+}
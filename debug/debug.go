@@ -0,0 +1,123 @@
+// Package debug provides a request echo operation which returns the parsed
+// request back to the caller, which is useful when debugging client
+// integrations.
+package debug
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// MaxBodyBytes is the maximum number of request body bytes which are read
+// and echoed back.
+var MaxBodyBytes int64 = 1024 * 1024
+
+// sensitiveHeaders are masked in the echoed request.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Cookie":              true,
+	"Proxy-Authorization": true,
+}
+
+// Config configures the echo operation.
+type Config struct {
+	// Enabled must be set for the operation to be registered, e.g. from a
+	// CLI option or environment variable, so that it is not exposed by
+	// accident.
+	Enabled bool
+
+	// Visible adds the operation to the OpenAPI. By default it is hidden.
+	Visible bool
+}
+
+// Echo describes the request as it was parsed by the API.
+type Echo struct {
+	Method      string              `json:"method" doc:"HTTP method"`
+	Host        string              `json:"host" doc:"Request host"`
+	Path        string              `json:"path" doc:"Request path"`
+	RemoteAddr  string              `json:"remoteAddr" doc:"Address of the client or last proxy"`
+	Query       map[string][]string `json:"query" doc:"Query params"`
+	Headers     map[string][]string `json:"headers" doc:"Request headers, with credentials masked"`
+	ContentType string              `json:"contentType,omitempty" doc:"Request body content type"`
+	Negotiated  string              `json:"negotiatedContentType" doc:"Response content type negotiated from the Accept header"`
+	Body        any                 `json:"body,omitempty" doc:"Parsed request body"`
+	BodyError   string              `json:"bodyError,omitempty" doc:"Why the request body could not be parsed"`
+}
+
+type echoInput struct {
+	echo Echo
+	raw  []byte
+}
+
+// Resolve captures the request before the handler is called.
+func (i *echoInput) Resolve(ctx huma.Context) []error {
+	u := ctx.URL()
+	i.echo = Echo{
+		Method:      ctx.Method(),
+		Host:        ctx.Host(),
+		Path:        u.Path,
+		RemoteAddr:  ctx.RemoteAddr(),
+		Query:       u.Query(),
+		Headers:     map[string][]string{},
+		ContentType: ctx.Header("Content-Type"),
+	}
+	ctx.EachHeader(func(name, value string) {
+		name = http.CanonicalHeaderKey(name)
+		if sensitiveHeaders[name] {
+			value = "***"
+		}
+		i.echo.Headers[name] = append(i.echo.Headers[name], value)
+	})
+
+	if r := ctx.BodyReader(); r != nil {
+		raw, err := io.ReadAll(io.LimitReader(r, MaxBodyBytes))
+		if err != nil {
+			return []error{&huma.ErrorDetail{Message: "cannot read request body", Location: "body"}}
+		}
+		i.raw = raw
+	}
+	return nil
+}
+
+// Register adds a hidden operation at the given path which echoes requests
+// with any common method back to the caller, including the parsed params,
+// headers, negotiated content type, and body. Nothing is registered unless
+// `Config.Enabled` is set.
+//
+//	debug.Register(api, "/_debug/echo", debug.Config{Enabled: opts.Debug})
+func Register(api huma.API, path string, config Config) {
+	if !config.Enabled {
+		return
+	}
+
+	handler := func(ctx context.Context, input *echoInput) (*struct{ Body *Echo }, error) {
+		echo := input.echo
+		echo.Negotiated, _ = api.Negotiate(strings.Join(echo.Headers["Accept"], ","))
+
+		if len(input.raw) > 0 {
+			if strings.HasPrefix(echo.ContentType, "text/") {
+				echo.Body = string(input.raw)
+			} else if err := api.Unmarshal(echo.ContentType, input.raw, &echo.Body); err != nil {
+				echo.Body = string(input.raw)
+				echo.BodyError = err.Error()
+			}
+		}
+		return &struct{ Body *Echo }{Body: &echo}, nil
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		huma.Register(api, huma.Operation{
+			OperationID: "debug-echo-" + strings.ToLower(method),
+			Method:      method,
+			Path:        path,
+			Summary:     "Echo the request",
+			Description: "Returns the request as parsed by the API, for debugging client integrations.",
+			Tags:        []string{"Debug"},
+			Hidden:      !config.Visible,
+		}, handler)
+	}
+}
@@ -0,0 +1,59 @@
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEcho(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	Register(api, "/_debug/echo", Config{Enabled: true})
+
+	// The operation is hidden from the OpenAPI.
+	assert.Nil(t, api.OpenAPI().Paths["/_debug/echo"])
+
+	resp := api.Post("/_debug/echo?tag=a&tag=b",
+		"Authorization: Bearer secret",
+		"Accept: text/html, application/json;q=0.9",
+		map[string]any{"name": "foo"},
+	)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var echo Echo
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &echo))
+	assert.Equal(t, http.MethodPost, echo.Method)
+	assert.Equal(t, "/_debug/echo", echo.Path)
+	assert.Equal(t, []string{"a", "b"}, echo.Query["tag"])
+	assert.Equal(t, []string{"***"}, echo.Headers["Authorization"])
+	assert.Equal(t, "application/json", echo.ContentType)
+	assert.Equal(t, "application/json", echo.Negotiated)
+	assert.Equal(t, map[string]any{"name": "foo"}, echo.Body)
+
+	resp = api.Put("/_debug/echo", "Content-Type: application/json", strings.NewReader(`{"bad`))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &echo))
+	assert.Equal(t, `{"bad`, echo.Body)
+	assert.NotEmpty(t, echo.BodyError)
+
+	resp = api.Get("/_debug/echo")
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestEchoDisabled(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	Register(api, "/_debug/echo", Config{})
+
+	resp := api.Get("/_debug/echo")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	_, api = humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	Register(api, "/_debug/echo", Config{Enabled: true, Visible: true})
+	assert.NotNil(t, api.OpenAPI().Paths["/_debug/echo"].Post)
+}
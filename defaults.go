@@ -1,7 +1,10 @@
 package huma
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
+	"fmt"
 	"io"
 )
 
@@ -18,6 +21,68 @@ var DefaultJSONFormat = Format{
 		return json.NewEncoder(w).Encode(v)
 	},
 	Unmarshal: json.Unmarshal,
+	UnmarshalNumber: func(data []byte, v any) error {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(v); err != nil {
+			return err
+		}
+		if rest := bytes.TrimSpace(data[dec.InputOffset():]); len(rest) > 0 {
+			return fmt.Errorf("invalid character %q after top-level value", rest[0])
+		}
+		return nil
+	},
+}
+
+// NewJSONFormat returns a JSON format which uses the given functions, making it
+// easy to plug in a faster encoder like `sonic` or `go-json` without changing
+// content negotiation. Values of type `json.RawMessage` are written as-is
+// and raw message targets are filled without calling `unmarshal`. Set the
+// returned format's `UnmarshalNumber` to support `Config.UseNumber`.
+//
+//	config := huma.DefaultConfig("My API", "1.0.0")
+//	config.Formats = huma.WithJSONFormat(config.Formats,
+//		huma.NewJSONFormat(sonic.Marshal, sonic.Unmarshal))
+func NewJSONFormat(marshal func(v any) ([]byte, error), unmarshal func(data []byte, v any) error) Format {
+	return Format{
+		Marshal: func(w io.Writer, v any) error {
+			var b []byte
+			if raw, ok := v.(json.RawMessage); ok {
+				b = raw
+			} else {
+				var err error
+				if b, err = marshal(v); err != nil {
+					return err
+				}
+			}
+			if _, err := w.Write(b); err != nil {
+				return err
+			}
+			// Match `json.Encoder`, which ends each value with a newline.
+			_, err := w.Write([]byte{'\n'})
+			return err
+		},
+		Unmarshal: func(data []byte, v any) error {
+			if raw, ok := v.(*json.RawMessage); ok {
+				*raw = append((*raw)[:0], data...)
+				return nil
+			}
+			return unmarshal(data, v)
+		},
+	}
+}
+
+// WithJSONFormat returns a copy of the formats with the `application/json` and
+// `json` entries set to the given format. The passed map is not modified, so
+// it is safe to use with the shared `DefaultFormats`.
+func WithJSONFormat(formats map[string]Format, format Format) map[string]Format {
+	result := make(map[string]Format, len(formats)+2)
+	for k, v := range formats {
+		result[k] = v
+	}
+	result["application/json"] = format
+	result["json"] = format
+	return result
 }
 
 // DefaultFormats is a map of default formats that can be set in the API's
@@ -67,11 +132,19 @@ func DefaultConfig(title, version string) Config {
 				Schemas: registry,
 			},
 		},
-		OpenAPIPath:   "/openapi",
-		DocsPath:      "/docs",
-		SchemasPath:   schemasPath,
-		Formats:       DefaultFormats,
-		DefaultFormat: "application/json",
+		OpenAPIPath: "/openapi",
+		OpenAPIEncoders: map[string]func(io.Writer) io.WriteCloser{
+			"gzip": func(w io.Writer) io.WriteCloser {
+				gz, _ := gzip.NewWriterLevel(w, gzip.BestCompression)
+				return gz
+			},
+		},
+		DocsPath:           "/docs",
+		SchemasPath:        schemasPath,
+		Formats:            DefaultFormats,
+		DefaultFormat:      "application/json",
+		NamedTransformers:  map[string]Transformer{},
+		DecompressRequests: true,
 		CreateHooks: []func(Config) Config{
 			func(c Config) Config {
 				// Add a link transformer to the API. This adds `Link` headers and
@@ -79,8 +152,14 @@ func DefaultConfig(title, version string) Config {
 				// Schema that describes the response structure.
 				// This is a create hook so we get the latest schema path setting.
 				linkTransformer := NewSchemaLinkTransformer(schemaPrefix, c.SchemasPath)
+				linkTransformer.BaseURL = c.SchemaLinkBaseURL
+				linkTransformer.BasePath = c.basePathFunc()
 				c.OpenAPI.OnAddOperation = append(c.OpenAPI.OnAddOperation, linkTransformer.OnAddOperation)
 				c.Transformers = append(c.Transformers, linkTransformer.Transform)
+				if c.NamedTransformers == nil {
+					c.NamedTransformers = map[string]Transformer{}
+				}
+				c.NamedTransformers["schemaLink"] = linkTransformer.Transform
 				return c
 			},
 		},
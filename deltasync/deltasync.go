@@ -0,0 +1,154 @@
+// Package deltasync provides helpers for collection endpoints which support
+// incremental (delta) sync. Clients make an initial request to list the
+// collection and receive a `nextSyncToken`, which they pass back via the
+// `syncToken` query param to receive only the items changed since then. The
+// tokens are signed so clients cannot forge them and expire after a
+// configurable duration, after which clients must do a full sync.
+//
+//	signer := deltasync.NewSigner(key, 24*time.Hour)
+//
+//	huma.Get(api, "/things", func(ctx context.Context, input *struct {
+//		deltasync.Params
+//	}) (*struct{ Body deltasync.Response[Thing] }, error) {
+//		since, err := signer.Since(input.Params)
+//		if err != nil {
+//			return nil, err
+//		}
+//		items, deleted, watermark := db.ChangedSince(since)
+//		return &struct{ Body deltasync.Response[Thing] }{
+//			Body: deltasync.NewResponse(signer, items, deleted, watermark),
+//		}, nil
+//	})
+package deltasync
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+var (
+	// ErrInvalidToken is returned when a sync token is malformed or its
+	// signature does not match.
+	ErrInvalidToken = errors.New("invalid sync token")
+
+	// ErrExpiredToken is returned when a sync token is older than the
+	// signer's TTL.
+	ErrExpiredToken = errors.New("sync token has expired")
+)
+
+// Params are the query params for a delta sync request. Embed them in your
+// operation's input struct. Clients send at most one of `since` or
+// `syncToken`, and when neither is sent the full collection should be
+// returned.
+type Params struct {
+	Since     time.Time `query:"since" doc:"Only return items changed after this time."`
+	SyncToken string    `query:"syncToken" doc:"Only return items changed since the request which returned this token as nextSyncToken."`
+}
+
+func (p *Params) Resolve(ctx huma.Context) []error {
+	if !p.Since.IsZero() && p.SyncToken != "" {
+		return []error{&huma.ErrorDetail{
+			Message:  "since and syncToken cannot be used together",
+			Location: "query.syncToken",
+			Value:    p.SyncToken,
+		}}
+	}
+	return nil
+}
+
+// Response is the response body envelope for a delta sync request.
+type Response[T any] struct {
+	// Items which were created or updated since the last sync, or all items
+	// for a full sync.
+	Items []T `json:"items" nullable:"false" doc:"Items created or updated since the last sync."`
+
+	// Deleted contains the IDs of items which were deleted since the last
+	// sync.
+	Deleted []string `json:"deleted,omitempty" doc:"IDs of items deleted since the last sync."`
+
+	// NextSyncToken should be passed as `syncToken` on the next request.
+	NextSyncToken string `json:"nextSyncToken" doc:"Pass as syncToken on the next request to get only newer changes."`
+}
+
+// Signer creates and verifies sync tokens, signed via `huma.SignToken`.
+type Signer struct {
+	key []byte
+
+	// TTL is how long tokens are valid after being issued. Zero means tokens
+	// never expire.
+	TTL time.Duration
+
+	// now returns the current time, and is overridden in tests.
+	now func() time.Time
+}
+
+// NewSigner creates a new token signer with the given secret key and TTL.
+func NewSigner(key []byte, ttl time.Duration) *Signer {
+	return &Signer{key: key, TTL: ttl, now: time.Now}
+}
+
+// Token returns a signed sync token for the given watermark, which is
+// typically the last modified time of the newest item returned.
+func (s *Signer) Token(watermark time.Time) string {
+	payload := make([]byte, 16)
+	binary.BigEndian.PutUint64(payload, uint64(watermark.UnixNano()))
+	binary.BigEndian.PutUint64(payload[8:], uint64(s.now().Unix()))
+	return huma.SignToken(s.key, payload)
+}
+
+// Parse verifies the token and returns its watermark. It returns
+// `ErrInvalidToken` or `ErrExpiredToken` if the token cannot be used.
+func (s *Signer) Parse(token string) (time.Time, error) {
+	payload, err := huma.VerifyToken(token, s.key)
+	if err != nil || len(payload) != 16 {
+		return time.Time{}, ErrInvalidToken
+	}
+
+	if s.TTL > 0 {
+		issued := time.Unix(int64(binary.BigEndian.Uint64(payload[8:])), 0)
+		if s.now().Sub(issued) > s.TTL {
+			return time.Time{}, ErrExpiredToken
+		}
+	}
+
+	return time.Unix(0, int64(binary.BigEndian.Uint64(payload))), nil
+}
+
+// Since returns the time after which changed items should be returned, or the
+// zero time if a full sync is needed. An invalid token results in a
+// `400 Bad Request` and an expired token in a `410 Gone`, which tells the
+// client to discard its state and do a full sync.
+func (s *Signer) Since(p Params) (time.Time, error) {
+	if p.SyncToken == "" {
+		return p.Since, nil
+	}
+	watermark, err := s.Parse(p.SyncToken)
+	if err != nil {
+		detail := &huma.ErrorDetail{
+			Message:  err.Error(),
+			Location: "query.syncToken",
+			Value:    p.SyncToken,
+		}
+		if errors.Is(err, ErrExpiredToken) {
+			return time.Time{}, huma.Error410Gone("sync token has expired, a full sync is required", detail)
+		}
+		return time.Time{}, huma.Error400BadRequest("invalid sync token", detail)
+	}
+	return watermark, nil
+}
+
+// NewResponse creates a response body with the changed items, deleted item
+// IDs, and a token signed for the watermark.
+func NewResponse[T any](s *Signer, items []T, deleted []string, watermark time.Time) Response[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return Response[T]{
+		Items:         items,
+		Deleted:       deleted,
+		NextSyncToken: s.Token(watermark),
+	}
+}
@@ -0,0 +1,130 @@
+package deltasync
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToken(t *testing.T) {
+	now := time.Now()
+	s := NewSigner([]byte("secret"), time.Hour)
+	s.now = func() time.Time { return now }
+
+	watermark := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	token := s.Token(watermark)
+
+	parsed, err := s.Parse(token)
+	require.NoError(t, err)
+	assert.True(t, watermark.Equal(parsed))
+
+	// Wrong key.
+	_, err = NewSigner([]byte("other"), time.Hour).Parse(token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// Malformed.
+	for _, bad := range []string{"", "abc", "abc.def", token[:len(token)-2]} {
+		_, err = s.Parse(bad)
+		require.ErrorIs(t, err, ErrInvalidToken, bad)
+	}
+
+	// Expired.
+	s.now = func() time.Time { return now.Add(2 * time.Hour) }
+	_, err = s.Parse(token)
+	require.ErrorIs(t, err, ErrExpiredToken)
+
+	// No expiration.
+	s.TTL = 0
+	_, err = s.Parse(token)
+	require.NoError(t, err)
+}
+
+type Thing struct {
+	ID       string    `json:"id"`
+	Modified time.Time `json:"modified"`
+}
+
+func TestSync(t *testing.T) {
+	_, api := humatest.New(t)
+
+	signer := NewSigner([]byte("secret"), time.Hour)
+	things := []Thing{
+		{ID: "a", Modified: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "b", Modified: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	huma.Get(api, "/things", func(ctx context.Context, input *struct {
+		Params
+	}) (*struct{ Body Response[Thing] }, error) {
+		since, err := signer.Since(input.Params)
+		if err != nil {
+			return nil, err
+		}
+		var items []Thing
+		watermark := since
+		for _, thing := range things {
+			if thing.Modified.After(since) {
+				items = append(items, thing)
+				watermark = thing.Modified
+			}
+		}
+		return &struct{ Body Response[Thing] }{
+			Body: NewResponse(signer, items, nil, watermark),
+		}, nil
+	})
+
+	// Full sync.
+	resp := api.Get("/things")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), `"id":"b"`)
+	var body Response[Thing]
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Len(t, body.Items, 2)
+	require.NotEmpty(t, body.NextSyncToken)
+
+	// No changes.
+	resp = api.Get("/things?syncToken=" + body.NextSyncToken)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), `"items":[]`)
+
+	// One change.
+	things = append(things, Thing{ID: "c", Modified: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)})
+	resp = api.Get("/things?syncToken=" + body.NextSyncToken)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	body = Response[Thing]{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Len(t, body.Items, 1)
+	assert.Equal(t, "c", body.Items[0].ID)
+
+	// Since a time.
+	resp = api.Get("/things?since=2024-01-01T12:00:00Z")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.NotContains(t, resp.Body.String(), `"id":"a"`)
+
+	// Errors.
+	resp = api.Get("/things?syncToken=bad")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = api.Get("/things?since=2024-01-01T12:00:00Z&syncToken=" + body.NextSyncToken)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	signer.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	resp = api.Get("/things?syncToken=" + body.NextSyncToken)
+	assert.Equal(t, http.StatusGone, resp.Code)
+
+	// Documented params.
+	op := api.OpenAPI().Paths["/things"].Get
+	var names []string
+	for _, p := range op.Parameters {
+		names = append(names, p.Name)
+	}
+	assert.Equal(t, "since,syncToken", strings.Join(names, ","))
+}
@@ -0,0 +1,111 @@
+package huma
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+// digestAlgorithms are the supported `Digest` header algorithms by lowercase
+// name.
+var digestAlgorithms = map[string]func() hash.Hash{
+	"md5":     md5.New,
+	"sha":     sha1.New,
+	"sha-256": sha256.New,
+	"sha-512": sha512.New,
+}
+
+// contentDigestAlgorithms are the supported `Content-Digest` header
+// algorithms by lowercase name. RFC 9530 deprecates the insecure `Digest`
+// algorithms, so requests using them are rejected.
+var contentDigestAlgorithms = map[string]func() hash.Hash{
+	"sha-256": sha256.New,
+	"sha-512": sha512.New,
+}
+
+// digestParams documents the request headers used by `VerifyDigest`.
+func digestParams() []*Param {
+	return []*Param{
+		{
+			Name:        "Content-MD5",
+			In:          "header",
+			Description: "Base64-encoded MD5 checksum of the request body, see RFC 1864. The request is rejected if it does not match.",
+			Schema:      &Schema{Type: TypeString},
+		},
+		{
+			Name:        "Digest",
+			In:          "header",
+			Description: "Checksums of the request body like `sha-256=<base64>`, see RFC 3230. Supports `md5`, `sha`, `sha-256`, and `sha-512`. The request is rejected if any do not match.",
+			Schema:      &Schema{Type: TypeString},
+		},
+		{
+			Name:        "Content-Digest",
+			In:          "header",
+			Description: "Checksums of the request body like `sha-256=:<base64>:`, see RFC 9530. Supports `sha-256` and `sha-512`. The request is rejected if any do not match or use the deprecated `md5` or `sha` algorithms.",
+			Schema:      &Schema{Type: TypeString},
+		},
+	}
+}
+
+// verifyDigest checks the body as it was received against the checksums in
+// the request's `Content-MD5`, `Digest`, and `Content-Digest` headers, if
+// present. Unknown algorithms are ignored, while the deprecated `md5` and `sha`
+// algorithms are rejected for `Content-Digest`.
+func verifyDigest(ctx Context, body []byte) *contextError {
+	var errs []error
+	check := func(header, alg, expected string) {
+		algorithms := digestAlgorithms
+		if header == "Content-Digest" {
+			algorithms = contentDigestAlgorithms
+		}
+		newHash := algorithms[alg]
+		if newHash == nil {
+			if header == "Content-Digest" && digestAlgorithms[alg] != nil {
+				errs = append(errs, &ErrorDetail{
+					Message:  fmt.Sprintf("unsupported %s algorithm %s, use sha-256 or sha-512", header, alg),
+					Location: "header." + header,
+					Value:    alg,
+				})
+			}
+			return
+		}
+		h := newHash()
+		h.Write(body)
+		actual := base64.StdEncoding.EncodeToString(h.Sum(nil))
+		if expected != actual {
+			errs = append(errs, &ErrorDetail{
+				Message:  fmt.Sprintf("expected %s checksum %s to match body checksum %s", alg, expected, actual),
+				Location: "header." + header,
+				Value:    expected,
+			})
+		}
+	}
+
+	if value := strings.TrimSpace(ctx.Header("Content-MD5")); value != "" {
+		check("Content-MD5", "md5", value)
+	}
+	for _, header := range []string{"Digest", "Content-Digest"} {
+		for _, entry := range strings.Split(ctx.Header(header), ",") {
+			alg, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+			if !ok {
+				continue
+			}
+			if header == "Content-Digest" {
+				// Structured field byte sequences are wrapped in colons.
+				value = strings.Trim(value, ":")
+			}
+			check(header, strings.ToLower(alg), value)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &contextError{Code: http.StatusBadRequest, Msg: "request body checksum mismatch", Errs: errs}
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package huma
+
+import (
+	"encoding/json"
+	"html"
+)
+
+// DocsPage describes the API documentation page that a `DocsRenderer` should
+// generate.
+type DocsPage struct {
+	// Title of the page, which defaults to the OpenAPI `info.title`.
+	Title string
+
+	// SpecPath is the URL path of the OpenAPI spec without an extension, e.g.
+	// `/openapi`. Append `.json` or `.yaml` to get the document.
+	SpecPath string
+
+	// AssetsPath is the URL path from which the renderer's static assets are
+	// served when `Config.DocsAssets` is set, e.g. `/docs/assets`. If empty,
+	// the renderer should load its assets from a public CDN.
+	AssetsPath string
+}
+
+// DocsRenderer renders the HTML page for the interactive API documentation
+// served at `Config.DocsPath`. Built-in renderers are available for Stoplight
+// Elements (the default), Swagger UI, Redoc, and Scalar.
+//
+//	config := huma.DefaultConfig("My API", "1.0.0")
+//	config.DocsRenderer = &huma.ScalarRenderer{Theme: "moon"}
+type DocsRenderer interface {
+	RenderDocs(page DocsPage) []byte
+}
+
+// assetURL returns the URL of an asset, either from the self-hosted assets
+// path or from the given CDN URL.
+func (p DocsPage) assetURL(file, cdn string) string {
+	if p.AssetsPath != "" {
+		return p.AssetsPath + "/" + file
+	}
+	return cdn
+}
+
+// ElementsRenderer renders docs using Stoplight Elements. When self-hosting
+// via `Config.DocsAssets`, provide the `web-components.min.js` and
+// `styles.min.css` files from the `@stoplight/elements` package.
+type ElementsRenderer struct {
+	// Layout is either `sidebar` (default) or `stacked`.
+	Layout string
+
+	// HideTryIt hides the interactive request panel.
+	HideTryIt bool
+}
+
+// RenderDocs implements `huma.DocsRenderer`.
+func (r *ElementsRenderer) RenderDocs(page DocsPage) []byte {
+	layout := r.Layout
+	if layout == "" {
+		layout = "sidebar"
+	}
+	integrity := ` integrity="sha256-Tqvw1qE2abI+G6dPQBc5zbeHqfVwGoamETU3/TSpUw4="`
+	if page.AssetsPath != "" {
+		integrity = ""
+	}
+	hideTryIt := ""
+	if r.HideTryIt {
+		hideTryIt = `
+      hideTryIt="true"`
+	}
+	return []byte(`<!doctype html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8" />
+    <meta name="referrer" content="same-origin" />
+    <meta name="viewport" content="width=device-width, initial-scale=1, shrink-to-fit=no" />
+    <title>` + html.EscapeString(page.Title) + `</title>
+    <!-- Embed elements Elements via Web Component -->
+    <link href="` + page.assetURL("styles.min.css", "https://unpkg.com/@stoplight/elements@9.0.0/styles.min.css") + `" rel="stylesheet" />
+    <script src="` + page.assetURL("web-components.min.js", "https://unpkg.com/@stoplight/elements@9.0.0/web-components.min.js") + `"` + integrity + `
+            crossorigin="anonymous"></script>
+  </head>
+  <body style="height: 100vh;">
+
+    <elements-api
+      apiDescriptionUrl="` + page.SpecPath + `.yaml"
+      router="hash"
+      layout="` + html.EscapeString(layout) + `"
+      tryItCredentialsPolicy="same-origin"` + hideTryIt + `
+    />
+
+  </body>
+</html>`)
+}
+
+// SwaggerUIRenderer renders docs using Swagger UI. When self-hosting via
+// `Config.DocsAssets`, provide the `swagger-ui-bundle.js` and `swagger-ui.css`
+// files from the `swagger-ui-dist` package.
+type SwaggerUIRenderer struct {
+	// HideTryIt disables the "Try it out" functionality.
+	HideTryIt bool
+
+	// Options are additional Swagger UI configuration options, e.g.
+	// `{"docExpansion": "none"}`.
+	Options map[string]any
+}
+
+// RenderDocs implements `huma.DocsRenderer`.
+func (r *SwaggerUIRenderer) RenderDocs(page DocsPage) []byte {
+	opts := map[string]any{}
+	for k, v := range r.Options {
+		opts[k] = v
+	}
+	opts["url"] = page.SpecPath + ".json"
+	opts["dom_id"] = "#swagger-ui"
+	if r.HideTryIt {
+		opts["supportedSubmitMethods"] = []string{}
+	}
+	return []byte(`<!doctype html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1" />
+    <title>` + html.EscapeString(page.Title) + `</title>
+    <link rel="stylesheet" href="` + page.assetURL("swagger-ui.css", "https://unpkg.com/swagger-ui-dist@5.17.14/swagger-ui.css") + `" />
+  </head>
+  <body>
+    <div id="swagger-ui"></div>
+    <script src="` + page.assetURL("swagger-ui-bundle.js", "https://unpkg.com/swagger-ui-dist@5.17.14/swagger-ui-bundle.js") + `" crossorigin></script>
+    <script>
+      window.onload = () => {
+        window.ui = SwaggerUIBundle(` + scriptJSON(opts) + `);
+      };
+    </script>
+  </body>
+</html>`)
+}
+
+// RedocRenderer renders read-only docs using Redoc. When self-hosting via
+// `Config.DocsAssets`, provide the `redoc.standalone.js` file from the `redoc`
+// package.
+type RedocRenderer struct {
+	// Options are additional Redoc configuration options, e.g.
+	// `{"theme": {"colors": {"primary": {"main": "#6c5ce7"}}}}`.
+	Options map[string]any
+}
+
+// RenderDocs implements `huma.DocsRenderer`.
+func (r *RedocRenderer) RenderDocs(page DocsPage) []byte {
+	opts := r.Options
+	if opts == nil {
+		opts = map[string]any{}
+	}
+	return []byte(`<!doctype html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1" />
+    <title>` + html.EscapeString(page.Title) + `</title>
+  </head>
+  <body>
+    <div id="redoc"></div>
+    <script src="` + page.assetURL("redoc.standalone.js", "https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js") + `"></script>
+    <script>
+      Redoc.init(` + scriptJSON(page.SpecPath+".yaml") + `, ` + scriptJSON(opts) + `, document.getElementById("redoc"));
+    </script>
+  </body>
+</html>`)
+}
+
+// ScalarRenderer renders docs using Scalar. When self-hosting via
+// `Config.DocsAssets`, provide the `standalone.js` file from the
+// `@scalar/api-reference` package.
+type ScalarRenderer struct {
+	// Theme is the name of a Scalar theme, e.g. `purple` or `moon`.
+	Theme string
+
+	// HideTryIt hides the button to send test requests.
+	HideTryIt bool
+
+	// Options are additional Scalar configuration options, e.g.
+	// `{"layout": "classic"}`.
+	Options map[string]any
+}
+
+// RenderDocs implements `huma.DocsRenderer`.
+func (r *ScalarRenderer) RenderDocs(page DocsPage) []byte {
+	opts := map[string]any{}
+	for k, v := range r.Options {
+		opts[k] = v
+	}
+	if r.Theme != "" {
+		opts["theme"] = r.Theme
+	}
+	if r.HideTryIt {
+		opts["hideTestRequestButton"] = true
+	}
+	if page.AssetsPath != "" {
+		// Don't load fonts from the internet when self-hosting.
+		opts["withDefaultFonts"] = false
+	}
+	return []byte(`<!doctype html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1" />
+    <title>` + html.EscapeString(page.Title) + `</title>
+  </head>
+  <body>
+    <script id="api-reference" data-url="` + page.SpecPath + `.json" data-configuration="` + html.EscapeString(scriptJSON(opts)) + `"></script>
+    <script src="` + page.assetURL("standalone.js", "https://cdn.jsdelivr.net/npm/@scalar/api-reference@1.25.28") + `"></script>
+  </body>
+</html>`)
+}
+
+// scriptJSON marshals a value for safe inclusion within an HTML script tag.
+func scriptJSON(v any) string {
+	// `json.Marshal` escapes `<`, `>`, and `&` so the result cannot close the
+	// script tag early.
+	b, _ := json.Marshal(v)
+	return string(b)
+}
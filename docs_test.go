@@ -0,0 +1,86 @@
+package huma_test
+
+import (
+	"net/http"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+func TestDocsRenderers(t *testing.T) {
+	for _, item := range []struct {
+		name     string
+		renderer huma.DocsRenderer
+		contains []string
+	}{
+		{
+			name:     "default",
+			contains: []string{`<title>Test &lt;API&gt;</title>`, "https://unpkg.com/@stoplight/elements", `apiDescriptionUrl="/openapi.yaml"`, `layout="sidebar"`},
+		},
+		{
+			name:     "elements",
+			renderer: &huma.ElementsRenderer{Layout: "stacked", HideTryIt: true},
+			contains: []string{`layout="stacked"`, `hideTryIt="true"`},
+		},
+		{
+			name:     "swagger-ui",
+			renderer: &huma.SwaggerUIRenderer{HideTryIt: true, Options: map[string]any{"docExpansion": "none"}},
+			contains: []string{"swagger-ui-bundle.js", `"url":"/openapi.json"`, `"supportedSubmitMethods":[]`, `"docExpansion":"none"`},
+		},
+		{
+			name:     "redoc",
+			renderer: &huma.RedocRenderer{Options: map[string]any{"hideDownloadButton": true}},
+			contains: []string{"redoc.standalone.js", `Redoc.init("/openapi.yaml", {"hideDownloadButton":true}`},
+		},
+		{
+			name:     "scalar",
+			renderer: &huma.ScalarRenderer{Theme: "moon", HideTryIt: true},
+			contains: []string{"@scalar/api-reference", `data-url="/openapi.json"`, `&#34;theme&#34;:&#34;moon&#34;`, `&#34;hideTestRequestButton&#34;:true`},
+		},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			config := huma.DefaultConfig("Test <API>", "1.0.0")
+			config.DocsRenderer = item.renderer
+			_, api := humatest.New(t, config)
+
+			resp := api.Get("/docs")
+			assert.Equal(t, http.StatusOK, resp.Code)
+			assert.Equal(t, "text/html", resp.Header().Get("Content-Type"))
+			for _, c := range item.contains {
+				assert.Contains(t, resp.Body.String(), c)
+			}
+		})
+	}
+}
+
+func TestDocsAssets(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.DocsRenderer = &huma.ScalarRenderer{}
+	config.DocsAssets = fstest.MapFS{
+		"standalone.js": {Data: []byte("console.log('hello');")},
+		"fonts/a.woff2": {Data: []byte("font")},
+	}
+	_, api := humatest.New(t, config)
+
+	resp := api.Get("/docs")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `src="/docs/assets/standalone.js"`)
+	assert.Contains(t, resp.Body.String(), `&#34;withDefaultFonts&#34;:false`)
+	assert.NotContains(t, resp.Body.String(), "cdn.jsdelivr.net")
+
+	resp = api.Get("/docs/assets/standalone.js")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Header().Get("Content-Type"), "javascript")
+	assert.Equal(t, "console.log('hello');", resp.Body.String())
+
+	resp = api.Get("/docs/assets/missing.js")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	// Only top-level files are served.
+	resp = api.Get("/docs/assets/fonts/a.woff2")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
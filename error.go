@@ -1,10 +1,13 @@
 package huma
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"sort"
 )
 
 // ErrorDetailer returns error details for responses & debugging. This enables
@@ -16,12 +19,12 @@ type ErrorDetailer interface {
 // ErrorDetail provides details about a specific error.
 type ErrorDetail struct {
 	// Message is a human-readable explanation of the error.
-	Message string `json:"message,omitempty" doc:"Error message text"`
+	Message string `json:"message,omitempty" example:"expected required property id to be present" doc:"Error message text"`
 
 	// Location is a path-like string indicating where the error occurred.
 	// It typically begins with `path`, `query`, `header`, or `body`. Example:
 	// `body.items[3].tags` or `path.thing-id`.
-	Location string `json:"location,omitempty" doc:"Where the error occurred, e.g. 'body.items[3].tags' or 'path.thing-id'"`
+	Location string `json:"location,omitempty" example:"body.items[3].tags" doc:"Where the error occurred, e.g. 'body.items[3].tags' or 'path.thing-id'"`
 
 	// Value is the value at the given location, echoed back to the client
 	// to help with debugging. This can be useful for e.g. validating that
@@ -89,6 +92,15 @@ type ErrorModel struct {
 	// Errors provides an optional mechanism of passing additional error details
 	// as a list.
 	Errors []*ErrorDetail `json:"errors,omitempty" doc:"Optional list of individual error details"`
+
+	// Code is an optional machine-readable code which identifies the specific
+	// problem within the status, like `USER_NOT_FOUND`. See `ErrorWithCode`.
+	Code string `json:"code,omitempty" example:"USER_NOT_FOUND" doc:"A machine-readable code identifying the specific problem."`
+
+	// Extensions are additional members which are serialized into the top
+	// level of the problem details body, as allowed by RFC 9457. They cannot
+	// override the standard members above.
+	Extensions map[string]any `json:"-"`
 }
 
 // Error satisfies the `error` interface. It returns the error's detail field.
@@ -96,6 +108,43 @@ func (e *ErrorModel) Error() string {
 	return e.Detail
 }
 
+// MarshalJSON marshals the error, appending any extension members which do
+// not conflict with the standard members.
+func (e *ErrorModel) MarshalJSON() ([]byte, error) {
+	type plain ErrorModel
+	b, err := json.Marshal((*plain)(e))
+	if err != nil || len(e.Extensions) == 0 {
+		return b, err
+	}
+
+	keys := make([]string, 0, len(e.Extensions))
+	for k := range e.Extensions {
+		switch k {
+		case "type", "title", "status", "detail", "instance", "errors", "code", "$schema":
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	buf := bytes.NewBuffer(b[:len(b)-1])
+	for _, k := range keys {
+		v, err := json.Marshal(e.Extensions[k])
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(k)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
 // Add an error to the `Errors` slice. If passed a struct that satisfies the
 // `huma.ErrorDetailer` interface, then it is used, otherwise the error
 // string is used as the error detail message.
@@ -115,6 +164,18 @@ func (e *ErrorModel) Add(err error) {
 	e.Errors = append(e.Errors, &ErrorDetail{Message: err.Error()})
 }
 
+// errs returns the error details as errors, e.g. to create a different
+// error type with the same details.
+func (e *ErrorModel) errs() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, detail := range e.Errors {
+		if detail != nil {
+			errs = append(errs, detail)
+		}
+	}
+	return errs
+}
+
 // GetStatus returns the HTTP status that should be returned to the client
 // for this error.
 func (e *ErrorModel) GetStatus() int {
@@ -252,11 +313,22 @@ var NewErrorWithContext = func(_ Context, status int, msg string, errs ...error)
 	return NewError(status, msg, errs...)
 }
 
+// newErrorForContext creates an error using the current operation's
+// `NewError` if set, otherwise falling back to `NewErrorWithContext`.
+func newErrorForContext(ctx Context, status int, msg string, errs ...error) StatusError {
+	if ctx != nil {
+		if op := ctx.Operation(); op != nil && op.NewError != nil {
+			return op.NewError(ctx, status, msg, errs...)
+		}
+	}
+	return NewErrorWithContext(ctx, status, msg, errs...)
+}
+
 // WriteErr writes an error response with the given context, using the
 // configured error type and with the given status code and message. It is
 // marshaled using the API's content negotiation methods.
 func WriteErr(api API, ctx Context, status int, msg string, errs ...error) error {
-	var err = NewErrorWithContext(ctx, status, msg, errs...)
+	var err = newErrorForContext(ctx, status, msg, errs...)
 
 	// NewError may have modified the status code, so update it here if needed.
 	// If it was not modified then this is a no-op.
@@ -270,6 +342,42 @@ func WriteErr(api API, ctx Context, status int, msg string, errs ...error) error
 	return writeErr
 }
 
+// ErrorWithCode returns an error for the given status which also carries a
+// machine-readable code, like `USER_NOT_FOUND`, letting clients tell apart
+// different problems with the same status. Document the possible codes for
+// each status via `Operation.ErrorCodes`. If `NewError` has been replaced with
+// one that does not return an `*ErrorModel`, the code is ignored.
+//
+//	return nil, huma.ErrorWithCode(http.StatusNotFound, "USER_NOT_FOUND", "user not found")
+func ErrorWithCode(status int, code, msg string, errs ...error) StatusError {
+	err := NewError(status, msg, errs...)
+	if model, ok := err.(*ErrorModel); ok {
+		model.Code = code
+	}
+	return err
+}
+
+// ErrorWithExtensions adds extension members to the top level of the error's
+// problem details body, e.g. to include the limit that was exceeded. The
+// error must be or wrap an `*ErrorModel`, otherwise it is returned unchanged.
+//
+//	return nil, huma.ErrorWithExtensions(
+//		huma.ErrorWithCode(http.StatusConflict, "BALANCE_TOO_LOW", "insufficient funds"),
+//		map[string]any{"balance": 30, "cost": 50},
+//	)
+func ErrorWithExtensions(err error, members map[string]any) error {
+	var model *ErrorModel
+	if errors.As(err, &model) {
+		if model.Extensions == nil {
+			model.Extensions = make(map[string]any, len(members))
+		}
+		for k, v := range members {
+			model.Extensions[k] = v
+		}
+	}
+	return err
+}
+
 // Status304NotModified returns a 304. This is not really an error, but
 // provides a way to send non-default responses.
 func Status304NotModified() StatusError {
@@ -321,6 +429,12 @@ func Error412PreconditionFailed(msg string, errs ...error) StatusError {
 	return NewError(http.StatusPreconditionFailed, msg, errs...)
 }
 
+// Error413RequestEntityTooLarge returns a 413. Pass the limit that was exceeded
+// via `ErrorDetail.Value` so that clients can discover it.
+func Error413RequestEntityTooLarge(msg string, errs ...error) StatusError {
+	return NewError(http.StatusRequestEntityTooLarge, msg, errs...)
+}
+
 // Error415UnsupportedMediaType returns a 415.
 func Error415UnsupportedMediaType(msg string, errs ...error) StatusError {
 	return NewError(http.StatusUnsupportedMediaType, msg, errs...)
@@ -2,6 +2,7 @@ package huma_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -64,6 +65,7 @@ func TestErrorResponses(t *testing.T) {
 		{huma.Error409Conflict, 409},
 		{huma.Error410Gone, 410},
 		{huma.Error412PreconditionFailed, 412},
+		{huma.Error413RequestEntityTooLarge, 413},
 		{huma.Error415UnsupportedMediaType, 415},
 		{huma.Error422UnprocessableEntity, 422},
 		{huma.Error429TooManyRequests, 429},
@@ -137,3 +139,112 @@ func TestErrorWithHeaders(t *testing.T) {
 	assert.Equal(t, "bar", resp.Header().Get("Another"))
 	assert.Contains(t, resp.Body.String(), "test")
 }
+
+func TestErrorWithCode(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/users/{id}",
+		ErrorCodes: map[int][]string{
+			http.StatusNotFound: {"USER_NOT_FOUND", "ORG_NOT_FOUND"},
+			http.StatusConflict: {"USER_LOCKED"},
+		},
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		if input.ID == "locked" {
+			return nil, huma.ErrorWithExtensions(
+				huma.ErrorWithCode(http.StatusConflict, "USER_LOCKED", "user is locked"),
+				map[string]any{"retryAfter": 30, "status": 200},
+			)
+		}
+		return nil, huma.ErrorWithCode(http.StatusNotFound, "USER_NOT_FOUND", "user not found")
+	})
+
+	op := api.OpenAPI().Paths["/users/{id}"].Get
+	for _, status := range []string{"404", "409", "422", "500"} {
+		assert.Contains(t, op.Responses, status)
+	}
+	schema := op.Responses["404"].Content["application/problem+json"].Schema
+	require.Len(t, schema.AllOf, 2)
+	assert.Equal(t, "#/components/schemas/ErrorModel", schema.AllOf[0].Ref)
+	assert.Equal(t, []any{"USER_NOT_FOUND", "ORG_NOT_FOUND"}, schema.AllOf[1].Properties["code"].Enum)
+	assert.Empty(t, op.Responses["500"].Content["application/problem+json"].Schema.AllOf)
+
+	resp := api.Get("/users/abc")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"code":"USER_NOT_FOUND"`)
+
+	resp = api.Get("/users/locked")
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, "USER_LOCKED", body["code"])
+	assert.InDelta(t, 30, body["retryAfter"], 0)
+	assert.InDelta(t, 409, body["status"], 0)
+	assert.Contains(t, body["$schema"], "/schemas/ErrorModel.json")
+}
+
+type NotFoundBody struct {
+	Resource string `json:"resource"`
+}
+
+func (e *NotFoundBody) Error() string {
+	return e.Resource + " not found"
+}
+
+type ConflictBody struct {
+	Reason string `json:"reason"`
+}
+
+func (e ConflictBody) Error() string {
+	return e.Reason
+}
+
+func TestErrorBodies(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/things/{id}",
+		ErrorBodies: map[int]any{
+			http.StatusNotFound: &NotFoundBody{},
+			http.StatusConflict: ConflictBody{},
+		},
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		switch input.ID {
+		case "missing":
+			return nil, &NotFoundBody{Resource: "thing"}
+		case "conflict":
+			return nil, fmt.Errorf("wrapped: %w", ConflictBody{Reason: "locked"})
+		}
+		return nil, huma.Error400BadRequest("bad")
+	})
+
+	op := api.OpenAPI().Paths["/things/{id}"].Get
+	assert.Equal(t, "#/components/schemas/NotFoundBody", op.Responses["404"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/ConflictBody", op.Responses["409"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/ErrorModel", op.Responses["500"].Content["application/problem+json"].Schema.Ref)
+
+	resp := api.Get("/things/missing")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"resource":"thing"`)
+
+	resp = api.Get("/things/conflict")
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"reason":"locked"`)
+
+	resp = api.Get("/things/other")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	assert.Panics(t, func() {
+		huma.Register(api, huma.Operation{
+			Method:      http.MethodGet,
+			Path:        "/invalid",
+			ErrorBodies: map[int]any{http.StatusNotFound: struct{}{}},
+		}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
@@ -1,17 +1,236 @@
 // Example field selection transform enabling a GraphQL-like behavior.
+//
+// Clients select fields via the `fields` query parameter, which supports
+// nested projections and exclusions:
+//
+//	?fields=id,owner(name,email),items(id)
+//	?fields=-secret,owner(-email)
+//
+// Excluded fields are removed from the response. If any fields are included
+// at a given level then only those fields are returned at that level. Nested
+// selections made up only of exclusions, like `owner(-email)`, do not limit
+// which sibling fields are returned.
 package fields
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/shorthand/v2"
 )
 
-// FieldSelectTransform is an example of a transform that can use an input
-// header value to modify the response on the server, providing a GraphQL-like
-// way to send only the fields that the client wants over the wire.
+// selection describes which fields to keep or remove from an object. A nil
+// nested selection in `include` means the entire field value is kept. When
+// `restrict` is set, fields not present in `include` are removed.
+type selection struct {
+	include  map[string]*selection
+	exclude  map[string]bool
+	restrict bool
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+// parse a field selection like `id,owner(name,email),-secret`.
+func parse(s string) (*selection, error) {
+	p := &parser{s: s}
+	sel, err := p.list()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.s) {
+		return nil, fmt.Errorf("unexpected '%c' at position %d", p.s[p.pos], p.pos)
+	}
+	return sel, nil
+}
+
+func (p *parser) list() (*selection, error) {
+	sel := &selection{}
+	for {
+		p.skipSpace()
+		exclude := false
+		if p.pos < len(p.s) && p.s[p.pos] == '-' {
+			exclude = true
+			p.pos++
+		}
+
+		start := p.pos
+		for p.pos < len(p.s) && !strings.ContainsRune(",()", rune(p.s[p.pos])) {
+			p.pos++
+		}
+		name := strings.TrimSpace(p.s[start:p.pos])
+		if name == "" {
+			return nil, fmt.Errorf("expected field name at position %d", start)
+		}
+
+		var nested *selection
+		if p.pos < len(p.s) && p.s[p.pos] == '(' {
+			if exclude {
+				return nil, fmt.Errorf("excluded field '%s' cannot have nested fields", name)
+			}
+			p.pos++
+			var err error
+			if nested, err = p.list(); err != nil {
+				return nil, err
+			}
+			if p.pos >= len(p.s) || p.s[p.pos] != ')' {
+				return nil, fmt.Errorf("expected ')' at position %d", p.pos)
+			}
+			p.pos++
+		}
+
+		if exclude {
+			if sel.exclude == nil {
+				sel.exclude = map[string]bool{}
+			}
+			sel.exclude[name] = true
+		} else {
+			if sel.include == nil {
+				sel.include = map[string]*selection{}
+			}
+			sel.include[name] = nested
+			if nested == nil || nested.restrict {
+				sel.restrict = true
+			}
+		}
+
+		p.skipSpace()
+		if p.pos >= len(p.s) || p.s[p.pos] != ',' {
+			return sel, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// apply the selection to a generic JSON value, returning the projected value.
+func (sel *selection) apply(v any) any {
+	switch value := v.(type) {
+	case []any:
+		result := make([]any, len(value))
+		for i, item := range value {
+			result[i] = sel.apply(item)
+		}
+		return result
+	case map[string]any:
+		result := make(map[string]any, len(value))
+		for k, item := range value {
+			if sel.exclude[k] {
+				continue
+			}
+			nested, ok := sel.include[k]
+			if !ok && sel.restrict {
+				continue
+			}
+			if nested != nil {
+				item = nested.apply(item)
+			}
+			result[k] = item
+		}
+		return result
+	}
+	return v
+}
+
+// validate the selection against the schema, returning an error for each
+// unknown field name.
+func (sel *selection) validate(registry huma.Registry, s *huma.Schema, path string) []error {
+	s = deref(registry, s)
+	for s != nil && s.Type == huma.TypeArray {
+		s = deref(registry, s.Items)
+	}
+	if s == nil || len(s.Properties) == 0 {
+		// Free-form value, nothing to validate against.
+		return nil
+	}
+
+	var errs []error
+	check := func(name string) bool {
+		if _, ok := s.Properties[name]; !ok {
+			errs = append(errs, &huma.ErrorDetail{
+				Message:  "unknown field " + path + name,
+				Location: "query.fields",
+				Value:    path + name,
+			})
+			return false
+		}
+		return true
+	}
+	for _, name := range sortedKeys(sel.include) {
+		if nested := sel.include[name]; check(name) && nested != nil {
+			errs = append(errs, nested.validate(registry, s.Properties[name], path+name+".")...)
+		}
+	}
+	for _, name := range sortedKeys(sel.exclude) {
+		check(name)
+	}
+	return errs
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func deref(registry huma.Registry, s *huma.Schema) *huma.Schema {
+	if s != nil && s.Ref != "" {
+		return registry.SchemaFromRef(s.Ref)
+	}
+	return s
+}
+
+// responseSchema returns the JSON schema of the operation's default response
+// body, if any.
+func responseSchema(op *huma.Operation) *huma.Schema {
+	resp := op.Responses[strconv.Itoa(op.DefaultStatus)]
+	if resp == nil || resp.Content == nil || resp.Content["application/json"] == nil {
+		return nil
+	}
+	return resp.Content["application/json"].Schema
+}
+
+// FieldSelectTransform is an example of a transform that can use the `fields`
+// query parameter to modify the response on the server, providing a
+// GraphQL-like way to send only the fields that the client wants over the
+// wire. For backward compatibility, a `Fields` header containing a shorthand
+// query is also supported.
+//
+// Only successful responses are modified. Transforms run after the handler,
+// once the response status has been decided, so they cannot reject a request
+// and invalid selections are left unapplied here. Use `Enable` to document the
+// `fields` parameter and reject invalid selections with a `400 Bad Request`
+// before the handler runs.
 func FieldSelectTransform(ctx huma.Context, status string, v any) (any, error) {
+	if !strings.HasPrefix(status, "2") {
+		// Don't touch errors or other non-success responses.
+		return v, nil
+	}
+	if fields := ctx.Query("fields"); fields != "" {
+		sel, err := parse(fields)
+		if err != nil {
+			return v, nil
+		}
+		var tmp any
+		b, _ := json.Marshal(v)
+		json.Unmarshal(b, &tmp)
+		return sel.apply(tmp), nil
+	}
 	if fields := ctx.Header("Fields"); fields != "" {
 		// Ugh this is inefficient... consider other ways of doing this :-(
 		var tmp any
@@ -22,3 +241,60 @@ func FieldSelectTransform(ctx huma.Context, status string, v any) (any, error) {
 	}
 	return v, nil
 }
+
+// Enable documents the `fields` query parameter on every operation
+// subsequently registered on the API which returns a JSON body, and validates
+// incoming selections against the response schema so that malformed
+// selections and unknown field names result in a `400 Bad Request` error. Use
+// it together with `FieldSelectTransform`:
+//
+//	config := huma.DefaultConfig("My API", "1.0.0")
+//	config.Transformers = append(config.Transformers, fields.FieldSelectTransform)
+//	api := humachi.New(router, config)
+//	fields.Enable(api)
+func Enable(api huma.API) {
+	oapi := api.OpenAPI()
+	oapi.OnAddOperation = append(oapi.OnAddOperation, func(oapi *huma.OpenAPI, op *huma.Operation) {
+		schema := responseSchema(op)
+		if schema == nil {
+			return
+		}
+		for _, p := range op.Parameters {
+			if p.In == "query" && p.Name == "fields" {
+				// Already defined by the operation itself.
+				return
+			}
+		}
+
+		op.Parameters = append(op.Parameters, &huma.Param{
+			Name:        "fields",
+			In:          "query",
+			Description: "Fields to include in the response, e.g. `id,owner(name,email)`. Prefix a field with `-` to exclude it, e.g. `-secret`.",
+			Schema:      &huma.Schema{Type: huma.TypeString},
+			Example:     "id,owner(name)",
+		})
+
+		registry := oapi.Components.Schemas
+		op.Middlewares = append(op.Middlewares, func(ctx huma.Context, next func(huma.Context)) {
+			fields := ctx.Query("fields")
+			if fields == "" {
+				next(ctx)
+				return
+			}
+			sel, err := parse(fields)
+			if err != nil {
+				huma.WriteErr(api, ctx, http.StatusBadRequest, "invalid field selection", &huma.ErrorDetail{
+					Message:  "invalid field selection: " + err.Error(),
+					Location: "query.fields",
+					Value:    fields,
+				})
+				return
+			}
+			if errs := sel.validate(registry, schema, ""); len(errs) > 0 {
+				huma.WriteErr(api, ctx, http.StatusBadRequest, "invalid field selection", errs...)
+				return
+			}
+			next(ctx)
+		})
+	})
+}
@@ -0,0 +1,152 @@
+package fields
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+func TestParse(t *testing.T) {
+	data := map[string]any{
+		"id":     "abc",
+		"secret": "shh",
+		"owner":  map[string]any{"name": "Alice", "email": "alice@example.com"},
+		"items":  []any{map[string]any{"id": "1", "name": "one"}},
+	}
+
+	for _, item := range []struct {
+		name     string
+		fields   string
+		expected map[string]any
+	}{
+		{"include", "id", map[string]any{"id": "abc"}},
+		{"spaces", " id , secret ", map[string]any{"id": "abc", "secret": "shh"}},
+		{"nested", "id,owner(name)", map[string]any{
+			"id":    "abc",
+			"owner": map[string]any{"name": "Alice"},
+		}},
+		{"array", "items(id)", map[string]any{
+			"items": []any{map[string]any{"id": "1"}},
+		}},
+		{"exclude", "-secret,-items", map[string]any{
+			"id":    "abc",
+			"owner": map[string]any{"name": "Alice", "email": "alice@example.com"},
+		}},
+		{"nested-exclude", "owner(-email),-secret,-items", map[string]any{
+			"id":    "abc",
+			"owner": map[string]any{"name": "Alice"},
+		}},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			sel, err := parse(item.fields)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result := sel.apply(data); !reflect.DeepEqual(item.expected, result) {
+				t.Errorf("expected %v, got %v", item.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, item := range []struct {
+		fields string
+		err    string
+	}{
+		{"", "expected field name at position 0"},
+		{"id,", "expected field name at position 3"},
+		{"owner(name", "expected ')' at position 10"},
+		{"owner)", "unexpected ')' at position 5"},
+		{"-owner(name)", "excluded field 'owner' cannot have nested fields"},
+	} {
+		t.Run(item.fields, func(t *testing.T) {
+			_, err := parse(item.fields)
+			if err == nil || err.Error() != item.err {
+				t.Errorf("expected error %q, got %v", item.err, err)
+			}
+		})
+	}
+}
+
+type Owner struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type Thing struct {
+	ID     string  `json:"id"`
+	Secret string  `json:"secret"`
+	Owner  Owner   `json:"owner"`
+	Items  []Owner `json:"items"`
+}
+
+func TestEnable(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Transformers = append(config.Transformers, FieldSelectTransform)
+	_, api := humatest.New(t, config)
+	Enable(api)
+
+	huma.Get(api, "/thing", func(ctx context.Context, input *struct{}) (*struct{ Body Thing }, error) {
+		return &struct{ Body Thing }{Body: Thing{
+			ID:     "abc",
+			Secret: "shh",
+			Owner:  Owner{Name: "Alice", Email: "alice@example.com"},
+			Items:  []Owner{{Name: "Bob", Email: "bob@example.com"}},
+		}}, nil
+	})
+
+	var fieldsParam *huma.Param
+	for _, p := range api.OpenAPI().Paths["/thing"].Get.Parameters {
+		if p.Name == "fields" {
+			fieldsParam = p
+		}
+	}
+	if fieldsParam == nil || fieldsParam.In != "query" {
+		t.Fatal("expected the fields query param to be documented")
+	}
+
+	resp := api.Get("/thing?fields=id,owner(name),items(email)")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var body map[string]any
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]any{
+		"id":    "abc",
+		"owner": map[string]any{"name": "Alice"},
+		"items": []any{map[string]any{"email": "bob@example.com"}},
+	}
+	if !reflect.DeepEqual(expected, body) {
+		t.Errorf("expected %v, got %v", expected, body)
+	}
+
+	for _, item := range []struct {
+		name   string
+		fields string
+		detail string
+	}{
+		{"malformed", "owner(name", "invalid field selection: expected ')' at position 10"},
+		{"unknown", "id,nope", "unknown field nope"},
+		{"unknown-nested", "owner(nope)", "unknown field owner.nope"},
+		{"unknown-array", "items(-nope)", "unknown field items.nope"},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			resp := api.Get("/thing?fields=" + item.fields)
+			if resp.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", resp.Code, resp.Body.String())
+			}
+			if !strings.Contains(resp.Body.String(), item.detail) {
+				t.Errorf("expected %q in %s", item.detail, resp.Body.String())
+			}
+		})
+	}
+}
@@ -15,12 +15,9 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
-	"reflect"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humachi"
@@ -35,34 +32,6 @@ type Options struct {
 	Port int `help:"Port to listen on" default:"8888"`
 }
 
-// OmittableNullable is a field which can be omitted from the input,
-// set to `null`, or set to a value. Each state is tracked and can
-// be checked for in handling code.
-type OmittableNullable[T any] struct {
-	Sent  bool
-	Null  bool
-	Value T
-}
-
-// UnmarshalJSON unmarshals this value from JSON input.
-func (o *OmittableNullable[T]) UnmarshalJSON(b []byte) error {
-	if len(b) > 0 {
-		o.Sent = true
-		if bytes.Equal(b, []byte("null")) {
-			o.Null = true
-			return nil
-		}
-		return json.Unmarshal(b, &o.Value)
-	}
-	return nil
-}
-
-// Schema returns a schema representing this value on the wire.
-// It returns the schema of the contained type.
-func (o OmittableNullable[T]) Schema(r huma.Registry) *huma.Schema {
-	return r.Schema(reflect.TypeOf(o.Value), true, "")
-}
-
 type MyResponse struct {
 	Body struct {
 		Message string `json:"message"`
@@ -84,7 +53,7 @@ func main() {
 		}, func(ctx context.Context, input *struct {
 			// Making the body a pointer makes it optional, as it may be `nil`.
 			Body *struct {
-				Name OmittableNullable[string] `json:"name,omitempty" maxLength:"10"`
+				Name huma.Omittable[string] `json:"name,omitempty" maxLength:"10"`
 			}
 		}) (*MyResponse, error) {
 			resp := &MyResponse{}
@@ -0,0 +1,216 @@
+package huma
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// SchemaLoader loads the raw JSON document at the given URL of an external
+// schema reference, i.e. without its `#` fragment.
+type SchemaLoader func(url string) ([]byte, error)
+
+// HTTPSchemaLoader returns a loader which fetches external schemas using the
+// given HTTP client, or `http.DefaultClient` if nil.
+func HTTPSchemaLoader(client *http.Client) SchemaLoader {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(u string) ([]byte, error) {
+		resp, err := client.Get(u)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return io.ReadAll(resp.Body)
+	}
+}
+
+// ExternalSchemas resolves and caches schemas referenced via an external
+// `$ref` like `https://example.com/schemas/thing.json` or
+// `https://example.com/common.json#/$defs/Thing`, so that request bodies
+// using them can be validated. Each document is loaded once, and refs within
+// it are resolved relative to its URL. Use `Add` to preload documents, e.g.
+// from an embedded filesystem, so that no network access is needed.
+//
+// External refs in request body schemas are resolved when operations are
+// registered, which panics if they cannot be loaded.
+//
+//	external := huma.NewExternalSchemas(huma.HTTPSchemaLoader(nil))
+//	config.Components.Schemas = huma.NewMapRegistry("#/components/schemas/",
+//		huma.DefaultSchemaNamer, huma.MapRegistryExternalSchemas(external))
+type ExternalSchemas struct {
+	// Loader loads documents which have not been added. If nil, only added
+	// documents can be used.
+	Loader SchemaLoader
+
+	mu      sync.Mutex
+	docs    map[string]any
+	schemas map[string]*Schema
+}
+
+// NewExternalSchemas creates a new external schema cache using the loader.
+func NewExternalSchemas(loader SchemaLoader) *ExternalSchemas {
+	return &ExternalSchemas{Loader: loader}
+}
+
+// Add preloads the JSON document at the given URL.
+func (e *ExternalSchemas) Add(u string, doc []byte) error {
+	var parsed any
+	if err := json.Unmarshal(doc, &parsed); err != nil {
+		return fmt.Errorf("unable to parse external schema %s: %w", u, err)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.docs == nil {
+		e.docs = map[string]any{}
+	}
+	e.docs[u] = parsed
+	return nil
+}
+
+// Get returns a previously resolved schema, or nil.
+func (e *ExternalSchemas) Get(ref string) *Schema {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.schemas[ref]
+}
+
+// Resolve returns the schema for the external ref, loading its document if
+// needed. Any refs within the schema are rewritten to absolute refs and
+// resolved as well.
+func (e *ExternalSchemas) Resolve(ref string) (*Schema, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.resolve(ref)
+}
+
+func (e *ExternalSchemas) resolve(ref string) (*Schema, error) {
+	if s := e.schemas[ref]; s != nil {
+		return s, nil
+	}
+
+	docURL, fragment, _ := strings.Cut(ref, "#")
+	if docURL == "" {
+		return nil, fmt.Errorf("schema ref %s is not external", ref)
+	}
+	doc, ok := e.docs[docURL]
+	if !ok {
+		if e.Loader == nil {
+			return nil, fmt.Errorf("unable to load external schema %s: no loader", docURL)
+		}
+		b, err := e.Loader(docURL)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load external schema %s: %w", docURL, err)
+		}
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return nil, fmt.Errorf("unable to parse external schema %s: %w", docURL, err)
+		}
+		if e.docs == nil {
+			e.docs = map[string]any{}
+		}
+		e.docs[docURL] = doc
+	}
+
+	node, err := jsonPointer(doc, fragment)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve %s: %w", ref, err)
+	}
+	b, err := json.Marshal(node)
+	if err != nil {
+		return nil, err
+	}
+	s := &Schema{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("unable to parse external schema %s: %w", ref, err)
+	}
+
+	// Make refs absolute so they can be looked up without knowing which
+	// document they came from.
+	base, err := url.Parse(docURL)
+	if err != nil {
+		return nil, err
+	}
+	var refs []string
+	s = copySchema(s, func(c *Schema) {
+		if c.Ref == "" {
+			return
+		}
+		if strings.HasPrefix(c.Ref, "#") {
+			c.Ref = docURL + c.Ref
+		} else if u, err := url.Parse(c.Ref); err == nil {
+			c.Ref = base.ResolveReference(u).String()
+		}
+		refs = append(refs, c.Ref)
+	})
+	s.PrecomputeMessages()
+
+	// Store before resolving the refs so that recursive schemas terminate.
+	if e.schemas == nil {
+		e.schemas = map[string]*Schema{}
+	}
+	e.schemas[ref] = s
+	for _, r := range refs {
+		if _, err := e.resolve(r); err != nil {
+			delete(e.schemas, ref)
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// jsonPointer returns the value at the JSON pointer (RFC 6901) within the
+// parsed document, e.g. `/$defs/Thing`.
+func jsonPointer(doc any, pointer string) (any, error) {
+	if pointer == "" || pointer == "/" {
+		return doc, nil
+	}
+	for _, part := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		m, ok := doc.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s not found", pointer)
+		}
+		if doc, ok = m[part]; !ok {
+			return nil, fmt.Errorf("%s not found", pointer)
+		}
+	}
+	return doc, nil
+}
+
+// isExternalRef returns whether the ref points outside of the OpenAPI
+// document.
+func isExternalRef(ref string) bool {
+	return ref != "" && !strings.HasPrefix(ref, "#")
+}
+
+// resolveExternalRefs resolves all external refs in the schema using the
+// registry's external schemas, if any, and panics if one cannot be resolved.
+func resolveExternalRefs(registry Registry, s *Schema) {
+	var refs []string
+	copySchema(s, func(c *Schema) {
+		if isExternalRef(c.Ref) {
+			refs = append(refs, c.Ref)
+		}
+	})
+	if len(refs) == 0 {
+		return
+	}
+	r, ok := registry.(*mapRegistry)
+	if !ok || r.external == nil {
+		// Custom registries resolve refs themselves.
+		return
+	}
+	for _, ref := range refs {
+		if _, err := r.external.Resolve(ref); err != nil {
+			panic(err)
+		}
+	}
+}
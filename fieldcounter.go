@@ -0,0 +1,41 @@
+package huma
+
+import "sync"
+
+// FieldCounter counts how often request fields are used. It is safe for
+// concurrent use, and its `Add` method can be used as the
+// `Config.OnUnknownFields` or `Config.OnDeprecatedFields` callback to
+// measure client usage of fields before removing them.
+//
+//	unknown := &huma.FieldCounter{}
+//	config.OnUnknownFields = unknown.Add
+//
+//	// Later, e.g. from a metrics endpoint:
+//	fmt.Println(unknown.Counts())
+type FieldCounter struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+// Add increments the count of each of the fields.
+func (c *FieldCounter) Add(ctx Context, fields []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = map[string]int64{}
+	}
+	for _, field := range fields {
+		c.counts[field]++
+	}
+}
+
+// Counts returns a copy of the number of times each field was used.
+func (c *FieldCounter) Counts() map[string]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := make(map[string]int64, len(c.counts))
+	for field, count := range c.counts {
+		counts[field] = count
+	}
+	return counts
+}
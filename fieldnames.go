@@ -0,0 +1,106 @@
+package huma
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// fieldNames matches undeclared request body property names to declared
+// ones, see `Config.CaseInsensitiveFields` and `Config.FieldAliases`.
+type fieldNames struct {
+	registry        Registry
+	aliases         bool
+	caseInsensitive bool
+}
+
+// unmarshaler wraps the unmarshaler to rename body properties to their
+// declared names before they are validated and decoded.
+func (o *fieldNames) unmarshaler(s *Schema, u intoUnmarshaler) intoUnmarshaler {
+	return func(data []byte, v any) error {
+		var parsed any
+		target, isAny := v.(*any)
+		if !isAny {
+			target = &parsed
+		}
+		if err := u(data, target); err != nil {
+			return err
+		}
+		renamed := o.renameFields(s, *target)
+		if isAny {
+			return nil
+		}
+		if !renamed {
+			return u(data, v)
+		}
+		// The body no longer matches what was sent, so decode the renamed
+		// properties instead.
+		b, err := json.Marshal(parsed)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(b, v)
+	}
+}
+
+// renameFields renames object properties in the parsed data which are not
+// declared in the schema but match a declared property via an alias or by
+// ignoring case. It returns whether anything was renamed.
+func (o *fieldNames) renameFields(s *Schema, data any) bool {
+	if s != nil && s.Ref != "" {
+		s = o.registry.SchemaFromRef(s.Ref)
+	}
+	if s == nil {
+		return false
+	}
+
+	renamed := false
+	switch d := data.(type) {
+	case map[string]any:
+		for k, v := range d {
+			if _, ok := s.Properties[k]; ok {
+				continue
+			}
+			name := o.declaredFieldName(s, k)
+			if name == "" {
+				continue
+			}
+			if _, ok := d[name]; ok {
+				// The declared name was sent as well, so keep it.
+				continue
+			}
+			d[name] = v
+			delete(d, k)
+			renamed = true
+		}
+		for k, v := range d {
+			if ps := s.Properties[k]; ps != nil {
+				renamed = o.renameFields(ps, v) || renamed
+			} else if ap, ok := s.AdditionalProperties.(*Schema); ok {
+				renamed = o.renameFields(ap, v) || renamed
+			}
+		}
+	case []any:
+		for _, item := range d {
+			renamed = o.renameFields(s.Items, item) || renamed
+		}
+	}
+	return renamed
+}
+
+// declaredFieldName returns the declared property name for an undeclared
+// one, or an empty string if there is none.
+func (o *fieldNames) declaredFieldName(s *Schema, name string) string {
+	if o.aliases {
+		if declared, ok := s.aliases[name]; ok {
+			return declared
+		}
+	}
+	if o.caseInsensitive {
+		for _, declared := range s.propertyNames {
+			if strings.EqualFold(declared, name) {
+				return declared
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,447 @@
+// Package filter provides query parameters for filtering and sorting
+// collections using a small, documented expression syntax, for example:
+//
+//	GET /things?filter=name eq 'x' and age gt 3&sort=-created,name
+//
+// Expressions are parsed into a typed AST which is validated against the
+// fields allowed for the collection, declared as a Go struct. Allowed fields
+// use their JSON names and can be excluded with `filter:"-"`. Only string,
+// number, boolean, and `time.Time` fields can be used.
+//
+//	type ThingFields struct {
+//		Name    string    `json:"name"`
+//		Age     int       `json:"age"`
+//		Created time.Time `json:"created"`
+//	}
+//
+//	huma.Register(api, op, func(ctx context.Context, input *struct {
+//		filter.Params[ThingFields]
+//	}) (*ListOutput, error) {
+//		// Use `input.Filter.Expr` and `input.Sort.Fields` to build a query for
+//		// your data store, or filter & sort in memory.
+//	})
+//
+// Invalid expressions result in a `422 Unprocessable Entity` response with the
+// parameter location and the offset of the problem within the expression.
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Kind is the type of a filterable field.
+type Kind string
+
+// Supported field kinds.
+const (
+	KindString   Kind = "string"
+	KindNumber   Kind = "number"
+	KindBoolean  Kind = "boolean"
+	KindDateTime Kind = "date-time"
+)
+
+// supports returns whether the operator can be used with this kind of field.
+func (k Kind) supports(op Op) bool {
+	switch op {
+	case Eq, Ne, In:
+		return true
+	case Gt, Ge, Lt, Le:
+		return k != KindBoolean
+	case Contains, StartsWith:
+		return k == KindString
+	}
+	return false
+}
+
+// Op is a comparison operator.
+type Op string
+
+// Supported comparison operators.
+const (
+	Eq         Op = "eq"
+	Ne         Op = "ne"
+	Gt         Op = "gt"
+	Ge         Op = "ge"
+	Lt         Op = "lt"
+	Le         Op = "le"
+	Contains   Op = "contains"
+	StartsWith Op = "startswith"
+	In         Op = "in"
+)
+
+var ops = []Op{Eq, Ne, Gt, Ge, Lt, Le, Contains, StartsWith, In}
+
+func validOp(op Op) bool {
+	return slices.Contains(ops, op)
+}
+
+// Expr is a node in a parsed filter expression. It is one of `*And`, `*Or`,
+// `*Not`, or `*Comparison`.
+type Expr interface {
+	expr()
+}
+
+// And matches when both sides match.
+type And struct {
+	Left  Expr
+	Right Expr
+}
+
+// Or matches when either side matches.
+type Or struct {
+	Left  Expr
+	Right Expr
+}
+
+// Not matches when the wrapped expression does not match.
+type Not struct {
+	Expr Expr
+}
+
+// Comparison compares a field to a value. The value's type depends on the
+// field's kind: `string`, `float64`, `bool`, or `time.Time`, or `nil` for
+// `null`. For the `in` operator it is a `[]any` of such values.
+type Comparison struct {
+	Field string
+	Op    Op
+	Value any
+}
+
+func (*And) expr()        {}
+func (*Or) expr()         {}
+func (*Not) expr()        {}
+func (*Comparison) expr() {}
+
+// SortField is a field to sort by.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// Error describes a problem with an expression at a byte offset.
+type Error struct {
+	Offset  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at offset %d", e.Message, e.Offset)
+}
+
+// field is an allowed field for filtering & sorting.
+type field struct {
+	name  string
+	kind  Kind
+	index []int
+}
+
+var fieldCache sync.Map
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// fieldsFor returns the allowed fields for the type by their JSON names.
+func fieldsFor(t reflect.Type) map[string]field {
+	if cached, ok := fieldCache.Load(t); ok {
+		return cached.(map[string]field)
+	}
+
+	fields := map[string]field{}
+	var collect func(t reflect.Type, index []int)
+	collect = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			idx := append(slices.Clone(index), i)
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				collect(f.Type, idx)
+				continue
+			}
+			if !f.IsExported() || f.Tag.Get("filter") == "-" {
+				continue
+			}
+			name := f.Name
+			if j := f.Tag.Get("json"); j != "" {
+				if j == "-" {
+					continue
+				}
+				if n, _, _ := strings.Cut(j, ","); n != "" {
+					name = n
+				}
+			}
+
+			typ := f.Type
+			for typ.Kind() == reflect.Pointer {
+				typ = typ.Elem()
+			}
+			var kind Kind
+			switch {
+			case typ == timeType:
+				kind = KindDateTime
+			case typ.Kind() == reflect.String:
+				kind = KindString
+			case typ.Kind() == reflect.Bool:
+				kind = KindBoolean
+			case typ.Kind() >= reflect.Int && typ.Kind() <= reflect.Float64:
+				kind = KindNumber
+			default:
+				continue
+			}
+			fields[name] = field{name: name, kind: kind, index: idx}
+		}
+	}
+	collect(t, nil)
+
+	fieldCache.Store(t, fields)
+	return fields
+}
+
+// describeFields returns a description of the allowed fields & their kinds.
+func describeFields(fields map[string]field) string {
+	names := make([]string, 0, len(fields))
+	for name, f := range fields {
+		names = append(names, "`"+name+"` ("+string(f.kind)+")")
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
+
+// Filter is a query parameter containing a filter expression for the fields
+// of `T`, like `name eq 'x' and (age gt 3 or not active eq true)`. Values are
+// `'quoted strings'` (double a single quote to escape it), numbers, `true`,
+// `false`, or `null`, and date-time values are RFC 3339 strings. The `in`
+// operator takes a list of values like `status in ('a', 'b')`.
+type Filter[T any] struct {
+	// Expr is the parsed expression, or nil if no filter was sent.
+	Expr Expr
+}
+
+// UnmarshalText parses and validates the filter expression.
+func (f *Filter[T]) UnmarshalText(text []byte) error {
+	f.Expr = nil
+	if strings.TrimSpace(string(text)) == "" {
+		return nil
+	}
+	tokens, err := lex(string(text))
+	if err != nil {
+		return err
+	}
+	p := &parser{tokens: tokens, fields: fieldsFor(reflect.TypeFor[T]())}
+	f.Expr, err = p.parse()
+	return err
+}
+
+// Schema documents the filter syntax and the allowed fields.
+func (f Filter[T]) Schema(r huma.Registry) *huma.Schema {
+	opNames := make([]string, len(ops))
+	for i, op := range ops {
+		opNames[i] = "`" + string(op) + "`"
+	}
+	return &huma.Schema{
+		Type: huma.TypeString,
+		Description: "Filter expression like `field op value`, combined with `and`, `or`, `not`, and parentheses. " +
+			"Operators: " + strings.Join(opNames, ", ") + ". " +
+			"Values are 'quoted strings', numbers, `true`, `false`, or `null`, and `in` takes a list like `('a', 'b')`. " +
+			"Fields: " + describeFields(fieldsFor(reflect.TypeFor[T]())) + ".",
+	}
+}
+
+// Match returns whether the value matches the filter expression, which is
+// useful for filtering in memory. A filter without an expression matches
+// everything.
+func (f Filter[T]) Match(v T) bool {
+	if f.Expr == nil {
+		return true
+	}
+	return match(f.Expr, reflect.ValueOf(v), fieldsFor(reflect.TypeFor[T]()))
+}
+
+func match(e Expr, v reflect.Value, fields map[string]field) bool {
+	switch e := e.(type) {
+	case *And:
+		return match(e.Left, v, fields) && match(e.Right, v, fields)
+	case *Or:
+		return match(e.Left, v, fields) || match(e.Right, v, fields)
+	case *Not:
+		return !match(e.Expr, v, fields)
+	case *Comparison:
+		actual := fieldValue(v, fields[e.Field])
+		if e.Op == In {
+			for _, value := range e.Value.([]any) {
+				if compare(actual, value) == 0 {
+					return true
+				}
+			}
+			return false
+		}
+		if e.Value == nil || actual == nil {
+			// Only equality checks are meaningful for `null`.
+			equal := e.Value == nil && actual == nil
+			return (e.Op == Eq && equal) || (e.Op == Ne && !equal)
+		}
+		switch e.Op {
+		case Contains:
+			return strings.Contains(actual.(string), e.Value.(string))
+		case StartsWith:
+			return strings.HasPrefix(actual.(string), e.Value.(string))
+		}
+		c := compare(actual, e.Value)
+		switch e.Op {
+		case Eq:
+			return c == 0
+		case Ne:
+			return c != 0
+		case Gt:
+			return c > 0
+		case Ge:
+			return c >= 0
+		case Lt:
+			return c < 0
+		case Le:
+			return c <= 0
+		}
+	}
+	return false
+}
+
+// fieldValue returns the field's value as the type used in comparisons, or
+// nil for nil pointers.
+func fieldValue(v reflect.Value, f field) any {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	for i, idx := range f.index {
+		if i > 0 {
+			for v.Kind() == reflect.Pointer {
+				if v.IsNil() {
+					return nil
+				}
+				v = v.Elem()
+			}
+		}
+		v = v.Field(idx)
+	}
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch f.kind {
+	case KindString:
+		return v.String()
+	case KindBoolean:
+		return v.Bool()
+	case KindDateTime:
+		return v.Interface().(time.Time)
+	}
+	switch {
+	case v.CanInt():
+		return float64(v.Int())
+	case v.CanUint():
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
+
+// compare returns -1, 0, or 1 comparing two values of the same kind. A nil
+// value sorts before any other value.
+func compare(a, b any) int {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		}
+		return 1
+	}
+	switch a := a.(type) {
+	case string:
+		return strings.Compare(a, b.(string))
+	case float64:
+		b := b.(float64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	case bool:
+		b := b.(bool)
+		switch {
+		case a == b:
+			return 0
+		case !a:
+			return -1
+		}
+		return 1
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	}
+	return 0
+}
+
+// Sort is a query parameter containing a comma-separated list of fields of
+// `T` to sort by, each optionally prefixed with `-` for descending order, like
+// `-created,name`.
+type Sort[T any] struct {
+	// Fields to sort by in order of precedence, or empty if no sort was sent.
+	Fields []SortField
+}
+
+// UnmarshalText parses and validates the sort fields.
+func (s *Sort[T]) UnmarshalText(text []byte) error {
+	s.Fields = nil
+	if strings.TrimSpace(string(text)) == "" {
+		return nil
+	}
+	fields, err := parseSort(string(text), fieldsFor(reflect.TypeFor[T]()))
+	if err != nil {
+		return err
+	}
+	s.Fields = fields
+	return nil
+}
+
+// Schema documents the sort syntax and the allowed fields.
+func (s Sort[T]) Schema(r huma.Registry) *huma.Schema {
+	return &huma.Schema{
+		Type:        huma.TypeString,
+		Description: "Comma-separated fields to sort by, prefixed with `-` for descending order. Fields: " + describeFields(fieldsFor(reflect.TypeFor[T]())) + ".",
+	}
+}
+
+// Compare two values using the sort fields, which is useful for sorting in
+// memory with e.g. `slices.SortFunc`.
+func (s Sort[T]) Compare(a, b T) int {
+	fields := fieldsFor(reflect.TypeFor[T]())
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	for _, sf := range s.Fields {
+		f := fields[sf.Field]
+		if c := compare(fieldValue(va, f), fieldValue(vb, f)); c != 0 {
+			if sf.Desc {
+				return -c
+			}
+			return c
+		}
+	}
+	return 0
+}
+
+// Params provides the `filter` and `sort` query parameters for the fields of
+// `T`. Embed it in your operation's input struct.
+type Params[T any] struct {
+	Filter Filter[T] `query:"filter"`
+	Sort   Sort[T]   `query:"sort"`
+}
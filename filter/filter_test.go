@@ -0,0 +1,157 @@
+package filter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/filter"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+type Thing struct {
+	Name    string    `json:"name"`
+	Age     int       `json:"age"`
+	Active  bool      `json:"active"`
+	Created time.Time `json:"created"`
+	Owner   *string   `json:"owner,omitempty"`
+	Secret  string    `json:"secret" filter:"-"`
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+var things = []Thing{
+	{Name: "a", Age: 1, Active: true, Created: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Owner: ptr("bob")},
+	{Name: "b", Age: 5, Active: false, Created: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	{Name: "O'Brien", Age: 10, Active: true, Created: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+}
+
+func TestParse(t *testing.T) {
+	var f filter.Filter[Thing]
+	require.NoError(t, f.UnmarshalText([]byte(`name eq 'x' and (age gt 3 or not active eq true)`)))
+	assert.Equal(t, &filter.And{
+		Left: &filter.Comparison{Field: "name", Op: filter.Eq, Value: "x"},
+		Right: &filter.Or{
+			Left:  &filter.Comparison{Field: "age", Op: filter.Gt, Value: 3.0},
+			Right: &filter.Not{Expr: &filter.Comparison{Field: "active", Op: filter.Eq, Value: true}},
+		},
+	}, f.Expr)
+
+	require.NoError(t, f.UnmarshalText([]byte(`name in ('a', 'O''Brien') or owner eq null`)))
+	assert.Equal(t, &filter.Or{
+		Left:  &filter.Comparison{Field: "name", Op: filter.In, Value: []any{"a", "O'Brien"}},
+		Right: &filter.Comparison{Field: "owner", Op: filter.Eq, Value: nil},
+	}, f.Expr)
+
+	for _, tc := range []struct {
+		input string
+		err   string
+	}{
+		{`secret eq 'x'`, `unknown field "secret" at offset 0`},
+		{`name eq 'x' and foo eq 1`, `unknown field "foo" at offset 16`},
+		{`name is 'x'`, `expected operator but found "is" at offset 5`},
+		{`age eq 'x'`, `expected number value for field "age" but found 'x' at offset 7`},
+		{`active gt true`, `operator gt is not supported for boolean field "active" at offset 7`},
+		{`age contains 1`, `operator contains is not supported for number field "age" at offset 4`},
+		{`created lt 'yesterday'`, `invalid RFC 3339 date-time 'yesterday' at offset 11`},
+		{`name eq 'x`, `unterminated string at offset 8`},
+		{`(name eq 'x'`, `expected ) but found end of expression at offset 12`},
+		{`name eq 'x' age eq 1`, `unexpected "age" at offset 12`},
+		{`name in ('a' 'b')`, `expected , or ) but found 'b' at offset 13`},
+		{`name eq $`, `unexpected character '$' at offset 8`},
+	} {
+		err := f.UnmarshalText([]byte(tc.input))
+		assert.EqualError(t, err, tc.err, tc.input)
+	}
+}
+
+func TestMatchAndSort(t *testing.T) {
+	filtered := func(expr string) []string {
+		var f filter.Filter[Thing]
+		require.NoError(t, f.UnmarshalText([]byte(expr)))
+		names := []string{}
+		for _, thing := range things {
+			if f.Match(thing) {
+				names = append(names, thing.Name)
+			}
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"a", "b", "O'Brien"}, filtered(""))
+	assert.Equal(t, []string{"b", "O'Brien"}, filtered(`age ge 5`))
+	assert.Equal(t, []string{"a", "O'Brien"}, filtered(`active eq true and not name startswith 'x'`))
+	assert.Equal(t, []string{"O'Brien"}, filtered(`name contains 'Bri' or age lt 0`))
+	assert.Equal(t, []string{"b", "O'Brien"}, filtered(`created gt '2024-01-15T00:00:00Z'`))
+	assert.Equal(t, []string{"a", "b"}, filtered(`age in (1, 5)`))
+	assert.Equal(t, []string{"b", "O'Brien"}, filtered(`owner eq null`))
+	assert.Equal(t, []string{"a"}, filtered(`owner ne null and owner eq 'bob'`))
+
+	var s filter.Sort[Thing]
+	require.NoError(t, s.UnmarshalText([]byte("-active, created")))
+	assert.Equal(t, []filter.SortField{{Field: "active", Desc: true}, {Field: "created"}}, s.Fields)
+
+	sorted := slices.Clone(things)
+	slices.SortFunc(sorted, s.Compare)
+	assert.Equal(t, "a", sorted[0].Name)
+	assert.Equal(t, "O'Brien", sorted[1].Name)
+	assert.Equal(t, "b", sorted[2].Name)
+
+	assert.EqualError(t, s.UnmarshalText([]byte("name,-secret")), `unknown field "secret" at offset 6`)
+	assert.EqualError(t, s.UnmarshalText([]byte("name,")), `expected field name at offset 5`)
+}
+
+func TestParams(t *testing.T) {
+	_, api := humatest.New(t)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-things",
+		Method:      http.MethodGet,
+		Path:        "/things",
+	}, func(ctx context.Context, input *struct {
+		filter.Params[Thing]
+	}) (*struct{ Body []string }, error) {
+		matches := []Thing{}
+		for _, thing := range things {
+			if input.Filter.Match(thing) {
+				matches = append(matches, thing)
+			}
+		}
+		slices.SortFunc(matches, input.Sort.Compare)
+		names := []string{}
+		for _, thing := range matches {
+			names = append(names, thing.Name)
+		}
+		return &struct{ Body []string }{Body: names}, nil
+	})
+
+	// The parameters document the syntax and allowed fields.
+	params := api.OpenAPI().Paths["/things"].Get.Parameters
+	require.Len(t, params, 2)
+	assert.Equal(t, "filter", params[0].Name)
+	assert.Contains(t, params[0].Schema.Description, "Fields: `active` (boolean), `age` (number), `created` (date-time), `name` (string), `owner` (string).")
+	assert.Equal(t, "sort", params[1].Name)
+	assert.Contains(t, params[1].Schema.Description, "prefixed with `-` for descending order")
+
+	resp := api.Get("/things?filter=" + url.QueryEscape("age gt 1") + "&sort=-age")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `["O'Brien", "b"]`, resp.Body.String())
+
+	resp = api.Get("/things?filter=" + url.QueryEscape("age gt 1 and color eq 'red'"))
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	var model huma.ErrorModel
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &model))
+	require.NotEmpty(t, model.Errors)
+	assert.Equal(t, "query.filter", model.Errors[0].Location)
+	assert.Equal(t, `invalid value: unknown field "color" at offset 13`, model.Errors[0].Message)
+}
@@ -0,0 +1,309 @@
+package filter
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return "'" + t.value + "'"
+	}
+	return strconv.Quote(t.value)
+}
+
+// lex splits the expression into tokens.
+func lex(input string) ([]token, error) {
+	tokens := []token{}
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokRParen, ")", i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{tokComma, ",", i})
+			i++
+		case c == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(input) {
+					return nil, &Error{Offset: start, Message: "unterminated string"}
+				}
+				if input[i] == '\'' {
+					if i+1 < len(input) && input[i+1] == '\'' {
+						// Escaped quote, e.g. 'O''Brien'.
+						sb.WriteByte('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				sb.WriteByte(input[i])
+				i++
+			}
+			tokens = append(tokens, token{tokString, sb.String(), start})
+		case c == '-' || c == '.' || (c >= '0' && c <= '9'):
+			start := i
+			i++
+			for i < len(input) && (input[i] == '.' || input[i] == 'e' || input[i] == 'E' || input[i] == '+' || input[i] == '-' || (input[i] >= '0' && input[i] <= '9')) {
+				i++
+			}
+			tokens = append(tokens, token{tokNumber, input[start:i], start})
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			start := i
+			for i < len(input) && (input[i] == '_' || input[i] == '-' || input[i] == '.' || (input[i] >= 'a' && input[i] <= 'z') || (input[i] >= 'A' && input[i] <= 'Z') || (input[i] >= '0' && input[i] <= '9')) {
+				i++
+			}
+			tokens = append(tokens, token{tokIdent, input[start:i], start})
+		default:
+			return nil, &Error{Offset: i, Message: "unexpected character " + strconv.QuoteRune(rune(c))}
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(input)}), nil
+}
+
+// parser is a recursive descent parser for filter expressions:
+//
+//	or         = and *("or" and)
+//	and        = unary *("and" unary)
+//	unary      = "not" unary / "(" or ")" / comparison
+//	comparison = field op value / field "in" "(" value *("," value) ")"
+type parser struct {
+	tokens []token
+	pos    int
+	fields map[string]field
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) keyword(kw string) bool {
+	if t := p.peek(); t.kind == tokIdent && t.value == kw {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parse() (Expr, error) {
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, &Error{Offset: t.pos, Message: "unexpected " + t.String()}
+	}
+	return e, nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.keyword("not") {
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: e}, nil
+	}
+
+	if p.peek().kind == tokLParen {
+		p.next()
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, &Error{Offset: t.pos, Message: "expected ) but found " + t.String()}
+		}
+		return e, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	t := p.next()
+	if t.kind != tokIdent {
+		return nil, &Error{Offset: t.pos, Message: "expected field name but found " + t.String()}
+	}
+	f, ok := p.fields[t.value]
+	if !ok {
+		return nil, &Error{Offset: t.pos, Message: "unknown field " + strconv.Quote(t.value)}
+	}
+
+	opTok := p.next()
+	op := Op(opTok.value)
+	if opTok.kind != tokIdent || !validOp(op) {
+		return nil, &Error{Offset: opTok.pos, Message: "expected operator but found " + opTok.String()}
+	}
+	if !f.kind.supports(op) {
+		return nil, &Error{Offset: opTok.pos, Message: "operator " + string(op) + " is not supported for " + string(f.kind) + " field " + strconv.Quote(f.name)}
+	}
+
+	c := &Comparison{Field: f.name, Op: op}
+	if op == In {
+		if t := p.next(); t.kind != tokLParen {
+			return nil, &Error{Offset: t.pos, Message: "expected ( but found " + t.String()}
+		}
+		values := []any{}
+		for {
+			v, err := p.parseValue(f)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+			t := p.next()
+			if t.kind == tokRParen {
+				break
+			}
+			if t.kind != tokComma {
+				return nil, &Error{Offset: t.pos, Message: "expected , or ) but found " + t.String()}
+			}
+		}
+		c.Value = values
+		return c, nil
+	}
+
+	v, err := p.parseValue(f)
+	if err != nil {
+		return nil, err
+	}
+	c.Value = v
+	return c, nil
+}
+
+// parseValue parses a literal value, checking that it matches the field type.
+func (p *parser) parseValue(f field) (any, error) {
+	t := p.next()
+	if t.kind == tokIdent && t.value == "null" {
+		return nil, nil
+	}
+
+	mismatch := &Error{Offset: t.pos, Message: "expected " + string(f.kind) + " value for field " + strconv.Quote(f.name) + " but found " + t.String()}
+	switch f.kind {
+	case KindString:
+		if t.kind == tokString {
+			return t.value, nil
+		}
+	case KindNumber:
+		if t.kind == tokNumber {
+			n, err := strconv.ParseFloat(t.value, 64)
+			if err != nil {
+				return nil, &Error{Offset: t.pos, Message: "invalid number " + t.String()}
+			}
+			return n, nil
+		}
+	case KindBoolean:
+		if t.kind == tokIdent && (t.value == "true" || t.value == "false") {
+			return t.value == "true", nil
+		}
+	case KindDateTime:
+		if t.kind == tokString {
+			ts, err := time.Parse(time.RFC3339Nano, t.value)
+			if err != nil {
+				return nil, &Error{Offset: t.pos, Message: "invalid RFC 3339 date-time " + t.String()}
+			}
+			return ts, nil
+		}
+	}
+	return nil, mismatch
+}
+
+// parseSort parses a comma-separated list of fields, each optionally prefixed
+// by `-` for descending or `+` for ascending order.
+func parseSort(input string, fields map[string]field) ([]SortField, error) {
+	result := []SortField{}
+	offset := 0
+	for _, part := range strings.Split(input, ",") {
+		pos := offset
+		offset += len(part) + 1
+
+		trimmed := strings.TrimLeft(part, " ")
+		pos += len(part) - len(trimmed)
+		trimmed = strings.TrimRight(trimmed, " ")
+
+		desc := false
+		if strings.HasPrefix(trimmed, "-") || strings.HasPrefix(trimmed, "+") {
+			desc = trimmed[0] == '-'
+			trimmed = trimmed[1:]
+			pos++
+		}
+		if trimmed == "" {
+			return nil, &Error{Offset: pos, Message: "expected field name"}
+		}
+		if _, ok := fields[trimmed]; !ok {
+			return nil, &Error{Offset: pos, Message: "unknown field " + strconv.Quote(trimmed)}
+		}
+		result = append(result, SortField{Field: trimmed, Desc: desc})
+	}
+	return result, nil
+}
@@ -0,0 +1,585 @@
+// Package jsonapi provides support for JSON:API (`application/vnd.api+json`)
+// documents. Resources are plain Go structs with a `jsonapi` tag on the ID
+// field giving the resource type, and optional `jsonapi` tags on relationship
+// fields. All other fields are attributes named via their `json` tags.
+//
+//	type Article struct {
+//		ID     string  `json:"id" jsonapi:"primary,articles"`
+//		Title  string  `json:"title"`
+//		Author *Person `json:"author" jsonapi:"relation"`
+//	}
+//
+// Use `Document[T]` or `Collection[T]` as the request or response body to
+// wrap resources in the JSON:API envelope. The generated OpenAPI schema
+// describes the envelope rather than the plain struct, and responses use the
+// `application/vnd.api+json` content type. Importing this package adds the
+// JSON:API media type to `huma.DefaultFormats`.
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// MediaType is the JSON:API media type.
+const MediaType = "application/vnd.api+json"
+
+func init() {
+	huma.DefaultFormats[MediaType] = huma.DefaultJSONFormat
+}
+
+// Document is a JSON:API document with a single primary resource. A nil
+// pointer resource is serialized as `null`.
+type Document[T any] struct {
+	Data     T
+	Included []any
+	Meta     map[string]any
+	Links    map[string]string
+}
+
+// Collection is a JSON:API document with a list of primary resources.
+type Collection[T any] struct {
+	Data     []T
+	Included []any
+	Meta     map[string]any
+	Links    map[string]string
+}
+
+// envelope is the serialized form of a document.
+type envelope struct {
+	Data     any               `json:"data"`
+	Included []*resourceObject `json:"included,omitempty"`
+	Meta     map[string]any    `json:"meta,omitempty"`
+	Links    map[string]string `json:"links,omitempty"`
+}
+
+// rawEnvelope is used to decode incoming documents.
+type rawEnvelope struct {
+	Data  json.RawMessage   `json:"data"`
+	Meta  map[string]any    `json:"meta,omitempty"`
+	Links map[string]string `json:"links,omitempty"`
+}
+
+type resourceObject struct {
+	Type          string                     `json:"type"`
+	ID            string                     `json:"id,omitempty"`
+	Attributes    map[string]json.RawMessage `json:"attributes,omitempty"`
+	Relationships map[string]*relationship   `json:"relationships,omitempty"`
+}
+
+type relationship struct {
+	Data json.RawMessage `json:"data"`
+}
+
+type identifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// MarshalJSON writes the document in the JSON:API format.
+func (d Document[T]) MarshalJSON() ([]byte, error) {
+	data, err := marshalResource(reflect.ValueOf(&d.Data).Elem())
+	if err != nil {
+		return nil, err
+	}
+	return marshalEnvelope(data, d.Included, d.Meta, d.Links)
+}
+
+// UnmarshalJSON reads a JSON:API document.
+func (d *Document[T]) UnmarshalJSON(b []byte) error {
+	var env rawEnvelope
+	if err := json.Unmarshal(b, &env); err != nil {
+		return err
+	}
+	d.Meta, d.Links = env.Meta, env.Links
+	return unmarshalResource(env.Data, reflect.ValueOf(&d.Data).Elem())
+}
+
+// ContentType returns the JSON:API media type for responses.
+func (d Document[T]) ContentType(ct string) string {
+	return contentType(ct)
+}
+
+// Schema returns the schema of the JSON:API envelope.
+func (d Document[T]) Schema(r huma.Registry) *huma.Schema {
+	return envelopeSchema(resourceSchema(r, reflect.TypeFor[T]()))
+}
+
+// MarshalJSON writes the document in the JSON:API format.
+func (c Collection[T]) MarshalJSON() ([]byte, error) {
+	data := make([]any, 0, len(c.Data))
+	for i := range c.Data {
+		r, err := marshalResource(reflect.ValueOf(&c.Data[i]).Elem())
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, r)
+	}
+	return marshalEnvelope(data, c.Included, c.Meta, c.Links)
+}
+
+// UnmarshalJSON reads a JSON:API document.
+func (c *Collection[T]) UnmarshalJSON(b []byte) error {
+	var env rawEnvelope
+	if err := json.Unmarshal(b, &env); err != nil {
+		return err
+	}
+	c.Meta, c.Links = env.Meta, env.Links
+	var items []json.RawMessage
+	if err := json.Unmarshal(env.Data, &items); err != nil {
+		return err
+	}
+	c.Data = make([]T, len(items))
+	for i, item := range items {
+		if err := unmarshalResource(item, reflect.ValueOf(&c.Data[i]).Elem()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ContentType returns the JSON:API media type for responses.
+func (c Collection[T]) ContentType(ct string) string {
+	return contentType(ct)
+}
+
+// Schema returns the schema of the JSON:API envelope.
+func (c Collection[T]) Schema(r huma.Registry) *huma.Schema {
+	return envelopeSchema(&huma.Schema{
+		Type:  huma.TypeArray,
+		Items: resourceSchema(r, reflect.TypeFor[T]()),
+	})
+}
+
+func contentType(ct string) string {
+	if ct == "application/json" {
+		return MediaType
+	}
+	return ct
+}
+
+func marshalEnvelope(data any, included []any, meta map[string]any, links map[string]string) ([]byte, error) {
+	env := envelope{Data: data, Meta: meta, Links: links}
+	for _, inc := range included {
+		r, err := marshalResource(reflect.ValueOf(inc))
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			env.Included = append(env.Included, r)
+		}
+	}
+	return json.Marshal(env)
+}
+
+// fieldKind describes how a struct field maps to a resource.
+type fieldKind int
+
+const (
+	kindAttribute fieldKind = iota
+	kindPrimary
+	kindRelation
+)
+
+type fieldInfo struct {
+	index     []int
+	name      string
+	omitEmpty bool
+	kind      fieldKind
+}
+
+type resourceInfo struct {
+	typ     string
+	primary *fieldInfo
+	fields  []*fieldInfo
+}
+
+var infoCache sync.Map
+
+// getInfo returns the resource information for a struct type.
+func getInfo(t reflect.Type) (*resourceInfo, error) {
+	if cached, ok := infoCache.Load(t); ok {
+		return cached.(*resourceInfo), nil
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("jsonapi: %v is not a struct", t)
+	}
+
+	info := &resourceInfo{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fi := &fieldInfo{index: f.Index, name: name, omitEmpty: strings.Contains(opts, "omitempty")}
+
+		tag := f.Tag.Get("jsonapi")
+		kind, value, _ := strings.Cut(tag, ",")
+		switch kind {
+		case "primary":
+			fi.kind = kindPrimary
+			info.typ = value
+			info.primary = fi
+		case "relation":
+			fi.kind = kindRelation
+			if value != "" {
+				fi.name = value
+			}
+		case "", "attr":
+			if value != "" {
+				fi.name = value
+			}
+		default:
+			return nil, fmt.Errorf("jsonapi: unknown tag %q on %v.%s", tag, t, f.Name)
+		}
+		info.fields = append(info.fields, fi)
+	}
+	if info.primary == nil || info.typ == "" {
+		return nil, fmt.Errorf(`jsonapi: %v has no field tagged with jsonapi:"primary,{type}"`, t)
+	}
+
+	infoCache.Store(t, info)
+	return info, nil
+}
+
+// formatID converts an ID field into its string form.
+func formatID(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// parseID sets an ID field from its string form.
+func parseID(id string, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(id)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return fmt.Errorf("jsonapi: invalid id %q: %w", id, err)
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return fmt.Errorf("jsonapi: invalid id %q: %w", id, err)
+		}
+		v.SetUint(i)
+	default:
+		return fmt.Errorf("jsonapi: unsupported id type %v", v.Type())
+	}
+	return nil
+}
+
+// identify returns the resource identifier for a related resource.
+func identify(v reflect.Value) (*identifier, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	info, err := getInfo(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	return &identifier{Type: info.typ, ID: formatID(v.FieldByIndex(info.primary.index))}, nil
+}
+
+// marshalResource converts a struct into a JSON:API resource object. Nil
+// pointers return nil.
+func marshalResource(v reflect.Value) (*resourceObject, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	info, err := getInfo(v.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	r := &resourceObject{
+		Type: info.typ,
+		ID:   formatID(v.FieldByIndex(info.primary.index)),
+	}
+	for _, f := range info.fields {
+		fv := v.FieldByIndex(f.index)
+		switch f.kind {
+		case kindAttribute:
+			if f.omitEmpty && fv.IsZero() {
+				continue
+			}
+			b, err := json.Marshal(fv.Interface())
+			if err != nil {
+				return nil, err
+			}
+			if r.Attributes == nil {
+				r.Attributes = map[string]json.RawMessage{}
+			}
+			r.Attributes[f.name] = b
+		case kindRelation:
+			if f.omitEmpty && fv.IsZero() {
+				continue
+			}
+			var linkage any
+			if fv.Kind() == reflect.Slice {
+				ids := make([]*identifier, 0, fv.Len())
+				for i := 0; i < fv.Len(); i++ {
+					id, err := identify(fv.Index(i))
+					if err != nil {
+						return nil, err
+					}
+					if id != nil {
+						ids = append(ids, id)
+					}
+				}
+				linkage = ids
+			} else {
+				id, err := identify(fv)
+				if err != nil {
+					return nil, err
+				}
+				if id != nil {
+					linkage = id
+				}
+			}
+			b, err := json.Marshal(linkage)
+			if err != nil {
+				return nil, err
+			}
+			if r.Relationships == nil {
+				r.Relationships = map[string]*relationship{}
+			}
+			r.Relationships[f.name] = &relationship{Data: b}
+		}
+	}
+	return r, nil
+}
+
+// unmarshalResource reads a JSON:API resource object into a struct.
+func unmarshalResource(data json.RawMessage, v reflect.Value) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	info, err := getInfo(v.Type())
+	if err != nil {
+		return err
+	}
+
+	var r resourceObject
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+	if r.Type != info.typ {
+		return fmt.Errorf("jsonapi: expected resource type %q but got %q", info.typ, r.Type)
+	}
+	if r.ID != "" {
+		if err := parseID(r.ID, v.FieldByIndex(info.primary.index)); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range info.fields {
+		fv := v.FieldByIndex(f.index)
+		switch f.kind {
+		case kindAttribute:
+			if raw, ok := r.Attributes[f.name]; ok {
+				if err := json.Unmarshal(raw, fv.Addr().Interface()); err != nil {
+					return fmt.Errorf("jsonapi: attribute %s: %w", f.name, err)
+				}
+			}
+		case kindRelation:
+			rel := r.Relationships[f.name]
+			if rel == nil || len(rel.Data) == 0 || string(rel.Data) == "null" {
+				continue
+			}
+			if fv.Kind() == reflect.Slice {
+				var ids []*identifier
+				if err := json.Unmarshal(rel.Data, &ids); err != nil {
+					return fmt.Errorf("jsonapi: relationship %s: %w", f.name, err)
+				}
+				s := reflect.MakeSlice(fv.Type(), len(ids), len(ids))
+				for i, id := range ids {
+					if err := setIdentifier(id, s.Index(i)); err != nil {
+						return fmt.Errorf("jsonapi: relationship %s: %w", f.name, err)
+					}
+				}
+				fv.Set(s)
+			} else {
+				var id identifier
+				if err := json.Unmarshal(rel.Data, &id); err != nil {
+					return fmt.Errorf("jsonapi: relationship %s: %w", f.name, err)
+				}
+				if err := setIdentifier(&id, fv); err != nil {
+					return fmt.Errorf("jsonapi: relationship %s: %w", f.name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// setIdentifier sets the ID of a related resource from its identifier.
+func setIdentifier(id *identifier, v reflect.Value) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	info, err := getInfo(v.Type())
+	if err != nil {
+		return err
+	}
+	if id.Type != info.typ {
+		return fmt.Errorf("expected resource type %q but got %q", info.typ, id.Type)
+	}
+	return parseID(id.ID, v.FieldByIndex(info.primary.index))
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t
+}
+
+// envelopeSchema returns the schema of a JSON:API document with the given
+// primary data schema.
+func envelopeSchema(data *huma.Schema) *huma.Schema {
+	return &huma.Schema{
+		Type: huma.TypeObject,
+		Properties: map[string]*huma.Schema{
+			"data": data,
+			"included": {
+				Type:        huma.TypeArray,
+				Description: "Related resources included in the document",
+				Items:       &huma.Schema{Type: huma.TypeObject},
+			},
+			"meta":  {Type: huma.TypeObject, AdditionalProperties: true},
+			"links": {Type: huma.TypeObject, AdditionalProperties: &huma.Schema{Type: huma.TypeString}},
+		},
+		Required: []string{"data"},
+	}
+}
+
+// resourceSchema returns a reference to the schema of a JSON:API resource
+// object for the struct type, registering it if needed.
+func resourceSchema(r huma.Registry, t reflect.Type) *huma.Schema {
+	t = deref(t)
+	info, err := getInfo(t)
+	if err != nil {
+		panic(err)
+	}
+
+	// Register the plain struct to get its attribute schemas and the registry's
+	// reference prefix.
+	ref := r.Schema(t, true, "").Ref
+	name := ref[strings.LastIndex(ref, "/")+1:] + "Resource"
+	if existing := r.Map()[name]; existing != nil {
+		return &huma.Schema{Ref: ref[:strings.LastIndex(ref, "/")+1] + name}
+	}
+	plain := r.SchemaFromRef(ref)
+
+	attributes := &huma.Schema{
+		Type:                 huma.TypeObject,
+		Properties:           map[string]*huma.Schema{},
+		AdditionalProperties: false,
+	}
+	relationships := &huma.Schema{
+		Type:                 huma.TypeObject,
+		Properties:           map[string]*huma.Schema{},
+		AdditionalProperties: false,
+	}
+	for _, f := range info.fields {
+		sf := t.FieldByIndex(f.index)
+		jsonName, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if jsonName == "" {
+			jsonName = sf.Name
+		}
+		required := false
+		for _, req := range plain.Required {
+			required = required || req == jsonName
+		}
+		switch f.kind {
+		case kindAttribute:
+			attributes.Properties[f.name] = plain.Properties[jsonName]
+			if required {
+				attributes.Required = append(attributes.Required, f.name)
+			}
+		case kindRelation:
+			related, err := getInfo(deref(sf.Type))
+			if err != nil {
+				panic(err)
+			}
+			linkage := &huma.Schema{
+				Type: huma.TypeObject,
+				Properties: map[string]*huma.Schema{
+					"type": {Type: huma.TypeString, Enum: []any{related.typ}},
+					"id":   {Type: huma.TypeString},
+				},
+				Required: []string{"type", "id"},
+			}
+			if sf.Type.Kind() == reflect.Slice {
+				linkage = &huma.Schema{Type: huma.TypeArray, Items: linkage}
+			} else {
+				linkage.Nullable = true
+			}
+			relationships.Properties[f.name] = &huma.Schema{
+				Type:       huma.TypeObject,
+				Properties: map[string]*huma.Schema{"data": linkage},
+				Required:   []string{"data"},
+			}
+			if required {
+				relationships.Required = append(relationships.Required, f.name)
+			}
+		}
+	}
+
+	s := &huma.Schema{
+		Type:  huma.TypeObject,
+		Title: info.typ + " resource",
+		Properties: map[string]*huma.Schema{
+			"type": {Type: huma.TypeString, Enum: []any{info.typ}},
+			"id":   {Type: huma.TypeString, Description: "Resource ID, which may be omitted when creating a resource"},
+		},
+		Required: []string{"type"},
+	}
+	if len(attributes.Properties) > 0 {
+		s.Properties["attributes"] = attributes
+	}
+	if len(relationships.Properties) > 0 {
+		s.Properties["relationships"] = relationships
+	}
+	s.PrecomputeMessages()
+	r.Map()[name] = s
+	return &huma.Schema{Ref: ref[:strings.LastIndex(ref, "/")+1] + name}
+}
@@ -0,0 +1,151 @@
+package jsonapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/formats/jsonapi"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+type Person struct {
+	ID   int    `json:"id" jsonapi:"primary,people"`
+	Name string `json:"name"`
+}
+
+type Article struct {
+	ID       string    `json:"id" jsonapi:"primary,articles"`
+	Title    string    `json:"title"`
+	Body     string    `json:"body,omitempty"`
+	Author   *Person   `json:"author" jsonapi:"relation"`
+	Comments []Comment `json:"comments,omitempty" jsonapi:"relation"`
+}
+
+type Comment struct {
+	ID   string `json:"id" jsonapi:"primary,comments"`
+	Text string `json:"text"`
+}
+
+func TestDocument(t *testing.T) {
+	doc := jsonapi.Document[Article]{
+		Data: Article{
+			ID:       "1",
+			Title:    "Hello",
+			Author:   &Person{ID: 9, Name: "Alice"},
+			Comments: []Comment{{ID: "c1"}},
+		},
+		Included: []any{&Person{ID: 9, Name: "Alice"}},
+		Meta:     map[string]any{"count": 1},
+	}
+	b, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"data": {
+			"type": "articles",
+			"id": "1",
+			"attributes": {"title": "Hello"},
+			"relationships": {
+				"author": {"data": {"type": "people", "id": "9"}},
+				"comments": {"data": [{"type": "comments", "id": "c1"}]}
+			}
+		},
+		"included": [{"type": "people", "id": "9", "attributes": {"name": "Alice"}}],
+		"meta": {"count": 1}
+	}`, string(b))
+
+	var decoded jsonapi.Document[Article]
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, Article{
+		ID:       "1",
+		Title:    "Hello",
+		Author:   &Person{ID: 9},
+		Comments: []Comment{{ID: "c1"}},
+	}, decoded.Data)
+
+	err = json.Unmarshal([]byte(`{"data": {"type": "people", "id": "1"}}`), &decoded)
+	assert.ErrorContains(t, err, `expected resource type "articles"`)
+
+	b, err = json.Marshal(jsonapi.Document[*Article]{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data": null}`, string(b))
+}
+
+func TestCollectionAPI(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	articles := []Article{{ID: "1", Title: "First", Author: &Person{ID: 1}}}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-articles",
+		Method:      http.MethodGet,
+		Path:        "/articles",
+	}, func(ctx context.Context, input *struct{}) (*struct {
+		Body jsonapi.Collection[Article]
+	}, error) {
+		resp := &struct {
+			Body jsonapi.Collection[Article]
+		}{}
+		resp.Body.Data = articles
+		return resp, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-article",
+		Method:        http.MethodPost,
+		Path:          "/articles",
+		DefaultStatus: http.StatusCreated,
+	}, func(ctx context.Context, input *struct {
+		Body jsonapi.Document[Article]
+	}) (*struct {
+		Body jsonapi.Document[Article]
+	}, error) {
+		article := input.Body.Data
+		article.ID = "2"
+		articles = append(articles, article)
+		return &struct {
+			Body jsonapi.Document[Article]
+		}{Body: jsonapi.Document[Article]{Data: article}}, nil
+	})
+
+	// The OpenAPI describes the envelope.
+	oapi := api.OpenAPI()
+	resp200 := oapi.Paths["/articles"].Get.Responses["200"]
+	require.Contains(t, resp200.Content, jsonapi.MediaType)
+	resource := oapi.Components.Schemas.Map()["ArticleResource"]
+	require.NotNil(t, resource)
+	assert.Equal(t, []any{"articles"}, resource.Properties["type"].Enum)
+	assert.Contains(t, resource.Properties["attributes"].Properties, "title")
+	assert.NotContains(t, resource.Properties["attributes"].Properties, "author")
+	assert.Contains(t, resource.Properties["relationships"].Properties, "author")
+
+	resp := api.Post("/articles", "Content-Type: application/vnd.api+json", strings.NewReader(`{
+		"data": {
+			"type": "articles",
+			"attributes": {"title": "Second"},
+			"relationships": {"author": {"data": {"type": "people", "id": "1"}}}
+		}
+	}`))
+	assert.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	assert.Equal(t, jsonapi.MediaType, resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Body.String(), `"id":"2"`)
+
+	// Attributes are validated against the schema.
+	resp = api.Post("/articles", "Content-Type: application/vnd.api+json", strings.NewReader(`{
+		"data": {"type": "articles", "attributes": {"body": "missing title"}}
+	}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "body.data.attributes")
+
+	resp = api.Get("/articles")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Len(t, body["data"], 2)
+}
@@ -0,0 +1,89 @@
+// Package strings provides validators for common string formats which are not
+// built into Huma. Importing this package registers them via
+// `huma.RegisterFormat`, so they can be used with the `format` field tag:
+//
+//	import _ "github.com/danielgtaylor/huma/v2/formats/strings"
+//
+//	type Payment struct {
+//		Account string `json:"account" format:"iban"`
+//		Phone   string `json:"phone" format:"e164"`
+//	}
+//
+// The following formats are available:
+//
+//   - `iban`: International Bank Account Number without spaces
+//   - `e164`: E.164 phone number like `+15555550123`
+//   - `ulid`: Universally Unique Lexicographically Sortable Identifier
+//   - `semver`: Semantic version like `1.2.3-beta.1`
+package strings
+
+import (
+	"errors"
+	"math/big"
+	"regexp"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+var (
+	rxIBAN   = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`)
+	rxE164   = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+	rxULID   = regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Za-hjkmnp-tv-z]{25}$`)
+	rxSemver = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+)
+
+// ValidateIBAN checks that the value is an IBAN with a valid check digit.
+func ValidateIBAN(value string) error {
+	if !rxIBAN.MatchString(value) {
+		return errors.New("invalid IBAN structure")
+	}
+
+	// Move the country code & check digits to the end, convert letters to
+	// numbers (A=10, B=11, ...), and check the remainder, see ISO 13616.
+	digits := make([]byte, 0, len(value)*2)
+	for _, c := range value[4:] + value[:4] {
+		if c >= 'A' && c <= 'Z' {
+			n := c - 'A' + 10
+			digits = append(digits, byte('0'+n/10), byte('0'+n%10))
+		} else {
+			digits = append(digits, byte(c))
+		}
+	}
+	n, _ := new(big.Int).SetString(string(digits), 10)
+	if new(big.Int).Mod(n, big.NewInt(97)).Int64() != 1 {
+		return errors.New("invalid IBAN check digits")
+	}
+	return nil
+}
+
+// ValidateE164 checks that the value is an E.164 phone number.
+func ValidateE164(value string) error {
+	if !rxE164.MatchString(value) {
+		return errors.New("expected + followed by up to 15 digits")
+	}
+	return nil
+}
+
+// ValidateULID checks that the value is a ULID.
+func ValidateULID(value string) error {
+	if !rxULID.MatchString(value) {
+		return errors.New("expected 26 Crockford base32 characters")
+	}
+	return nil
+}
+
+// ValidateSemver checks that the value is a semantic version, see
+// https://semver.org/.
+func ValidateSemver(value string) error {
+	if !rxSemver.MatchString(value) {
+		return errors.New("expected MAJOR.MINOR.PATCH version")
+	}
+	return nil
+}
+
+func init() {
+	huma.RegisterFormat("iban", ValidateIBAN)
+	huma.RegisterFormat("e164", ValidateE164)
+	huma.RegisterFormat("ulid", ValidateULID)
+	huma.RegisterFormat("semver", ValidateSemver)
+}
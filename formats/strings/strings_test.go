@@ -0,0 +1,40 @@
+package strings
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func TestFormats(t *testing.T) {
+	for _, item := range []struct {
+		format string
+		valid  []string
+		bad    []string
+	}{
+		{"iban", []string{"GB82WEST12345698765432", "DE89370400440532013000"}, []string{"GB82WEST12345698765431", "GB82 WEST 1234 5698 7654 32", "1234"}},
+		{"e164", []string{"+15555550123", "+442071838750"}, []string{"5555550123", "+0123", "+1234567890123456"}},
+		{"ulid", []string{"01ARZ3NDEKTSV4RRFFQ69G5FAV", "01arz3ndektsv4rrffq69g5fav"}, []string{"81ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FAU0", "01ARZ3NDEKTSV4RRFFQ69G5FAI"}},
+		{"semver", []string{"1.2.3", "1.0.0-beta.1+build.5"}, []string{"1.2", "v1.2.3", "01.2.3"}},
+	} {
+		t.Run(item.format, func(t *testing.T) {
+			registry := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+			s := registry.Schema(reflect.TypeOf(""), false, "")
+			s.Format = item.format
+
+			for _, value := range item.valid {
+				res := &huma.ValidateResult{}
+				huma.Validate(registry, s, huma.NewPathBuffer([]byte{}, 0), huma.ModeWriteToServer, value, res)
+				assert.Empty(t, res.Errors, value)
+			}
+			for _, value := range item.bad {
+				res := &huma.ValidateResult{}
+				huma.Validate(registry, s, huma.NewPathBuffer([]byte{}, 0), huma.ModeWriteToServer, value, res)
+				assert.Len(t, res.Errors, 1, value)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -20,6 +21,9 @@ type FormFile struct {
 type MultipartFormFiles[T any] struct {
 	Form *multipart.Form
 	data *T
+
+	// open opens files when the form was read using a `MultipartConfig`.
+	open func(fh *multipart.FileHeader) (multipart.File, error)
 }
 
 type MimeTypeValidator struct {
@@ -44,8 +48,17 @@ func (v MimeTypeValidator) Validate(fh *multipart.FileHeader, location string) (
 	if err != nil {
 		return "", &ErrorDetail{Message: "Failed to open file", Location: location}
 	}
+	defer file.Close()
+	return v.validate(fh, file, location)
+}
 
+// validate checks the mime type of the already opened file. Files without
+// contents, which were streamed to a sink, are not sniffed.
+func (v MimeTypeValidator) validate(fh *multipart.FileHeader, file multipart.File, location string) (string, *ErrorDetail) {
 	mimeType := fh.Header.Get("Content-Type")
+	if mimeType == "" && file == nil {
+		mimeType = "application/octet-stream"
+	}
 	if mimeType == "" {
 		var buffer = make([]byte, 1000)
 		if _, err := file.Read(buffer); err != nil {
@@ -85,10 +98,74 @@ func (v MimeTypeValidator) Validate(fh *multipart.FileHeader, location string) (
 	}
 }
 
+// fileTooLargeError is returned when a file exceeds its `maxFileSize`, which
+// results in a `413 Request Entity Too Large` response.
+type fileTooLargeError struct {
+	detail *ErrorDetail
+}
+
+func (e fileTooLargeError) Error() string {
+	return e.detail.Error()
+}
+
+// ErrorDetail satisfies the `ErrorDetailer` interface.
+func (e fileTooLargeError) ErrorDetail() *ErrorDetail {
+	return e.detail
+}
+
+func newFileTooLargeError(fh *multipart.FileHeader, location string, maxSize int64) fileTooLargeError {
+	return fileTooLargeError{&ErrorDetail{
+		Message:  fmt.Sprintf("file %q exceeds the maximum size of %d bytes", fh.Filename, maxSize),
+		Location: location,
+		Value:    fh.Size,
+	}}
+}
+
+// fileLimits are the optional `maxFileSize` and `maxFiles` limits of a form
+// file field. Zero means no limit.
+type fileLimits struct {
+	maxSize  int64
+	maxFiles int
+
+	// multiple is set for `[]huma.FormFile` fields, whose error locations
+	// include the index of the file like `docs[1]`.
+	multiple bool
+}
+
+// formFileLimits parses the limits from the field tags, panicking if they
+// are invalid. It is called once when the operation is registered and the
+// result is stored in the form schema.
+func formFileLimits(f reflect.StructField) fileLimits {
+	limits := fileLimits{multiple: f.Type == reflect.TypeOf([]FormFile{})}
+	if v := f.Tag.Get("maxFileSize"); v != "" {
+		size, err := ParseByteSize(v)
+		if err != nil || size <= 0 {
+			panic(fmt.Errorf("invalid maxFileSize tag for field '%s': %s", f.Name, v))
+		}
+		limits.maxSize = int64(size)
+	}
+	if v := f.Tag.Get("maxFiles"); v != "" {
+		if !limits.multiple {
+			panic(fmt.Errorf("maxFiles tag is only supported for []huma.FormFile, field '%s'", f.Name))
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			panic(fmt.Errorf("invalid maxFiles tag for field '%s': %s", f.Name, v))
+		}
+		limits.maxFiles = n
+	}
+	return limits
+}
+
 func (m *MultipartFormFiles[T]) Data() *T {
 	return m.data
 }
 
+// setOpener sets how files are opened when decoding.
+func (m *MultipartFormFiles[T]) setOpener(open func(fh *multipart.FileHeader) (multipart.File, error)) {
+	m.open = open
+}
+
 // Decodes multipart.Form data into *T, returning []*ErrorDetail if any
 // Schema is used to check for validation constraints
 func (m *MultipartFormFiles[T]) Decode(opMediaType *MediaType) []error {
@@ -96,7 +173,13 @@ func (m *MultipartFormFiles[T]) Decode(opMediaType *MediaType) []error {
 		dataType = reflect.TypeOf(m.data).Elem()
 		value    = reflect.New(dataType)
 		errors   []error
+		open     = m.open
 	)
+	if open == nil {
+		open = func(fh *multipart.FileHeader) (multipart.File, error) {
+			return fh.Open()
+		}
+	}
 	for i := 0; i < dataType.NumField(); i++ {
 		field := value.Elem().Field(i)
 		structField := dataType.Field(i)
@@ -105,16 +188,17 @@ func (m *MultipartFormFiles[T]) Decode(opMediaType *MediaType) []error {
 			key = structField.Name
 		}
 		fileHeaders := m.Form.File[key]
+		limits := opMediaType.Schema.fileLimits[key]
 		switch {
 		case field.Type() == reflect.TypeOf(FormFile{}):
-			file, err := readSingleFile(fileHeaders, key, opMediaType)
+			file, err := readSingleFile(fileHeaders, key, opMediaType, limits, open)
 			if err != nil {
 				errors = append(errors, err)
 				continue
 			}
 			field.Set(reflect.ValueOf(file))
 		case field.Type() == reflect.TypeOf([]FormFile{}):
-			files, errs := readMultipleFiles(fileHeaders, key, opMediaType)
+			files, errs := readMultipleFiles(fileHeaders, key, opMediaType, limits, open)
 			if errs != nil {
 				errors = append(errors, errs...)
 				continue
@@ -129,7 +213,7 @@ func (m *MultipartFormFiles[T]) Decode(opMediaType *MediaType) []error {
 	return errors
 }
 
-func readSingleFile(fileHeaders []*multipart.FileHeader, key string, opMediaType *MediaType) (FormFile, *ErrorDetail) {
+func readSingleFile(fileHeaders []*multipart.FileHeader, key string, opMediaType *MediaType, limits fileLimits, open func(*multipart.FileHeader) (multipart.File, error)) (FormFile, error) {
 	if len(fileHeaders) == 0 {
 		if opMediaType.Schema.requiredMap[key] {
 			return FormFile{}, &ErrorDetail{Message: "File required", Location: key}
@@ -138,7 +222,7 @@ func readSingleFile(fileHeaders []*multipart.FileHeader, key string, opMediaType
 		}
 	} else if len(fileHeaders) == 1 {
 		validator := NewMimeTypeValidator(opMediaType.Encoding[key])
-		return readFile(fileHeaders[0], key, validator)
+		return readFile(fileHeaders[0], key, validator, limits, open)
 	}
 	return FormFile{}, &ErrorDetail{
 		Message:  "Multiple files received but only one was expected",
@@ -146,7 +230,7 @@ func readSingleFile(fileHeaders []*multipart.FileHeader, key string, opMediaType
 	}
 }
 
-func readMultipleFiles(fileHeaders []*multipart.FileHeader, key string, opMediaType *MediaType) ([]FormFile, []error) {
+func readMultipleFiles(fileHeaders []*multipart.FileHeader, key string, opMediaType *MediaType, limits fileLimits, open func(*multipart.FileHeader) (multipart.File, error)) ([]FormFile, []error) {
 	var (
 		files  = make([]FormFile, len(fileHeaders))
 		errors []error
@@ -154,12 +238,21 @@ func readMultipleFiles(fileHeaders []*multipart.FileHeader, key string, opMediaT
 	if opMediaType.Schema.requiredMap[key] && len(fileHeaders) == 0 {
 		return nil, []error{&ErrorDetail{Message: "At least one file is required", Location: key}}
 	}
+	if limits.maxFiles > 0 && len(fileHeaders) > limits.maxFiles {
+		return nil, []error{&ErrorDetail{
+			Message:  fmt.Sprintf("Too many files received, expected at most %d", limits.maxFiles),
+			Location: key,
+			Value:    len(fileHeaders),
+		}}
+	}
 	validator := NewMimeTypeValidator(opMediaType.Encoding[key])
 	for i, fh := range fileHeaders {
 		file, err := readFile(
 			fh,
 			fmt.Sprintf("%s[%d]", key, i),
 			validator,
+			limits,
+			open,
 		)
 		if err != nil {
 			errors = append(errors, err)
@@ -174,13 +267,21 @@ func readFile(
 	fh *multipart.FileHeader,
 	location string,
 	validator MimeTypeValidator,
-) (FormFile, *ErrorDetail) {
-	f, err := fh.Open()
+	limits fileLimits,
+	open func(*multipart.FileHeader) (multipart.File, error),
+) (FormFile, error) {
+	if limits.maxSize > 0 && fh.Size > limits.maxSize {
+		return FormFile{}, newFileTooLargeError(fh, location, limits.maxSize)
+	}
+	f, err := open(fh)
 	if err != nil {
 		return FormFile{}, &ErrorDetail{Message: "Failed to open file", Location: location}
 	}
-	contentType, validationErr := validator.Validate(fh, location)
+	contentType, validationErr := validator.validate(fh, f, location)
 	if validationErr != nil {
+		if f != nil {
+			f.Close()
+		}
 		return FormFile{}, validationErr
 	}
 	return FormFile{
@@ -206,6 +307,7 @@ func multiPartFormFileSchema(t reflect.Type) *Schema {
 		Type:        "object",
 		Properties:  make(map[string]*Schema, nFields),
 		requiredMap: make(map[string]bool, nFields),
+		fileLimits:  make(map[string]fileLimits, nFields),
 	}
 	requiredFields := make([]string, 0, nFields)
 	for i := 0; i < nFields; i++ {
@@ -214,11 +316,18 @@ func multiPartFormFileSchema(t reflect.Type) *Schema {
 
 		switch {
 		case f.Type == reflect.TypeOf(FormFile{}):
-			schema.Properties[name] = multiPartFileSchema(f)
+			limits := formFileLimits(f)
+			schema.fileLimits[name] = limits
+			schema.Properties[name] = multiPartFileSchema(f, limits)
 		case f.Type == reflect.TypeOf([]FormFile{}):
+			limits := formFileLimits(f)
+			schema.fileLimits[name] = limits
 			schema.Properties[name] = &Schema{
 				Type:  "array",
-				Items: multiPartFileSchema(f),
+				Items: multiPartFileSchema(f, limits),
+			}
+			if limits.maxFiles > 0 {
+				schema.Properties[name].MaxItems = &limits.maxFiles
 			}
 		default:
 			// Should we panic if [T] struct defines fields with unsupported types ?
@@ -234,13 +343,19 @@ func multiPartFormFileSchema(t reflect.Type) *Schema {
 	return schema
 }
 
-func multiPartFileSchema(f reflect.StructField) *Schema {
-	return &Schema{
+func multiPartFileSchema(f reflect.StructField, limits fileLimits) *Schema {
+	s := &Schema{
 		Type:            "string",
 		Format:          "binary",
 		Description:     f.Tag.Get("doc"),
 		ContentEncoding: "binary",
 	}
+	if limits.maxSize > 0 {
+		// JSON Schema cannot limit the size of binary data, so document it
+		// via an extension instead.
+		s.Extensions = map[string]any{"x-max-file-size": limits.maxSize}
+	}
+	return s
 }
 
 func multiPartContentEncoding(t reflect.Type) map[string]*Encoding {
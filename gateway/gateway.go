@@ -0,0 +1,144 @@
+// Package gateway exports OpenAPI documents annotated for managed API
+// gateways, so the same spec which documents your API can be used to deploy
+// it behind AWS API Gateway or Google Cloud API Gateway. Each operation is
+// mapped to a backend, which is added as an
+// `x-amazon-apigateway-integration` or `x-google-backend` extension.
+//
+//	spec := gateway.AWS(api.OpenAPI(), gateway.Config{
+//		Default: &gateway.Backend{URL: "https://internal.example.com"},
+//	})
+//	b, _ := spec.DowngradeYAML()
+//
+// The original document is not modified. Both gateways import OpenAPI 3.0,
+// so use `DowngradeYAML` or `Downgrade` to write the exported document.
+package gateway
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Backend is where the gateway sends requests for an operation.
+type Backend struct {
+	// URL is the base URL of the backend, e.g. `https://internal.example.com`.
+	// The operation's path is appended to it.
+	URL string
+
+	// Timeout for requests to the backend. Defaults to the gateway's default.
+	Timeout time.Duration
+
+	// VPCLinkID connects to the backend through an AWS VPC link. Only used by
+	// `AWS`.
+	VPCLinkID string
+
+	// JWTAudience sets the audience of the ID token the gateway sends to the
+	// backend for authentication. Only used by `Google`.
+	JWTAudience string
+}
+
+// Config maps operations to their backends.
+type Config struct {
+	// Backends maps operation IDs to their backend.
+	Backends map[string]Backend
+
+	// Default is the backend used for operations not in `Backends`. If not
+	// set, such operations are exported without an integration.
+	Default *Backend
+}
+
+func (c Config) backend(op *huma.Operation) *Backend {
+	if b, ok := c.Backends[op.OperationID]; ok {
+		return &b
+	}
+	return c.Default
+}
+
+// AWS returns a copy of the document with an `x-amazon-apigateway-integration`
+// HTTP proxy integration on each operation which has a backend. Path params
+// are passed through to the backend.
+func AWS(oapi *huma.OpenAPI, config Config) *huma.OpenAPI {
+	return annotate(oapi, config, "x-amazon-apigateway-integration", func(method, path string, op *huma.Operation, b *Backend) any {
+		integration := map[string]any{
+			"type":                "http_proxy",
+			"httpMethod":          method,
+			"uri":                 strings.TrimSuffix(b.URL, "/") + path,
+			"passthroughBehavior": "when_no_match",
+		}
+		if b.Timeout > 0 {
+			integration["timeoutInMillis"] = b.Timeout.Milliseconds()
+		}
+		if b.VPCLinkID != "" {
+			integration["connectionType"] = "VPC_LINK"
+			integration["connectionId"] = b.VPCLinkID
+		}
+		params := map[string]any{}
+		for _, p := range op.Parameters {
+			if p.In == "path" {
+				params["integration.request.path."+p.Name] = "method.request.path." + p.Name
+			}
+		}
+		if len(params) > 0 {
+			integration["requestParameters"] = params
+		}
+		return integration
+	})
+}
+
+// Google returns a copy of the document with an `x-google-backend` on each
+// operation which has a backend, appending the operation's path to the
+// backend address.
+func Google(oapi *huma.OpenAPI, config Config) *huma.OpenAPI {
+	return annotate(oapi, config, "x-google-backend", func(method, path string, op *huma.Operation, b *Backend) any {
+		backend := map[string]any{
+			"address":          strings.TrimSuffix(b.URL, "/"),
+			"path_translation": "APPEND_PATH_TO_ADDRESS",
+		}
+		if b.Timeout > 0 {
+			backend["deadline"] = b.Timeout.Seconds()
+		}
+		if b.JWTAudience != "" {
+			backend["jwt_audience"] = b.JWTAudience
+		}
+		return backend
+	})
+}
+
+// annotate returns a copy of the document with the extension set on a copy of
+// each operation which has a backend.
+func annotate(oapi *huma.OpenAPI, config Config, name string, extension func(method, path string, op *huma.Operation, b *Backend) any) *huma.OpenAPI {
+	c := *oapi
+	c.Paths = make(map[string]*huma.PathItem, len(oapi.Paths))
+	for path, item := range oapi.Paths {
+		ic := *item
+		for method, op := range map[string]**huma.Operation{
+			http.MethodGet:     &ic.Get,
+			http.MethodPut:     &ic.Put,
+			http.MethodPost:    &ic.Post,
+			http.MethodDelete:  &ic.Delete,
+			http.MethodOptions: &ic.Options,
+			http.MethodHead:    &ic.Head,
+			http.MethodPatch:   &ic.Patch,
+			http.MethodTrace:   &ic.Trace,
+		} {
+			if *op == nil {
+				continue
+			}
+			b := config.backend(*op)
+			if b == nil {
+				continue
+			}
+			oc := **op
+			oc.Extensions = make(map[string]any, len((*op).Extensions)+1)
+			for k, v := range (*op).Extensions {
+				oc.Extensions[k] = v
+			}
+			oc.Extensions[name] = extension(method, path, &oc, b)
+			*op = &oc
+		}
+		c.Paths[path] = &ic
+	}
+	return &c
+}
@@ -0,0 +1,87 @@
+package gateway_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/gateway"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+func newAPI(t *testing.T) huma.API {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-things",
+		Method:      http.MethodGet,
+		Path:        "/things",
+		Extensions:  map[string]any{"x-internal": true},
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	return api
+}
+
+func TestAWS(t *testing.T) {
+	api := newAPI(t)
+
+	spec := gateway.AWS(api.OpenAPI(), gateway.Config{
+		Backends: map[string]gateway.Backend{
+			"get-thing": {URL: "https://things.internal/", Timeout: 10 * time.Second, VPCLinkID: "abc123"},
+		},
+	})
+
+	assert.Equal(t, map[string]any{
+		"type":                "http_proxy",
+		"httpMethod":          http.MethodGet,
+		"uri":                 "https://things.internal/things/{id}",
+		"passthroughBehavior": "when_no_match",
+		"timeoutInMillis":     int64(10000),
+		"connectionType":      "VPC_LINK",
+		"connectionId":        "abc123",
+		"requestParameters": map[string]any{
+			"integration.request.path.id": "method.request.path.id",
+		},
+	}, spec.Paths["/things/{id}"].Get.Extensions["x-amazon-apigateway-integration"])
+
+	// Operations without a backend are unchanged.
+	assert.Same(t, api.OpenAPI().Paths["/things"].Get, spec.Paths["/things"].Get)
+
+	// The original document is not modified.
+	assert.Nil(t, api.OpenAPI().Paths["/things/{id}"].Get.Extensions["x-amazon-apigateway-integration"])
+}
+
+func TestGoogle(t *testing.T) {
+	api := newAPI(t)
+
+	spec := gateway.Google(api.OpenAPI(), gateway.Config{
+		Default: &gateway.Backend{URL: "https://things-abc.run.app", Timeout: 1500 * time.Millisecond, JWTAudience: "things"},
+	})
+
+	backend := map[string]any{
+		"address":          "https://things-abc.run.app",
+		"path_translation": "APPEND_PATH_TO_ADDRESS",
+		"deadline":         1.5,
+		"jwt_audience":     "things",
+	}
+	assert.Equal(t, backend, spec.Paths["/things/{id}"].Get.Extensions["x-google-backend"])
+	assert.Equal(t, backend, spec.Paths["/things"].Get.Extensions["x-google-backend"])
+	assert.Equal(t, true, spec.Paths["/things"].Get.Extensions["x-internal"])
+	assert.Len(t, api.OpenAPI().Paths["/things"].Get.Extensions, 1)
+}
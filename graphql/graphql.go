@@ -0,0 +1,722 @@
+// Package graphql provides a GraphQL endpoint whose fields are generated from
+// the operations registered with a Huma API. Each field resolves by calling the
+// underlying operation through the API's router, so middleware, validation,
+// and handlers are shared with the REST API and no logic is duplicated.
+//
+// `GET` operations become fields on the `Query` type, and when enabled other
+// operations become fields on the `Mutation` type. Field names are the
+// lower camel-cased operation IDs, arguments are the operation's parameters
+// plus a `body` argument for the request body, and the result type is
+// generated from the operation's success response schema. The generated
+// schema is available in SDL form for clients and tooling.
+//
+// Fragments, directives, and introspection queries are not supported.
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/casing"
+)
+
+// Config controls how the GraphQL endpoint is generated.
+type Config struct {
+	// Path of the GraphQL endpoint. Defaults to `/graphql`. The generated SDL
+	// is served at `{Path}/schema.graphql`.
+	Path string
+
+	// Mutations enables generating `Mutation` fields for operations which do
+	// not use `GET`. By default only reads are exposed.
+	Mutations bool
+
+	// MaxBodyBytes limits the size of `POST` request bodies. Larger requests
+	// are rejected with a `413 Request Entity Too Large` error. Defaults to
+	// 1MB.
+	MaxBodyBytes int64
+
+	// MaxFields limits how many fields a single query may select on the
+	// `Query` or `Mutation` type, including aliases of the same field. Each
+	// of these fields calls an operation, so larger queries are rejected with
+	// a `400 Bad Request` error. Defaults to 10.
+	MaxFields int
+}
+
+// Request is a GraphQL request sent via `POST`.
+type Request struct {
+	Query         string         `json:"query" doc:"GraphQL query document"`
+	OperationName string         `json:"operationName,omitempty" doc:"Operation to run if the document contains several"`
+	Variables     map[string]any `json:"variables,omitempty" doc:"Values for the operation's variables"`
+}
+
+// Response is a GraphQL response.
+type Response struct {
+	Data   any      `json:"data"`
+	Errors []*Error `json:"errors,omitempty"`
+}
+
+// Error is a GraphQL error. Errors returned by an operation include the HTTP
+// status code and the operation's error details in the extensions.
+type Error struct {
+	Message    string         `json:"message"`
+	Path       []string       `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// field is a generated GraphQL field which resolves by calling an operation.
+type field struct {
+	name   string
+	op     *huma.Operation
+	args   map[string]*huma.Param
+	body   *huma.RequestBody
+	result *huma.Schema
+}
+
+// gateway resolves GraphQL requests using the API's operations.
+type gateway struct {
+	api    huma.API
+	config Config
+
+	mu        sync.Mutex
+	dirty     bool
+	queries   map[string]*field
+	mutations map[string]*field
+}
+
+// Register a GraphQL endpoint on the API. It should be called after all other
+// operations have been registered, although operations registered later are
+// picked up as well.
+//
+//	graphql.Register(api, graphql.Config{Path: "/graphql"})
+func Register(api huma.API, config Config) {
+	if config.Path == "" {
+		config.Path = "/graphql"
+	}
+	if config.MaxBodyBytes == 0 {
+		config.MaxBodyBytes = 1024 * 1024
+	}
+	if config.MaxFields == 0 {
+		config.MaxFields = 10
+	}
+	g := &gateway{api: api, config: config, dirty: true}
+
+	oapi := api.OpenAPI()
+	oapi.OnAddOperation = append(oapi.OnAddOperation, func(oapi *huma.OpenAPI, op *huma.Operation) {
+		g.mu.Lock()
+		g.dirty = true
+		g.mu.Unlock()
+	})
+
+	requestSchema := huma.SchemaFromType(oapi.Components.Schemas, reflect.TypeOf(Request{}))
+	responseSchema := &huma.Schema{
+		Type: huma.TypeObject,
+		Properties: map[string]*huma.Schema{
+			"data": {Description: "Results keyed by the selected field names"},
+			"errors": {
+				Type: huma.TypeArray,
+				Items: &huma.Schema{
+					Type: huma.TypeObject,
+					Properties: map[string]*huma.Schema{
+						"message":    {Type: huma.TypeString},
+						"path":       {Type: huma.TypeArray, Items: &huma.Schema{Type: huma.TypeString}},
+						"extensions": {Type: huma.TypeObject, AdditionalProperties: true},
+					},
+					Required: []string{"message"},
+				},
+			},
+		},
+		Required: []string{"data"},
+	}
+	responses := map[string]*huma.Response{
+		"200": {
+			Description: "GraphQL response",
+			Content: map[string]*huma.MediaType{
+				"application/json": {Schema: responseSchema},
+			},
+		},
+		"400": {
+			Description: "Invalid GraphQL request",
+			Content: map[string]*huma.MediaType{
+				"application/json": {Schema: responseSchema},
+			},
+		},
+	}
+	postResponses := maps.Clone(responses)
+	postResponses["413"] = &huma.Response{
+		Description: "Request body too large",
+		Content: map[string]*huma.MediaType{
+			"application/json": {Schema: responseSchema},
+		},
+	}
+
+	post := &huma.Operation{
+		OperationID: "post-graphql",
+		Method:      http.MethodPost,
+		Path:        config.Path,
+		Summary:     "Run a GraphQL query",
+		Description: "Runs a GraphQL query whose fields are generated from this API's operations. See `" + config.Path + "/schema.graphql` for the schema.",
+		Tags:        []string{"GraphQL"},
+		RequestBody: &huma.RequestBody{
+			Required: true,
+			Content: map[string]*huma.MediaType{
+				"application/json": {Schema: requestSchema},
+			},
+		},
+		Responses:    postResponses,
+		MaxBodyBytes: config.MaxBodyBytes,
+	}
+	get := &huma.Operation{
+		OperationID: "get-graphql",
+		Method:      http.MethodGet,
+		Path:        config.Path,
+		Summary:     "Run a GraphQL query via GET",
+		Description: "Runs a GraphQL query using query parameters. Only queries, not mutations, can be run via `GET`.",
+		Tags:        []string{"GraphQL"},
+		Parameters: []*huma.Param{
+			{Name: "query", In: "query", Required: true, Schema: &huma.Schema{Type: huma.TypeString}},
+			{Name: "operationName", In: "query", Schema: &huma.Schema{Type: huma.TypeString}},
+			{Name: "variables", In: "query", Description: "JSON-encoded variables", Schema: &huma.Schema{Type: huma.TypeString}},
+		},
+		Responses: responses,
+	}
+	schema := &huma.Operation{
+		OperationID: "get-graphql-schema",
+		Method:      http.MethodGet,
+		Path:        config.Path + "/schema.graphql",
+		Summary:     "Get the GraphQL schema",
+		Tags:        []string{"GraphQL"},
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "GraphQL schema definition language document",
+				Content: map[string]*huma.MediaType{
+					"text/plain": {Schema: &huma.Schema{Type: huma.TypeString}},
+				},
+			},
+		},
+	}
+
+	// Manually register the handlers with the router, wrapped in the API's
+	// middleware like regular operations and mounted under the base path.
+	adapter := api.Adapter()
+	for _, op := range []*huma.Operation{post, get, schema} {
+		oapi.AddOperation(op)
+	}
+	mount := func(op *huma.Operation) *huma.Operation {
+		tmp := *op
+		tmp.Path = huma.MountPath(api, nil, op.Path)
+		return &tmp
+	}
+	middlewares := api.Middlewares()
+	adapter.Handle(mount(post), middlewares.Handler(func(ctx huma.Context) {
+		var req Request
+		b, err := io.ReadAll(io.LimitReader(ctx.BodyReader(), config.MaxBodyBytes+1))
+		if err == nil && int64(len(b)) > config.MaxBodyBytes {
+			writeResponse(ctx, http.StatusRequestEntityTooLarge, &Response{Errors: []*Error{{Message: fmt.Sprintf("request body is too large limit=%d bytes", config.MaxBodyBytes)}}})
+			return
+		}
+		if err == nil {
+			err = json.Unmarshal(b, &req)
+		}
+		if err != nil {
+			writeResponse(ctx, http.StatusBadRequest, &Response{Errors: []*Error{{Message: "invalid request body: " + err.Error()}}})
+			return
+		}
+		g.serve(ctx, &req, true)
+	}))
+	adapter.Handle(mount(get), middlewares.Handler(func(ctx huma.Context) {
+		req := Request{
+			Query:         ctx.Query("query"),
+			OperationName: ctx.Query("operationName"),
+		}
+		if v := ctx.Query("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				writeResponse(ctx, http.StatusBadRequest, &Response{Errors: []*Error{{Message: "invalid variables: " + err.Error()}}})
+				return
+			}
+		}
+		g.serve(ctx, &req, false)
+	}))
+	adapter.Handle(mount(schema), middlewares.Handler(func(ctx huma.Context) {
+		ctx.SetHeader("Content-Type", "text/plain; charset=utf-8")
+		ctx.BodyWriter().Write([]byte(g.sdl()))
+	}))
+}
+
+// fields returns the current query and mutation fields, regenerating them if
+// operations have been added since they were last generated.
+func (g *gateway) fields() (map[string]*field, map[string]*field) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.dirty {
+		return g.queries, g.mutations
+	}
+
+	g.queries = map[string]*field{}
+	g.mutations = map[string]*field{}
+	oapi := g.api.OpenAPI()
+	for path, item := range oapi.Paths {
+		if path == g.config.Path || strings.HasPrefix(path, g.config.Path+"/") {
+			continue
+		}
+		for _, op := range []*huma.Operation{item.Get, item.Post, item.Put, item.Patch, item.Delete} {
+			if op == nil || op.OperationID == "" {
+				continue
+			}
+			target := g.queries
+			if op.Method != http.MethodGet {
+				if !g.config.Mutations {
+					continue
+				}
+				target = g.mutations
+			}
+			if f := newField(op); f != nil {
+				target[f.name] = f
+			}
+		}
+	}
+	g.dirty = false
+	return g.queries, g.mutations
+}
+
+// newField creates a field for the operation, or returns nil if the operation
+// cannot be represented, e.g. because it does not return JSON.
+func newField(op *huma.Operation) *field {
+	f := &field{
+		name: casing.LowerCamel(op.OperationID),
+		op:   op,
+		args: map[string]*huma.Param{},
+	}
+
+	// Use the first successful response for the result type. Responses with
+	// no body resolve to `true`.
+	statuses := make([]string, 0, len(op.Responses))
+	for status := range op.Responses {
+		if strings.HasPrefix(status, "2") {
+			statuses = append(statuses, status)
+		}
+	}
+	if len(statuses) == 0 {
+		return nil
+	}
+	sort.Strings(statuses)
+	if content := op.Responses[statuses[0]].Content; len(content) > 0 {
+		mt := content["application/json"]
+		if mt == nil {
+			return nil
+		}
+		f.result = mt.Schema
+		if f.result == nil {
+			f.result = &huma.Schema{}
+		}
+	}
+
+	for _, p := range op.Parameters {
+		f.args[casing.LowerCamel(p.Name)] = p
+	}
+	if op.RequestBody != nil && op.RequestBody.Content["application/json"] != nil {
+		f.body = op.RequestBody
+	}
+	return f
+}
+
+// serve executes a GraphQL request and writes the response.
+func (g *gateway) serve(ctx huma.Context, req *Request, allowMutations bool) {
+	doc, err := parse(req.Query, req.OperationName)
+	if err != nil {
+		writeResponse(ctx, http.StatusBadRequest, &Response{Errors: []*Error{{Message: err.Error()}}})
+		return
+	}
+
+	queries, mutations := g.fields()
+	fields := queries
+	switch doc.operation {
+	case "mutation":
+		if !allowMutations {
+			writeResponse(ctx, http.StatusMethodNotAllowed, &Response{Errors: []*Error{{Message: "mutations must use POST"}}})
+			return
+		}
+		fields = mutations
+	case "subscription":
+		writeResponse(ctx, http.StatusBadRequest, &Response{Errors: []*Error{{Message: "subscriptions are not supported"}}})
+		return
+	}
+
+	count := 0
+	for _, sel := range doc.selections {
+		if sel.name != "__typename" {
+			count++
+		}
+	}
+	if count > g.config.MaxFields {
+		writeResponse(ctx, http.StatusBadRequest, &Response{Errors: []*Error{{Message: fmt.Sprintf("query selects %d fields, more than the limit of %d", count, g.config.MaxFields)}}})
+		return
+	}
+
+	variables := map[string]any{}
+	for _, v := range doc.variables {
+		if value, ok := req.Variables[v.name]; ok {
+			variables[v.name] = value
+		} else if v.hasDefault {
+			variables[v.name] = v.defaultVal
+		}
+	}
+
+	resp := &Response{}
+	data := &object{}
+	for _, sel := range doc.selections {
+		if sel.name == "__typename" {
+			data.set(sel.key(), casing.Camel(doc.operation))
+			continue
+		}
+		f := fields[sel.name]
+		if f == nil {
+			resp.Errors = append(resp.Errors, &Error{Message: fmt.Sprintf("unknown field %q on type %s", sel.name, casing.Camel(doc.operation)), Path: []string{sel.key()}})
+			data.set(sel.key(), nil)
+			continue
+		}
+		value, err := g.resolve(ctx, f, sel, variables)
+		if err != nil {
+			resp.Errors = append(resp.Errors, err)
+		}
+		data.set(sel.key(), value)
+	}
+	resp.Data = data
+	writeResponse(ctx, http.StatusOK, resp)
+}
+
+// resolve calls the field's operation and returns the selected parts of its
+// response.
+func (g *gateway) resolve(ctx huma.Context, f *field, sel *selection, variables map[string]any) (any, *Error) {
+	fail := func(msg string, extensions map[string]any) (any, *Error) {
+		return nil, &Error{Message: msg, Path: []string{sel.key()}, Extensions: extensions}
+	}
+
+	path := huma.MountPath(g.api, ctx, f.op.Path)
+	query := url.Values{}
+	header := http.Header{}
+	var body io.Reader
+	for name, raw := range sel.args {
+		value, err := substitute(raw, variables)
+		if err != nil {
+			return fail(err.Error(), nil)
+		}
+		if name == "body" && f.body != nil {
+			b, err := json.Marshal(value)
+			if err != nil {
+				return fail(err.Error(), nil)
+			}
+			body = bytes.NewReader(b)
+			header.Set("Content-Type", "application/json")
+			continue
+		}
+		p := f.args[name]
+		if p == nil {
+			return fail(fmt.Sprintf("unknown argument %q on field %q", name, sel.name), nil)
+		}
+		if value == nil {
+			continue
+		}
+		values := stringValues(value)
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(strings.Join(values, ",")))
+		case "query":
+			if p.Explode != nil && *p.Explode {
+				query[p.Name] = values
+			} else {
+				query.Set(p.Name, strings.Join(values, ","))
+			}
+		case "header":
+			header.Set(p.Name, strings.Join(values, ","))
+		case "cookie":
+			header.Add("Cookie", (&http.Cookie{Name: p.Name, Value: strings.Join(values, ",")}).String())
+		}
+	}
+	for _, p := range f.op.Parameters {
+		if p.In == "path" && strings.Contains(path, "{"+p.Name+"}") {
+			return fail(fmt.Sprintf("missing required argument %q", casing.LowerCamel(p.Name)), nil)
+		}
+	}
+	if body == nil && f.body != nil && f.body.Required {
+		return fail(`missing required argument "body"`, nil)
+	}
+
+	target := path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	r, err := http.NewRequestWithContext(ctx.Context(), f.op.Method, target, body)
+	if err != nil {
+		return fail(err.Error(), nil)
+	}
+
+	// Copy incoming headers like `Authorization` so the operation sees the
+	// same caller.
+	ctx.EachHeader(func(k, v string) {
+		switch http.CanonicalHeaderKey(k) {
+		case "Accept", "Accept-Encoding", "Content-Type", "Content-Length":
+			return
+		}
+		r.Header.Add(k, v)
+	})
+	for k, v := range header {
+		if k == "Cookie" {
+			r.Header[k] = append(r.Header[k], v...)
+		} else {
+			r.Header[k] = v
+		}
+	}
+	r.Header.Set("Accept", "application/json")
+
+	w := newRecorder()
+	g.api.Adapter().ServeHTTP(w, r)
+
+	if w.Code < 200 || w.Code >= 300 {
+		extensions := map[string]any{"status": w.Code}
+		msg := http.StatusText(w.Code)
+		var model huma.ErrorModel
+		if json.Unmarshal(w.Body.Bytes(), &model) == nil {
+			if model.Detail != "" {
+				msg = model.Detail
+			} else if model.Title != "" {
+				msg = model.Title
+			}
+			if len(model.Errors) > 0 {
+				extensions["errors"] = model.Errors
+			}
+		}
+		return fail(msg, extensions)
+	}
+
+	if f.result == nil || w.Body.Len() == 0 {
+		return true, nil
+	}
+
+	dec := json.NewDecoder(w.Body)
+	dec.UseNumber()
+	var result any
+	if err := dec.Decode(&result); err != nil {
+		return fail("invalid response: "+err.Error(), nil)
+	}
+	return g.project(result, f.result, sel.selections, typeName(g.api.OpenAPI(), f.result, casing.Camel(f.op.OperationID)+"Result"), []string{sel.key()})
+}
+
+// project returns only the selected fields of a value.
+func (g *gateway) project(value any, schema *huma.Schema, selections []*selection, name string, path []string) (any, *Error) {
+	if value == nil {
+		return nil, nil
+	}
+	schema = deref(g.api.OpenAPI(), schema)
+
+	if list, ok := value.([]any); ok {
+		var items *huma.Schema
+		if schema != nil {
+			items = schema.Items
+		}
+		itemName := typeName(g.api.OpenAPI(), items, name)
+		out := make([]any, len(list))
+		for i, item := range list {
+			v, err := g.project(item, items, selections, itemName, append(slices.Clone(path), strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		return out, nil
+	}
+
+	if len(selections) == 0 {
+		return value, nil
+	}
+
+	m, ok := value.(map[string]any)
+	if !ok {
+		return nil, &Error{Message: "cannot select fields on a scalar value", Path: path}
+	}
+	out := &object{}
+	for _, sel := range selections {
+		if sel.name == "__typename" {
+			out.set(sel.key(), name)
+			continue
+		}
+		var prop *huma.Schema
+		if schema != nil {
+			prop = schema.Properties[sel.name]
+		}
+		v, present := m[sel.name]
+		if !present && prop == nil {
+			return nil, &Error{Message: fmt.Sprintf("unknown field %q on type %s", sel.name, name), Path: append(slices.Clone(path), sel.key())}
+		}
+		projected, err := g.project(v, prop, sel.selections, typeName(g.api.OpenAPI(), prop, name+casing.Camel(sel.name)), append(slices.Clone(path), sel.key()))
+		if err != nil {
+			return nil, err
+		}
+		out.set(sel.key(), projected)
+	}
+	return out, nil
+}
+
+// substitute replaces variable references within an argument value.
+func substitute(value any, variables map[string]any) (any, error) {
+	switch v := value.(type) {
+	case variable:
+		return variables[string(v)], nil
+	case enumValue:
+		return string(v), nil
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			s, err := substitute(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = s
+		}
+		return out, nil
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			s, err := substitute(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = s
+		}
+		return out, nil
+	}
+	return value, nil
+}
+
+// stringValues converts an argument value into parameter strings.
+func stringValues(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case bool:
+		return []string{strconv.FormatBool(v)}
+	case int64:
+		return []string{strconv.FormatInt(v, 10)}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case json.Number:
+		return []string{v.String()}
+	case []any:
+		out := []string{}
+		for _, item := range v {
+			out = append(out, stringValues(item)...)
+		}
+		return out
+	}
+	b, _ := json.Marshal(value)
+	return []string{string(b)}
+}
+
+// object is a JSON object which preserves the order of its keys, as GraphQL
+// responses must match the order of the selections.
+type object struct {
+	keys   []string
+	values map[string]any
+}
+
+func (o *object) set(key string, value any) {
+	if o.values == nil {
+		o.values = map[string]any{}
+	}
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// MarshalJSON writes the object's keys in order.
+func (o *object) MarshalJSON() ([]byte, error) {
+	buf := bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		kb, _ := json.Marshal(k)
+		buf.Write(kb)
+		buf.WriteByte(':')
+		vb, err := json.Marshal(o.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func writeResponse(ctx huma.Context, status int, resp *Response) {
+	ctx.SetHeader("Content-Type", "application/json")
+	ctx.SetStatus(status)
+	json.NewEncoder(ctx.BodyWriter()).Encode(resp)
+}
+
+// deref returns the schema a `$ref` points to, if any.
+func deref(oapi *huma.OpenAPI, s *huma.Schema) *huma.Schema {
+	if s != nil && s.Ref != "" {
+		return oapi.Components.Schemas.SchemaFromRef(s.Ref)
+	}
+	return s
+}
+
+// typeName returns the GraphQL type name for a schema, using the referenced
+// schema name if available or the fallback for inline schemas.
+func typeName(oapi *huma.OpenAPI, s *huma.Schema, fallback string) string {
+	if s != nil && s.Ref != "" {
+		return s.Ref[strings.LastIndex(s.Ref, "/")+1:]
+	}
+	if s != nil && s.Type == huma.TypeArray {
+		return typeName(oapi, s.Items, fallback)
+	}
+	return fallback
+}
+
+// sortedKeys returns the keys of a map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// recorder is an `http.ResponseWriter` which buffers the response of an
+// operation called to resolve a field.
+type recorder struct {
+	header http.Header
+	Code   int
+	Body   *bytes.Buffer
+}
+
+func newRecorder() *recorder {
+	return &recorder{header: http.Header{}, Code: http.StatusOK, Body: &bytes.Buffer{}}
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(status int) {
+	r.Code = status
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	return r.Body.Write(p)
+}
@@ -0,0 +1,218 @@
+package graphql_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/graphql"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+type Author struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+type Book struct {
+	ID      string   `json:"id"`
+	Title   string   `json:"title" doc:"Book title"`
+	Authors []Author `json:"authors"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+type BookOutput struct {
+	Body Book
+}
+
+func setup(t *testing.T, config graphql.Config, apiConfig ...huma.Config) humatest.TestAPI {
+	_, api := humatest.New(t, apiConfig...)
+
+	books := map[string]Book{
+		"1": {ID: "1", Title: "Go", Authors: []Author{{Name: "Alan", Email: "alan@example.com"}}, Tags: []string{"go"}},
+	}
+
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		if ctx.Header("Authorization") != "secret" {
+			huma.WriteErr(api, ctx, http.StatusUnauthorized, "missing auth")
+			return
+		}
+		next(ctx)
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-book",
+		Method:      http.MethodGet,
+		Path:        "/books/{book-id}",
+		Summary:     "Get a book",
+	}, func(ctx context.Context, input *struct {
+		BookID string `path:"book-id"`
+		Upper  bool   `query:"upper"`
+	}) (*BookOutput, error) {
+		book, ok := books[input.BookID]
+		if !ok {
+			return nil, huma.Error404NotFound("book not found")
+		}
+		if input.Upper {
+			book.Title = strings.ToUpper(book.Title)
+		}
+		return &BookOutput{Body: book}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-book",
+		Method:      http.MethodPut,
+		Path:        "/books/{book-id}",
+	}, func(ctx context.Context, input *struct {
+		BookID string `path:"book-id"`
+		Body   Book
+	}) (*BookOutput, error) {
+		books[input.BookID] = input.Body
+		return &BookOutput{Body: input.Body}, nil
+	})
+
+	graphql.Register(api, config)
+	return api
+}
+
+func TestGraphQLQuery(t *testing.T) {
+	api := setup(t, graphql.Config{})
+
+	resp := api.Post("/graphql", "Authorization: secret", map[string]any{
+		"query": `query GetBook($id: String!) {
+			book: getBook(bookId: $id, upper: true) {
+				__typename
+				title
+				authors { name }
+			}
+		}`,
+		"variables": map[string]any{"id": "1"},
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"data": {"book": {"__typename": "Book", "title": "GO", "authors": [{"name": "Alan"}]}}}`, resp.Body.String())
+
+	// Fields are returned in the order they were selected.
+	assert.Contains(t, resp.Body.String(), `{"__typename":"Book","title":"GO","authors"`)
+
+	// Errors from the operation are returned as GraphQL errors.
+	resp = api.Post("/graphql", "Authorization: secret", map[string]any{
+		"query": `{ getBook(bookId: "missing") { title } }`,
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"data": {"getBook": null}, "errors": [{"message": "book not found", "path": ["getBook"], "extensions": {"status": 404}}]}`, resp.Body.String())
+
+	// API middleware applies to the endpoint itself as well as the underlying
+	// operations.
+	resp = api.Post("/graphql", map[string]any{
+		"query": `{ getBook(bookId: "1") { title } }`,
+	})
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = api.Post("/graphql", "Authorization: secret", map[string]any{
+		"query": `{ getBook(bookId: "1") { unknown } }`,
+	})
+	assert.Contains(t, resp.Body.String(), `unknown field \"unknown\" on type Book`)
+
+	resp = api.Get("/graphql?query="+url.QueryEscape(`{getBook(bookId: "1") {id tags}}`), "Authorization: secret")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"data": {"getBook": {"id": "1", "tags": ["go"]}}}`, resp.Body.String())
+
+	// Mutations are disabled by default.
+	resp = api.Post("/graphql", "Authorization: secret", map[string]any{
+		"query": `mutation { putBook(bookId: "2", body: {id: "2", title: "New", authors: []}) { id } }`,
+	})
+	assert.Contains(t, resp.Body.String(), `unknown field \"putBook\" on type Mutation`)
+
+	resp = api.Post("/graphql", "Authorization: secret", map[string]any{
+		"query": `{ getBook(bookId: "1") { ...BookFields } }`,
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), "fragments are not supported")
+}
+
+func TestGraphQLBasePath(t *testing.T) {
+	apiConfig := huma.DefaultConfig("Test API", "1.0.0")
+	apiConfig.BasePath = "/t/{tenant}"
+	api := setup(t, graphql.Config{}, apiConfig)
+
+	resp := api.Post("/t/acme/graphql", "Authorization: secret", map[string]any{
+		"query": `{ getBook(bookId: "1") { title } }`,
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"data": {"getBook": {"title": "Go"}}}`, resp.Body.String())
+
+	resp = api.Get("/t/acme/graphql/schema.graphql", "Authorization: secret")
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = api.Post("/graphql", "Authorization: secret", map[string]any{
+		"query": `{ getBook(bookId: "1") { title } }`,
+	})
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestGraphQLMaxBodyBytes(t *testing.T) {
+	api := setup(t, graphql.Config{MaxBodyBytes: 64})
+
+	assert.Contains(t, api.OpenAPI().Paths["/graphql"].Post.Responses, "413")
+
+	resp := api.Post("/graphql", "Authorization: secret", map[string]any{
+		"query": `{ getBook(bookId: "1") { title } }`,
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = api.Post("/graphql", "Authorization: secret", map[string]any{
+		"query": `{ getBook(bookId: "1") { title } }` + strings.Repeat(" ", 100),
+	})
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+	assert.Contains(t, resp.Body.String(), "request body is too large")
+}
+
+func TestGraphQLMaxFields(t *testing.T) {
+	api := setup(t, graphql.Config{MaxFields: 2})
+
+	resp := api.Post("/graphql", "Authorization: secret", map[string]any{
+		"query": `{ a: getBook(bookId: "1") { title } b: getBook(bookId: "1") { title } __typename }`,
+	})
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	// Each alias calls the operation again, so they count against the limit.
+	resp = api.Post("/graphql", "Authorization: secret", map[string]any{
+		"query": `{ a: getBook(bookId: "1") { title } b: getBook(bookId: "1") { title } c: getBook(bookId: "1") { title } }`,
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"data": null, "errors": [{"message": "query selects 3 fields, more than the limit of 2"}]}`, resp.Body.String())
+}
+
+func TestGraphQLMutation(t *testing.T) {
+	api := setup(t, graphql.Config{Path: "/gql", Mutations: true})
+
+	resp := api.Post("/gql", "Authorization: secret", map[string]any{
+		"query": `mutation { putBook(bookId: "2", body: {id: "2", title: "New", authors: []}) { id title } }`,
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"data": {"putBook": {"id": "2", "title": "New"}}}`, resp.Body.String())
+
+	// Validation errors include the details.
+	resp = api.Post("/gql", "Authorization: secret", map[string]any{
+		"query": `mutation { putBook(bookId: "2", body: {id: "2"}) { id } }`,
+	})
+	assert.Contains(t, resp.Body.String(), `"status":422`)
+	assert.Contains(t, resp.Body.String(), "expected required property title to be present")
+
+	resp = api.Get("/gql?query="+url.QueryEscape(`mutation { putBook(bookId: "2") { id } }`), "Authorization: secret")
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+
+	resp = api.Get("/gql/schema.graphql", "Authorization: secret")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	sdl := resp.Body.String()
+	assert.Contains(t, sdl, "type Query {\n  \"Get a book\"\n  getBook(bookId: String!, upper: Boolean): Book\n}")
+	assert.Contains(t, sdl, "type Mutation {\n  putBook(bookId: String!, body: JSON!): Book\n}")
+	assert.Contains(t, sdl, "type Book {\n  authors: [Author]\n  id: String!\n")
+	assert.Contains(t, sdl, "  \"Book title\"\n  title: String!\n")
+	assert.Contains(t, sdl, "type Author {\n  email: String\n  name: String!\n}")
+}
@@ -0,0 +1,505 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// document is a parsed GraphQL operation ready to be executed. Fragments and
+// directives are not supported.
+type document struct {
+	operation  string
+	name       string
+	variables  []*variableDef
+	selections []*selection
+}
+
+// variableDef is an operation's declared variable and its optional default.
+type variableDef struct {
+	name       string
+	defaultVal any
+	hasDefault bool
+}
+
+// selection is a single field selection, e.g. `alias: name(arg: 1) { ... }`.
+type selection struct {
+	alias      string
+	name       string
+	args       map[string]any
+	selections []*selection
+}
+
+// key returns the name of the selection in the response.
+func (s *selection) key() string {
+	if s.alias != "" {
+		return s.alias
+	}
+	return s.name
+}
+
+// variable is a reference to a variable within an argument value.
+type variable string
+
+// enumValue is an unquoted enum value. Enums are passed to operations as
+// strings.
+type enumValue string
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// parser is a small recursive descent parser for GraphQL executable
+// documents.
+type parser struct {
+	src string
+	pos int
+	tok token
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("syntax error at position %d: %s", p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+// next advances to the next token, skipping whitespace, commas, and comments.
+func (p *parser) next() error {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			p.pos++
+			continue
+		}
+		if c == '#' {
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+			continue
+		}
+		break
+	}
+
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokenEOF, pos: start}
+		return nil
+	}
+
+	c := p.src[p.pos]
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "..."):
+		p.pos += 3
+		p.tok = token{kind: tokenPunct, value: "...", pos: start}
+	case strings.ContainsRune("{}()[]:!$=@|&", rune(c)):
+		p.pos++
+		p.tok = token{kind: tokenPunct, value: string(c), pos: start}
+	case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		for p.pos < len(p.src) && isNameChar(p.src[p.pos]) {
+			p.pos++
+		}
+		p.tok = token{kind: tokenName, value: p.src[start:p.pos], pos: start}
+	case c == '-' || (c >= '0' && c <= '9'):
+		kind := tokenInt
+		p.pos++
+		for p.pos < len(p.src) {
+			c := p.src[p.pos]
+			if c == '.' || c == 'e' || c == 'E' || c == '+' || (c == '-' && (p.src[p.pos-1] == 'e' || p.src[p.pos-1] == 'E')) {
+				kind = tokenFloat
+			} else if c < '0' || c > '9' {
+				break
+			}
+			p.pos++
+		}
+		p.tok = token{kind: kind, value: p.src[start:p.pos], pos: start}
+	case c == '"':
+		s, err := p.readString()
+		if err != nil {
+			return err
+		}
+		p.tok = token{kind: tokenString, value: s, pos: start}
+	default:
+		r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+		return fmt.Errorf("syntax error at position %d: unexpected character %q", start, r)
+	}
+	return nil
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// readString reads a quoted or block string starting at the current position.
+func (p *parser) readString() (string, error) {
+	start := p.pos
+	if strings.HasPrefix(p.src[p.pos:], `"""`) {
+		end := strings.Index(p.src[p.pos+3:], `"""`)
+		if end == -1 {
+			return "", fmt.Errorf("syntax error at position %d: unterminated string", start)
+		}
+		s := p.src[p.pos+3 : p.pos+3+end]
+		p.pos += end + 6
+		return strings.TrimSpace(strings.ReplaceAll(s, `\"""`, `"""`)), nil
+	}
+
+	p.pos++
+	sb := strings.Builder{}
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return sb.String(), nil
+		case '\n', '\r':
+			return "", fmt.Errorf("syntax error at position %d: unterminated string", start)
+		case '\\':
+			if p.pos+1 >= len(p.src) {
+				return "", fmt.Errorf("syntax error at position %d: unterminated string", start)
+			}
+			p.pos++
+			switch e := p.src[p.pos]; e {
+			case '"', '\\', '/':
+				sb.WriteByte(e)
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if p.pos+5 > len(p.src) {
+					return "", fmt.Errorf("syntax error at position %d: invalid unicode escape", p.pos)
+				}
+				r, err := strconv.ParseUint(p.src[p.pos+1:p.pos+5], 16, 32)
+				if err != nil {
+					return "", fmt.Errorf("syntax error at position %d: invalid unicode escape", p.pos)
+				}
+				sb.WriteRune(rune(r))
+				p.pos += 4
+			default:
+				return "", fmt.Errorf("syntax error at position %d: invalid escape %q", p.pos, e)
+			}
+			p.pos++
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", fmt.Errorf("syntax error at position %d: unterminated string", start)
+}
+
+func (p *parser) is(value string) bool {
+	return (p.tok.kind == tokenPunct || p.tok.kind == tokenName) && p.tok.value == value
+}
+
+func (p *parser) expect(value string) error {
+	if !p.is(value) {
+		return p.errorf("expected %q", value)
+	}
+	return p.next()
+}
+
+func (p *parser) name() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.errorf("expected name")
+	}
+	name := p.tok.value
+	return name, p.next()
+}
+
+// parse parses a document and returns the operation with the given name, or
+// the only operation if no name is given.
+func parse(src, operationName string) (*document, error) {
+	p := &parser{src: src}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	var docs []*document
+	for p.tok.kind != tokenEOF {
+		doc, err := p.parseOperation()
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no operations in document")
+	}
+	if operationName == "" {
+		if len(docs) > 1 {
+			return nil, fmt.Errorf("operationName is required when the document has multiple operations")
+		}
+		return docs[0], nil
+	}
+	for _, doc := range docs {
+		if doc.name == operationName {
+			return doc, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", operationName)
+}
+
+func (p *parser) parseOperation() (*document, error) {
+	doc := &document{operation: "query"}
+	if !p.is("{") {
+		if p.is("fragment") {
+			return nil, p.errorf("fragments are not supported")
+		}
+		if !p.is("query") && !p.is("mutation") && !p.is("subscription") {
+			return nil, p.errorf("expected operation")
+		}
+		doc.operation = p.tok.value
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind == tokenName {
+			doc.name = p.tok.value
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		}
+		if p.is("(") {
+			vars, err := p.parseVariableDefs()
+			if err != nil {
+				return nil, err
+			}
+			doc.variables = vars
+		}
+		if p.is("@") {
+			return nil, p.errorf("directives are not supported")
+		}
+	}
+
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	doc.selections = selections
+	return doc, nil
+}
+
+func (p *parser) parseVariableDefs() ([]*variableDef, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var vars []*variableDef
+	for !p.is(")") {
+		if err := p.expect("$"); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if err := p.skipType(); err != nil {
+			return nil, err
+		}
+		v := &variableDef{name: name}
+		if p.is("=") {
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			if v.defaultVal, err = p.parseValue(true); err != nil {
+				return nil, err
+			}
+			v.hasDefault = true
+		}
+		vars = append(vars, v)
+	}
+	return vars, p.next()
+}
+
+// skipType skips over a type reference like `[String!]!`. Variable values are
+// validated by the operation itself, so the declared type is not needed.
+func (p *parser) skipType() error {
+	if p.is("[") {
+		if err := p.next(); err != nil {
+			return err
+		}
+		if err := p.skipType(); err != nil {
+			return err
+		}
+		if err := p.expect("]"); err != nil {
+			return err
+		}
+	} else if _, err := p.name(); err != nil {
+		return err
+	}
+	if p.is("!") {
+		return p.next()
+	}
+	return nil
+}
+
+func (p *parser) parseSelectionSet() ([]*selection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var selections []*selection
+	for !p.is("}") {
+		if p.tok.kind == tokenEOF {
+			return nil, p.errorf("expected \"}\"")
+		}
+		if p.is("...") {
+			return nil, p.errorf("fragments are not supported")
+		}
+		sel := &selection{}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		sel.name = name
+		if p.is(":") {
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			sel.alias = name
+			if sel.name, err = p.name(); err != nil {
+				return nil, err
+			}
+		}
+		if p.is("(") {
+			if err := p.parseArguments(sel); err != nil {
+				return nil, err
+			}
+		}
+		if p.is("@") {
+			return nil, p.errorf("directives are not supported")
+		}
+		if p.is("{") {
+			if sel.selections, err = p.parseSelectionSet(); err != nil {
+				return nil, err
+			}
+		}
+		selections = append(selections, sel)
+	}
+	if len(selections) == 0 {
+		return nil, p.errorf("selection set must not be empty")
+	}
+	return selections, p.next()
+}
+
+func (p *parser) parseArguments(sel *selection) error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	sel.args = map[string]any{}
+	for !p.is(")") {
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		value, err := p.parseValue(false)
+		if err != nil {
+			return err
+		}
+		sel.args[name] = value
+	}
+	return p.next()
+}
+
+// parseValue parses an input value. Constant values may not contain variable
+// references.
+func (p *parser) parseValue(constant bool) (any, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokenInt:
+		v, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			return nil, p.errorf("invalid integer %q", tok.value)
+		}
+		return v, p.next()
+	case tokenFloat:
+		v, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, p.errorf("invalid float %q", tok.value)
+		}
+		return v, p.next()
+	case tokenString:
+		return tok.value, p.next()
+	case tokenName:
+		var v any
+		switch tok.value {
+		case "true":
+			v = true
+		case "false":
+			v = false
+		case "null":
+			v = nil
+		default:
+			v = enumValue(tok.value)
+		}
+		return v, p.next()
+	case tokenPunct:
+		switch tok.value {
+		case "$":
+			if constant {
+				return nil, p.errorf("unexpected variable")
+			}
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			name, err := p.name()
+			return variable(name), err
+		case "[":
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			list := []any{}
+			for !p.is("]") {
+				if p.tok.kind == tokenEOF {
+					return nil, p.errorf("expected \"]\"")
+				}
+				v, err := p.parseValue(constant)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, v)
+			}
+			return list, p.next()
+		case "{":
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			obj := map[string]any{}
+			for !p.is("}") {
+				name, err := p.name()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				if obj[name], err = p.parseValue(constant); err != nil {
+					return nil, err
+				}
+			}
+			return obj, p.next()
+		}
+	}
+	return nil, p.errorf("expected value")
+}
@@ -0,0 +1,172 @@
+package graphql
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/casing"
+)
+
+// validName matches valid GraphQL names. JSON properties with other names are
+// left out of the generated schema.
+var validName = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// sdlBuilder generates GraphQL type definitions from JSON schemas.
+type sdlBuilder struct {
+	oapi  *huma.OpenAPI
+	types map[string]string
+}
+
+// sdl returns the GraphQL schema definition language document describing the
+// generated fields and types.
+func (g *gateway) sdl() string {
+	queries, mutations := g.fields()
+	b := &sdlBuilder{oapi: g.api.OpenAPI(), types: map[string]string{}}
+
+	sb := strings.Builder{}
+	sb.WriteString("\"Arbitrary JSON value\"\nscalar JSON\n")
+	b.writeRoot(&sb, "Query", queries)
+	if len(mutations) > 0 {
+		b.writeRoot(&sb, "Mutation", mutations)
+	}
+	for _, name := range sortedKeys(b.types) {
+		sb.WriteString("\n")
+		sb.WriteString(b.types[name])
+	}
+	return sb.String()
+}
+
+func (b *sdlBuilder) writeRoot(sb *strings.Builder, name string, fields map[string]*field) {
+	sb.WriteString("\ntype " + name + " {\n")
+	if len(fields) == 0 {
+		// Object types must have at least one field.
+		sb.WriteString("  _empty: Boolean\n")
+	}
+	for _, fieldName := range sortedKeys(fields) {
+		f := fields[fieldName]
+		writeDescription(sb, "  ", f.op.Summary)
+		sb.WriteString("  " + f.name)
+
+		args := []string{}
+		for _, argName := range sortedKeys(f.args) {
+			p := f.args[argName]
+			if !validName.MatchString(argName) {
+				continue
+			}
+			args = append(args, argName+": "+b.inputType(p.Schema)+nonNull(p.Required))
+		}
+		if f.body != nil {
+			args = append(args, "body: JSON"+nonNull(f.body.Required))
+		}
+		if len(args) > 0 {
+			sb.WriteString("(" + strings.Join(args, ", ") + ")")
+		}
+
+		result := "Boolean"
+		if f.result != nil {
+			result = b.outputType(f.result, casing.Camel(f.op.OperationID)+"Result")
+		}
+		sb.WriteString(": " + result + "\n")
+	}
+	sb.WriteString("}\n")
+}
+
+// inputType returns the GraphQL type of an argument. Only scalars and lists
+// of scalars are typed, anything else is passed as JSON.
+func (b *sdlBuilder) inputType(s *huma.Schema) string {
+	s = deref(b.oapi, s)
+	if s == nil {
+		return "JSON"
+	}
+	if s.Type == huma.TypeArray {
+		return "[" + b.inputType(s.Items) + "]"
+	}
+	return scalarType(s)
+}
+
+// outputType returns the GraphQL type of a result, generating object types as
+// needed. Inline objects are named using the fallback.
+func (b *sdlBuilder) outputType(s *huma.Schema, fallback string) string {
+	if s == nil {
+		return "JSON"
+	}
+	name := typeName(b.oapi, s, fallback)
+	if s.Ref != "" {
+		s = deref(b.oapi, s)
+		if s == nil {
+			return "JSON"
+		}
+	}
+
+	switch s.Type {
+	case huma.TypeArray:
+		return "[" + b.outputType(s.Items, fallback) + "]"
+	case huma.TypeObject:
+		if _, ok := b.types[name]; ok {
+			return name
+		}
+		// Reserve the name first to handle recursive types.
+		b.types[name] = ""
+		sb := strings.Builder{}
+		writeDescription(&sb, "", s.Description)
+		sb.WriteString("type " + name + " {\n")
+		count := 0
+		for _, prop := range sortedKeys(s.Properties) {
+			if !validName.MatchString(prop) {
+				continue
+			}
+			ps := s.Properties[prop]
+			required := false
+			for _, r := range s.Required {
+				required = required || r == prop
+			}
+			if ps != nil {
+				required = required && !deref(b.oapi, ps).Nullable
+				writeDescription(&sb, "  ", deref(b.oapi, ps).Description)
+			}
+			sb.WriteString("  " + prop + ": " + b.outputType(ps, name+casing.Camel(prop)) + nonNull(required) + "\n")
+			count++
+		}
+		sb.WriteString("}\n")
+		if count == 0 {
+			// Free-form objects and maps have no fixed fields.
+			delete(b.types, name)
+			return "JSON"
+		}
+		b.types[name] = sb.String()
+		return name
+	}
+	return scalarType(s)
+}
+
+// scalarType returns the built-in GraphQL scalar for a JSON schema type.
+func scalarType(s *huma.Schema) string {
+	switch s.Type {
+	case huma.TypeString:
+		return "String"
+	case huma.TypeInteger:
+		return "Int"
+	case huma.TypeNumber:
+		return "Float"
+	case huma.TypeBoolean:
+		return "Boolean"
+	}
+	return "JSON"
+}
+
+func nonNull(required bool) string {
+	if required {
+		return "!"
+	}
+	return ""
+}
+
+func writeDescription(sb *strings.Builder, indent, description string) {
+	if description == "" {
+		return
+	}
+	b, _ := json.Marshal(description)
+	sb.WriteString(indent + string(b) + "\n")
+}
@@ -0,0 +1,311 @@
+package huma
+
+import (
+	"encoding/json"
+	"html"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2/casing"
+)
+
+// GroupedDocs is an `http.Handler` which serves a landing page listing the
+// OpenAPI specs and documentation of multiple APIs hosted on the same router,
+// e.g. a public and an admin API. Each API should use a server URL with a
+// distinct path prefix or distinct `OpenAPIPath` & `DocsPath` settings.
+//
+// When `Merge` is enabled, a combined OpenAPI document containing the
+// operations of all the APIs is also served at `openapi.json` and
+// `openapi.yaml` below the landing page path.
+//
+//	public := humachi.New(router, publicConfig)
+//	admin := humachi.New(router, adminConfig)
+//	docs := huma.NewGroupedDocs(public, admin)
+//	docs.Merge = true
+//	router.Handle("/apis", docs)
+//	router.Handle("/apis/*", docs)
+type GroupedDocs struct {
+	// Title of the landing page and merged OpenAPI document.
+	Title string
+
+	// APIs to include, in the order they should be listed.
+	APIs []API
+
+	// Merge enables serving a merged OpenAPI document. Tags and operation IDs
+	// are prefixed with each API's title to avoid collisions, and schemas which
+	// share a name but differ between APIs are renamed.
+	Merge bool
+}
+
+// NewGroupedDocs creates a new grouped docs handler for the given APIs.
+func NewGroupedDocs(apis ...API) *GroupedDocs {
+	return &GroupedDocs{
+		Title: "API Reference",
+		APIs:  apis,
+	}
+}
+
+// ServeHTTP serves the landing page, or the merged OpenAPI document when
+// enabled and requested.
+func (g *GroupedDocs) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if g.Merge {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/openapi.json"):
+			b, err := json.Marshal(g.OpenAPI())
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/vnd.oai.openapi+json")
+			w.Write(b)
+			return
+		case strings.HasSuffix(r.URL.Path, "/openapi.yaml"):
+			b, err := g.OpenAPI().YAML()
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/vnd.oai.openapi+yaml")
+			w.Write(b)
+			return
+		}
+	}
+
+	items := ""
+	for _, api := range g.APIs {
+		oapi := api.OpenAPI()
+		prefix := getAPIPrefix(oapi)
+		title, version, description := "", "", ""
+		if oapi.Info != nil {
+			title, version, description = oapi.Info.Title, oapi.Info.Version, oapi.Info.Description
+		}
+		items += `
+      <li>
+        <h2>` + html.EscapeString(title) + ` <small>` + html.EscapeString(version) + `</small></h2>`
+		if description != "" {
+			items += `
+        <p>` + html.EscapeString(description) + `</p>`
+		}
+		items += `
+        <p>`
+		config := apiOf(api).config
+		if config.DocsPath != "" {
+			items += `<a href="` + html.EscapeString(path.Join(prefix, config.DocsPath)) + `">Docs</a> `
+		}
+		if config.OpenAPIPath != "" {
+			spec := html.EscapeString(path.Join(prefix, config.OpenAPIPath))
+			items += `<a href="` + spec + `.json">OpenAPI JSON</a> <a href="` + spec + `.yaml">OpenAPI YAML</a>`
+		}
+		items += `</p>
+      </li>`
+	}
+	if g.Merge {
+		base := html.EscapeString(path.Join(r.URL.Path, "openapi"))
+		items += `
+      <li>
+        <h2>All APIs</h2>
+        <p><a href="` + base + `.json">OpenAPI JSON</a> <a href="` + base + `.yaml">OpenAPI YAML</a></p>
+      </li>`
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	w.Write([]byte(`<!doctype html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1" />
+    <title>` + html.EscapeString(g.Title) + `</title>
+  </head>
+  <body>
+    <h1>` + html.EscapeString(g.Title) + `</h1>
+    <ul>` + items + `
+    </ul>
+  </body>
+</html>`))
+}
+
+// OpenAPI returns a new OpenAPI document combining the operations of all the
+// APIs in the group. Paths include each API's server path prefix.
+func (g *GroupedDocs) OpenAPI() *OpenAPI {
+	merged := &OpenAPI{
+		OpenAPI: "3.1.0",
+		Info: &Info{
+			Title: g.Title,
+		},
+		Paths: map[string]*PathItem{},
+		Components: &Components{
+			Schemas:         NewMapRegistry("#/components/schemas/", DefaultSchemaNamer),
+			SecuritySchemes: map[string]*SecurityScheme{},
+		},
+	}
+	schemas := merged.Components.Schemas.Map()
+	versions := []string{}
+	tags := map[string]bool{}
+
+	for _, api := range g.APIs {
+		oapi := api.OpenAPI()
+		prefix := getAPIPrefix(oapi)
+		title := ""
+		if oapi.Info != nil {
+			title = oapi.Info.Title
+			if oapi.Info.Version != "" && !slices.Contains(versions, oapi.Info.Version) {
+				versions = append(versions, oapi.Info.Version)
+			}
+		}
+		if merged.Servers == nil {
+			for _, server := range oapi.Servers {
+				// Keep only the scheme & host, as paths now include the prefix.
+				if u, err := url.Parse(server.URL); err == nil && u.Host != "" {
+					u.Path = ""
+					merged.Servers = []*Server{{URL: u.String()}}
+					break
+				}
+			}
+		}
+
+		// Copy schemas, renaming any which conflict with another API's schemas.
+		renames := map[string]string{}
+		rewrite := func(s *Schema) *Schema {
+			if len(renames) == 0 {
+				return s
+			}
+			return copySchema(s, func(c *Schema) {
+				if name, ok := strings.CutPrefix(c.Ref, "#/components/schemas/"); ok && renames[name] != "" {
+					c.Ref = "#/components/schemas/" + renames[name]
+				}
+			})
+		}
+		if oapi.Components != nil && oapi.Components.Schemas != nil {
+			for name, s := range oapi.Components.Schemas.Map() {
+				if existing, ok := schemas[name]; ok && !sameSchema(existing, s) {
+					renames[name] = casing.Camel(title, casing.Identity) + name
+				}
+			}
+			for name, s := range oapi.Components.Schemas.Map() {
+				if renamed := renames[name]; renamed != "" {
+					name = renamed
+				}
+				if _, ok := schemas[name]; !ok {
+					schemas[name] = rewrite(s)
+				}
+			}
+		}
+
+		for p, item := range oapi.Paths {
+			p = path.Join("/", prefix, p)
+			mergedItem := merged.Paths[p]
+			if mergedItem == nil {
+				mergedItem = &PathItem{}
+				merged.Paths[p] = mergedItem
+			}
+			for _, pair := range []struct {
+				src **Operation
+				dst **Operation
+			}{
+				{&item.Get, &mergedItem.Get}, {&item.Put, &mergedItem.Put},
+				{&item.Post, &mergedItem.Post}, {&item.Delete, &mergedItem.Delete},
+				{&item.Options, &mergedItem.Options}, {&item.Head, &mergedItem.Head},
+				{&item.Patch, &mergedItem.Patch}, {&item.Trace, &mergedItem.Trace},
+			} {
+				if *pair.src == nil || *pair.dst != nil {
+					// Nothing to copy, or another API already defines it.
+					continue
+				}
+				*pair.dst = mergeOperation(*pair.src, oapi, title, rewrite)
+				for _, tag := range (*pair.dst).Tags {
+					if !tags[tag] {
+						tags[tag] = true
+						merged.Tags = append(merged.Tags, &Tag{Name: tag})
+					}
+				}
+			}
+		}
+
+		if oapi.Components != nil {
+			for name, scheme := range oapi.Components.SecuritySchemes {
+				if merged.Components.SecuritySchemes[name] == nil {
+					merged.Components.SecuritySchemes[name] = scheme
+				}
+			}
+		}
+	}
+
+	merged.Info.Version = strings.Join(versions, ", ")
+	return merged
+}
+
+// mergeOperation returns a copy of the operation suitable for a merged OpenAPI
+// document, with a prefixed operation ID and tags and any renamed schema
+// references rewritten.
+func mergeOperation(op *Operation, oapi *OpenAPI, title string, rewrite func(*Schema) *Schema) *Operation {
+	c := *op
+	if c.OperationID != "" {
+		c.OperationID = casing.Kebab(title) + "-" + c.OperationID
+	}
+	if len(c.Tags) == 0 {
+		c.Tags = []string{title}
+	} else {
+		c.Tags = make([]string, len(op.Tags))
+		for i, tag := range op.Tags {
+			c.Tags[i] = title + ": " + tag
+		}
+	}
+	if c.Security == nil {
+		// Operations inherit the API's global security requirements.
+		c.Security = oapi.Security
+	}
+	if c.Parameters != nil {
+		c.Parameters = make([]*Param, len(op.Parameters))
+		for i, p := range op.Parameters {
+			cp := *p
+			cp.Schema = rewrite(p.Schema)
+			cp.Content = rewriteContent(p.Content, rewrite)
+			c.Parameters[i] = &cp
+		}
+	}
+	if c.RequestBody != nil {
+		body := *c.RequestBody
+		body.Content = rewriteContent(body.Content, rewrite)
+		c.RequestBody = &body
+	}
+	if c.Responses != nil {
+		c.Responses = make(map[string]*Response, len(op.Responses))
+		for status, resp := range op.Responses {
+			cr := *resp
+			cr.Content = rewriteContent(resp.Content, rewrite)
+			if resp.Headers != nil {
+				cr.Headers = make(map[string]*Header, len(resp.Headers))
+				for name, h := range resp.Headers {
+					ch := *h
+					ch.Schema = rewrite(h.Schema)
+					cr.Headers[name] = &ch
+				}
+			}
+			c.Responses[status] = &cr
+		}
+	}
+	return &c
+}
+
+func rewriteContent(content map[string]*MediaType, rewrite func(*Schema) *Schema) map[string]*MediaType {
+	if content == nil {
+		return nil
+	}
+	result := make(map[string]*MediaType, len(content))
+	for ct, mt := range content {
+		c := *mt
+		c.Schema = rewrite(mt.Schema)
+		result[ct] = &c
+	}
+	return result
+}
+
+// sameSchema returns whether two schemas serialize identically.
+func sameSchema(a, b *Schema) bool {
+	ab, errA := json.Marshal(a)
+	bb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ab) == string(bb)
+}
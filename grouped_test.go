@@ -0,0 +1,117 @@
+package huma_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+func TestGroupedDocs(t *testing.T) {
+	type Thing struct {
+		ID string `json:"id"`
+	}
+
+	publicConfig := huma.DefaultConfig("Public API", "1.0.0")
+	publicConfig.Servers = []*huma.Server{{URL: "https://example.com/public"}}
+	_, public := humatest.New(t, publicConfig)
+	huma.Register(public, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+		Tags:        []string{"Things"},
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{ Body Thing }, error) {
+		return nil, nil
+	})
+
+	// The admin API has its own, different `Thing` schema.
+	type AdminThing struct {
+		ID    string `json:"id"`
+		Owner string `json:"owner"`
+	}
+	adminConfig := huma.DefaultConfig("Admin API", "2.0.0")
+	adminConfig.Servers = []*huma.Server{{URL: "https://example.com/admin"}}
+	adminConfig.DocsPath = ""
+	_, admin := humatest.New(t, adminConfig)
+	huma.Register(admin, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{ Body AdminThing }, error) {
+		return nil, nil
+	})
+	admin.OpenAPI().Components.Schemas.Map()["Thing"] = admin.OpenAPI().Components.Schemas.Map()["AdminThing"]
+	delete(admin.OpenAPI().Components.Schemas.Map(), "AdminThing")
+	admin.OpenAPI().Paths["/things/{id}"].Get.Responses["200"].Content["application/json"].Schema.Ref = "#/components/schemas/Thing"
+
+	docs := huma.NewGroupedDocs(public, admin)
+	docs.Merge = true
+
+	// Landing page
+	w := httptest.NewRecorder()
+	docs.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/apis", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "Public API")
+	assert.Contains(t, body, `href="/public/docs"`)
+	assert.Contains(t, body, `href="/public/openapi.yaml"`)
+	assert.Contains(t, body, "Admin API")
+	assert.NotContains(t, body, `href="/admin/docs"`)
+	assert.Contains(t, body, `href="/admin/openapi.json"`)
+	assert.Contains(t, body, `href="/apis/openapi.json"`)
+
+	// Merged spec
+	w = httptest.NewRecorder()
+	docs.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/apis/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var merged struct {
+		Info    map[string]any `json:"info"`
+		Servers []map[string]any
+		Paths   map[string]map[string]struct {
+			OperationID string   `json:"operationId"`
+			Tags        []string `json:"tags"`
+			Responses   map[string]struct {
+				Content map[string]struct {
+					Schema map[string]any `json:"schema"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &merged))
+	assert.Equal(t, "1.0.0, 2.0.0", merged.Info["version"])
+	assert.Equal(t, "https://example.com", merged.Servers[0]["url"])
+
+	pub := merged.Paths["/public/things/{id}"]["get"]
+	assert.Equal(t, "public-api-get-thing", pub.OperationID)
+	assert.Equal(t, []string{"Public API: Things"}, pub.Tags)
+	assert.Equal(t, "#/components/schemas/Thing", pub.Responses["200"].Content["application/json"].Schema["$ref"])
+
+	adm := merged.Paths["/admin/things/{id}"]["get"]
+	assert.Equal(t, "admin-api-get-thing", adm.OperationID)
+	assert.Equal(t, []string{"Admin API"}, adm.Tags)
+	assert.Equal(t, "#/components/schemas/AdminAPIThing", adm.Responses["200"].Content["application/json"].Schema["$ref"])
+
+	assert.Contains(t, merged.Components.Schemas, "Thing")
+	assert.Contains(t, merged.Components.Schemas, "AdminAPIThing")
+	assert.Contains(t, merged.Components.Schemas, "ErrorModel")
+
+	w = httptest.NewRecorder()
+	docs.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/apis/openapi.yaml", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "title: API Reference")
+}
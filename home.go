@@ -0,0 +1,132 @@
+package huma
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+)
+
+// jsonHome is an API home document, which lists the API's resources so that
+// clients can discover them without downloading the full OpenAPI. See
+// https://datatracker.ietf.org/doc/html/draft-nottingham-json-home.
+type jsonHome struct {
+	API       jsonHomeAPI                  `json:"api"`
+	Resources map[string]*jsonHomeResource `json:"resources"`
+}
+
+type jsonHomeAPI struct {
+	Title string            `json:"title,omitempty"`
+	Links map[string]string `json:"links,omitempty"`
+}
+
+type jsonHomeResource struct {
+	Href         string            `json:"href,omitempty"`
+	HrefTemplate string            `json:"hrefTemplate,omitempty"`
+	HrefVars     map[string]string `json:"hrefVars,omitempty"`
+	Hints        jsonHomeHints     `json:"hints"`
+}
+
+type jsonHomeHints struct {
+	Allow       []string            `json:"allow"`
+	Formats     map[string]struct{} `json:"formats,omitempty"`
+	AcceptPost  []string            `json:"acceptPost,omitempty"`
+	AcceptPatch []string            `json:"acceptPatch,omitempty"`
+	Status      string              `json:"status,omitempty"`
+}
+
+// renderHome creates the home document for the API's operations. Each
+// operation is a resource named by its operation ID, with a URI template
+// including its path & query params, and hints describing the allowed method
+// and content types. Variables are identified by relative `param/{name}` URIs.
+func renderHome(oapi *OpenAPI, specPath string) ([]byte, error) {
+	prefix := getAPIPrefix(oapi)
+
+	home := jsonHome{
+		Resources: map[string]*jsonHomeResource{},
+	}
+	if oapi.Info != nil {
+		home.API.Title = oapi.Info.Title
+		if oapi.Info.License != nil && oapi.Info.License.URL != "" {
+			home.API.Links = map[string]string{"license": oapi.Info.License.URL}
+		}
+	}
+	if specPath != "" {
+		if home.API.Links == nil {
+			home.API.Links = map[string]string{}
+		}
+		home.API.Links["describedBy"] = path.Join(prefix, specPath) + ".json"
+	}
+
+	eachOperation(oapi, func(location string, op *Operation) {
+		method, p, _ := strings.Cut(location, " ")
+		name := op.OperationID
+		if name == "" {
+			name = strings.ToLower(method) + p
+		}
+
+		resource := &jsonHomeResource{
+			Hints: jsonHomeHints{Allow: []string{method}},
+		}
+
+		vars := map[string]string{}
+		query := []string{}
+		for _, param := range op.Parameters {
+			switch param.In {
+			case "path":
+				vars[param.Name] = "param/" + param.Name
+			case "query":
+				vars[param.Name] = "param/" + param.Name
+				query = append(query, param.Name)
+			}
+		}
+		href := path.Join(prefix, p)
+		if strings.HasSuffix(p, "/") && !strings.HasSuffix(href, "/") {
+			href += "/"
+		}
+		if len(vars) > 0 {
+			if len(query) > 0 {
+				href += "{?" + strings.Join(query, ",") + "}"
+			}
+			resource.HrefTemplate = href
+			resource.HrefVars = vars
+		} else {
+			resource.Href = href
+		}
+
+		for status, response := range op.Responses {
+			if !strings.HasPrefix(status, "2") {
+				continue
+			}
+			for ct := range response.Content {
+				if resource.Hints.Formats == nil {
+					resource.Hints.Formats = map[string]struct{}{}
+				}
+				resource.Hints.Formats[ct] = struct{}{}
+			}
+		}
+
+		if op.RequestBody != nil {
+			accept := make([]string, 0, len(op.RequestBody.Content))
+			for ct := range op.RequestBody.Content {
+				accept = append(accept, ct)
+			}
+			sort.Strings(accept)
+			switch method {
+			case http.MethodPost:
+				resource.Hints.AcceptPost = accept
+			case http.MethodPatch:
+				resource.Hints.AcceptPatch = accept
+			}
+		}
+
+		if op.Deprecated {
+			resource.Hints.Status = "deprecated"
+		}
+
+		home.Resources[name] = resource
+	})
+
+	return json.Marshal(home)
+}
@@ -9,11 +9,15 @@ package huma
 
 import (
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"encoding"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net"
 	"net/http"
 	"net/url"
@@ -26,11 +30,15 @@ import (
 	"time"
 
 	"github.com/danielgtaylor/huma/v2/casing"
+	"github.com/danielgtaylor/huma/v2/negotiation"
+	"github.com/danielgtaylor/huma/v2/validation"
 )
 
 var errDeadlineUnsupported = fmt.Errorf("%w", http.ErrNotSupported)
 
 var bodyCallbackType = reflect.TypeOf(func(Context) {})
+var readerType = reflect.TypeFor[io.Reader]()
+var readerBodyType = reflect.TypeFor[ReaderBody]()
 var cookieType = reflect.TypeOf((*http.Cookie)(nil)).Elem()
 var fmtStringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
 var stringType = reflect.TypeOf("")
@@ -54,6 +62,74 @@ func SetReadDeadline(w http.ResponseWriter, deadline time.Time) error {
 	}
 }
 
+// EarlyHints sends a `103 Early Hints` informational response with the given
+// `Link` header values, e.g. `</style.css>; rel=preload; as=style`, so clients
+// can start loading critical resources while the final response is prepared.
+// Context wrappers are unwrapped via their `Unwrap() Context` method until one
+// implements `EarlyHintsContext`. It is a no-op if the router or protocol does
+// not support it.
+//
+//	huma.EarlyHints(ctx, []string{"</style.css>; rel=preload; as=style"})
+func EarlyHints(ctx Context, links []string) {
+	for {
+		switch c := ctx.(type) {
+		case EarlyHintsContext:
+			c.EarlyHints(links)
+			return
+		case interface{ Unwrap() Context }:
+			ctx = c.Unwrap()
+		default:
+			return
+		}
+	}
+}
+
+// SetTrailer sets a trailer header which is sent after the response body,
+// e.g. a checksum of streamed data. Context wrappers are unwrapped via their
+// `Unwrap() Context` method until one implements `TrailerContext`. Trailers
+// require a chunked HTTP/1.1 or an HTTP/2 response, and are ignored if the
+// router does not support them.
+//
+//	huma.SetTrailer(ctx, "X-Checksum", hex.EncodeToString(hash.Sum(nil)))
+func SetTrailer(ctx Context, name, value string) {
+	for {
+		switch c := ctx.(type) {
+		case TrailerContext:
+			c.SetTrailer(name, value)
+			return
+		case interface{ Unwrap() Context }:
+			ctx = c.Unwrap()
+		default:
+			return
+		}
+	}
+}
+
+// WriteEarlyHints is a utility to send a `103 Early Hints` informational
+// response with the given `Link` header values, for adapters to implement
+// `EarlyHintsContext`. Like `http.ResponseController`, wrapped response
+// writers are unwrapped so that the hints are not mistaken for the final
+// status. The links are also kept in the final response's headers. Nothing is
+// sent for HTTP/1.0 requests, which do not support informational responses.
+//
+//	huma.WriteEarlyHints(w, r, []string{"</style.css>; rel=preload; as=style"})
+func WriteEarlyHints(w http.ResponseWriter, r *http.Request, links []string) {
+	if len(links) == 0 || !r.ProtoAtLeast(1, 1) {
+		return
+	}
+	for {
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			break
+		}
+		w = u.Unwrap()
+	}
+	for _, link := range links {
+		w.Header().Add("Link", link)
+	}
+	w.WriteHeader(http.StatusEarlyHints)
+}
+
 // StreamResponse is a response that streams data to the client. The body
 // function will be called once the response headers have been written and
 // the body writer is ready to be written to.
@@ -79,6 +155,52 @@ func SetReadDeadline(w http.ResponseWriter, deadline time.Time) error {
 //	}
 type StreamResponse struct {
 	Body func(ctx Context)
+
+	// Trailers lists the names of trailer headers which the body function
+	// will set via `huma.SetTrailer`, announcing them to the client in the
+	// `Trailer` response header.
+	Trailers []string
+
+	// OnClientDisconnect is called after the body function returns if the
+	// client went away before the response was complete, i.e. the request
+	// context was canceled or writing to the body failed. It is not called
+	// when the body function finishes or stops on its own, letting handlers
+	// tell client disconnects apart from server-side termination to clean up
+	// resources like subscriptions.
+	OnClientDisconnect func(ctx Context)
+}
+
+// ReaderBody is a response body which is streamed from a reader, e.g. a file
+// from object storage, with a known length and content type. Use it, or any
+// `io.Reader` type, as the output `Body` field. The reader is closed after
+// the response is written if it implements `io.Closer`.
+//
+//	type DownloadOutput struct {
+//		Body huma.ReaderBody
+//	}
+//
+//	func handler(ctx context.Context, input *struct{}) (*DownloadOutput, error) {
+//		obj, err := bucket.Get(ctx, "large-file.zip")
+//		if err != nil {
+//			return nil, err
+//		}
+//		return &DownloadOutput{Body: huma.ReaderBody{
+//			Reader:      obj.Body,
+//			Length:      obj.Size,
+//			ContentType: "application/zip",
+//		}}, nil
+//	}
+type ReaderBody struct {
+	// Reader provides the response body.
+	Reader io.Reader
+
+	// Length sets the `Content-Length` header if greater than zero.
+	Length int64
+
+	// ContentType sets the `Content-Type` header, unless it is set by an
+	// output header field. Defaults to the body field's `contentType` tag or
+	// `application/octet-stream`.
+	ContentType string
 }
 
 type paramFieldInfo struct {
@@ -89,7 +211,31 @@ type paramFieldInfo struct {
 	Default    string
 	TimeFormat string
 	Explode    bool
+	NoSplit    bool
 	Schema     *Schema
+
+	// Aliases are old names of a renamed query or header param, which are
+	// still accepted but deprecated.
+	Aliases    []string
+	Deprecated bool
+
+	// ValidateSchema is used to validate the parsed value. It is the same as
+	// `Schema` except for slices with `uniqueItems`, where duplicates are
+	// reported per index by `validateUniqueItems` instead.
+	ValidateSchema *Schema
+	UniqueItems    bool
+
+	// TextUnmarshaler is set for non-string scalar types like `huma.Duration`
+	// which are parsed from text rather than by their underlying kind.
+	TextUnmarshaler bool
+
+	// Lenient enables parsing localized decimals and dates, see the
+	// `lenient` tag.
+	Lenient bool
+
+	// JSON makes the value get parsed as a JSON document, e.g. for struct
+	// query params via `query:"filter,json"`.
+	JSON bool
 }
 
 func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*paramFieldInfo] {
@@ -121,10 +267,24 @@ func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*p
 			if slices.Contains(split[1:], "explode") {
 				pfi.Explode = true
 			}
-			explode = &pfi.Explode
+			if slices.Contains(split[1:], "json") {
+				// The value is a JSON document, which is documented via the
+				// param's content rather than a style.
+				pfi.JSON = true
+			} else {
+				explode = &pfi.Explode
+			}
 		} else if h := f.Tag.Get("header"); h != "" {
 			pfi.Loc = "header"
-			name = h
+			split := strings.Split(h, ",")
+			name = split[0]
+			if f.Type.Kind() == reflect.Slice {
+				// Repeated headers are combined and split on commas as a list
+				// (RFC 9110 section 5.3), which is the `simple` style without
+				// `explode`. Use `nosplit` for values which may contain commas.
+				pfi.NoSplit = slices.Contains(split[1:], "nosplit")
+				explode = &pfi.Explode
+			}
 		} else if c := f.Tag.Get("cookie"); c != "" {
 			pfi.Loc = "cookie"
 			name = c
@@ -134,6 +294,10 @@ func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*p
 				// `http.Cookie` struct.
 				f.Type = stringType
 			}
+		} else if sv := f.Tag.Get("server"); sv != "" {
+			pfi.Loc = "server"
+			name = sv
+			pfi.Required = true
 		} else {
 			return nil
 		}
@@ -148,6 +312,18 @@ func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*p
 		}
 
 		pfi.Schema = SchemaFromField(registry, f, "")
+		pfi.ValidateSchema = pfi.Schema
+		if pfi.Schema.UniqueItems && f.Type.Kind() == reflect.Slice && f.Type.Elem().Comparable() {
+			s := *pfi.Schema
+			s.UniqueItems = false
+			pfi.ValidateSchema = &s
+			pfi.UniqueItems = true
+		}
+		switch f.Type.Kind() {
+		case reflect.String, reflect.Slice, reflect.Struct:
+		default:
+			pfi.TextUnmarshaler = !pfi.JSON && reflect.PointerTo(f.Type).Implements(textUnmarshalerType)
+		}
 
 		var example any
 		if e := f.Tag.Get("example"); e != "" {
@@ -163,6 +339,14 @@ func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*p
 		}
 
 		pfi.Name = name
+		pfi.Deprecated = boolTag(f, "deprecated", false)
+		pfi.Lenient = boolTag(f, "lenient", false)
+		if alias := f.Tag.Get("alias"); alias != "" {
+			if pfi.Loc != "query" && pfi.Loc != "header" {
+				panic(fmt.Errorf("alias tag is only supported for query and header params, field '%s'", f.Name))
+			}
+			pfi.Aliases = strings.Split(alias, ",")
+		}
 
 		if f.Type == timeType {
 			timeFormat := time.RFC3339Nano
@@ -175,7 +359,8 @@ func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*p
 			pfi.TimeFormat = timeFormat
 		}
 
-		if !boolTag(f, "hidden", false) {
+		// Server variables are documented on the servers, not the operation.
+		if pfi.Loc != "server" && !boolTag(f, "hidden", false) {
 			desc := ""
 			if pfi.Schema != nil {
 				// If the schema has a description, use it. Some tools will not show
@@ -184,21 +369,58 @@ func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*p
 			}
 
 			// Document the parameter if not hidden.
-			op.Parameters = append(op.Parameters, &Param{
+			param := &Param{
 				Name:        name,
 				Description: desc,
 				In:          pfi.Loc,
+				Deprecated:  pfi.Deprecated,
 				Explode:     explode,
 				Required:    pfi.Required,
 				Schema:      pfi.Schema,
 				Example:     example,
-			})
+			}
+			if pfi.JSON {
+				param.Content = map[string]*MediaType{
+					"application/json": {Schema: pfi.Schema, Example: example},
+				}
+				param.Schema = nil
+				param.Example = nil
+			}
+			op.Parameters = append(op.Parameters, param)
+
+			// Document the aliases as deprecated params.
+			for _, alias := range pfi.Aliases {
+				op.Parameters = append(op.Parameters, &Param{
+					Name:        alias,
+					Description: "Deprecated: use `" + name + "` instead.",
+					In:          pfi.Loc,
+					Deprecated:  true,
+					Explode:     explode,
+					Schema:      pfi.Schema,
+				})
+			}
 		}
 
 		return pfi
 	}, false, "Body")
 }
 
+// validateUniqueItems reports each duplicate item of a slice param at its
+// index, e.g. `query.tags[2]`, so clients can tell which value to remove.
+func validateUniqueItems(pb *PathBuffer, f reflect.Value, res *ValidateResult) {
+	seen := make(map[any]struct{}, f.Len())
+	for i := 0; i < f.Len(); i++ {
+		item := f.Index(i).Interface()
+		if _, ok := seen[item]; ok {
+			pb.PushIndex(i)
+			res.addMsg(pb, item, "", validation.MsgExpectedArrayItemsUnique)
+			pb.Pop()
+			continue
+		}
+		seen[item] = struct{}{}
+	}
+}
+
 func findResolvers(resolverType, t reflect.Type) *findResult[bool] {
 	return findInType(t, func(t reflect.Type, path []int) bool {
 		tp := reflect.PointerTo(t)
@@ -475,7 +697,7 @@ func writeResponse(api API, ctx Context, status int, ct string, body any) error
 		var err error
 		ct, err = api.Negotiate(ctx.Header("Accept"))
 		if err != nil {
-			notAccept := NewErrorWithContext(ctx, http.StatusNotAcceptable, "unable to marshal response", err)
+			notAccept := newErrorForContext(ctx, http.StatusNotAcceptable, "unable to marshal response", err)
 			if e := transformAndWrite(api, ctx, http.StatusNotAcceptable, "application/json", notAccept); e != nil {
 				return e
 			}
@@ -566,8 +788,11 @@ func writeHeader(write func(string, string), info *headerInfo, f reflect.Value)
 	case reflect.Bool:
 		write(info.Name, strconv.FormatBool(f.Bool()))
 	default:
-		if f.Type() == timeType && !f.Interface().(time.Time).IsZero() {
-			write(info.Name, f.Interface().(time.Time).Format(info.TimeFormat))
+		if f.Type() == timeType {
+			// Don't set empty headers.
+			if t := f.Interface().(time.Time); !t.IsZero() {
+				write(info.Name, t.Format(info.TimeFormat))
+			}
 			return
 		}
 
@@ -605,12 +830,63 @@ func writeHeader(write func(string, string), info *headerInfo, f reflect.Value)
 //		return resp, nil
 //	})
 func Register[I, O any](api API, op Operation, handler func(context.Context, *I) (*O, error)) {
+	register(api, op, handler, nil)
+}
+
+// RegisterValue registers an operation handler which returns its output
+// struct by value rather than as a pointer. It otherwise works like
+// `huma.Register`. Outputs are copied into pooled structs which are reused
+// once the response has been written, avoiding a heap allocation per request
+// for small output structs. Returning the zero value is not special cased, so
+// it is written like any other output.
+//
+//	huma.RegisterValue(api, huma.Operation{
+//		OperationID: "get-greeting",
+//		Method:      http.MethodGet,
+//		Path:        "/greeting/{name}",
+//	}, func(ctx context.Context, input *GreetingInput) (GreetingOutput, error) {
+//		var resp GreetingOutput
+//		resp.Body.Message = fmt.Sprintf("Hello, %s!", input.Name)
+//		return resp, nil
+//	})
+func RegisterValue[I, O any](api API, op Operation, handler func(context.Context, *I) (O, error)) {
+	pool := sync.Pool{New: func() any { return new(O) }}
+	register(api, op, func(ctx context.Context, input *I) (*O, error) {
+		out, err := handler(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		p := pool.Get().(*O)
+		*p = out
+		return p, nil
+	}, func(p *O) {
+		var zero O
+		*p = zero
+		pool.Put(p)
+	})
+}
+
+// register implements `Register`. If set, `release` is called with each
+// output once its response has been written.
+func register[I, O any](api API, op Operation, handler func(context.Context, *I) (*O, error), release func(*O)) {
+	base := apiOf(api)
+	config := &base.config
 	oapi := api.OpenAPI()
 	registry := oapi.Components.Schemas
+	names := &fieldNames{registry: registry, aliases: config.FieldAliases, caseInsensitive: config.CaseInsensitiveFields}
+
+	validationStatus := http.StatusUnprocessableEntity
+	if config.ValidationErrorStatus != 0 {
+		validationStatus = config.ValidationErrorStatus
+	}
 
 	if op.Method == "" || op.Path == "" {
 		panic("method and path must be specified in operation")
 	}
+	base.checkConflicts(&op)
+	if op.NewError == nil {
+		op.NewError = config.NewError
+	}
 	initResponses(&op)
 
 	inputType := reflect.TypeOf((*I)(nil)).Elem()
@@ -618,16 +894,99 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		panic("input must be a struct")
 	}
 	inputParams, inputBodyIndex, hasInputBody, rawBodyIndex, rbt, inSchema := processInputType(inputType, &op, registry)
+	passthrough := findPassthroughParams(inputType, &op, inputParams)
+	if hasInputBody && config.BodyLimits != nil && !slices.Contains(op.Errors, http.StatusRequestEntityTooLarge) {
+		op.Errors = append(op.Errors, http.StatusRequestEntityTooLarge)
+	}
+	for _, p := range inputParams.Paths {
+		if p.Value.Loc == "server" && oapi.serverVariable(p.Value.Name) == nil {
+			panic(fmt.Errorf("server variable '%s' is not declared in the OpenAPI servers for %s %s", p.Value.Name, op.Method, op.Path))
+		}
+	}
+	maxDecompressedBytes := op.MaxDecompressedBodyBytes
+	if maxDecompressedBytes == 0 {
+		maxDecompressedBytes = op.MaxBodyBytes
+	}
 
 	outputType := reflect.TypeOf((*O)(nil)).Elem()
 	if outputType.Kind() != reflect.Struct {
 		panic("output must be a struct")
 	}
-	outHeaders, outStatusIndex, outBodyIndex, outBodyFunc := processOutputType(outputType, &op, registry)
+	outHeaders, outStatusIndex, outBodyIndex, outBodyFunc, outReaderContentType := processOutputType(outputType, &op, registry)
+	if len(op.ResponseVersions) > 0 {
+		documentResponseVersions(&op, registry)
+	}
+
+	if len(op.Trailers) > 0 {
+		for status, resp := range op.Responses {
+			if !strings.HasPrefix(status, "2") {
+				continue
+			}
+			if resp.Extensions == nil {
+				resp.Extensions = map[string]any{}
+			}
+			resp.Extensions["x-trailers"] = op.Trailers
+		}
+	}
+
+	if len(op.RequiredScopes) > 0 {
+		op.Security = withScopes(op.Security, oapi.Security, op.RequiredScopes)
+		if !slices.Contains(op.Errors, http.StatusForbidden) {
+			op.Errors = append(op.Errors, http.StatusForbidden)
+		}
+	}
+
+	if op.Prefer {
+		op.Parameters = append(op.Parameters, &Param{
+			Name:        "Prefer",
+			In:          "header",
+			Description: "Send `return=minimal` to omit the response body, or `return=representation` to include it.",
+			Schema:      &Schema{Type: TypeString},
+			Example:     "return=minimal",
+		})
+	}
+
+	if op.VerifyDigest {
+		op.Parameters = append(op.Parameters, digestParams()...)
+		if !slices.Contains(op.Errors, http.StatusBadRequest) {
+			op.Errors = append(op.Errors, http.StatusBadRequest)
+		}
+	}
+
+	if op.MaxRequestTimeout > 0 {
+		op.Parameters = append(op.Parameters, &Param{
+			Name:        "X-Request-Timeout",
+			In:          "header",
+			Description: "Maximum time the server should spend on the request, as a duration like `1.5s` or a number of seconds. A gRPC-style `grpc-timeout` header is also accepted. Limited to " + op.MaxRequestTimeout.String() + ".",
+			Schema:      &Schema{Type: TypeString},
+			Example:     "1.5s",
+		})
+		if !slices.Contains(op.Errors, http.StatusGatewayTimeout) {
+			op.Errors = append(op.Errors, http.StatusGatewayTimeout)
+		}
+	}
+
+	errorBodies := findErrorBodies(&op)
+
+	if len(op.ErrorCodes) > 0 || len(errorBodies) > 0 {
+		statuses := make([]int, 0, len(op.ErrorCodes)+len(errorBodies))
+		for status := range op.ErrorCodes {
+			statuses = append(statuses, status)
+		}
+		for _, body := range errorBodies {
+			statuses = append(statuses, body.status)
+		}
+		slices.Sort(statuses)
+		for _, status := range statuses {
+			if !slices.Contains(op.Errors, status) {
+				op.Errors = append(op.Errors, status)
+			}
+		}
+	}
 
 	if len(op.Errors) > 0 {
 		if len(inputParams.Paths) > 0 || hasInputBody {
-			op.Errors = append(op.Errors, http.StatusUnprocessableEntity)
+			op.Errors = append(op.Errors, validationStatus)
 		}
 		op.Errors = append(op.Errors, http.StatusInternalServerError)
 	}
@@ -639,25 +998,59 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 
 	resolvers := findResolvers(resolverType, inputType)
 	defaults := findDefaults(registry, inputType)
-	a := api.Adapter()
-	a.Handle(&op, api.Middlewares().Handler(op.Middlewares.Handler(func(ctx Context) {
+	var outDefaults *findResult[any]
+	if op.ApplyResponseDefaults && outBodyIndex != -1 && !outBodyFunc && outReaderContentType == "" {
+		outDefaults = findDefaults(registry, outputType.Field(outBodyIndex).Type)
+	}
+	var opMiddlewares Middlewares
+	if op.Enabled != nil {
+		opMiddlewares = append(opMiddlewares, enabledMiddleware(api, &op))
+	}
+	for _, cm := range base.conditional {
+		if cm.match(&op) {
+			opMiddlewares = append(opMiddlewares, cm.middlewares...)
+		}
+	}
+	opMiddlewares = append(opMiddlewares, op.Middlewares...)
+
+	handle := func(ctx Context) {
 		var input I
+		timing, _ := ctx.(*serverTiming)
 
 		// Get the validation dependencies from the shared pool.
 		deps := validatePool.Get().(*validateDeps)
 		defer func() {
 			deps.pb.Reset()
 			deps.res.Reset()
+			deps.res.MessageFunc = nil
+			deps.res.CollectUnknown = false
 			validatePool.Put(deps)
 		}()
 		pb := deps.pb
 		res := deps.res
+		res.CollectUnknown = config.OnUnknownFields != nil
+		if config.MessageFunc != nil {
+			res.MessageFunc = func(format string, args ...any) string {
+				return config.MessageFunc(ctx, format, args...)
+			}
+		}
 
-		errStatus := http.StatusUnprocessableEntity
+		errStatus := validationStatus
 
 		var cookies map[string]*http.Cookie
 
+		// Parsed params are only collected when resolvers can access them.
+		var params Params
+		if len(resolvers.Paths) > 0 {
+			params = Params{}
+		}
+
+		var deprecatedParams []string
+
 		v := reflect.ValueOf(&input).Elem()
+		if passthrough != nil {
+			passthrough.set(ctx, v)
+		}
 		inputParams.Every(v, func(f reflect.Value, p *paramFieldInfo) {
 			f = reflect.Indirect(f)
 			if f.Kind() == reflect.Invalid {
@@ -680,15 +1073,42 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 					// Special case: http.Cookie type, meaning we want the entire parsed
 					// cookie struct, not just the value.
 					f.Set(reflect.ValueOf(c).Elem())
+					if params != nil {
+						params.set(p.Loc, p.Name, f.Interface())
+					}
 					return
 				}
 			}
 
-			value := getParamValue(*p, ctx, cookies)
+			var value string
+			if p.Loc == "server" {
+				value = oapi.serverVariableValue(ctx, p.Name, config.ServerVariableFunc)
+			} else {
+				value = getParamValue(*p, ctx, cookies)
+			}
+			if value == "" {
+				for _, alias := range p.Aliases {
+					aliased := *p
+					aliased.Name = alias
+					if value = getParamValue(aliased, ctx, cookies); value != "" {
+						// Parse the value using the old name and report errors there.
+						p = &aliased
+						pb.Pop()
+						pb.Push(alias)
+						deprecatedParams = append(deprecatedParams, p.Loc+"."+alias)
+						break
+					}
+				}
+			} else if p.Deprecated {
+				deprecatedParams = append(deprecatedParams, p.Loc+"."+p.Name)
+			}
+			if value == "" {
+				value = p.Default
+			}
 			if value == "" {
 				if !op.SkipValidateParams && p.Required {
 					// Path params are always required.
-					res.Add(pb, "", "required "+p.Loc+" parameter is missing")
+					res.addMsg(pb, "", "", validation.MsgRequiredParameterMissing, p.Loc)
 				}
 				return
 			}
@@ -696,13 +1116,26 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			pv, err := parseInto(ctx, f, value, *p)
 			if err != nil {
 				res.Add(pb, value, err.Error())
+			} else if params != nil {
+				params.set(p.Loc, p.Name, f.Interface())
 			}
 
 			if !op.SkipValidateParams {
-				Validate(oapi.Components.Schemas, p.Schema, pb, ModeWriteToServer, pv, res)
+				validateStart := timing.now()
+				if p.UniqueItems {
+					validateUniqueItems(pb, f, res)
+				}
+				Validate(oapi.Components.Schemas, p.ValidateSchema, pb, ModeWriteToServer, pv, res)
+				timing.trackValidate(validateStart)
 			}
 		})
 
+		if len(deprecatedParams) > 0 {
+			// Let clients know they are using deprecated params.
+			ctx.SetHeader("Deprecation", "true")
+			res.Deprecated = append(res.Deprecated, deprecatedParams...)
+		}
+
 		// Read input body if defined.
 		if hasInputBody || len(rawBodyIndex) > 0 {
 			if op.BodyReadTimeout > 0 {
@@ -713,7 +1146,13 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			}
 
 			if rbt.isMultipart() {
-				if cErr := processMultipartMsgBody(op, ctx, v, rbt, rawBodyIndex, res); cErr != nil {
+				cleanup, cErr := processMultipartMsgBody(op, ctx, config.Multipart, v, rbt, rawBodyIndex, validationStatus, res)
+				if cleanup != nil {
+					// Remove temporary files once the handler is done, even if
+					// it panics.
+					defer cleanup()
+				}
+				if cErr != nil {
 					writeErr(api, ctx, cErr, *res)
 					return
 				}
@@ -729,7 +1168,28 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 					writeErr(api, ctx, cErr, *res)
 					return
 				}
+				if op.VerifyDigest {
+					if cErr := verifyDigest(ctx, buf.Bytes()); cErr != nil {
+						bufCloser()
+						writeErr(api, ctx, cErr, *res)
+						return
+					}
+				}
+				if config.DecompressRequests {
+					if cErr := decompressBody(buf, ctx, maxDecompressedBytes); cErr != nil {
+						bufCloser()
+						writeErr(api, ctx, cErr, *res)
+						return
+					}
+				}
 				body := buf.Bytes()
+				if config.BodyLimits != nil && isJSONContentType(ctx.Header("Content-Type")) {
+					if cErr := config.BodyLimits.check(body); cErr != nil {
+						bufCloser()
+						writeErr(api, ctx, cErr, *res)
+						return
+					}
+				}
 
 				// Store raw body
 				if len(rawBodyIndex) > 0 {
@@ -739,12 +1199,17 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 
 				// Process body
 				unmarshaler := func(data []byte, v any) error { return api.Unmarshal(ctx.Header("Content-Type"), data, v) }
+				if inSchema != nil && (config.CaseInsensitiveFields || config.FieldAliases) {
+					unmarshaler = names.unmarshaler(inSchema, unmarshaler)
+				}
 				validator := func(data any, res *ValidateResult) {
 					pb.Reset()
 					pb.Push("body")
+					validateStart := timing.now()
 					Validate(oapi.Components.Schemas, inSchema, pb, ModeWriteToServer, data, res)
+					timing.trackValidate(validateStart)
 				}
-				processErrStatus, cErr := processRegularMsgBody(body, op, v, hasInputBody, inputBodyIndex, unmarshaler, validator, defaults, res)
+				processErrStatus, cErr := processRegularMsgBody(body, op, v, hasInputBody, inputBodyIndex, unmarshaler, validator, defaults, validationStatus, res)
 				if processErrStatus > 0 {
 					errStatus = processErrStatus
 				}
@@ -767,6 +1232,17 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			}
 		}
 
+		if len(res.Deprecated) > 0 && config.OnDeprecatedFields != nil {
+			config.OnDeprecatedFields(ctx, res.Deprecated)
+		}
+		if len(res.Unknown) > 0 && config.OnUnknownFields != nil {
+			config.OnUnknownFields(ctx, res.Unknown)
+		}
+
+		rctx := ctx
+		if params != nil {
+			rctx = WithValue(ctx, paramsKey{}, params)
+		}
 		resolvers.EveryPB(pb, v, func(item reflect.Value, _ bool) {
 			item = reflect.Indirect(item)
 			if item.Kind() == reflect.Invalid {
@@ -788,9 +1264,12 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			var errs []error
 			switch resolver := item.Interface().(type) {
 			case Resolver:
-				errs = resolver.Resolve(ctx)
+				errs = resolver.Resolve(rctx)
+				if pb.Len() > 0 {
+					qualifyErrorLocations(pb, errs)
+				}
 			case ResolverWithPath:
-				errs = resolver.Resolve(ctx, pb)
+				errs = resolver.Resolve(rctx, pb)
 			default:
 				panic("matched resolver cannot be run, please file a bug")
 			}
@@ -799,6 +1278,14 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			}
 		})
 
+		var timeout time.Duration
+		if op.MaxRequestTimeout > 0 {
+			var detail *ErrorDetail
+			if timeout, detail = requestTimeout(ctx, op.MaxRequestTimeout); detail != nil {
+				res.Errors = append(res.Errors, detail)
+			}
+		}
+
 		if len(res.Errors) > 0 {
 			for i := len(res.Errors) - 1; i >= 0; i-- {
 				// If there are errors, and they provide a status, then update the
@@ -813,7 +1300,28 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			return
 		}
 
-		output, err := handler(ctx.Context(), &input)
+		hctx := ctx.Context()
+		var prefs Preferences
+		if op.Prefer {
+			prefs = readPreferences(ctx)
+			hctx = context.WithValue(hctx, preferencesKey{}, prefs)
+		}
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			hctx, cancel = context.WithTimeout(hctx, timeout)
+			defer cancel()
+		}
+
+		if timing != nil {
+			timing.handlerStart = time.Now()
+		}
+		output, err := handler(hctx, &input)
+		if release != nil && output != nil {
+			defer release(output)
+		}
+		if timing != nil {
+			timing.handlerEnd = time.Now()
+		}
 		if err != nil {
 			var he HeadersError
 			if errors.As(err, &he) {
@@ -826,14 +1334,36 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 
 			status := http.StatusInternalServerError
 
+			// handle custom error bodies for specific statuses
+			for _, body := range errorBodies {
+				target := reflect.New(body.typ)
+				if errors.As(err, target.Interface()) {
+					writeResponseWithPanic(api, ctx, body.status, "", target.Elem().Interface())
+					return
+				}
+			}
+
 			// handle status error
 			var se StatusError
 			if errors.As(err, &se) {
+				if op.NewError != nil {
+					// Convert default error models into the operation's error type.
+					var model *ErrorModel
+					if errors.As(err, &model) {
+						se = op.NewError(ctx, model.Status, model.Detail, model.errs()...)
+					}
+				}
 				writeResponseWithPanic(api, ctx, se.GetStatus(), "", se)
 				return
 			}
 
-			se = NewErrorWithContext(ctx, status, "unexpected error occurred", err)
+			if timeout > 0 && errors.Is(hctx.Err(), context.DeadlineExceeded) {
+				se = newErrorForContext(ctx, http.StatusGatewayTimeout, "request timeout exceeded", err)
+				writeResponseWithPanic(api, ctx, se.GetStatus(), "", se)
+				return
+			}
+
+			se = newErrorForContext(ctx, status, "unexpected error occurred", err)
 			writeResponseWithPanic(api, ctx, se.GetStatus(), "", se)
 			return
 		}
@@ -848,6 +1378,9 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		// Serialize output headers
 		ct := ""
 		vo := reflect.ValueOf(output).Elem()
+		if outDefaults != nil {
+			setDefaults(vo.Field(outBodyIndex), outDefaults)
+		}
 		outHeaders.Every(vo, func(f reflect.Value, info *headerInfo) {
 			f = reflect.Indirect(f)
 			if f.Kind() == reflect.Invalid {
@@ -872,15 +1405,62 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			status = int(vo.Field(outStatusIndex).Int())
 		}
 
+		if op.Prefer && !outBodyFunc && outReaderContentType == "" {
+			if applyReturnPreference(ctx, prefs, status) && outBodyIndex != -1 {
+				writeMinimal(ctx, status)
+				return
+			}
+		}
+
 		if outBodyIndex != -1 {
 			// Serialize output body
 			body := vo.Field(outBodyIndex).Interface()
 
 			if outBodyFunc {
+				for _, name := range op.Trailers {
+					ctx.AppendHeader("Trailer", name)
+				}
+				sr, _ := any(output).(*StreamResponse)
+				if sr != nil {
+					for _, name := range sr.Trailers {
+						ctx.AppendHeader("Trailer", name)
+					}
+				}
+				if sr != nil && sr.OnClientDisconnect != nil {
+					// Track write errors to detect clients which have gone away
+					// even if the router does not cancel the request context.
+					sctx := &countingContext{humaContext: ctx}
+					sr.Body(sctx)
+					if ctx.Context().Err() != nil || (sctx.w != nil && sctx.w.err != nil) {
+						sr.OnClientDisconnect(ctx)
+					}
+					return
+				}
 				body.(func(Context))(ctx)
 				return
 			}
 
+			if outReaderContentType != "" {
+				writeReaderBody(ctx, status, ct, outReaderContentType, body)
+				return
+			}
+
+			if len(op.ResponseVersions) > 0 && status >= 200 && status < 300 {
+				var err error
+				if body, ct, err = applyResponseVersion(api, ctx, &op, ct, body); err != nil {
+					WriteErr(api, ctx, http.StatusInternalServerError, "error converting response version", err)
+					return
+				}
+			}
+
+			if ct == "" && len(op.Produces) > 0 {
+				ct = negotiation.SelectQValueFast(ctx.Header("Accept"), op.Produces)
+				if ct == "" {
+					ct = op.Produces[0]
+				}
+				ctx.SetHeader("Content-Type", ct)
+			}
+
 			if b, ok := body.([]byte); ok {
 				ctx.SetStatus(status)
 				ctx.BodyWriter().Write(b)
@@ -891,7 +1471,115 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		} else {
 			ctx.SetStatus(status)
 		}
-	})))
+	}
+
+	if op.ServerTiming || config.ServerTiming {
+		// Streaming responses cannot be buffered to measure marshaling.
+		buffered := !outBodyFunc && outReaderContentType == ""
+		next := handle
+		handle = func(ctx Context) {
+			timing := newServerTiming(ctx, buffered)
+			next(timing)
+			timing.finish()
+		}
+	}
+
+	mounted := &op
+	if config.BasePath != "" {
+		// Mount under the base path while documenting the operation without it.
+		tmp := op
+		tmp.Path = config.BasePath + op.Path
+		mounted = &tmp
+	}
+
+	a := api.Adapter()
+	a.Handle(mounted, api.Middlewares().Handler(opMiddlewares.Handler(handle)))
+}
+
+// writeReaderBody streams a `ReaderBody` or `io.Reader` response body. The
+// content type from an output header field takes precedence, then the one set
+// on the body, then the documented default.
+func writeReaderBody(ctx Context, status int, ct, defaultCT string, body any) {
+	var rb ReaderBody
+	switch b := body.(type) {
+	case ReaderBody:
+		rb = b
+	case *ReaderBody:
+		if b != nil {
+			rb = *b
+		}
+	case io.Reader:
+		rb.Reader = b
+	}
+	if c, ok := rb.Reader.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	if ct == "" {
+		ct = rb.ContentType
+	}
+	if ct == "" {
+		ct = defaultCT
+	}
+	ctx.SetHeader("Content-Type", ct)
+	if rb.Length > 0 {
+		ctx.SetHeader("Content-Length", strconv.FormatInt(rb.Length, 10))
+	}
+	ctx.SetStatus(status)
+	if rb.Reader != nil {
+		// The status has already been sent, so errors cannot be reported.
+		_, _ = io.Copy(ctx.BodyWriter(), rb.Reader)
+	}
+}
+
+// locationRoots are the first part of the location of each request input.
+var locationRoots = []string{"body", "path", "query", "header", "cookie"}
+
+// qualifyErrorLocations prefixes the relative locations of error details from
+// nested resolvers, which don't know where they are in the input, with their
+// path, e.g. `name` becomes `body[3].name`. Locations which already start
+// from one of the `locationRoots` are left as-is.
+func qualifyErrorLocations(pb *PathBuffer, errs []error) {
+	for _, err := range errs {
+		var detail *ErrorDetail
+		if !errors.As(err, &detail) {
+			continue
+		}
+		qualified := false
+		for _, root := range locationRoots {
+			if detail.Location == root || strings.HasPrefix(detail.Location, root+".") || strings.HasPrefix(detail.Location, root+"[") {
+				qualified = true
+				break
+			}
+		}
+		if qualified {
+			continue
+		}
+		switch {
+		case detail.Location == "":
+			detail.Location = pb.String()
+		case detail.Location[0] == '[':
+			detail.Location = pb.String() + detail.Location
+		default:
+			detail.Location = pb.String() + "." + detail.Location
+		}
+	}
+}
+
+// enabledMiddleware returns a middleware which writes the operation's
+// `DisabledStatus` error when its `Enabled` function returns false.
+func enabledMiddleware(api API, op *Operation) func(ctx Context, next func(Context)) {
+	status := op.DisabledStatus
+	if status == 0 {
+		status = http.StatusNotFound
+	}
+	return func(ctx Context, next func(Context)) {
+		if !op.Enabled(ctx) {
+			WriteErr(api, ctx, status, strings.ToLower(http.StatusText(status)))
+			return
+		}
+		next(ctx)
+	}
 }
 
 // initResponses initializes Responses if it was unset.
@@ -905,6 +1593,8 @@ func initResponses(op *Operation) {
 // defines them on the operation op.
 func processInputType(inputType reflect.Type, op *Operation, registry Registry) (*findResult[*paramFieldInfo], []int, bool, []int, rawBodyType, *Schema) {
 	inputParams := findParams(registry, op, inputType)
+	// Only document limits which were explicitly set, not the defaults.
+	documentLimits := op.MaxBodyBytes > 0 || op.BodyReadTimeout > 0
 	inputBodyIndex := []int{}
 	hasInputBody := false
 	if f, ok := inputType.FieldByName("Body"); ok {
@@ -923,12 +1613,17 @@ func processInputType(inputType reflect.Type, op *Operation, registry Registry)
 		rbt = setRequestBodyFromRawBody(op, f)
 	}
 
+	if op.RequestBody != nil && documentLimits {
+		documentBodyLimits(op)
+	}
+
 	if op.RequestBody != nil {
 		for _, mediatype := range op.RequestBody.Content {
 			if mediatype.Schema != nil {
 				// Ensure all schema validation errors are set up properly as some
 				// parts of the schema may have been user-supplied.
 				mediatype.Schema.PrecomputeMessages()
+				resolveExternalRefs(registry, mediatype.Schema)
 			}
 		}
 	}
@@ -937,6 +1632,8 @@ func processInputType(inputType reflect.Type, op *Operation, registry Registry)
 	if op.RequestBody != nil && op.RequestBody.Content != nil && op.RequestBody.Content["application/json"] != nil && op.RequestBody.Content["application/json"].Schema != nil {
 		hasInputBody = true
 		inSchema = op.RequestBody.Content["application/json"].Schema
+	} else if hasInputBody && len(op.Consumes) > 0 && op.RequestBody.Content[op.Consumes[0]] != nil {
+		inSchema = op.RequestBody.Content[op.Consumes[0]].Schema
 	}
 	return inputParams, inputBodyIndex, hasInputBody, rawBodyIndex, rbt, inSchema
 }
@@ -949,6 +1646,27 @@ func ensureMaxBodyBytes(op *Operation) {
 	}
 }
 
+// documentBodyLimits describes the request body size and read time limits in
+// the OpenAPI via extensions so that clients can discover them, and adds the
+// errors returned when they are exceeded.
+func documentBodyLimits(op *Operation) {
+	if op.Extensions == nil {
+		op.Extensions = map[string]any{}
+	}
+	if op.MaxBodyBytes > 0 {
+		op.Extensions["x-max-body-bytes"] = op.MaxBodyBytes
+		if !slices.Contains(op.Errors, http.StatusRequestEntityTooLarge) {
+			op.Errors = append(op.Errors, http.StatusRequestEntityTooLarge)
+		}
+	}
+	if op.BodyReadTimeout > 0 {
+		op.Extensions["x-body-read-timeout"] = op.BodyReadTimeout.Seconds()
+		if !slices.Contains(op.Errors, http.StatusRequestTimeout) {
+			op.Errors = append(op.Errors, http.StatusRequestTimeout)
+		}
+	}
+}
+
 // ensureBodyReadTimeout sets the BodyReadTimeout to a default value if it was unset.
 func ensureBodyReadTimeout(op *Operation) {
 	if op.BodyReadTimeout == 0 {
@@ -962,20 +1680,23 @@ func setRequestBodyFromBody(op *Operation, registry Registry, fBody reflect.Stru
 	if fBody.Tag.Get("required") == "true" || (fBody.Type.Kind() != reflect.Ptr && fBody.Type.Kind() != reflect.Interface) {
 		setRequestBodyRequired(op.RequestBody)
 	}
-	contentType := "application/json"
+	contentTypes := []string{"application/json"}
 	if c := fBody.Tag.Get("contentType"); c != "" {
-		contentType = c
+		contentTypes = []string{c}
+	} else if len(op.Consumes) > 0 {
+		contentTypes = op.Consumes
 	}
 	hint := getHint(inputType, fBody.Name, op.OperationID+"Request")
 	if nameHint := fBody.Tag.Get("nameHint"); nameHint != "" {
 		hint = nameHint
 	}
 	s := SchemaFromField(registry, fBody, hint)
-	if op.RequestBody.Content[contentType] == nil {
-		op.RequestBody.Content[contentType] = &MediaType{}
+	for _, contentType := range contentTypes {
+		if op.RequestBody.Content[contentType] == nil {
+			op.RequestBody.Content[contentType] = &MediaType{}
+		}
+		op.RequestBody.Content[contentType].Schema = s
 	}
-	op.RequestBody.Content[contentType].Schema = s
-
 }
 
 type rawBodyType int
@@ -1002,16 +1723,22 @@ func setRequestBodyFromRawBody(op *Operation, fRawBody reflect.StructField) rawB
 		contentType = "multipart/form-data"
 		rbt = rbtMultipartDecoded
 	}
+	contentTypes := []string{contentType}
 	if c := fRawBody.Tag.Get("contentType"); c != "" {
 		contentType = c
+		contentTypes = []string{c}
+	} else if rbt == rbtOther && len(op.Consumes) > 0 {
+		contentTypes = op.Consumes
 	}
 
 	if contentType != "multipart/form-data" {
-		op.RequestBody.Content[contentType] = &MediaType{
-			Schema: &Schema{
-				Type:   "string",
-				Format: "binary",
-			},
+		for _, contentType := range contentTypes {
+			op.RequestBody.Content[contentType] = &MediaType{
+				Schema: &Schema{
+					Type:   "string",
+					Format: "binary",
+				},
+			}
 		}
 		return rbt
 	}
@@ -1047,6 +1774,11 @@ func setRequestBodyFromRawBody(op *Operation, fRawBody reflect.StructField) rawB
 			Encoding: multiPartContentEncoding(dataField.Type.Elem()),
 		}
 		op.RequestBody.Required = false
+		for _, limits := range op.RequestBody.Content["multipart/form-data"].Schema.fileLimits {
+			if limits.maxSize > 0 && !slices.Contains(op.Errors, http.StatusRequestEntityTooLarge) {
+				op.Errors = append(op.Errors, http.StatusRequestEntityTooLarge)
+			}
+		}
 	}
 	return rbt
 }
@@ -1070,7 +1802,7 @@ func setRequestBodyRequired(rb *RequestBody) {
 
 // processOutputType validates the output type, extracts possible responses and
 // defines them on the operation op.
-func processOutputType(outputType reflect.Type, op *Operation, registry Registry) (*findResult[*headerInfo], int, int, bool) {
+func processOutputType(outputType reflect.Type, op *Operation, registry Registry) (*findResult[*headerInfo], int, int, bool, string) {
 	outStatusIndex := -1
 	if f, ok := outputType.FieldByName("Status"); ok {
 		outStatusIndex = f.Index[0]
@@ -1083,6 +1815,7 @@ func processOutputType(outputType reflect.Type, op *Operation, registry Registry
 	}
 	outBodyIndex := -1
 	outBodyFunc := false
+	outReaderContentType := ""
 	if f, ok := outputType.FieldByName("Body"); ok {
 		outBodyIndex = f.Index[0]
 		if f.Type.Kind() == reflect.Func {
@@ -1092,6 +1825,15 @@ func processOutputType(outputType reflect.Type, op *Operation, registry Registry
 				panic("body field must be a function with signature func(huma.Context)")
 			}
 		}
+		if deref(f.Type) == readerBodyType || (f.Type.Kind() == reflect.Interface && f.Type.Implements(readerType)) {
+			outReaderContentType = "application/octet-stream"
+			if len(op.Produces) > 0 {
+				outReaderContentType = op.Produces[0]
+			}
+			if c := f.Tag.Get("contentType"); c != "" {
+				outReaderContentType = c
+			}
+		}
 		status := op.DefaultStatus
 		if status == 0 {
 			status = http.StatusOK
@@ -1106,7 +1848,30 @@ func processOutputType(outputType reflect.Type, op *Operation, registry Registry
 		if op.Responses[statusStr].Headers == nil {
 			op.Responses[statusStr].Headers = map[string]*Param{}
 		}
-		if !outBodyFunc {
+		if outReaderContentType != "" {
+			if op.Responses[statusStr].Content == nil {
+				op.Responses[statusStr].Content = map[string]*MediaType{
+					outReaderContentType: {
+						Schema: &Schema{
+							Type:   "string",
+							Format: "binary",
+						},
+					},
+				}
+			}
+		} else if streamer, ok := reflect.New(outputType).Interface().(jsonArrayStreamer); ok {
+			if op.Responses[statusStr].Content == nil {
+				itemType := streamer.jsonArrayItemType()
+				op.Responses[statusStr].Content = map[string]*MediaType{
+					"application/json": {
+						Schema: &Schema{
+							Type:  TypeArray,
+							Items: registry.Schema(itemType, true, getHint(itemType, "", op.OperationID+"Item")),
+						},
+					},
+				}
+			}
+		} else if !outBodyFunc {
 			hint := getHint(outputType, f.Name, op.OperationID+"Response")
 			if nameHint := f.Tag.Get("nameHint"); nameHint != "" {
 				hint = nameHint
@@ -1115,17 +1880,28 @@ func processOutputType(outputType reflect.Type, op *Operation, registry Registry
 			if op.Responses[statusStr].Content == nil {
 				op.Responses[statusStr].Content = map[string]*MediaType{}
 			}
-			// Check if the field's type implements ContentTypeFilter
-			contentType := "application/json"
-			if reflect.PointerTo(f.Type).Implements(reflect.TypeFor[ContentTypeFilter]()) {
-				instance := reflect.New(f.Type).Interface().(ContentTypeFilter)
-				contentType = instance.ContentType(contentType)
-			}
-			if len(op.Responses[statusStr].Content) == 0 {
-				op.Responses[statusStr].Content[contentType] = &MediaType{}
-			}
-			if op.Responses[statusStr].Content[contentType] != nil && op.Responses[statusStr].Content[contentType].Schema == nil {
-				op.Responses[statusStr].Content[contentType].Schema = outSchema
+			if len(op.Produces) > 0 {
+				for _, contentType := range op.Produces {
+					if op.Responses[statusStr].Content[contentType] == nil {
+						op.Responses[statusStr].Content[contentType] = &MediaType{}
+					}
+					if op.Responses[statusStr].Content[contentType].Schema == nil {
+						op.Responses[statusStr].Content[contentType].Schema = outSchema
+					}
+				}
+			} else {
+				// Check if the field's type implements ContentTypeFilter
+				contentType := "application/json"
+				if reflect.PointerTo(f.Type).Implements(reflect.TypeFor[ContentTypeFilter]()) {
+					instance := reflect.New(f.Type).Interface().(ContentTypeFilter)
+					contentType = instance.ContentType(contentType)
+				}
+				if len(op.Responses[statusStr].Content) == 0 {
+					op.Responses[statusStr].Content[contentType] = &MediaType{}
+				}
+				if op.Responses[statusStr].Content[contentType] != nil && op.Responses[statusStr].Content[contentType].Schema == nil {
+					op.Responses[statusStr].Content[contentType].Schema = outSchema
+				}
 			}
 		}
 	}
@@ -1164,13 +1940,43 @@ func processOutputType(outputType reflect.Type, op *Operation, registry Registry
 			Schema: SchemaFromField(registry, f, getHint(outputType, f.Name, op.OperationID+defaultStatusStr+v.Name)),
 		}
 	}
-	return outHeaders, outStatusIndex, outBodyIndex, outBodyFunc
+	return outHeaders, outStatusIndex, outBodyIndex, outBodyFunc, outReaderContentType
 }
 
 // defineErrors extracts possible error responses and defines them on the
 // operation op.
+type errorBody struct {
+	status int
+	typ    reflect.Type
+}
+
+// findErrorBodies returns the operation's custom error body types sorted by
+// status code, panicking if any of them cannot be returned as an error.
+func findErrorBodies(op *Operation) []errorBody {
+	bodies := make([]errorBody, 0, len(op.ErrorBodies))
+	for status, body := range op.ErrorBodies {
+		if body == nil {
+			continue
+		}
+		t := reflect.TypeOf(body)
+		if !t.Implements(reflect.TypeFor[error]()) {
+			panic(fmt.Errorf("error body %s for status %d must implement error", t, status))
+		}
+		bodies = append(bodies, errorBody{status: status, typ: t})
+	}
+	slices.SortFunc(bodies, func(a, b errorBody) int {
+		return a.status - b.status
+	})
+	return bodies
+}
+
 func defineErrors(op *Operation, registry Registry) {
-	exampleErr := NewError(0, "")
+	var exampleErr StatusError
+	if op.NewError != nil {
+		exampleErr = op.NewError(nil, 0, "")
+	} else {
+		exampleErr = NewError(0, "")
+	}
 	errContentType := "application/json"
 	if ctf, ok := exampleErr.(ContentTypeFilter); ok {
 		errContentType = ctf.ContentType(errContentType)
@@ -1178,14 +1984,48 @@ func defineErrors(op *Operation, registry Registry) {
 	errType := deref(reflect.TypeOf(exampleErr))
 	errSchema := registry.Schema(errType, true, getHint(errType, "", "Error"))
 	for _, code := range op.Errors {
-		op.Responses[strconv.Itoa(code)] = &Response{
+		schema := errSchema
+		contentType := errContentType
+		if body, ok := op.ErrorBodies[code]; ok && body != nil {
+			if ctf, ok := body.(ContentTypeFilter); ok {
+				contentType = ctf.ContentType("application/json")
+			} else {
+				contentType = "application/json"
+			}
+			t := deref(reflect.TypeOf(body))
+			schema = registry.Schema(t, true, getHint(t, "", "Error"+strconv.Itoa(code)))
+		}
+		if codes := op.ErrorCodes[code]; len(codes) > 0 {
+			// Narrow the error schema's `code` to the documented values.
+			enum := make([]any, len(codes))
+			for i, c := range codes {
+				enum[i] = c
+			}
+			schema = &Schema{
+				AllOf: []*Schema{
+					errSchema,
+					{
+						Type: TypeObject,
+						Properties: map[string]*Schema{
+							"code": {Type: TypeString, Enum: enum},
+						},
+					},
+				},
+			}
+		}
+		resp := &Response{
 			Description: http.StatusText(code),
 			Content: map[string]*MediaType{
-				errContentType: {
-					Schema: errSchema,
+				contentType: {
+					Schema: schema,
 				},
 			},
 		}
+		if existing := op.Responses[strconv.Itoa(code)]; existing != nil {
+			// Keep headers documented by operation handlers, e.g. `Retry-After`.
+			resp.Headers = existing.Headers
+		}
+		op.Responses[strconv.Itoa(code)] = resp
 	}
 	if len(op.Responses) <= 1 && len(op.Errors) == 0 {
 		// No errors are defined, so set a default response.
@@ -1201,8 +2041,7 @@ func defineErrors(op *Operation, registry Registry) {
 }
 
 // getParamValue extracts the requested parameter from the relevant
-// context or cookie source. If unset, the function returns the default value
-// for this parameter.
+// context or cookie source. It returns an empty string if unset.
 func getParamValue(p paramFieldInfo, ctx Context, cookies map[string]*http.Cookie) string {
 	var value string
 	switch p.Loc {
@@ -1217,17 +2056,63 @@ func getParamValue(p paramFieldInfo, ctx Context, cookies map[string]*http.Cooki
 			value = c.Value
 		}
 	}
-	if value == "" {
-		value = p.Default
-	}
 	return value
 }
 
+// headerValues returns all values of a repeated header, split on commas
+// unless the parameter is marked `nosplit`. If the header is not present,
+// the given (default) value is used.
+func headerValues(ctx Context, p paramFieldInfo, value string) []string {
+	var values []string
+	ctx.EachHeader(func(name, v string) {
+		if strings.EqualFold(name, p.Name) {
+			values = append(values, v)
+		}
+	})
+	if len(values) == 0 {
+		values = []string{value}
+	}
+	if p.NoSplit {
+		return values
+	}
+
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+	return result
+}
+
 var errUnparsable = errors.New("unparsable value")
 
 // parseInto converts the string value into the expected type using the
 // parameter field information p and sets the result on f.
 func parseInto(ctx Context, f reflect.Value, value string, p paramFieldInfo) (any, error) {
+	if p.JSON {
+		// Validation uses the generic parsed value, like for request bodies.
+		var parsed any
+		if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+			return nil, errors.New("invalid JSON: " + err.Error())
+		}
+		if err := json.Unmarshal([]byte(value), f.Addr().Interface()); err != nil {
+			return parsed, errors.New("invalid value: " + err.Error())
+		}
+		return parsed, nil
+	}
+
+	if p.TextUnmarshaler {
+		// Types like `huma.Duration` are documented as strings, so parse them
+		// as such instead of by their underlying kind.
+		if err := f.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value)); err != nil {
+			return nil, errors.New("invalid value: " + err.Error())
+		}
+		return value, nil
+	}
+
 	// built-in types
 	switch p.Type.Kind() {
 	case reflect.String:
@@ -1248,6 +2133,9 @@ func parseInto(ctx Context, f reflect.Value, value string, p paramFieldInfo) (an
 		f.SetUint(v)
 		return v, nil
 	case reflect.Float32, reflect.Float64:
+		if p.Lenient {
+			value = normalizeDecimal(value)
+		}
 		v, err := strconv.ParseFloat(value, 64)
 		if err != nil {
 			return nil, errors.New("invalid float")
@@ -1263,7 +2151,9 @@ func parseInto(ctx Context, f reflect.Value, value string, p paramFieldInfo) (an
 		return v, nil
 	case reflect.Slice:
 		var values []string
-		if p.Explode {
+		if p.Loc == "header" {
+			values = headerValues(ctx, p, value)
+		} else if p.Explode {
 			u := ctx.URL()
 			values = (&u).Query()[p.Name]
 		} else {
@@ -1285,7 +2175,15 @@ func parseInto(ctx Context, f reflect.Value, value string, p paramFieldInfo) (an
 		// return nil, errors.New(value)
 		t, err := time.Parse(p.TimeFormat, value)
 		if err != nil {
-			return nil, errors.New("invalid date/time for format " + p.TimeFormat)
+			lenient, ok := time.Time{}, false
+			if p.Lenient {
+				lenient, ok = parseLenientTime(value)
+			}
+			if !ok {
+				return nil, errors.New("invalid date/time for format " + p.TimeFormat)
+			}
+			// Validate the normalized value against the documented format.
+			t, value = lenient, lenient.Format(p.TimeFormat)
 		}
 		f.Set(reflect.ValueOf(t))
 		return value, nil
@@ -1504,14 +2402,46 @@ func writeErr(api API, ctx Context, cErr *contextError, res ValidateResult) {
 	}
 }
 
-func processMultipartMsgBody(op Operation, ctx Context, inputValue reflect.Value, rbt rawBodyType, rawBodyIndex []int, res *ValidateResult) *contextError {
-	form, err := ctx.GetMultipartForm()
+// processMultipartMsgBody reads the multipart form into the raw body field.
+// The returned cleanup func, if any, removes temporary files of the form.
+func processMultipartMsgBody(op Operation, ctx Context, cfg *MultipartConfig, inputValue reflect.Value, rbt rawBodyType, rawBodyIndex []int, validationStatus int, res *ValidateResult) (func(), *contextError) {
+	var (
+		form    *multipart.Form
+		err     error
+		cleanup func()
+		spool   *multipartSpool
+	)
+	var limits map[string]fileLimits
+	if rbt == rbtMultipartDecoded {
+		limits = op.RequestBody.Content["multipart/form-data"].Schema.fileLimits
+		if cfg == nil && hasMaxFileSize(limits) {
+			// The adapter would buffer or spool whole files before their size
+			// could be checked, so read the form using the defaults instead.
+			cfg = &MultipartConfig{}
+		}
+	}
+	if cfg != nil && rbt == rbtMultipartDecoded {
+		spool, err = cfg.readMultipart(ctx, limits)
+		if spool != nil {
+			form = spool.form
+			spool.keep = cfg.KeepFiles
+			cleanup = spool.cleanup
+		}
+		if tooLarge, ok := err.(fileTooLargeError); ok {
+			return cleanup, &contextError{Code: http.StatusRequestEntityTooLarge, Msg: "request body file is too large", Errs: []error{tooLarge}}
+		}
+	} else {
+		form, err = ctx.GetMultipartForm()
+		if form != nil && (cfg == nil || !cfg.KeepFiles) {
+			cleanup = func() { form.RemoveAll() }
+		}
+	}
 	if err != nil {
 		res.Errors = append(res.Errors, &ErrorDetail{
 			Location: "body",
 			Message:  "cannot read multipart form: " + err.Error(),
 		})
-		return nil
+		return cleanup, nil
 	}
 	f := inputValue
 	for _, i := range rawBodyIndex {
@@ -1522,6 +2452,11 @@ func processMultipartMsgBody(op Operation, ctx Context, inputValue reflect.Value
 		f.Set(reflect.ValueOf(*form))
 	case rbtMultipartDecoded:
 		f.FieldByName("Form").Set(reflect.ValueOf(form))
+		if spool != nil {
+			f.Addr().Interface().(interface {
+				setOpener(func(*multipart.FileHeader) (multipart.File, error))
+			}).setOpener(spool.open)
+		}
 		r := f.Addr().
 			MethodByName("Decode").
 			Call(
@@ -1530,10 +2465,26 @@ func processMultipartMsgBody(op Operation, ctx Context, inputValue reflect.Value
 				})
 		errs := r[0].Interface().([]error)
 		if errs != nil {
-			return &contextError{Code: http.StatusUnprocessableEntity, Msg: "validation failed", Errs: errs}
+			for _, err := range errs {
+				if _, ok := err.(fileTooLargeError); ok {
+					return cleanup, &contextError{Code: http.StatusRequestEntityTooLarge, Msg: "request body file is too large", Errs: errs}
+				}
+			}
+			return cleanup, &contextError{Code: validationStatus, Msg: "validation failed", Errs: errs}
 		}
 	}
-	return nil
+	return cleanup, nil
+}
+
+// hasMaxFileSize returns whether any of the form's file fields has a
+// `maxFileSize` limit.
+func hasMaxFileSize(limits map[string]fileLimits) bool {
+	for _, l := range limits {
+		if l.maxSize > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 type intoUnmarshaler = func(data []byte, v any) error
@@ -1542,7 +2493,7 @@ type intoUnmarshaler = func(data []byte, v any) error
 // with validator. Validation errors are documented in res and the
 // corresponding error code is returned. If no errors were found, the return
 // value is -1.
-func processRegularMsgBody(body []byte, op Operation, v reflect.Value, hasInputBody bool, inputBodyIndex []int, unmarshaler intoUnmarshaler, validator func(data any, res *ValidateResult), defaults *findResult[any], res *ValidateResult) (int, *contextError) {
+func processRegularMsgBody(body []byte, op Operation, v reflect.Value, hasInputBody bool, inputBodyIndex []int, unmarshaler intoUnmarshaler, validator func(data any, res *ValidateResult), defaults *findResult[any], validationStatus int, res *ValidateResult) (int, *contextError) {
 	errStatus := -1
 	// Check preconditions
 	if len(body) == 0 {
@@ -1558,7 +2509,7 @@ func processRegularMsgBody(body []byte, op Operation, v reflect.Value, hasInputB
 	// Validate
 	isValid := true
 	if !op.SkipValidateBody {
-		validateErrStatus := validateBody(body, unmarshaler, validator, res)
+		validateErrStatus := validateBody(body, unmarshaler, validator, validationStatus, res)
 		errStatus = validateErrStatus
 		if errStatus > 0 {
 			isValid = false
@@ -1577,8 +2528,9 @@ func processRegularMsgBody(body []byte, op Operation, v reflect.Value, hasInputB
 
 // validateBody parses the raw body with u and validates it with the validator.
 // Any errors are documented in res and the corresponding error code is
-// returned. If no errors were found, the return value is -1.
-func validateBody(body []byte, u intoUnmarshaler, validator func(data any, res *ValidateResult), res *ValidateResult) int {
+// returned, using `validationStatus` for validation errors. If no errors were
+// found, the return value is -1.
+func validateBody(body []byte, u intoUnmarshaler, validator func(data any, res *ValidateResult), validationStatus int, res *ValidateResult) int {
 	errStatus := -1
 	// Validate the input. First, parse the body into []any or map[string]any
 	// or equivalent, which can be easily validated. Then, convert to the
@@ -1599,7 +2551,7 @@ func validateBody(body []byte, u intoUnmarshaler, validator func(data any, res *
 		preValidationErrCount := len(res.Errors)
 		validator(parsed, res)
 		if len(res.Errors)-preValidationErrCount > 0 {
-			errStatus = http.StatusUnprocessableEntity
+			errStatus = validationStatus
 		}
 	}
 	return errStatus
@@ -1623,8 +2575,15 @@ func parseBodyInto(v reflect.Value, bodyIndex []int, u intoUnmarshaler, body []b
 		}
 	}
 	// Set defaults for any fields that were not in the input.
+	setDefaults(v, defaults)
+	return nil
+}
+
+// setDefaults sets the default value on each zero-valued field of v which has
+// a default.
+func setDefaults(v reflect.Value, defaults *findResult[any]) {
 	defaults.Every(v, func(item reflect.Value, def any) {
-		if item.IsZero() {
+		if item.IsZero() && item.CanSet() {
 			if item.Kind() == reflect.Pointer {
 				item.Set(reflect.New(item.Type().Elem()))
 				item = item.Elem()
@@ -1632,7 +2591,6 @@ func parseBodyInto(v reflect.Value, bodyIndex []int, u intoUnmarshaler, body []b
 			item.Set(reflect.Indirect(reflect.ValueOf(def)))
 		}
 	})
-	return nil
 }
 
 // readBody reads the message body from ctx into buf, respecting the
@@ -1650,7 +2608,9 @@ func readBody(buf io.Writer, ctx Context, maxBytes int64) *contextError {
 	count, err := io.Copy(buf, reader)
 	if maxBytes > 0 {
 		if count == maxBytes {
-			return &contextError{Code: http.StatusRequestEntityTooLarge, Msg: fmt.Sprintf("request body is too large limit=%d bytes", maxBytes)}
+			return &contextError{Code: http.StatusRequestEntityTooLarge, Msg: fmt.Sprintf("request body is too large limit=%d bytes", maxBytes), Errs: []error{
+				&ErrorDetail{Message: fmt.Sprintf("expected at most %d bytes", maxBytes), Location: "body", Value: maxBytes},
+			}}
 		}
 	}
 	if err != nil {
@@ -1663,6 +2623,56 @@ func readBody(buf io.Writer, ctx Context, maxBytes int64) *contextError {
 	return nil
 }
 
+// decompressBody replaces the compressed body in buf with its decompressed
+// contents based on the request's `Content-Encoding`, respecting the maximum
+// decompressed size.
+func decompressBody(buf *bytes.Buffer, ctx Context, maxBytes int64) *contextError {
+	encoding := strings.ToLower(strings.TrimSpace(ctx.Header("Content-Encoding")))
+	if encoding == "" || encoding == "identity" {
+		return nil
+	}
+
+	var reader io.ReadCloser
+	var err error
+	switch encoding {
+	case "gzip", "x-gzip":
+		reader, err = gzip.NewReader(bytes.NewReader(buf.Bytes()))
+	case "deflate":
+		reader, err = zlib.NewReader(bytes.NewReader(buf.Bytes()))
+	default:
+		return &contextError{Code: http.StatusUnsupportedMediaType, Msg: fmt.Sprintf("unsupported content encoding %s", encoding), Errs: []error{
+			&ErrorDetail{Message: "expected one of gzip, deflate", Location: "header.Content-Encoding", Value: encoding},
+		}}
+	}
+	if err != nil {
+		return &contextError{Code: http.StatusBadRequest, Msg: "cannot decompress request body", Errs: []error{err}}
+	}
+	defer reader.Close()
+
+	var limited io.Reader = reader
+	if maxBytes > 0 {
+		limited = io.LimitReader(reader, maxBytes+1)
+	}
+	decompressed := bufPool.Get().(*bytes.Buffer)
+	defer func() {
+		decompressed.Reset()
+		bufPool.Put(decompressed)
+	}()
+	count, err := io.Copy(decompressed, limited)
+	if err != nil {
+		return &contextError{Code: http.StatusBadRequest, Msg: "cannot decompress request body", Errs: []error{err}}
+	}
+	if maxBytes > 0 && count > maxBytes {
+		return &contextError{Code: http.StatusRequestEntityTooLarge, Msg: fmt.Sprintf("decompressed request body is too large limit=%d bytes", maxBytes), Errs: []error{
+			&ErrorDetail{Message: fmt.Sprintf("expected at most %d bytes after decompression", maxBytes), Location: "body", Value: maxBytes},
+		}}
+	}
+
+	buf.Reset()
+	buf.Write(decompressed.Bytes())
+	return nil
+}
+
 // AutoRegister auto-detects operation registration methods and registers them
 // with the given API. Any method named `Register...` will be called and
 // passed the API as the only argument. Since registration happens at
@@ -1761,6 +2771,26 @@ func OperationTags(tags ...string) func(o *Operation) {
 	}
 }
 
+// OperationConsumes sets the request body content types of an operation,
+// replacing the default of `application/json`.
+//
+//	huma.Post(api, "/things", handler, huma.OperationConsumes("application/xml"))
+func OperationConsumes(contentTypes ...string) func(o *Operation) {
+	return func(o *Operation) {
+		o.Consumes = contentTypes
+	}
+}
+
+// OperationProduces sets the response body content types of an operation,
+// replacing the default of `application/json`.
+//
+//	huma.Get(api, "/things", handler, huma.OperationProduces("text/csv"))
+func OperationProduces(contentTypes ...string) func(o *Operation) {
+	return func(o *Operation) {
+		o.Produces = contentTypes
+	}
+}
+
 func convenience[I, O any](api API, method, path string, handler func(context.Context, *I) (*O, error), operationHandlers ...func(o *Operation)) {
 	var o *O
 	operation := Operation{
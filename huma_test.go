@@ -1,7 +1,13 @@
 package huma_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,7 +19,10 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -2108,6 +2117,426 @@ func TestOpenAPI(t *testing.T) {
 	})
 }
 
+func TestBundledSchemas(t *testing.T) {
+	config := huma.DefaultConfig("Features Test API", "1.0.0")
+	config.BundleSchemas = true
+	_, api := humatest.New(t, config)
+
+	type Item struct {
+		ID string `json:"id"`
+	}
+
+	type ItemList struct {
+		Items []Item `json:"items"`
+	}
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/items",
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body ItemList }, error) {
+		return nil, nil
+	})
+
+	resp := api.Get("/schemas/ItemList.json")
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), `"$ref":"#/$defs/Item"`)
+	assert.Contains(t, resp.Body.String(), `"$defs":{"Item":`)
+	assert.NotContains(t, resp.Body.String(), "/schemas/Item.json")
+}
+
+func TestSchemaLinkBaseURL(t *testing.T) {
+	config := huma.DefaultConfig("Features Test API", "1.0.0")
+	config.SchemaLinkBaseURL = func(ctx huma.Context) string {
+		return "https://api.example.com/"
+	}
+	_, api := humatest.New(t, config)
+
+	type GreetingBody struct {
+		Greeting string `json:"greeting"`
+	}
+
+	handler := func(ctx context.Context, input *struct{}) (*struct{ Body GreetingBody }, error) {
+		return &struct{ Body GreetingBody }{Body: GreetingBody{Greeting: "Hello"}}, nil
+	}
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/linked",
+	}, handler)
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/unlinked",
+		Metadata: map[string]any{
+			"schemaLink": false,
+		},
+	}, handler)
+
+	resp := api.Get("/linked")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `<https://api.example.com/schemas/GreetingBody.json>; rel="describedBy"`, resp.Header().Get("Link"))
+	assert.JSONEq(t, `{"$schema": "https://api.example.com/schemas/GreetingBody.json", "greeting": "Hello"}`, resp.Body.String())
+
+	resp = api.Get("/unlinked")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Empty(t, resp.Header().Get("Link"))
+	assert.JSONEq(t, `{"greeting": "Hello"}`, resp.Body.String())
+}
+
+func TestOperationTransformers(t *testing.T) {
+	config := huma.DefaultConfig("Features Test API", "1.0.0")
+	config.NamedTransformers["upper"] = func(ctx huma.Context, status string, v any) (any, error) {
+		if m, ok := v.(map[string]any); ok {
+			m["greeting"] = strings.ToUpper(m["greeting"].(string))
+		}
+		return v, nil
+	}
+	_, api := humatest.New(t, config)
+
+	handler := func(ctx context.Context, input *struct{}) (*struct{ Body map[string]any }, error) {
+		return &struct{ Body map[string]any }{Body: map[string]any{"greeting": "Hello"}}, nil
+	}
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/default",
+	}, handler)
+
+	huma.Register(api, huma.Operation{
+		Method:           http.MethodGet,
+		Path:             "/ordered",
+		TransformerOrder: []string{"upper", "schemaLink"},
+		Transformers: []huma.Transformer{
+			func(ctx huma.Context, status string, v any) (any, error) {
+				ctx.SetHeader("X-Op", "yes")
+				return v, nil
+			},
+		},
+	}, handler)
+
+	huma.Register(api, huma.Operation{
+		Method:           http.MethodGet,
+		Path:             "/none",
+		TransformerOrder: []string{},
+	}, handler)
+
+	resp := api.Get("/default")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"greeting": "Hello"}`, resp.Body.String())
+	assert.Empty(t, resp.Header().Get("X-Op"))
+
+	resp = api.Get("/ordered")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"greeting": "HELLO"}`, resp.Body.String())
+	assert.Equal(t, "yes", resp.Header().Get("X-Op"))
+
+	resp = api.Get("/none")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"greeting": "Hello"}`, resp.Body.String())
+
+	assert.Panics(t, func() {
+		huma.Register(api, huma.Operation{
+			Method:           http.MethodGet,
+			Path:             "/typo",
+			TransformerOrder: []string{"does-not-exist"},
+		}, handler)
+	})
+}
+
+func TestWriteOnlyTransformer(t *testing.T) {
+	type Account struct {
+		Name     string `json:"name"`
+		Password string `json:"password,omitempty" writeOnly:"true"`
+	}
+
+	type AccountList struct {
+		Accounts []Account          `json:"accounts"`
+		ByName   map[string]Account `json:"byName,omitempty"`
+	}
+
+	for _, mode := range []huma.WriteOnlyMode{huma.WriteOnlyStrip, huma.WriteOnlyError} {
+		config := huma.DefaultConfig("Test API", "1.0.0")
+		wo := huma.NewWriteOnlyTransformer(config.Components.Schemas, mode)
+		config.Transformers = append(config.Transformers, wo.Transform)
+		_, api := humatest.New(t, config)
+
+		huma.Register(api, huma.Operation{
+			Method: http.MethodGet,
+			Path:   "/accounts",
+		}, func(ctx context.Context, input *struct {
+			Leak bool `query:"leak"`
+		}) (*struct{ Body AccountList }, error) {
+			resp := &struct{ Body AccountList }{}
+			resp.Body.Accounts = []Account{{Name: "alice"}}
+			if input.Leak {
+				resp.Body.Accounts = append(resp.Body.Accounts, Account{Name: "bob", Password: "secret"})
+				resp.Body.ByName = map[string]Account{"bob": {Name: "bob", Password: "secret"}}
+			}
+			return resp, nil
+		})
+
+		// Responses without write-only fields are untouched.
+		resp := api.Get("/accounts")
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"accounts":[{"name":"alice"}]`)
+
+		if mode == huma.WriteOnlyError {
+			assert.PanicsWithError(t, "error transforming response for GET /accounts 200: write only fields in response: accounts[1].password, byName.bob.password", func() {
+				api.Get("/accounts?leak=true")
+			})
+			continue
+		}
+
+		resp = api.Get("/accounts?leak=true")
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.NotContains(t, resp.Body.String(), "secret")
+		assert.Contains(t, resp.Body.String(), `{"name":"bob"}`)
+	}
+}
+
+func TestRedactTransformer(t *testing.T) {
+	type Owner struct {
+		Name  string `json:"name"`
+		Phone string `json:"phone,omitempty"`
+	}
+
+	type User struct {
+		ID       int    `json:"id" readOnly:"true"`
+		Email    string `json:"email" format:"email" redact:"true"`
+		Password string `json:"password,omitempty" minLength:"8" redact:"true"`
+		Owner    *Owner `json:"owner,omitempty"`
+	}
+
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	redact := huma.NewRedactTransformer(config.Components.Schemas)
+	config.Transformers = append(config.Transformers, redact.Transform)
+	_, api := humatest.New(t, config)
+
+	huma.Register(api, huma.Operation{
+		Method:   http.MethodPut,
+		Path:     "/users/{id}",
+		Metadata: map[string]any{"redact": []string{"owner.phone"}},
+	}, func(ctx context.Context, input *struct {
+		ID    int    `path:"id"`
+		Token string `query:"token" minLength:"10" redact:"true"`
+		Body  User
+	}) (*struct{ Body []User }, error) {
+		input.Body.ID = input.ID
+		return &struct{ Body []User }{Body: []User{input.Body}}, nil
+	})
+
+	// The schema is annotated so consumers know the value may be masked.
+	user := api.OpenAPI().Components.Schemas.Map()["User"]
+	assert.Equal(t, true, user.Properties["email"].Extensions["x-redact"])
+	assert.NotContains(t, user.Properties["id"].Extensions, "x-redact")
+
+	resp := api.Put("/users/1", map[string]any{
+		"email":    "alice@example.com",
+		"password": "supersecret",
+		"owner":    map[string]any{"name": "Bob", "phone": "555-1234"},
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `[{"id": 1, "email": "***", "password": "***", "owner": {"name": "Bob", "phone": "***"}}]`, resp.Body.String())
+
+	// Error values from redacted fields are masked too.
+	resp = api.Put("/users/1?token=abc", map[string]any{
+		"email":    "alice@example.com",
+		"password": "short",
+		"owner":    map[string]any{"phone": "555-1234"},
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	body := resp.Body.String()
+	assert.NotContains(t, body, "short")
+	assert.NotContains(t, body, "abc")
+	assert.Contains(t, body, `"location":"body.password","value":"***"`)
+	assert.Contains(t, body, `"location":"query.token","value":"***"`)
+	assert.NotContains(t, body, "555")
+	assert.Contains(t, body, `"location":"body.owner","value":{"phone":"***"}`)
+
+	// A custom mask can be used.
+	redact.Mask = func(path string, value any) any {
+		if s, ok := value.(string); ok && path == "email" {
+			return s[:1] + "***"
+		}
+		return nil
+	}
+	resp = api.Put("/users/1", map[string]any{"email": "alice@example.com"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `[{"id": 1, "email": "a***"}]`, resp.Body.String())
+}
+
+func TestVisibilityTransformer(t *testing.T) {
+	type Owner struct {
+		Name  string `json:"name"`
+		Email string `json:"email" visibility:"internal"`
+	}
+
+	type Thing struct {
+		ID     string  `json:"id"`
+		Cost   int     `json:"cost" visibility:"internal"`
+		Owners []Owner `json:"owners"`
+	}
+
+	register := func(api huma.API) {
+		huma.Put(api, "/things/{id}", func(ctx context.Context, input *struct {
+			ID   string `path:"id"`
+			Body Thing
+		}) (*struct{ Body Thing }, error) {
+			input.Body.ID = input.ID
+			return &struct{ Body Thing }{Body: input.Body}, nil
+		})
+	}
+
+	_, internal := humatest.New(t, huma.DefaultConfig("Internal API", "1.0.0"))
+	register(internal)
+
+	config := huma.DefaultConfig("Public API", "1.0.0")
+	vis := huma.NewVisibilityTransformer(config.Components.Schemas, "internal")
+	config.OnAddOperation = append(config.OnAddOperation, vis.OnAddOperation)
+	config.Transformers = append(config.Transformers, vis.Transform)
+	_, public := humatest.New(t, config)
+	register(public)
+
+	body := map[string]any{
+		"id":     "abc",
+		"cost":   5,
+		"owners": []map[string]any{{"name": "alice", "email": "alice@example.com"}},
+	}
+
+	// The internal API documents & returns all fields.
+	thing := internal.OpenAPI().Components.Schemas.Map()["Thing"]
+	assert.Equal(t, "internal", thing.Properties["cost"].Extensions["x-visibility"])
+	spec, _ := json.Marshal(internal.OpenAPI())
+	assert.Contains(t, string(spec), `"cost"`)
+	resp := internal.Put("/things/abc", body)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"cost":5`)
+	assert.Contains(t, resp.Body.String(), "alice@example.com")
+
+	// The public API hides them.
+	spec, _ = json.Marshal(public.OpenAPI())
+	assert.NotContains(t, string(spec), `"cost"`)
+	assert.NotContains(t, string(spec), `"email"`)
+	resp = public.Put("/things/abc", map[string]any{
+		"id":     "abc",
+		"owners": []map[string]any{{"name": "alice"}},
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"id": "abc", "owners": [{"name": "alice"}]}`, resp.Body.String())
+}
+
+func TestEnvelopeTransformer(t *testing.T) {
+	type Thing struct {
+		Name     string `json:"name"`
+		Password string `json:"password,omitempty" writeOnly:"true"`
+	}
+
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	wo := huma.NewWriteOnlyTransformer(config.Components.Schemas, huma.WriteOnlyStrip)
+	envelope := huma.NewEnvelopeTransformer()
+	envelope.Meta = func(ctx huma.Context, status string) map[string]any {
+		return map[string]any{"requestId": ctx.Header("X-Request-ID")}
+	}
+	config.OnAddOperation = append(config.OnAddOperation, envelope.OnAddOperation)
+	config.Transformers = append(config.Transformers, wo.Transform, envelope.Transform)
+	_, api := humatest.New(t, config)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{name}",
+	}, func(ctx context.Context, input *struct {
+		Name string `path:"name"`
+	}) (*struct{ Body Thing }, error) {
+		if input.Name == "missing" {
+			return nil, huma.Error404NotFound("not found")
+		}
+		resp := &struct{ Body Thing }{Body: Thing{Name: input.Name}}
+		if input.Name == "leak" {
+			resp.Body.Password = "secret"
+		}
+		return resp, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-things",
+		Method:      http.MethodGet,
+		Path:        "/things",
+		Metadata:    map[string]any{"envelope": false},
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body []Thing }, error) {
+		return &struct{ Body []Thing }{Body: []Thing{{Name: "a"}}}, nil
+	})
+
+	// The documented schema describes the envelope.
+	schema := api.OpenAPI().Paths["/things/{name}"].Get.Responses["200"].Content["application/json"].Schema
+	assert.Equal(t, []string{"data"}, schema.Required)
+	assert.Equal(t, "#/components/schemas/Thing", schema.Properties["data"].Ref)
+	assert.NotNil(t, schema.Properties["meta"])
+
+	// Errors are not wrapped.
+	errSchema := api.OpenAPI().Paths["/things/{name}"].Get.Responses["default"].Content["application/problem+json"].Schema
+	assert.Equal(t, "#/components/schemas/ErrorModel", errSchema.Ref)
+
+	resp := api.Get("/things/a", "X-Request-ID: abc123")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, map[string]any{"requestId": "abc123"}, body["meta"])
+	data := body["data"].(map[string]any)
+	assert.Equal(t, "a", data["name"])
+	assert.Contains(t, data, "$schema")
+
+	// Other transformers see the unwrapped body.
+	resp = api.Get("/things/leak")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"data":{"name":"leak"}`)
+
+	resp = api.Get("/things/missing")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.NotContains(t, resp.Body.String(), `"data"`)
+
+	// Disabled for the operation.
+	schema = api.OpenAPI().Paths["/things"].Get.Responses["200"].Content["application/json"].Schema
+	assert.Equal(t, huma.TypeArray, schema.Type)
+	resp = api.Get("/things")
+	assert.JSONEq(t, `[{"name": "a"}]`, resp.Body.String())
+}
+
+func TestUseNumber(t *testing.T) {
+	for _, useNumber := range []bool{false, true} {
+		t.Run(fmt.Sprintf("%v", useNumber), func(t *testing.T) {
+			config := huma.DefaultConfig("Features Test API", "1.0.0")
+			config.UseNumber = useNumber
+			_, api := humatest.New(t, config)
+
+			huma.Register(api, huma.Operation{
+				Method: http.MethodPut,
+				Path:   "/ids",
+			}, func(ctx context.Context, input *struct {
+				Body struct {
+					ID    int64 `json:"id" maximum:"9007199254740992"`
+					Extra any   `json:"extra,omitempty"`
+				}
+			}) (*struct{ Body map[string]any }, error) {
+				return &struct{ Body map[string]any }{Body: map[string]any{"extra": input.Body.Extra}}, nil
+			})
+
+			resp := api.Put("/ids", strings.NewReader(`{"id": 9007199254740993}`))
+			if !useNumber {
+				// Precision is lost when validating with `float64`.
+				assert.Equal(t, http.StatusOK, resp.Code)
+				return
+			}
+			assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+			assert.Contains(t, resp.Body.String(), `"value":9007199254740993`)
+
+			resp = api.Put("/ids", strings.NewReader(`{"id": 1, "extra": 12345678901234567890}`))
+			assert.Equal(t, http.StatusOK, resp.Code)
+			assert.Contains(t, resp.Body.String(), `"extra":12345678901234567890`)
+		})
+	}
+}
+
 type CTFilterBody struct {
 	Field string `json:"field"`
 }
@@ -2284,6 +2713,95 @@ func TestCustomError(t *testing.T) {
 	assert.Equal(t, `{"$schema":"http://localhost/schemas/MyError.json","message":"not found","details":["some-other-error"]}`+"\n", resp.Body.String())
 }
 
+type OtherError struct {
+	status int
+	Code   int    `json:"code"`
+	Reason string `json:"reason"`
+}
+
+func (e *OtherError) Error() string {
+	return e.Reason
+}
+
+func (e *OtherError) GetStatus() int {
+	return e.status
+}
+
+func TestCustomErrorPerAPI(t *testing.T) {
+	newMyError := func(ctx huma.Context, status int, message string, errs ...error) huma.StatusError {
+		details := make([]string, len(errs))
+		for i, err := range errs {
+			details[i] = err.Error()
+		}
+		return &MyError{status: status, Message: message, Details: details}
+	}
+
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.NewError = newMyError
+	_, api := humatest.New(t, config)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-error",
+		Method:      http.MethodGet,
+		Path:        "/error/{id}",
+		Errors:      []int{http.StatusNotFound},
+	}, func(ctx context.Context, i *struct {
+		ID int `path:"id"`
+	}) (*struct{}, error) {
+		return nil, huma.Error404NotFound("not found", errors.New("no such thing"))
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-other-error",
+		Method:      http.MethodGet,
+		Path:        "/other-error/{id}",
+		Errors:      []int{http.StatusNotFound},
+		NewError: func(ctx huma.Context, status int, msg string, errs ...error) huma.StatusError {
+			return &OtherError{status: status, Code: status, Reason: msg}
+		},
+	}, func(ctx context.Context, i *struct {
+		ID int `path:"id"`
+	}) (*struct{}, error) {
+		return nil, huma.Error404NotFound("not found")
+	})
+
+	// A second API in the same process keeps the default error model.
+	_, defaultAPI := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Get(defaultAPI, "/error/{id}", func(ctx context.Context, i *struct {
+		ID int `path:"id"`
+	}) (*struct{}, error) {
+		return nil, huma.Error404NotFound("not found")
+	})
+
+	// Errors are documented with the configured error types.
+	responses := api.OpenAPI().Paths["/error/{id}"].Get.Responses
+	assert.Equal(t, "#/components/schemas/MyError", responses["404"].Content["application/json"].Schema.Ref)
+	responses = api.OpenAPI().Paths["/other-error/{id}"].Get.Responses
+	assert.Equal(t, "#/components/schemas/OtherError", responses["404"].Content["application/json"].Schema.Ref)
+
+	// Handler errors are converted.
+	resp := api.Get("/error/1", "Host: localhost")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.JSONEq(t, `{"$schema":"http://localhost/schemas/MyError.json","message":"not found","details":["no such thing"]}`, resp.Body.String())
+
+	resp = api.Get("/other-error/1", "Host: localhost")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.JSONEq(t, `{"$schema":"http://localhost/schemas/OtherError.json","code":404,"reason":"not found"}`, resp.Body.String())
+
+	// Validation errors use the configured error types.
+	resp = api.Get("/error/abc")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"message":"validation failed"`)
+
+	resp = api.Get("/other-error/abc")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"reason":"validation failed"`)
+
+	resp = defaultAPI.Get("/error/1")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"title":"Not Found"`)
+}
+
 type BrokenWriter struct {
 	http.ResponseWriter
 }
@@ -2357,6 +2875,63 @@ func TestNestedResolverWithPath(t *testing.T) {
 	assert.Contains(t, w.Body.String(), `"location":"body.field1.foo[0].field2"`)
 }
 
+type ArrayBodyItem struct {
+	Name  string `json:"name" minLength:"2"`
+	Color string `json:"color,omitempty" default:"red"`
+}
+
+func (i *ArrayBodyItem) Resolve(ctx huma.Context) []error {
+	if i.Name == "bad" {
+		return []error{&huma.ErrorDetail{
+			Location: "name",
+			Message:  "name is not allowed",
+			Value:    i.Name,
+		}}
+	}
+	return nil
+}
+
+func TestArrayBodyRoot(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Register(api, huma.Operation{
+		Method: http.MethodPut,
+		Path:   "/items",
+	}, func(ctx context.Context, input *struct {
+		Body []*ArrayBodyItem
+	}) (*struct{ Body []*ArrayBodyItem }, error) {
+		return &struct{ Body []*ArrayBodyItem }{Body: input.Body}, nil
+	})
+
+	// Defaults are applied to every element.
+	resp := api.Put("/items", []any{
+		map[string]any{"name": "ab"},
+		map[string]any{"name": "cd", "color": "blue"},
+		map[string]any{"name": "ef"},
+	})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.JSONEq(t, `[
+		{"name": "ab", "color": "red"},
+		{"name": "cd", "color": "blue"},
+		{"name": "ef", "color": "red"}
+	]`, resp.Body.String())
+
+	// Validation and resolver errors include the index.
+	resp = api.Put("/items", []any{
+		map[string]any{"name": "ab"},
+		map[string]any{"name": "a"},
+		map[string]any{"name": "ok"},
+		map[string]any{"name": "bad"},
+	})
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+	var model huma.ErrorModel
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &model))
+	locations := []string{}
+	for _, detail := range model.Errors {
+		locations = append(locations, detail.Location)
+	}
+	assert.Equal(t, []string{"body[1].name", "body[3].name"}, locations)
+}
+
 type ResolverCustomStatus struct{}
 
 func (r *ResolverCustomStatus) Resolve(ctx huma.Context) []error {
@@ -2411,6 +2986,52 @@ func TestResolverCompositionCalledOnce(t *testing.T) {
 	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
 }
 
+type ResolverParamsBody struct {
+	ThingID int `json:"thing_id"`
+}
+
+func (b *ResolverParamsBody) Resolve(ctx huma.Context) []error {
+	if ctx.Operation().OperationID != "params" {
+		return []error{errors.New("missing operation")}
+	}
+	id, ok := huma.ParamValue[int](ctx.Context(), "path", "id")
+	if !ok || id != b.ThingID {
+		return []error{&huma.ErrorDetail{
+			Location: "body.thing_id",
+			Message:  "must match the path ID",
+			Value:    b.ThingID,
+		}}
+	}
+	if v, _ := huma.ParamsFromContext(ctx.Context()).Get("query", "verbose"); v != true {
+		return []error{errors.New("missing query param")}
+	}
+	return nil
+}
+
+func TestResolverParams(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Register(api, huma.Operation{
+		OperationID: "params",
+		Method:      http.MethodPut,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID      int  `path:"id"`
+		Verbose bool `query:"verbose"`
+		Body    ResolverParamsBody
+	}) (*struct{}, error) {
+		// Params are only available within resolvers.
+		assert.Nil(t, huma.ParamsFromContext(ctx))
+		return nil, nil
+	})
+
+	resp := api.Put("/things/5?verbose=true", map[string]any{"thing_id": 5})
+	assert.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+
+	resp = api.Put("/things/5?verbose=true", map[string]any{"thing_id": 6})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), "must match the path ID")
+}
+
 type ResolverWithPointer struct {
 	Ptr *string
 }
@@ -2526,56 +3147,423 @@ func TestConvenienceMethods(t *testing.T) {
 	assert.Equal(t, []string{"Things"}, api.OpenAPI().Paths[path].Delete.Tags)
 }
 
-type EmbeddedWithMethod struct{}
-
-func (e EmbeddedWithMethod) Method() {}
-
-func TestUnsupportedEmbeddedTypeWithMethods(t *testing.T) {
+func TestRouteConflicts(t *testing.T) {
 	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
 
-	// Should not panic!
-	huma.Post(api, "/things", func(ctx context.Context, input *struct{}) (*struct {
-		Body struct {
-			EmbeddedWithMethod
-		}
-	}, error) {
+	handler := func(ctx context.Context, input *struct{}) (*struct{}, error) {
 		return nil, nil
+	}
+
+	huma.Get(api, "/things/{id}", handler)
+
+	// Different methods on the same path are fine.
+	huma.Delete(api, "/things/{id}", handler)
+
+	// Paths which only differ in param names conflict.
+	assert.PanicsWithError(t, `route conflict: GET /things/{name} (operation "get-things-by-name") conflicts with existing GET /things/{id} (operation "get-things-by-id")`, func() {
+		huma.Get(api, "/things/{name}", handler)
 	})
+
+	// Operation IDs must be unique.
+	defer func() {
+		err := recover().(*huma.RouteConflictError)
+		assert.Equal(t, "/things/{id}", err.Existing.Path)
+		assert.Equal(t, "/other", err.Operation.Path)
+		assert.Equal(t, `duplicate operation ID "get-things-by-id": GET /other (operation "get-things-by-id") conflicts with existing GET /things/{id} (operation "get-things-by-id")`, err.Error())
+	}()
+	huma.Register(api, huma.Operation{
+		OperationID: "get-things-by-id",
+		Method:      http.MethodGet,
+		Path:        "/other",
+	}, handler)
 }
 
-type SchemaWithExample int
+func TestRouteConflictsWrapped(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.BasePath = "/api"
+	_, api := humatest.New(t, config)
 
-func (*SchemaWithExample) Schema(r huma.Registry) *huma.Schema {
-	schema := &huma.Schema{
-		Type:     huma.TypeInteger,
-		Examples: []any{1},
+	handler := func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
 	}
-	return schema
-}
 
-func TestSchemaWithExample(t *testing.T) {
-	_, app := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
-	huma.Register(app, huma.Operation{
-		OperationID: "test",
-		Method:      http.MethodGet,
-		Path:        "/test",
-	}, func(ctx context.Context, input *struct {
-		Test SchemaWithExample `query:"test"`
-	}) (*struct{}, error) {
-		return nil, nil
+	// Registrations through different wrappers share the same routes and
+	// settings.
+	huma.Get(wrappedAPI{api}, "/things/{id}", handler)
+	assert.Equal(t, http.StatusNoContent, api.Get("/api/things/1").Code)
+	assert.Panics(t, func() {
+		huma.Get(wrappedAPI{api}, "/things/{id}", handler)
+	})
+	assert.Panics(t, func() {
+		huma.Get(api, "/things/{name}", handler)
+	})
+	assert.Panics(t, func() {
+		huma.Register(wrappedAPI{api}, huma.Operation{
+			OperationID: "get-things-by-id",
+			Method:      http.MethodGet,
+			Path:        "/other",
+		}, handler)
 	})
-
-	example := app.OpenAPI().Paths["/test"].Get.Parameters[0].Example
-	assert.Equal(t, 1, example)
 }
 
-func TestCustomSchemaErrors(t *testing.T) {
-	// Ensure that custom schema errors are correctly reported without having
-	// to manually call `schema.PrecomputeMessages()`.
-	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+func TestBindOperation(t *testing.T) {
+	spec, err := huma.LoadOpenAPI([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users/{user-id}": {
+				"parameters": [{"$ref": "#/components/parameters/UserID"}],
+				"put": {
+					"operationId": "put-user",
+					"x-internal": true,
+					"parameters": [{"name": "dry-run", "in": "query", "schema": {"type": "boolean"}}],
+					"requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}},
+					"responses": {
+						"201": {"description": "Created", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}}
+					}
+				}
+			}
+		},
+		"components": {
+			"parameters": {
+				"UserID": {"name": "user-id", "in": "path", "required": true, "schema": {"type": "string"}}
+			},
+			"schemas": {
+				"User": {
+					"type": "object",
+					"required": ["name"],
+					"properties": {
+						"name": {"type": "string"},
+						"nickname": {"type": ["string", "null"]},
+						"tags": {"type": "array", "items": {"type": "string"}}
+					}
+				}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPut, spec.Paths["/users/{user-id}"].Put.Method)
+	assert.Equal(t, true, spec.Paths["/users/{user-id}"].Put.Extensions["x-internal"])
+	assert.True(t, spec.Components.Schemas.Map()["User"].Properties["nickname"].Nullable)
+
+	config := huma.DefaultConfig("Users", "1.0.0")
+	config.OpenAPI = spec
+	_, api := humatest.New(t, config)
+
+	type User struct {
+		Name     string   `json:"name"`
+		Nickname *string  `json:"nickname,omitempty" nullable:"true"`
+		Tags     []string `json:"tags,omitempty"`
+	}
 
-	huma.Register(api, huma.Operation{
-		OperationID: "test",
+	type Input struct {
+		ID     string `path:"user-id"`
+		DryRun bool   `query:"dry-run"`
+		Body   User
+	}
+
+	huma.BindOperation(api, "put-user", func(ctx context.Context, input *Input) (*struct{ Body User }, error) {
+		return &struct{ Body User }{Body: input.Body}, nil
+	})
+
+	// The pre-authored document is unchanged.
+	assert.Nil(t, api.OpenAPI().Paths["/users/{user-id}"].Put.Responses["200"])
+	assert.Len(t, api.OpenAPI().Components.Schemas.Map(), 1)
+
+	resp := api.Put("/users/abc", map[string]any{"name": "Alice"})
+	assert.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	assert.JSONEq(t, `{"name": "Alice"}`, resp.Body.String())
+
+	resp = api.Put("/users/abc", map[string]any{"name": 123})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	assert.PanicsWithError(t, `operation "missing" not found in the OpenAPI document`, func() {
+		huma.BindOperation(api, "missing", func(ctx context.Context, input *Input) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+
+	_, api = humatest.New(t, config)
+	assert.PanicsWithError(t, `operation "put-user" is incompatible with the OpenAPI document:
+required path param user-id is missing from the input
+request body.age is not in the document
+request body.name has type integer but the document has string
+request body.nickname is missing from the struct
+request body.tags is missing from the struct`, func() {
+		huma.BindOperation(api, "put-user", func(ctx context.Context, input *struct {
+			Body struct {
+				Name int `json:"name"`
+				Age  int `json:"age"`
+			}
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
+
+func TestBasePath(t *testing.T) {
+	type ThingOutput struct {
+		Body struct {
+			Name string `json:"name"`
+		}
+	}
+
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.BasePath = "/t/{tenant}"
+	_, api := humatest.New(t, config)
+
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		next(huma.WithValue(ctx, "tenant", ctx.Param("tenant")))
+	})
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*ThingOutput, error) {
+		resp := &ThingOutput{}
+		resp.Body.Name = ctx.Value("tenant").(string) + "/" + input.ID
+		return resp, nil
+	})
+
+	// Operations are documented without the base path.
+	assert.NotNil(t, api.OpenAPI().Paths["/things/{id}"])
+
+	resp := api.Get("/t/acme/things/1")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"name":"acme/1"`)
+	assert.Contains(t, resp.Body.String(), `/t/acme/schemas/ThingOutputBody.json"`)
+	assert.Equal(t, `</t/acme/schemas/ThingOutputBody.json>; rel="describedBy"`, resp.Header().Get("Link"))
+
+	resp = api.Get("/t/acme/schemas/ThingOutputBody.json")
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = api.Get("/t/acme/openapi.json")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"servers":[{"url":"/t/acme"}]`)
+	assert.Contains(t, resp.Body.String(), `"/things/{id}"`)
+
+	resp = api.Get("/t/other/docs")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "/t/other/openapi.yaml")
+
+	resp = api.Get("/things/1")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	// The base path can also come from the host or a gateway header, while
+	// operations are mounted without a prefix.
+	config = huma.DefaultConfig("Test API", "1.0.0")
+	config.Servers = []*huma.Server{{URL: "https://api.example.com/"}}
+	config.BasePathFunc = func(ctx huma.Context) string {
+		return ctx.Header("X-Forwarded-Prefix")
+	}
+	_, api = humatest.New(t, config)
+
+	huma.Get(api, "/things/{id}", func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*ThingOutput, error) {
+		return &ThingOutput{}, nil
+	})
+
+	resp = api.Get("/things/1", "X-Forwarded-Prefix: /t/acme")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `/t/acme/schemas/ThingOutputBody.json"`)
+
+	resp = api.Get("/openapi.json", "X-Forwarded-Prefix: /t/acme")
+	assert.Contains(t, resp.Body.String(), `"servers":[{"url":"https://api.example.com/t/acme"}]`)
+
+	resp = api.Get("/openapi.json")
+	assert.Contains(t, resp.Body.String(), `"servers":[{"url":"https://api.example.com/"}]`)
+}
+
+func TestBasePathSpecCache(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.BasePath = "/t/{tenant}"
+	filtered := 0
+	config.SpecFilter = func(ctx huma.Context, doc *huma.OpenAPI) *huma.OpenAPI {
+		filtered++
+		return doc
+	}
+	config.SpecAudience = func(ctx huma.Context) string {
+		return "public"
+	}
+	_, api := humatest.New(t, config)
+
+	huma.Get(api, "/things", func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	// Tenants come from the client, so the filtered document is shared by
+	// all of them and only the servers differ.
+	for _, tenant := range []string{"acme", "other", "acme"} {
+		resp := api.Get("/t/" + tenant + "/openapi.json")
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"servers":[{"url":"/t/`+tenant+`"}]`)
+	}
+	assert.Equal(t, 1, filtered)
+}
+
+func TestOperationConsumesProduces(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Formats["application/vnd.thing+json"] = huma.DefaultJSONFormat
+	config.Formats["text/plain"] = huma.Format{
+		Marshal: func(w io.Writer, v any) error {
+			_, err := fmt.Fprint(w, v)
+			return err
+		},
+	}
+	_, api := humatest.New(t, config)
+
+	type Thing struct {
+		Name string `json:"name" minLength:"3"`
+	}
+
+	huma.Post(api, "/things", func(ctx context.Context, input *struct{ Body Thing }) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: input.Body.Name}, nil
+	}, huma.OperationConsumes("application/vnd.thing+json"), huma.OperationProduces("text/plain", "application/json"))
+
+	op := api.OpenAPI().Paths["/things"].Post
+	assert.Len(t, op.RequestBody.Content, 1)
+	assert.NotNil(t, op.RequestBody.Content["application/vnd.thing+json"].Schema)
+	assert.Len(t, op.Responses["200"].Content, 2)
+	assert.NotNil(t, op.Responses["200"].Content["text/plain"].Schema)
+	assert.NotNil(t, op.Responses["200"].Content["application/json"].Schema)
+
+	// The first produced type is the default.
+	resp := api.Post("/things", "Content-Type: application/vnd.thing+json", strings.NewReader(`{"name": "foo"}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/plain", resp.Header().Get("Content-Type"))
+	assert.Equal(t, "foo", resp.Body.String())
+
+	resp = api.Post("/things", "Content-Type: application/vnd.thing+json", "Accept: application/json", strings.NewReader(`{"name": "foo"}`))
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	assert.Equal(t, `"foo"`, strings.TrimSpace(resp.Body.String()))
+
+	// The body is still validated.
+	resp = api.Post("/things", "Content-Type: application/vnd.thing+json", strings.NewReader(`{"name": "a"}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
+
+func TestResponseVersions(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	type User struct {
+		Name string `json:"name"`
+	}
+	type UserV2 struct {
+		FirstName string `json:"first_name"`
+	}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-user",
+		Method:      http.MethodGet,
+		Path:        "/user",
+		ResponseVersions: map[string]huma.ResponseVersion{
+			"2": {
+				Body: UserV2{},
+				Convert: func(ctx huma.Context, body any) (any, error) {
+					return UserV2{FirstName: body.(User).Name}, nil
+				},
+			},
+		},
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body User }, error) {
+		return &struct{ Body User }{Body: User{Name: "Alice"}}, nil
+	})
+
+	content := api.OpenAPI().Paths["/user"].Get.Responses["200"].Content
+	assert.Equal(t, "#/components/schemas/User", content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/UserV2", content["application/json; version=2"].Schema.Ref)
+
+	resp := api.Get("/user")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", resp.Header().Get("Vary"))
+	assert.Contains(t, resp.Body.String(), `"name":"Alice"`)
+
+	resp = api.Get("/user", "Accept: application/json; version=2")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json; version=2", resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Body.String(), `"first_name":"Alice"`)
+
+	// Unknown versions get the unversioned body.
+	resp = api.Get("/user", "Accept: application/json; version=9")
+	assert.Contains(t, resp.Body.String(), `"name":"Alice"`)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-user-header",
+		Method:      http.MethodGet,
+		Path:        "/user-header",
+		ResponseVersions: map[string]huma.ResponseVersion{
+			"2": {
+				Body: UserV2{},
+				Convert: func(ctx huma.Context, body any) (any, error) {
+					return nil, errors.New("conversion failed")
+				},
+			},
+		},
+		SelectVersion: func(ctx huma.Context) string {
+			return ctx.Header("Accept-Version")
+		},
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body User }, error) {
+		return &struct{ Body User }{Body: User{Name: "Alice"}}, nil
+	})
+
+	resp = api.Get("/user-header", "Accept-Version: 2")
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Contains(t, resp.Body.String(), "conversion failed")
+}
+
+type EmbeddedWithMethod struct{}
+
+func (e EmbeddedWithMethod) Method() {}
+
+func TestUnsupportedEmbeddedTypeWithMethods(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	// Should not panic!
+	huma.Post(api, "/things", func(ctx context.Context, input *struct{}) (*struct {
+		Body struct {
+			EmbeddedWithMethod
+		}
+	}, error) {
+		return nil, nil
+	})
+}
+
+type SchemaWithExample int
+
+func (*SchemaWithExample) Schema(r huma.Registry) *huma.Schema {
+	schema := &huma.Schema{
+		Type:     huma.TypeInteger,
+		Examples: []any{1},
+	}
+	return schema
+}
+
+func TestSchemaWithExample(t *testing.T) {
+	_, app := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Register(app, huma.Operation{
+		OperationID: "test",
+		Method:      http.MethodGet,
+		Path:        "/test",
+	}, func(ctx context.Context, input *struct {
+		Test SchemaWithExample `query:"test"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	example := app.OpenAPI().Paths["/test"].Get.Parameters[0].Example
+	assert.Equal(t, 1, example)
+}
+
+func TestCustomSchemaErrors(t *testing.T) {
+	// Ensure that custom schema errors are correctly reported without having
+	// to manually call `schema.PrecomputeMessages()`.
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "test",
 		Method:      http.MethodPost,
 		Path:        "/test",
 		RequestBody: &huma.RequestBody{
@@ -2678,6 +3666,123 @@ func TestCustomValidationErrorStatus(t *testing.T) {
 	assert.Contains(t, resp.Body.String(), "Bad Request")
 }
 
+// wrappedAPI extends an API by embedding it without implementing `Unwrap`.
+type wrappedAPI struct {
+	huma.API
+}
+
+func TestWrappedAPI(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.ValidationErrorStatus = http.StatusBadRequest
+	_, api := humatest.New(t, config)
+	wrapped := wrappedAPI{api}
+
+	huma.Post(wrapped, "/test", func(ctx context.Context, input *struct {
+		Body struct {
+			Value string `json:"value" minLength:"5"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	// Settings from the config are used for operations registered against
+	// the wrapper.
+	resp := api.Post("/test", map[string]any{"value": "foo"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+
+	// APIs which weren't created with `NewAPI` are rejected rather than
+	// silently using default settings.
+	assert.PanicsWithError(t, "unknown API implementation huma_test.unknownAPI, APIs must be created with huma.NewAPI", func() {
+		huma.Get(unknownAPI{api}, "/unknown", func(ctx context.Context, input *struct{}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
+
+// unknownAPI is an API implementation with its own OpenAPI document.
+type unknownAPI struct {
+	huma.API
+}
+
+func (unknownAPI) OpenAPI() *huma.OpenAPI {
+	return &huma.OpenAPI{Components: &huma.Components{Schemas: huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)}}
+}
+
+func TestExternalSchemaRefs(t *testing.T) {
+	loads := 0
+	external := huma.NewExternalSchemas(func(url string) ([]byte, error) {
+		loads++
+		if url == "https://example.com/schemas/common.json" {
+			return []byte(`{"$defs": {"Tag": {"type": "string", "maxLength": 5}}}`), nil
+		}
+		return nil, errors.New("not found")
+	})
+
+	// Preloaded documents don't need the loader.
+	require.NoError(t, external.Add("https://example.com/schemas/thing.json", []byte(`{
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string"},
+			"tags": {"type": "array", "items": {"$ref": "common.json#/$defs/Tag"}},
+			"parent": {"$ref": "#"}
+		}
+	}`)))
+
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Components.Schemas = huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer, huma.MapRegistryExternalSchemas(external))
+	_, api := humatest.New(t, config)
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodPut,
+		Path:   "/things",
+		RequestBody: &huma.RequestBody{
+			Content: map[string]*huma.MediaType{
+				"application/json": {
+					Schema: &huma.Schema{Ref: "https://example.com/schemas/thing.json"},
+				},
+			},
+		},
+	}, func(ctx context.Context, input *struct {
+		RawBody []byte
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+	assert.Equal(t, 1, loads)
+
+	resp := api.Put("/things", map[string]any{"name": "a", "tags": []string{"b"}, "parent": map[string]any{"name": "c"}})
+	assert.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+
+	resp = api.Put("/things", map[string]any{"tags": []string{"too long"}, "parent": map[string]any{}})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"location":"body.tags[0]"`)
+	assert.Contains(t, resp.Body.String(), `"location":"body.parent"`)
+	assert.Contains(t, resp.Body.String(), `"location":"body"`)
+
+	// Documents are only loaded once.
+	_, err := external.Resolve("https://example.com/schemas/common.json#/$defs/Tag")
+	require.NoError(t, err)
+	assert.Equal(t, 1, loads)
+
+	assert.PanicsWithError(t, "unable to load external schema https://example.com/missing.json: not found", func() {
+		huma.Register(api, huma.Operation{
+			Method: http.MethodPost,
+			Path:   "/things",
+			RequestBody: &huma.RequestBody{
+				Content: map[string]*huma.MediaType{
+					"application/json": {
+						Schema: &huma.Schema{Ref: "https://example.com/missing.json"},
+					},
+				},
+			},
+		}, func(ctx context.Context, input *struct {
+			RawBody []byte
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
+
 // func BenchmarkSecondDecode(b *testing.B) {
 // 	//nolint: musttag
 // 	type MediumSized struct {
@@ -2812,14 +3917,1831 @@ func BenchmarkHandlerFunc(b *testing.B) {
 	})
 }
 
-func TestGenerateFuncsPanicWithDescriptiveMessage(t *testing.T) {
-	var resp *int
-	assert.PanicsWithValue(t, "Response type must be a struct", func() {
-		huma.GenerateOperationID("GET", "/foo", resp)
+func TestRegisterValue(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	type GreetingOutput struct {
+		ETag string `header:"ETag"`
+		Body struct {
+			Message string `json:"message"`
+		}
+	}
+
+	huma.RegisterValue(api, huma.Operation{
+		OperationID: "greet",
+		Method:      http.MethodGet,
+		Path:        "/greet/{name}",
+	}, func(ctx context.Context, input *struct {
+		Name string `path:"name"`
+	}) (GreetingOutput, error) {
+		var resp GreetingOutput
+		if input.Name == "bob" {
+			return resp, huma.Error404NotFound("no greeting for bob")
+		}
+		if input.Name != "empty" {
+			resp.ETag = "abc"
+			resp.Body.Message = "Hello, " + input.Name
+		}
+		return resp, nil
 	})
 
-	assert.PanicsWithValue(t, "Response type must be a struct", func() {
-		huma.GenerateSummary("GET", "/foo", resp)
+	assert.Contains(t, api.OpenAPI().Paths["/greet/{name}"].Get.Responses["200"].Content, "application/json")
+
+	resp := api.Get("/greet/alice")
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Equal(t, "abc", resp.Header().Get("ETag"))
+	assert.Contains(t, resp.Body.String(), `"message":"Hello, alice"`)
+
+	// Pooled outputs are reset before being reused.
+	resp = api.Get("/greet/empty")
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Empty(t, resp.Header().Get("ETag"))
+	assert.Contains(t, resp.Body.String(), `"message":""`)
+
+	resp = api.Get("/greet/bob")
+	assert.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+}
+
+func TestPrecompute(t *testing.T) {
+	type Thing struct {
+		Name string `json:"name" pattern:"^[a-z]+$"`
+	}
+
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Register(api, huma.Operation{
+		Method: http.MethodPut,
+		Path:   "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID   string `path:"id"`
+		Body Thing
+	}) (*struct{ Body Thing }, error) {
+		return &struct{ Body Thing }{Body: input.Body}, nil
+	})
+
+	require.NoError(t, huma.Precompute(api))
+
+	// Requests still work once precomputed.
+	resp := api.Put("/things/1", map[string]any{"name": "abc"})
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	resp = api.Put("/things/1", map[string]any{"name": "ABC"})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+	resp = api.Get("/openapi.json")
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// The registry is locked, so new types cannot be added.
+	assert.PanicsWithError(t, "schema registry is locked, cannot add Other for type huma_test.Other", func() {
+		type Other struct {
+			Value int `json:"value"`
+		}
+		huma.Put(api, "/other", func(ctx context.Context, input *struct{ Body Other }) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+
+	// Latent errors, like schemas modified after registration, are returned.
+	_, api = humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Put(api, "/things/{id}", func(ctx context.Context, input *struct {
+		ID   string `path:"id"`
+		Body Thing
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+	api.OpenAPI().Components.Schemas.Map()["Thing"].Properties["name"].Pattern = "[a-z"
+	api.OpenAPI().Paths["/things/{id}"].Put.Parameters[0].Schema = &huma.Schema{Ref: "#/components/schemas/Missing"}
+	err := huma.Precompute(api)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `schema Thing: invalid pattern "[a-z"`)
+	assert.Contains(t, err.Error(), "PUT /things/{id} path param id: unresolved ref #/components/schemas/Missing")
+}
+
+func TestUseMiddlewareIf(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	trace := func(name string) func(ctx huma.Context, next func(huma.Context)) {
+		return func(ctx huma.Context, next func(huma.Context)) {
+			ctx.AppendHeader("Trace", name)
+			next(ctx)
+		}
+	}
+
+	api.UseMiddleware(trace("api"))
+	huma.UseMiddlewareIf(api, huma.OperationHasTag("admin"), trace("admin"))
+	huma.UseMiddlewareIf(api, huma.OperationPathPrefix("/audit/"), trace("audit"))
+	huma.UseMiddlewareIf(api, huma.OperationHasMetadata("billing"), trace("billing"))
+
+	handler := func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	}
+	huma.Register(api, huma.Operation{
+		Method:      http.MethodGet,
+		Path:        "/admin",
+		Tags:        []string{"admin"},
+		Middlewares: huma.Middlewares{trace("op")},
+	}, handler)
+	huma.Register(api, huma.Operation{
+		Method:   http.MethodGet,
+		Path:     "/audit/things",
+		Metadata: map[string]any{"billing": true},
+		Hidden:   true,
+	}, handler)
+	huma.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/public",
+	}, handler)
+
+	resp := api.Get("/admin")
+	assert.Equal(t, []string{"api", "admin", "op"}, resp.Header().Values("Trace"))
+
+	resp = api.Get("/audit/things")
+	assert.Equal(t, []string{"api", "audit", "billing"}, resp.Header().Values("Trace"))
+
+	resp = api.Get("/public")
+	assert.Equal(t, []string{"api"}, resp.Header().Values("Trace"))
+
+	// Middlewares can't be attached to operations registered before.
+	assert.PanicsWithError(t, "UseMiddlewareIf must be called before registering matching operation GET /public", func() {
+		huma.UseMiddlewareIf(api, huma.OperationPathPrefix("/public"), trace("late"))
 	})
+}
+
+func TestUseMiddlewareIfWrapped(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	wrapped := wrappedAPI{api}
 
+	huma.UseMiddlewareIf(wrapped, huma.OperationHasTag("admin"), func(ctx huma.Context, next func(huma.Context)) {
+		huma.WriteErr(api, ctx, http.StatusUnauthorized, "unauthorized")
+	})
+	huma.Register(wrapped, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/admin",
+		Tags:   []string{"admin"},
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	resp := api.Get("/admin")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	assert.Panics(t, func() {
+		huma.UseMiddlewareIf(unknownAPI{api}, huma.OperationHasTag("admin"))
+	})
+}
+
+func TestGenerateFuncsPanicWithDescriptiveMessage(t *testing.T) {
+	var resp *int
+	assert.PanicsWithValue(t, "Response type must be a struct", func() {
+		huma.GenerateOperationID("GET", "/foo", resp)
+	})
+
+	assert.PanicsWithValue(t, "Response type must be a struct", func() {
+		huma.GenerateSummary("GET", "/foo", resp)
+	})
+
+}
+
+func TestDeprecatedFields(t *testing.T) {
+	type Address struct {
+		Street string `json:"street"`
+		Zip    string `json:"zip,omitempty" deprecated:"true"`
+	}
+
+	type User struct {
+		Name     string   `json:"name"`
+		FullName string   `json:"fullName,omitempty" removedAfter:"2025-01-01"`
+		Address  *Address `json:"address,omitempty"`
+	}
+
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.OnDeprecatedFields = func(ctx huma.Context, fields []string) {
+		ctx.SetHeader("Deprecated-Fields", strings.Join(fields, ", "))
+	}
+	_, api := humatest.New(t, config)
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodPut,
+		Path:   "/user",
+	}, func(ctx context.Context, input *struct{ Body User }) (*struct{}, error) {
+		return nil, nil
+	})
+
+	resp := api.Put("/user", map[string]any{"name": "alice"})
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Empty(t, resp.Header().Get("Deprecated-Fields"))
+
+	resp = api.Put("/user", map[string]any{
+		"name":     "alice",
+		"fullName": "Alice Smith",
+		"address":  map[string]any{"street": "Main St", "zip": "12345"},
+	})
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, "body.address.zip, body.fullName", resp.Header().Get("Deprecated-Fields"))
+}
+
+func TestUnknownFields(t *testing.T) {
+	type User struct {
+		Name    string `json:"name"`
+		Address *struct {
+			Street string `json:"street"`
+		} `json:"address,omitempty"`
+	}
+
+	counter := &huma.FieldCounter{}
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.OnUnknownFields = counter.Add
+	_, api := humatest.New(t, config)
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodPut,
+		Path:   "/user",
+	}, func(ctx context.Context, input *struct{ Body User }) (*struct{ Body User }, error) {
+		return &struct{ Body User }{Body: input.Body}, nil
+	})
+
+	resp := api.Put("/user", map[string]any{
+		"name":     "alice",
+		"fullName": "Alice Smith",
+		"address":  map[string]any{"street": "Main St", "zip": "12345"},
+	})
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), `"name":"alice"`)
+
+	resp = api.Put("/user", map[string]any{"name": "bob", "fullName": "Bob Smith"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	assert.Equal(t, map[string]int64{
+		"body.fullName":    2,
+		"body.address.zip": 1,
+	}, counter.Counts())
+
+	// Other validation errors are still reported.
+	resp = api.Put("/user", map[string]any{"name": 123, "fullName": "Bob Smith"})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.NotContains(t, resp.Body.String(), "unexpected property")
+}
+
+func TestDeprecatedParams(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.OnDeprecatedFields = func(ctx huma.Context, fields []string) {
+		ctx.SetHeader("Deprecated-Fields", strings.Join(fields, ", "))
+	}
+	_, api := humatest.New(t, config)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-things",
+		Method:      http.MethodGet,
+		Path:        "/things",
+	}, func(ctx context.Context, input *struct {
+		PageSize int    `query:"page_size" alias:"limit,per_page" maximum:"100"`
+		Sort     string `query:"sort" deprecated:"true"`
+		TraceID  string `header:"X-Trace-ID" alias:"X-Request-ID"`
+	}) (*struct {
+		Body []any
+	}, error) {
+		return &struct{ Body []any }{Body: []any{input.PageSize, input.TraceID}}, nil
+	})
+
+	// Both the new and old names are documented.
+	params := api.OpenAPI().Paths["/things"].Get.Parameters
+	names := []string{}
+	for _, p := range params {
+		names = append(names, p.Name)
+		switch p.Name {
+		case "page_size", "X-Trace-ID":
+			assert.False(t, p.Deprecated)
+		default:
+			assert.True(t, p.Deprecated, p.Name)
+		}
+	}
+	assert.Equal(t, []string{"page_size", "limit", "per_page", "sort", "X-Trace-ID", "X-Request-ID"}, names)
+
+	resp := api.Get("/things?page_size=5", "X-Trace-ID: abc")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `[5, "abc"]`, resp.Body.String())
+	assert.Empty(t, resp.Header().Get("Deprecation"))
+
+	resp = api.Get("/things?per_page=10&sort=name", "X-Request-ID: def")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `[10, "def"]`, resp.Body.String())
+	assert.Equal(t, "true", resp.Header().Get("Deprecation"))
+	assert.Equal(t, "query.per_page, query.sort, header.X-Request-ID", resp.Header().Get("Deprecated-Fields"))
+
+	// Validation errors use the old name.
+	resp = api.Get("/things?limit=500")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"location":"query.limit"`)
+
+	assert.Panics(t, func() {
+		huma.Get(api, "/bad/{id}", func(ctx context.Context, input *struct {
+			ID string `path:"id" alias:"thing_id"`
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
+
+func TestServerVariableParams(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Servers = []*huma.Server{
+		{
+			URL: "https://{region}.api.example.com",
+			Variables: map[string]*huma.ServerVariable{
+				"region": {Default: "us-east", Enum: []string{"us-east", "eu-west"}},
+			},
+		},
+	}
+	config.ServerVariableFunc = func(ctx huma.Context, name string) string {
+		if name == "region" {
+			return ctx.Header("X-Region")
+		}
+		return ""
+	}
+	_, api := humatest.New(t, config)
+
+	huma.Get(api, "/region", func(ctx context.Context, input *struct {
+		Region string `server:"region" enum:"us-east,eu-west"`
+	}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: input.Region}, nil
+	})
+
+	// Server variables are not documented as operation params.
+	assert.Empty(t, api.OpenAPI().Paths["/region"].Get.Parameters)
+
+	resp := api.Get("/region")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `"us-east"`+"\n", resp.Body.String())
+
+	resp = api.Get("/region", "X-Region: eu-west")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `"eu-west"`+"\n", resp.Body.String())
+
+	resp = api.Get("/region", "X-Region: mars")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "server.region")
+
+	assert.PanicsWithError(t, "server variable 'zone' is not declared in the OpenAPI servers for GET /zone", func() {
+		huma.Get(api, "/zone", func(ctx context.Context, input *struct {
+			Zone string `server:"zone"`
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
+
+func TestJSONQueryParams(t *testing.T) {
+	type Filter struct {
+		Field string `json:"field" enum:"name,color"`
+		Value string `json:"value" maxLength:"5"`
+	}
+
+	_, api := humatest.New(t)
+
+	huma.Get(api, "/things", func(ctx context.Context, input *struct {
+		Filter  Filter   `query:"filter,json"`
+		Filters []Filter `query:"filters,json"`
+	}) (*struct{ Body []Filter }, error) {
+		return &struct{ Body []Filter }{Body: append([]Filter{input.Filter}, input.Filters...)}, nil
+	})
+
+	params := api.OpenAPI().Paths["/things"].Get.Parameters
+	require.Len(t, params, 2)
+	assert.Nil(t, params[0].Schema)
+	assert.Nil(t, params[0].Explode)
+	assert.Equal(t, "#/components/schemas/Filter", params[0].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/Filter", params[1].Content["application/json"].Schema.Items.Ref)
+
+	b, _ := json.Marshal(params[0])
+	assert.JSONEq(t, `{"name": "filter", "in": "query", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Filter"}}}}`, string(b))
+
+	query := url.Values{
+		"filter":  {`{"field": "name", "value": "a"}`},
+		"filters": {`[{"field": "color", "value": "red"}]`},
+	}
+	resp := api.Get("/things?" + query.Encode())
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.JSONEq(t, `[{"field": "name", "value": "a"}, {"field": "color", "value": "red"}]`, resp.Body.String())
+
+	query = url.Values{"filters": {`[{"field": "size", "value": "too long"}]`}}
+	resp = api.Get("/things?" + query.Encode())
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"location":"query.filters[0].field"`)
+	assert.Contains(t, resp.Body.String(), `"location":"query.filters[0].value"`)
+
+	resp = api.Get("/things?filter=%7Bbad")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "invalid JSON")
+}
+
+func TestPassthroughParams(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/search",
+	}, func(ctx context.Context, input *struct {
+		Limit             int        `query:"limit" maximum:"10" alias:"max"`
+		PassthroughParams url.Values `doc:"Params forwarded to the backend"`
+	}) (*struct {
+		Body map[string][]string
+	}, error) {
+		return &struct{ Body map[string][]string }{Body: input.PassthroughParams}, nil
+	})
+
+	params := api.OpenAPI().Paths["/search"].Get.Parameters
+	passthrough := params[len(params)-1]
+	assert.Equal(t, "params", passthrough.Name)
+	assert.Equal(t, "form", passthrough.Style)
+	assert.Equal(t, "Params forwarded to the backend", passthrough.Description)
+	assert.Equal(t, huma.TypeObject, passthrough.Schema.Type)
+
+	// Declared params and their aliases are not passed through.
+	resp := api.Get("/search?limit=5&max=3&q=shoes&color=red&color=blue")
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.JSONEq(t, `{"q":["shoes"],"color":["red","blue"]}`, resp.Body.String())
+
+	// Declared params are still validated.
+	resp = api.Get("/search?limit=50&q=shoes")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+
+	assert.Panics(t, func() {
+		huma.Get(api, "/bad", func(ctx context.Context, input *struct {
+			PassthroughParams map[string]string
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
+
+type proxyTransport func(req *http.Request) (*http.Response, error)
+
+func (f proxyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestProxy(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	var upstream *http.Request
+	var upstreamBody string
+	client := &http.Client{Transport: proxyTransport(func(req *http.Request) (*http.Response, error) {
+		upstream = req
+		b, _ := io.ReadAll(req.Body)
+		upstreamBody = string(b)
+		if req.URL.Path == "/legacy/v1/user/fail" {
+			return nil, errors.New("connection refused")
+		}
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Header:     http.Header{"Content-Type": {"application/json"}, "X-Upstream": {"yes"}, "Connection": {"close, X-Upstream-Hop"}, "X-Upstream-Hop": {"1"}},
+			Body:       io.NopCloser(strings.NewReader(`{"id":"123","name":"Alice"}`)),
+		}, nil
+	})}
+
+	type User struct {
+		ID   string `json:"id"`
+		Name string `json:"name" minLength:"2"`
+	}
+
+	target, _ := url.Parse("http://legacy.internal/legacy?key=secret")
+	huma.Proxy[struct {
+		ID   string `path:"id"`
+		Body User
+	}, struct {
+		Upstream string `header:"X-Upstream"`
+		Body     User
+	}](api, huma.Operation{
+		OperationID:   "put-user",
+		Method:        http.MethodPut,
+		Path:          "/users/{id}",
+		DefaultStatus: http.StatusCreated,
+	}, target, &huma.ProxyOptions{
+		Client: client,
+		Rewrite: func(path string) string {
+			return strings.Replace(path, "/users/", "/v1/user/", 1)
+		},
+		ModifyRequest: func(ctx huma.Context, req *http.Request) error {
+			req.Header.Set("Authorization", "Bearer legacy")
+			return nil
+		},
+	})
+
+	// The declared schemas are documented.
+	op := api.OpenAPI().Paths["/users/{id}"].Put
+	assert.Equal(t, "#/components/schemas/User", op.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/User", op.Responses["201"].Content["application/json"].Schema.Ref)
+	assert.Contains(t, op.Responses["201"].Headers, "X-Upstream")
+	assert.Contains(t, op.Responses, "502")
+
+	resp := api.Put("/users/123?verbose=true", "Connection: keep-alive, X-Hop", "X-Hop: 1", "X-Custom: abc", map[string]any{"id": "123", "name": "Alice"})
+	assert.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	assert.JSONEq(t, `{"id":"123","name":"Alice"}`, resp.Body.String())
+	assert.Equal(t, "yes", resp.Header().Get("X-Upstream"))
+	assert.Empty(t, resp.Header().Get("Connection"))
+	assert.Empty(t, resp.Header().Get("X-Upstream-Hop"))
+	require.NotNil(t, upstream)
+	assert.Equal(t, "http://legacy.internal/legacy/v1/user/123?key=secret&verbose=true", upstream.URL.String())
+	assert.Equal(t, "abc", upstream.Header.Get("X-Custom"))
+	assert.Equal(t, "Bearer legacy", upstream.Header.Get("Authorization"))
+	assert.Empty(t, upstream.Header.Get("Connection"))
+	assert.Empty(t, upstream.Header.Get("X-Hop"))
+	assert.JSONEq(t, `{"id":"123","name":"Alice"}`, upstreamBody)
+
+	// Invalid requests never reach the upstream.
+	upstream = nil
+	resp = api.Put("/users/123", map[string]any{"id": "123", "name": "A"})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+	assert.Nil(t, upstream)
+
+	resp = api.Put("/users/fail", map[string]any{"id": "123", "name": "Alice"})
+	assert.Equal(t, http.StatusBadGateway, resp.Code, resp.Body.String())
+}
+
+func TestMirror(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	type mirrored struct {
+		req           *http.Request
+		body          string
+		primary, resp *huma.MirrorResponse
+	}
+	results := make(chan mirrored, 10)
+	compare := func(req *http.Request, primary, mirror *huma.MirrorResponse) {
+		b, _ := io.ReadAll(req.Body)
+		results <- mirrored{req: req, body: string(b), primary: primary, resp: mirror}
+	}
+
+	// Mirror writes to an in-process handler.
+	huma.UseMiddlewareIf(api, huma.OperationHasTag("mirror"), huma.Mirror(huma.MirrorConfig{
+		Percent: 100,
+		Match: func(ctx huma.Context) bool {
+			return ctx.Method() == http.MethodPost
+		},
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"name":"rewritten"}`))
+		}),
+		Compare: compare,
+	}))
+
+	type Item struct {
+		Name string `json:"name"`
+	}
+	type ItemInput struct {
+		Body Item
+	}
+	type ItemOutput struct {
+		Body Item
+	}
+	handler := func(ctx context.Context, input *ItemInput) (*ItemOutput, error) {
+		return &ItemOutput{Body: input.Body}, nil
+	}
+	huma.Post(api, "/items", handler, huma.OperationTags("mirror"))
+	huma.Put(api, "/items", handler, huma.OperationTags("mirror"))
+	huma.Post(api, "/other", handler)
+
+	resp := api.Post("/items", "Connection: X-Hop", "X-Hop: 1", map[string]any{"name": "original"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"name":"original"`)
+
+	m := <-results
+	assert.Empty(t, m.req.Header.Get("X-Hop"))
+	assert.Equal(t, http.MethodPost, m.req.Method)
+	assert.Equal(t, "/items", m.req.URL.Path)
+	assert.Equal(t, "true", m.req.Header.Get("X-Mirrored-Request"))
+	assert.JSONEq(t, `{"name":"original"}`, m.body)
+	assert.Equal(t, http.StatusOK, m.primary.Status)
+	assert.Contains(t, m.primary.Header.Get("Content-Type"), "json")
+	assert.Contains(t, string(m.primary.Body), `"name":"original"`)
+	require.NoError(t, m.resp.Err)
+	assert.Equal(t, http.StatusOK, m.resp.Status)
+	assert.Equal(t, `{"name":"rewritten"}`, string(m.resp.Body))
+
+	// Requests which don't match are not mirrored.
+	api.Put("/items", map[string]any{"name": "x"})
+	api.Post("/other", map[string]any{"name": "x"})
+
+	// Mirror to an upstream, which may fail without affecting the client.
+	_, api = humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	var calls atomic.Int32
+	target, _ := url.Parse("http://shadow.internal/v2?mirror=1")
+	api.UseMiddleware(huma.Mirror(huma.MirrorConfig{
+		Percent: 100,
+		Target:  target,
+		Client: &http.Client{Transport: proxyTransport(func(req *http.Request) (*http.Response, error) {
+			calls.Add(1)
+			assert.Equal(t, "/v2/items", req.URL.Path)
+			assert.Equal(t, "mirror=1&q=1", req.URL.RawQuery)
+			return nil, errors.New("connection refused")
+		})},
+		Compare: compare,
+	}))
+	huma.Post(api, "/items", handler)
+
+	resp = api.Post("/items?q=1", map[string]any{"name": "original"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	m = <-results
+	assert.Error(t, m.resp.Err)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Empty(t, results)
+
+	// Nothing is mirrored at 0%.
+	_, api = humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	api.UseMiddleware(huma.Mirror(huma.MirrorConfig{Target: target, Compare: compare}))
+	huma.Post(api, "/items", handler)
+	api.Post("/items", map[string]any{"name": "original"})
+	assert.Empty(t, results)
+
+	assert.Panics(t, func() {
+		huma.Mirror(huma.MirrorConfig{Percent: 100})
+	})
+}
+
+func TestLenientParams(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "lenient",
+		Method:      http.MethodGet,
+		Path:        "/lenient",
+	}, func(ctx context.Context, input *struct {
+		Amount float64   `query:"amount" lenient:"true"`
+		Strict float64   `query:"strict"`
+		Since  time.Time `query:"since" lenient:"true"`
+		Day    time.Time `query:"day" lenient:"true" timeFormat:"2006-01-02"`
+	}) (*struct{ Body []any }, error) {
+		return &struct{ Body []any }{Body: []any{input.Amount, input.Since, input.Day}}, nil
+	})
+
+	for _, tc := range []struct {
+		query    string
+		expected string
+	}{
+		{"amount=1,5", `[1.5, "0001-01-01T00:00:00Z", "0001-01-01T00:00:00Z"]`},
+		{"amount=1.234,5", `[1234.5, "0001-01-01T00:00:00Z", "0001-01-01T00:00:00Z"]`},
+		{"amount=1,234.5", `[1234.5, "0001-01-01T00:00:00Z", "0001-01-01T00:00:00Z"]`},
+		{"amount=1.234.567", `[1234567, "0001-01-01T00:00:00Z", "0001-01-01T00:00:00Z"]`},
+		{"amount=1%20234.5", `[1234.5, "0001-01-01T00:00:00Z", "0001-01-01T00:00:00Z"]`},
+		{"since=01/02/2024", `[0, "2024-01-02T00:00:00Z", "0001-01-01T00:00:00Z"]`},
+		{"since=02.01.2024", `[0, "2024-01-02T00:00:00Z", "0001-01-01T00:00:00Z"]`},
+		{"since=2024-01-02T03:04:05Z", `[0, "2024-01-02T03:04:05Z", "0001-01-01T00:00:00Z"]`},
+		{"day=01/02/2024", `[0, "0001-01-01T00:00:00Z", "2024-01-02T00:00:00Z"]`},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			resp := api.Get("/lenient?" + tc.query)
+			assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+			assert.JSONEq(t, tc.expected, resp.Body.String())
+		})
+	}
+
+	// Params without the tag are strict.
+	resp := api.Get("/lenient?strict=1,5")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	resp = api.Get("/lenient?since=yesterday")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "invalid date/time")
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.MethodNotAllowed = true
+	_, api := humatest.New(t, config)
+
+	handler := func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	}
+	huma.Get(api, "/things/{id}", handler)
+	huma.Put(api, "/things/{id}", handler)
+	huma.Delete(api, "/things/{id}", handler)
+	huma.Post(api, "/things/new", handler)
+	huma.Register(api, huma.Operation{
+		OperationID: "hidden-thing",
+		Method:      http.MethodPatch,
+		Path:        "/things/{id}/hidden",
+		Hidden:      true,
+	}, handler)
+
+	resp := api.Post("/things/123")
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	assert.Equal(t, "GET, PUT, DELETE", resp.Header().Get("Allow"))
+	assert.Equal(t, "application/problem+json", resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Body.String(), "method POST not allowed")
+
+	// Literal path segments are preferred over parameters.
+	resp = api.Patch("/things/new")
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	assert.Equal(t, "POST", resp.Header().Get("Allow"))
+
+	// Hidden operations are served, so their methods are allowed.
+	resp = api.Get("/things/123/hidden")
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	assert.Equal(t, "PATCH", resp.Header().Get("Allow"))
+
+	resp = api.Get("/missing")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Equal(t, "application/problem+json", resp.Header().Get("Content-Type"))
+}
+
+func TestResponseDefaults(t *testing.T) {
+	type Item struct {
+		Name  string `json:"name"`
+		Color string `json:"color" default:"red"`
+	}
+
+	type Thing struct {
+		Name  string  `json:"name"`
+		Count int     `json:"count" default:"1"`
+		Label *string `json:"label,omitempty" default:"none"`
+		Items []Item  `json:"items"`
+		Owner *Item   `json:"owner,omitempty"`
+	}
+
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	handler := func(ctx context.Context, input *struct{}) (*struct{ Body *Thing }, error) {
+		return &struct{ Body *Thing }{Body: &Thing{
+			Name:  "a",
+			Items: []Item{{Name: "x"}, {Name: "y", Color: "blue"}},
+			Owner: &Item{Name: "z"},
+		}}, nil
+	}
+
+	huma.Register(api, huma.Operation{
+		Method:                http.MethodGet,
+		Path:                  "/defaults",
+		ApplyResponseDefaults: true,
+	}, handler)
+	huma.Get(api, "/sparse", handler)
+
+	resp := api.Get("/defaults")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	delete(body, "$schema")
+	b, _ := json.Marshal(body)
+	assert.JSONEq(t, `{
+		"name": "a",
+		"count": 1,
+		"label": "none",
+		"items": [{"name": "x", "color": "red"}, {"name": "y", "color": "blue"}],
+		"owner": {"name": "z", "color": "red"}
+	}`, string(b))
+
+	resp = api.Get("/sparse")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"count":0`)
+	assert.NotContains(t, resp.Body.String(), `"label"`)
+}
+
+func TestBodyLimitsDocumented(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	handler := func(ctx context.Context, input *struct {
+		Body struct {
+			Name string `json:"name"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	}
+
+	huma.Register(api, huma.Operation{
+		Method:          http.MethodPut,
+		Path:            "/limited",
+		MaxBodyBytes:    5,
+		BodyReadTimeout: 10 * time.Second,
+	}, handler)
+	huma.Put(api, "/default", handler)
+
+	op := api.OpenAPI().Paths["/limited"].Put
+	assert.Equal(t, int64(5), op.Extensions["x-max-body-bytes"])
+	assert.Equal(t, 10.0, op.Extensions["x-body-read-timeout"])
+	assert.Contains(t, op.Responses, "413")
+	assert.Contains(t, op.Responses, "408")
+
+	// Default limits are not documented.
+	op = api.OpenAPI().Paths["/default"].Put
+	assert.NotContains(t, op.Extensions, "x-max-body-bytes")
+	assert.NotContains(t, op.Responses, "413")
+
+	resp := api.Put("/limited", map[string]any{"name": "too long"})
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+	var model huma.ErrorModel
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &model))
+	require.Len(t, model.Errors, 1)
+	assert.Equal(t, "body", model.Errors[0].Location)
+	assert.Equal(t, 5.0, model.Errors[0].Value)
+}
+
+func TestFieldNameMatching(t *testing.T) {
+	type Address struct {
+		ZipCode string `json:"zipCode" altNames:"zip_code,zip"`
+	}
+
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.CaseInsensitiveFields = true
+	config.FieldAliases = true
+	_, api := humatest.New(t, config)
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodPut,
+		Path:   "/users",
+	}, func(ctx context.Context, input *struct {
+		Body struct {
+			UserID    string    `json:"userId" altNames:"user_id"`
+			Addresses []Address `json:"addresses,omitempty"`
+		}
+	}) (*struct {
+		Body struct {
+			UserID string `json:"userId"`
+			Zip    string `json:"zip"`
+		}
+	}, error) {
+		resp := &struct {
+			Body struct {
+				UserID string `json:"userId"`
+				Zip    string `json:"zip"`
+			}
+		}{}
+		resp.Body.UserID = input.Body.UserID
+		if len(input.Body.Addresses) > 0 {
+			resp.Body.Zip = input.Body.Addresses[0].ZipCode
+		}
+		return resp, nil
+	})
+
+	resp := api.Put("/users", map[string]any{"user_id": "abc", "addresses": []any{map[string]any{"zip_code": "12345"}}})
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), `"userId":"abc","zip":"12345"`)
+
+	resp = api.Put("/users", map[string]any{"USERID": "def", "Addresses": []any{map[string]any{"zip": "6789"}}})
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), `"userId":"def","zip":"6789"`)
+
+	// The declared name wins, and the alias is then unknown.
+	resp = api.Put("/users", map[string]any{"userId": "abc", "user_id": "def"})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), "body.user_id")
+
+	// Without the config, only the declared names are accepted.
+	_, strict := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Register(strict, huma.Operation{
+		Method: http.MethodPut,
+		Path:   "/users",
+	}, func(ctx context.Context, input *struct {
+		Body struct {
+			UserID string `json:"userId" altNames:"user_id"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+	resp = strict.Put("/users", map[string]any{"user_id": "abc"})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+}
+
+func TestHeaderArrays(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/headers",
+	}, func(ctx context.Context, input *struct {
+		Tags  []string `header:"X-Tag" enum:"a,b,c"`
+		IDs   []int    `header:"X-ID"`
+		Dates []string `header:"X-Date,nosplit"`
+	}) (*struct {
+		Body struct {
+			Tags  []string `json:"tags"`
+			IDs   []int    `json:"ids"`
+			Dates []string `json:"dates"`
+		}
+	}, error) {
+		out := &struct {
+			Body struct {
+				Tags  []string `json:"tags"`
+				IDs   []int    `json:"ids"`
+				Dates []string `json:"dates"`
+			}
+		}{}
+		out.Body.Tags = input.Tags
+		out.Body.IDs = input.IDs
+		out.Body.Dates = input.Dates
+		return out, nil
+	})
+
+	for _, p := range api.OpenAPI().Paths["/headers"].Get.Parameters {
+		require.NotNil(t, p.Explode, p.Name)
+		assert.False(t, *p.Explode)
+		assert.Equal(t, "array", p.Schema.Type)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/headers", nil)
+	req.Header.Add("X-Tag", "a, b")
+	req.Header.Add("X-Tag", "c")
+	req.Header.Add("X-ID", "1,2")
+	req.Header.Add("X-ID", "3")
+	req.Header.Add("X-Date", "Wed, 21 Oct 2015 07:28:00 GMT")
+	req.Header.Add("X-Date", "Thu, 22 Oct 2015 07:28:00 GMT")
+	w := httptest.NewRecorder()
+	api.Adapter().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"$schema": "https:///schemas/Response.json",
+		"tags": ["a", "b", "c"],
+		"ids": [1, 2, 3],
+		"dates": ["Wed, 21 Oct 2015 07:28:00 GMT", "Thu, 22 Oct 2015 07:28:00 GMT"]
+	}`, w.Body.String())
+
+	// Each element is validated.
+	req, _ = http.NewRequest(http.MethodGet, "/headers", nil)
+	req.Header.Add("X-Tag", "a")
+	req.Header.Add("X-Tag", "d")
+	w = httptest.NewRecorder()
+	api.Adapter().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "header.X-Tag[1]")
+}
+
+func TestValidationErrorStatus(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.ValidationErrorStatus = http.StatusBadRequest
+	_, api := humatest.New(t, config)
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodPost,
+		Path:   "/things/{id}",
+		Errors: []int{http.StatusNotFound},
+	}, func(ctx context.Context, input *struct {
+		ID   int `path:"id" minimum:"1"`
+		Body struct {
+			Name string `json:"name" minLength:"3"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodPost,
+		Path:   "/upload",
+	}, func(ctx context.Context, input *struct {
+		RawBody huma.MultipartFormFiles[struct {
+			File huma.FormFile `form:"file" contentType:"text/plain" required:"true"`
+		}]
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	responses := api.OpenAPI().Paths["/things/{id}"].Post.Responses
+	assert.Contains(t, responses, "400")
+	assert.NotContains(t, responses, "422")
+
+	resp := api.Post("/things/0", map[string]any{"name": "abc"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), "path.id")
+
+	resp = api.Post("/things/1", map[string]any{"name": "a"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), "body.name")
+
+	resp = api.Post("/upload", "Content-Type: multipart/form-data; boundary=SimpleBoundary", strings.NewReader(`--SimpleBoundary
+Content-Disposition: form-data; name="other"; filename="test.txt"
+Content-Type: text/plain
+
+Hello, World!
+--SimpleBoundary--`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+
+	resp = api.Post("/things/1", map[string]any{"name": "abc"})
+	assert.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+func TestMultipartFileLimits(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodPost,
+		Path:   "/upload",
+	}, func(ctx context.Context, input *struct {
+		RawBody huma.MultipartFormFiles[struct {
+			Avatar huma.FormFile   `form:"avatar" contentType:"text/plain" maxFileSize:"10"`
+			Docs   []huma.FormFile `form:"docs" contentType:"text/plain" maxFiles:"2" maxFileSize:"1KiB"`
+		}]
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	op := api.OpenAPI().Paths["/upload"].Post
+	assert.Contains(t, op.Responses, "413")
+	schema := op.RequestBody.Content["multipart/form-data"].Schema
+	assert.Equal(t, int64(10), schema.Properties["avatar"].Extensions["x-max-file-size"])
+	assert.Equal(t, 2, *schema.Properties["docs"].MaxItems)
+	assert.Equal(t, int64(1024), schema.Properties["docs"].Items.Extensions["x-max-file-size"])
+
+	part := func(name, content string) string {
+		return "--SimpleBoundary\r\nContent-Disposition: form-data; name=\"" + name + "\"; filename=\"" + name + ".txt\"\r\nContent-Type: text/plain\r\n\r\n" + content + "\r\n"
+	}
+	post := func(parts ...string) *httptest.ResponseRecorder {
+		return api.Post("/upload", "Content-Type: multipart/form-data; boundary=SimpleBoundary", strings.NewReader(strings.Join(parts, "")+"--SimpleBoundary--\r\n"))
+	}
+
+	resp := post(part("avatar", "small"), part("docs", "a"), part("docs", "b"))
+	assert.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+
+	resp = post(part("avatar", "this is too large"))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), `"location":"avatar"`)
+	assert.Contains(t, resp.Body.String(), `file \"avatar.txt\" exceeds the maximum size of 10 bytes`)
+
+	resp = post(part("docs", "a"), part("docs", strings.Repeat("b", 2000)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), `"location":"docs[1]"`)
+
+	resp = post(part("docs", "a"), part("docs", "b"), part("docs", "c"))
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), "at most 2")
+
+	// Reading stops at the limit rather than buffering the whole upload.
+	var read int
+	body := io.TeeReader(io.MultiReader(
+		strings.NewReader("--SimpleBoundary\r\nContent-Disposition: form-data; name=\"avatar\"; filename=\"avatar.txt\"\r\nContent-Type: text/plain\r\n\r\n"),
+		strings.NewReader(strings.Repeat("x", 10<<20)),
+	), writerFunc(func(p []byte) (int, error) {
+		read += len(p)
+		return len(p), nil
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/upload", body)
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=SimpleBoundary")
+	resp = httptest.NewRecorder()
+	api.Adapter().ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code, resp.Body.String())
+	assert.Less(t, read, 1<<20)
+
+	assert.Panics(t, func() {
+		huma.Register(api, huma.Operation{
+			Method: http.MethodPost,
+			Path:   "/bad",
+		}, func(ctx context.Context, input *struct {
+			RawBody huma.MultipartFormFiles[struct {
+				File huma.FormFile `form:"file" maxFiles:"2"`
+			}]
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
+
+func TestMultipartConfig(t *testing.T) {
+	dir := t.TempDir()
+	var sunk bytes.Buffer
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Multipart = &huma.MultipartConfig{
+		MaxMemory: 8,
+		TempDir:   dir,
+		Sink: func(ctx huma.Context, field string, fh *multipart.FileHeader) (io.Writer, error) {
+			if field == "video" {
+				return &sunk, nil
+			}
+			return nil, nil
+		},
+	}
+	_, api := humatest.New(t, config)
+
+	var spooled []string
+	var opened *os.File
+	huma.Register(api, huma.Operation{
+		Method: http.MethodPost,
+		Path:   "/upload",
+	}, func(ctx context.Context, input *struct {
+		RawBody huma.MultipartFormFiles[struct {
+			Docs  []huma.FormFile `form:"docs" contentType:"text/plain"`
+			Video huma.FormFile   `form:"video"`
+		}]
+	}) (*struct{}, error) {
+		data := input.RawBody.Data()
+		assert.Equal(t, "name", input.RawBody.Form.Value["name"][0])
+
+		small, _ := io.ReadAll(data.Docs[0])
+		assert.Equal(t, "small", string(small))
+		large, _ := io.ReadAll(data.Docs[1])
+		assert.Equal(t, "this one is spooled to disk", string(large))
+		opened, _ = data.Docs[1].File.(*os.File)
+
+		entries, _ := os.ReadDir(dir)
+		for _, e := range entries {
+			spooled = append(spooled, e.Name())
+		}
+
+		assert.True(t, data.Video.IsSet)
+		assert.Nil(t, data.Video.File)
+		assert.EqualValues(t, 5, data.Video.Size)
+		if input.RawBody.Form.Value["panic"] != nil {
+			panic("boom")
+		}
+		return nil, nil
+	})
+
+	part := func(name, filename, content string) string {
+		disposition := "form-data; name=\"" + name + "\""
+		if filename != "" {
+			disposition += "; filename=\"" + filename + "\""
+		}
+		return "--SimpleBoundary\r\nContent-Disposition: " + disposition + "\r\nContent-Type: text/plain\r\n\r\n" + content + "\r\n"
+	}
+	body := part("name", "", "name") + part("docs", "a.txt", "small") + part("docs", "b.txt", "this one is spooled to disk") + part("video", "v.mp4", "video")
+
+	resp := api.Post("/upload", "Content-Type: multipart/form-data; boundary=SimpleBoundary", strings.NewReader(body+"--SimpleBoundary--\r\n"))
+	assert.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+	assert.Len(t, spooled, 1)
+	assert.Equal(t, "video", sunk.String())
+	entries, _ := os.ReadDir(dir)
+	assert.Empty(t, entries)
+
+	// Files are removed even if the handler panics.
+	spooled = nil
+	assert.Panics(t, func() {
+		api.Post("/upload", "Content-Type: multipart/form-data; boundary=SimpleBoundary", strings.NewReader(body+part("panic", "", "1")+"--SimpleBoundary--\r\n"))
+	})
+	assert.Len(t, spooled, 1)
+	entries, _ = os.ReadDir(dir)
+	assert.Empty(t, entries)
+
+	// Keep files for the handler to move into place.
+	config.Multipart.KeepFiles = true
+	resp = api.Post("/upload", "Content-Type: multipart/form-data; boundary=SimpleBoundary", strings.NewReader(body+"--SimpleBoundary--\r\n"))
+	assert.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+	entries, _ = os.ReadDir(dir)
+	assert.Len(t, entries, 1)
+	require.NotNil(t, opened)
+	assert.ErrorIs(t, opened.Close(), os.ErrClosed)
+	os.Remove(filepath.Join(dir, entries[0].Name()))
+
+	// Files are only read up to their size limit.
+	config.Multipart.KeepFiles = false
+	huma.Register(api, huma.Operation{
+		Method: http.MethodPost,
+		Path:   "/limited",
+	}, func(ctx context.Context, input *struct {
+		RawBody huma.MultipartFormFiles[struct {
+			Docs  []huma.FormFile `form:"docs" contentType:"text/plain" maxFileSize:"16"`
+			Video huma.FormFile   `form:"video" maxFileSize:"4"`
+		}]
+	}) (*struct{}, error) {
+		t.Fatal("handler should not be called")
+		return nil, nil
+	})
+
+	resp = api.Post("/limited", "Content-Type: multipart/form-data; boundary=SimpleBoundary", strings.NewReader(part("docs", "a.txt", "small")+part("docs", "b.txt", strings.Repeat("x", 1000))+"--SimpleBoundary--\r\n"))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), `"location":"docs[1]"`)
+	entries, _ = os.ReadDir(dir)
+	assert.Empty(t, entries)
+
+	sunk.Reset()
+	resp = api.Post("/limited", "Content-Type: multipart/form-data; boundary=SimpleBoundary", strings.NewReader(part("video", "v.mp4", strings.Repeat("v", 1000))+"--SimpleBoundary--\r\n"))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), `"location":"video"`)
+	assert.Equal(t, 5, sunk.Len())
+}
+
+func TestParsePreferences(t *testing.T) {
+	prefs := huma.ParsePreferences(`return=minimal; foo="bar", respond-async`, `Wait=10, return=representation`, `handling="lenient"`)
+	assert.Equal(t, huma.Preferences{
+		"return":        "minimal",
+		"respond-async": "",
+		"wait":          "10",
+		"handling":      "lenient",
+	}, prefs)
+	assert.Equal(t, "minimal", prefs.Return())
+	assert.Empty(t, huma.ParsePreferences().Return())
+}
+
+func TestPrefer(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	type Thing struct {
+		Name string `json:"name"`
+	}
+
+	var prefs huma.Preferences
+	huma.Register(api, huma.Operation{
+		Method: http.MethodPut,
+		Path:   "/things/{id}",
+		Prefer: true,
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{ Body Thing }, error) {
+		prefs = huma.PreferencesFromContext(ctx)
+		return &struct{ Body Thing }{Body: Thing{Name: input.ID}}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		Method:        http.MethodPost,
+		Path:          "/things",
+		DefaultStatus: http.StatusCreated,
+		Prefer:        true,
+	}, func(ctx context.Context, input *struct{}) (*struct {
+		Location string `header:"Location"`
+		Body     Thing
+	}, error) {
+		return &struct {
+			Location string `header:"Location"`
+			Body     Thing
+		}{Location: "/things/a", Body: Thing{Name: "a"}}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/other",
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body Thing }, error) {
+		prefs = huma.PreferencesFromContext(ctx)
+		return &struct{ Body Thing }{Body: Thing{Name: "other"}}, nil
+	})
+
+	params := api.OpenAPI().Paths["/things/{id}"].Put.Parameters
+	require.Len(t, params, 2)
+	assert.Equal(t, "Prefer", params[1].Name)
+	assert.Equal(t, "header", params[1].In)
+
+	resp := api.Put("/things/a", "Prefer: return=minimal")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Empty(t, resp.Body.String())
+	assert.Equal(t, "return=minimal", resp.Header().Get("Preference-Applied"))
+	assert.Equal(t, "minimal", prefs.Return())
+
+	resp = api.Put("/things/a", "Prefer: return=representation")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"name":"a"`)
+	assert.Equal(t, "return=representation", resp.Header().Get("Preference-Applied"))
+
+	resp = api.Put("/things/a")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"name":"a"`)
+	assert.Empty(t, resp.Header().Get("Preference-Applied"))
+
+	// Other statuses and headers are kept.
+	resp = api.Post("/things", "Prefer: return=minimal")
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Empty(t, resp.Body.String())
+	assert.Equal(t, "/things/a", resp.Header().Get("Location"))
+
+	// Operations without `Prefer` ignore the header.
+	resp = api.Get("/other", "Prefer: return=minimal")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"name":"other"`)
+	assert.Nil(t, prefs)
+}
+
+func TestMaxRequestTimeout(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		OperationID:       "slow",
+		Method:            http.MethodGet,
+		Path:              "/slow",
+		MaxRequestTimeout: time.Second,
+	}, func(ctx context.Context, input *struct {
+		Wait bool `query:"wait"`
+	}) (*struct {
+		Remaining time.Duration `header:"Remaining"`
+	}, error) {
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		if input.Wait {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return &struct {
+			Remaining time.Duration `header:"Remaining"`
+		}{Remaining: time.Until(deadline)}, nil
+	})
+
+	op := api.OpenAPI().Paths["/slow"].Get
+	assert.Equal(t, "X-Request-Timeout", op.Parameters[len(op.Parameters)-1].Name)
+	assert.Contains(t, op.Responses, "504")
+
+	remaining := func(resp *httptest.ResponseRecorder) time.Duration {
+		require.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+		d, err := strconv.ParseInt(resp.Header().Get("Remaining"), 10, 64)
+		require.NoError(t, err)
+		return time.Duration(d)
+	}
+
+	// The maximum is used when no timeout is sent.
+	assert.Greater(t, remaining(api.Get("/slow")), 500*time.Millisecond)
+
+	assert.LessOrEqual(t, remaining(api.Get("/slow", "X-Request-Timeout: 50ms")), 50*time.Millisecond)
+	assert.LessOrEqual(t, remaining(api.Get("/slow", "X-Request-Timeout: 0.05")), 50*time.Millisecond)
+	assert.LessOrEqual(t, remaining(api.Get("/slow", "Grpc-Timeout: 50m")), 50*time.Millisecond)
+
+	// Client timeouts are bounded by the maximum.
+	assert.LessOrEqual(t, remaining(api.Get("/slow", "Grpc-Timeout: 5H")), time.Second)
+
+	resp := api.Get("/slow", "X-Request-Timeout: soon")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "header.X-Request-Timeout")
+
+	resp = api.Get("/slow?wait=true", "X-Request-Timeout: 10ms")
+	assert.Equal(t, http.StatusGatewayTimeout, resp.Code)
+}
+
+func TestStreamTrailers(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		Method:   http.MethodGet,
+		Path:     "/download",
+		Trailers: []string{"X-Checksum"},
+	}, func(ctx context.Context, input *struct{}) (*huma.StreamResponse, error) {
+		return &huma.StreamResponse{
+			Trailers: []string{"X-Status"},
+			Body: func(ctx huma.Context) {
+				ctx.SetHeader("Content-Type", "text/plain")
+				ctx.BodyWriter().Write([]byte("hello"))
+				huma.SetTrailer(ctx, "X-Checksum", "abc123")
+				huma.SetTrailer(huma.WithValue(ctx, "key", "value"), "X-Status", "complete")
+			},
+		}, nil
+	})
+
+	resp := api.OpenAPI().Paths["/download"].Get.Responses["200"]
+	assert.Equal(t, []string{"X-Checksum"}, resp.Extensions["x-trailers"])
+
+	w := api.Get("/download")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"X-Checksum", "X-Status"}, w.Header().Values("Trailer"))
+	result := w.Result()
+	assert.Equal(t, "abc123", result.Trailer.Get("X-Checksum"))
+	assert.Equal(t, "complete", result.Trailer.Get("X-Status"))
+}
+
+func TestStreamClientDisconnect(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	disconnects := 0
+	huma.Get(api, "/stream", func(ctx context.Context, input *struct{}) (*huma.StreamResponse, error) {
+		return &huma.StreamResponse{
+			Body: func(ctx huma.Context) {
+				ctx.SetHeader("Content-Type", "text/plain")
+				ctx.BodyWriter().Write([]byte("hello"))
+			},
+			OnClientDisconnect: func(ctx huma.Context) {
+				disconnects++
+			},
+		}, nil
+	})
+
+	// Completed streams don't call the hook.
+	resp := api.Get("/stream")
+	assert.Equal(t, "hello", resp.Body.String())
+	assert.Equal(t, 0, disconnects)
+
+	// Write failures mean the client has gone away.
+	req, _ := http.NewRequest(http.MethodGet, "/stream", nil)
+	api.Adapter().ServeHTTP(&BrokenWriter{httptest.NewRecorder()}, req)
+	assert.Equal(t, 1, disconnects)
+
+	// So does a canceled request context.
+	reqCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ = http.NewRequestWithContext(reqCtx, http.MethodGet, "/stream", nil)
+	api.Adapter().ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, 2, disconnects)
+}
+
+func TestStreamJSONArray(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	type Item struct {
+		ID int `json:"id"`
+	}
+
+	huma.Get(api, "/items", func(ctx context.Context, input *struct {
+		Count int  `query:"count"`
+		Fail  bool `query:"fail"`
+	}) (*huma.JSONArrayResponse[Item], error) {
+		return huma.StreamJSONArray(func(yield func(Item) error) error {
+			for i := 0; i < input.Count; i++ {
+				if err := yield(Item{ID: i}); err != nil {
+					return err
+				}
+			}
+			if input.Fail {
+				return errors.New("database went away")
+			}
+			return nil
+		}), nil
+	})
+
+	content := api.OpenAPI().Paths["/items"].Get.Responses["200"].Content["application/json"]
+	assert.Equal(t, "array", content.Schema.Type)
+	assert.Equal(t, "#/components/schemas/Item", content.Schema.Items.Ref)
+
+	resp := api.Get("/items?count=3")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	assert.Equal(t, `[{"id":0},{"id":1},{"id":2}]`, resp.Body.String())
+
+	resp = api.Get("/items?count=0")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `[]`, resp.Body.String())
+
+	// Errors before the first item result in an error response.
+	resp = api.Get("/items?count=0&fail=true")
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Contains(t, resp.Body.String(), "database went away")
+
+	// Errors after the first item leave the array unterminated.
+	resp = api.Get("/items?count=2&fail=true")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `[{"id":0},{"id":1}`, resp.Body.String())
+}
+
+func TestDecompressRequests(t *testing.T) {
+	type Input struct {
+		Body struct {
+			Name string `json:"name" maxLength:"200"`
+		}
+	}
+
+	compress := func(encoding, body string) io.Reader {
+		buf := &bytes.Buffer{}
+		var w io.WriteCloser
+		if encoding == "gzip" {
+			w = gzip.NewWriter(buf)
+		} else {
+			w = zlib.NewWriter(buf)
+		}
+		w.Write([]byte(body))
+		w.Close()
+		return buf
+	}
+
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Register(api, huma.Operation{
+		Method:                   http.MethodPost,
+		Path:                     "/things",
+		MaxDecompressedBodyBytes: 100,
+	}, func(ctx context.Context, input *Input) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: input.Body.Name}, nil
+	})
+
+	for _, encoding := range []string{"gzip", "deflate"} {
+		resp := api.Post("/things", "Content-Encoding: "+encoding, compress(encoding, `{"name": "foo"}`))
+		assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		assert.Equal(t, `"foo"`, strings.TrimSpace(resp.Body.String()))
+	}
+
+	resp := api.Post("/things", "Content-Encoding: gzip", compress("gzip", `{"name": "`+strings.Repeat("a", 150)+`"}`))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+
+	resp = api.Post("/things", "Content-Encoding: gzip", strings.NewReader(`{"name": "foo"}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = api.Post("/things", "Content-Encoding: br", strings.NewReader(`{"name": "foo"}`))
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.Code)
+	assert.Contains(t, resp.Body.String(), "header.Content-Encoding")
+
+	// Bodies are passed through as-is when disabled.
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.DecompressRequests = false
+	_, api = humatest.New(t, config)
+	huma.Post(api, "/things", func(ctx context.Context, input *Input) (*struct{}, error) {
+		return nil, nil
+	})
+	resp = api.Post("/things", "Content-Encoding: gzip", compress("gzip", `{"name": "foo"}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestVerifyDigest(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Register(api, huma.Operation{
+		Method:       http.MethodPost,
+		Path:         "/things",
+		VerifyDigest: true,
+	}, func(ctx context.Context, input *struct {
+		Body struct {
+			Name string `json:"name"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	params := api.OpenAPI().Paths["/things"].Post.Parameters
+	assert.Equal(t, []string{"Content-MD5", "Digest", "Content-Digest"}, []string{params[0].Name, params[1].Name, params[2].Name})
+
+	body := `{"name":"a"}`
+	md5Sum := md5.Sum([]byte(body))
+	shaSum := sha256.Sum256([]byte(body))
+	md5Value := base64.StdEncoding.EncodeToString(md5Sum[:])
+	shaValue := base64.StdEncoding.EncodeToString(shaSum[:])
+
+	for _, header := range []string{
+		"",
+		"Content-MD5: " + md5Value,
+		"Digest: SHA-256=" + shaValue + ",unknown=abc",
+		"Content-Digest: sha-256=:" + shaValue + ":",
+	} {
+		args := []any{strings.NewReader(body)}
+		if header != "" {
+			args = append([]any{header}, args...)
+		}
+		resp := api.Post("/things", args...)
+		assert.Equal(t, http.StatusNoContent, resp.Code, header)
+	}
+
+	resp := api.Post("/things", "Content-MD5: "+md5Value, strings.NewReader(`{"name":"b"}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), "request body checksum mismatch")
+	assert.Contains(t, resp.Body.String(), `"location":"header.Content-MD5"`)
+	assert.Contains(t, resp.Body.String(), "expected md5 checksum "+md5Value)
+
+	// Content-Digest only accepts the algorithms allowed by RFC 9530, even if
+	// the checksum matches.
+	resp = api.Post("/things", "Content-Digest: md5=:"+md5Value+":", strings.NewReader(body))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"location":"header.Content-Digest"`)
+	assert.Contains(t, resp.Body.String(), "unsupported Content-Digest algorithm md5")
+}
+
+func TestBodyLimits(t *testing.T) {
+	limits := &huma.BodyLimits{MaxDepth: 3, MaxElements: 10, MaxProperties: 3}
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.BodyLimits = limits
+	_, api := humatest.New(t, config)
+
+	huma.Put(api, "/things", func(ctx context.Context, input *struct {
+		Body map[string]any
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	assert.Contains(t, api.OpenAPI().Paths["/things"].Put.Responses, "413")
+
+	resp := api.Put("/things", map[string]any{"a": []any{1, "x]", map[string]any{"b": true}}})
+	assert.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+
+	for _, item := range []struct {
+		body string
+		msg  string
+	}{
+		{`{"a": [[[1]]]}`, "request body is nested too deeply limit=3"},
+		{`{"a": [1, 2, 3, 4, 5, 6, 7, 8, 9, 10]}`, "request body has too many elements limit=10"},
+		{`{"a": 1, "b": 2, "c": 3, "d": 4}`, "request body object has too many properties limit=3"},
+	} {
+		t.Run(item.msg, func(t *testing.T) {
+			resp := api.Put("/things", strings.NewReader(item.body))
+			assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+			assert.Contains(t, resp.Body.String(), item.msg)
+		})
+	}
+
+	// Strings containing brackets & commas don't count.
+	resp = api.Put("/things", strings.NewReader(`{"a": "[[[{,,,,,,,,,,,,}]]]", "b": "\"[["}`))
+	assert.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+
+	stats := limits.Stats()
+	assert.Equal(t, int64(5), stats.Checked)
+	assert.Equal(t, int64(3), stats.Rejected)
+	assert.Equal(t, int64(4), stats.MaxDepth)
+	assert.Equal(t, int64(11), stats.MaxElements)
+	assert.Equal(t, int64(4), stats.MaxProperties)
+}
+
+func TestParamUniqueItems(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/things",
+	}, func(ctx context.Context, input *struct {
+		Tags []string `query:"tags" uniqueItems:"true" maxItems:"3"`
+		IDs  []int    `header:"X-ID" uniqueItems:"true"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	// The keyword is documented on the param schema.
+	for _, p := range api.OpenAPI().Paths["/things"].Get.Parameters {
+		assert.True(t, p.Schema.UniqueItems, p.Name)
+	}
+
+	resp := api.Get("/things?tags=a,b,c", "X-ID: 1, 2")
+	assert.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+
+	resp = api.Get("/things?tags=a,b,a,a", "X-ID: 1, 2, 1")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	var model huma.ErrorModel
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &model))
+	locations := []string{}
+	for _, detail := range model.Errors {
+		locations = append(locations, detail.Location)
+	}
+	assert.Equal(t, []string{"query.tags[2]", "query.tags[3]", "query.tags", "header.X-ID[2]"}, locations)
+	assert.Equal(t, "a", model.Errors[0].Value)
+	assert.Equal(t, "expected array items to be unique", model.Errors[0].Message)
+}
+
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestReaderBody(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	tracker := &closeTracker{Reader: strings.NewReader("hello, world")}
+	huma.Get(api, "/download", func(ctx context.Context, input *struct{}) (*struct {
+		ETag string `header:"ETag"`
+		Body huma.ReaderBody
+	}, error) {
+		return &struct {
+			ETag string `header:"ETag"`
+			Body huma.ReaderBody
+		}{
+			ETag: "abc",
+			Body: huma.ReaderBody{Reader: tracker, Length: 12, ContentType: "text/plain"},
+		}, nil
+	})
+
+	huma.Get(api, "/reader", func(ctx context.Context, input *struct{}) (*struct {
+		Status int
+		Body   io.Reader `contentType:"image/png"`
+	}, error) {
+		return &struct {
+			Status int
+			Body   io.Reader `contentType:"image/png"`
+		}{Status: http.StatusPartialContent, Body: strings.NewReader("\x01\x02\x03")}, nil
+	})
+
+	media := api.OpenAPI().Paths["/download"].Get.Responses["200"].Content["application/octet-stream"]
+	require.NotNil(t, media)
+	assert.Equal(t, "binary", media.Schema.Format)
+	assert.NotNil(t, api.OpenAPI().Paths["/reader"].Get.Responses["200"].Content["image/png"])
+
+	resp := api.Get("/download")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/plain", resp.Header().Get("Content-Type"))
+	assert.Equal(t, "12", resp.Header().Get("Content-Length"))
+	assert.Equal(t, "abc", resp.Header().Get("ETag"))
+	assert.Equal(t, "hello, world", resp.Body.String())
+	assert.True(t, tracker.closed)
+
+	resp = api.Get("/reader")
+	assert.Equal(t, http.StatusPartialContent, resp.Code)
+	assert.Equal(t, "image/png", resp.Header().Get("Content-Type"))
+	assert.Equal(t, []byte{1, 2, 3}, resp.Body.Bytes())
+}
+
+func TestOperationEnabled(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	beta := func(ctx huma.Context) bool {
+		return ctx.Header("X-Beta") == "true"
+	}
+
+	huma.Register(api, huma.Operation{
+		OperationID:      "get-beta",
+		Method:           http.MethodGet,
+		Path:             "/beta",
+		Enabled:          beta,
+		HideWhenDisabled: true,
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:    "get-preview",
+		Method:         http.MethodGet,
+		Path:           "/preview",
+		Enabled:        beta,
+		DisabledStatus: http.StatusNotImplemented,
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	assert.Equal(t, http.StatusNotFound, api.Get("/beta").Code)
+	assert.Equal(t, http.StatusNoContent, api.Get("/beta", "X-Beta: true").Code)
+	assert.Equal(t, http.StatusNotImplemented, api.Get("/preview").Code)
+	assert.Equal(t, http.StatusNoContent, api.Get("/preview", "X-Beta: true").Code)
+
+	// Hidden from clients which cannot use it, but still in the full document.
+	assert.NotNil(t, api.OpenAPI().Paths["/beta"])
+	resp := api.Get("/openapi.json")
+	assert.NotContains(t, resp.Body.String(), `"/beta"`)
+	assert.Contains(t, resp.Body.String(), `"/preview"`)
+	resp = api.Get("/openapi.yaml", "X-Beta: true")
+	assert.Contains(t, resp.Body.String(), "/beta:")
+}
+
+func TestServerTiming(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.ServerTiming = true
+	_, api := humatest.New(t, config)
+
+	huma.Put(api, "/things/{id}", func(ctx context.Context, input *struct {
+		ID   string `path:"id" maxLength:"5"`
+		Body struct {
+			Name string `json:"name"`
+		}
+	}) (*struct {
+		Body struct {
+			Name string `json:"name"`
+		}
+	}, error) {
+		out := &struct {
+			Body struct {
+				Name string `json:"name"`
+			}
+		}{}
+		out.Body.Name = input.Body.Name
+		return out, nil
+	})
+
+	huma.Get(api, "/stream", func(ctx context.Context, input *struct{}) (*huma.StreamResponse, error) {
+		return &huma.StreamResponse{Body: func(ctx huma.Context) {
+			ctx.SetStatus(http.StatusOK)
+			ctx.BodyWriter().Write([]byte("streamed"))
+		}}, nil
+	})
+
+	phases := func(header string) []string {
+		var names []string
+		for _, metric := range strings.Split(header, ", ") {
+			name, dur, ok := strings.Cut(metric, ";dur=")
+			require.True(t, ok, metric)
+			_, err := strconv.ParseFloat(dur, 64)
+			require.NoError(t, err)
+			names = append(names, name)
+		}
+		return names
+	}
+
+	resp := api.Put("/things/abc", map[string]any{"name": "foo"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"name":"foo"`)
+	assert.Equal(t, []string{"parse", "validate", "handler", "marshal"}, phases(resp.Header().Get("Server-Timing")))
+
+	// The handler is not called when validation fails.
+	resp = api.Put("/things/toolong", map[string]any{"name": "foo"})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Equal(t, []string{"parse", "validate", "marshal"}, phases(resp.Header().Get("Server-Timing")))
+
+	// Streaming responses are not buffered.
+	resp = api.Get("/stream")
+	assert.Equal(t, "streamed", resp.Body.String())
+	assert.Equal(t, []string{"parse", "validate", "handler"}, phases(resp.Header().Get("Server-Timing")))
+}
+
+func TestAccessLog(t *testing.T) {
+	for _, format := range []huma.AccessLogFormat{huma.AccessLogCommon, huma.AccessLogJSON} {
+		buf := &bytes.Buffer{}
+		_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+		api.UseMiddleware(huma.AccessLog(buf, format))
+
+		huma.Register(api, huma.Operation{
+			OperationID: "get-thing",
+			Method:      http.MethodGet,
+			Path:        "/things/{id}",
+		}, func(ctx context.Context, input *struct {
+			ID string `path:"id"`
+		}) (*struct{ Body string }, error) {
+			if input.ID == "missing" {
+				return nil, huma.Error404NotFound("not found")
+			}
+			return &struct{ Body string }{Body: "hello"}, nil
+		})
+
+		api.Get("/things/1?verbose=true")
+		api.Get("/things/missing")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+
+		if format == huma.AccessLogJSON {
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+			assert.Equal(t, "GET", entry["method"])
+			assert.Equal(t, "/things/1?verbose=true", entry["uri"])
+			assert.Equal(t, "HTTP/1.1", entry["proto"])
+			assert.InDelta(t, 200, entry["status"], 0)
+			assert.InDelta(t, 8, entry["bytes"], 0)
+			assert.Equal(t, "get-thing", entry["operationId"])
+			assert.Contains(t, entry, "latencyMs")
+			assert.Contains(t, lines[1], `"status":404`)
+		} else {
+			assert.Regexp(t, `^127\.0\.0\.1 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "GET /things/1\?verbose=true HTTP/1\.1" 200 8 get-thing \d+\.\d{3}$`, lines[0])
+			assert.Contains(t, lines[1], `"GET /things/missing HTTP/1.1" 404 `)
+		}
+	}
 }
@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -249,3 +250,124 @@ func TestCLIBadDefaults(t *testing.T) {
 		humacli.New(func(hooks humacli.Hooks, options *OptionsInt) {})
 	})
 }
+
+func TestSnapshotter(t *testing.T) {
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("My API", "1.0.0"))
+
+	register := func(path string) {
+		huma.Register(api, huma.Operation{
+			OperationID: "get" + path,
+			Method:      http.MethodGet,
+			Path:        path,
+		}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+			return nil, nil
+		})
+	}
+
+	register("/one")
+
+	path := t.TempDir() + "/openapi.yaml"
+	snap := humacli.NewSnapshotter(api, path)
+	changes := 0
+	snap.OnSpecChange(func(spec []byte) {
+		changes++
+	})
+
+	// Nothing is written until the first snapshot.
+	register("/two")
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, snap.Snapshot())
+	assert.Equal(t, 1, changes)
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "/two:")
+
+	// Unchanged documents are not rewritten.
+	assert.NoError(t, snap.Snapshot())
+	assert.Equal(t, 1, changes)
+
+	// New operations trigger a snapshot.
+	register("/three")
+	assert.Equal(t, 2, changes)
+	b, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "/three:")
+
+	// Other extensions are written as JSON.
+	jsonPath := t.TempDir() + "/openapi.json"
+	assert.NoError(t, humacli.NewSnapshotter(api, jsonPath).Snapshot())
+	b, err = os.ReadFile(jsonPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"/three": {`)
+}
+
+type TSThing struct {
+	ID      string            `json:"id" readOnly:"true" doc:"Unique ID"`
+	Kind    string            `json:"kind" enum:"small,large"`
+	Count   int               `json:"count,omitempty"`
+	Note    *string           `json:"note,omitempty" nullable:"true"`
+	Tags    []string          `json:"tags,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Parent  *TSThing          `json:"parent,omitempty"`
+	Old     bool              `json:"old,omitempty" deprecated:"true"`
+	Content []byte            `json:"content,omitempty"`
+}
+
+func TestTypeScript(t *testing.T) {
+	api := humago.New(http.NewServeMux(), huma.DefaultConfig("My API", "1.0.0"))
+	huma.Get(api, "/thing", func(ctx context.Context, input *struct{}) (*struct{ Body TSThing }, error) {
+		return nil, nil
+	})
+
+	registry := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	registry.Schema(reflect.TypeOf(TSThing{}), true, "")
+
+	ts := humacli.TypeScript(registry)
+	assert.Equal(t, `// Code generated by huma. DO NOT EDIT.
+
+export interface TSThing {
+  content?: string;
+  count?: number;
+  /**
+   * Unique ID
+   */
+  readonly id: string;
+  kind: "small" | "large";
+  labels?: Record<string, string>;
+  note?: string | null;
+  /**
+   * @deprecated
+   */
+  old?: boolean;
+  parent?: TSThing;
+  tags?: string[] | null;
+}
+`, ts)
+
+	ts = humacli.TypeScript(registry, humacli.TypeScriptOptions{Zod: true})
+	assert.Contains(t, ts, `import { z } from "zod";`)
+	assert.Contains(t, ts, `export const TSThingSchema: z.ZodType<TSThing> = z.object({
+  content: z.string().optional(),`)
+	assert.Contains(t, ts, `  kind: z.enum(["small", "large"]),`)
+	assert.Contains(t, ts, `  count: z.number().int().optional(),`)
+	assert.Contains(t, ts, `  parent: z.lazy(() => TSThingSchema).optional(),`)
+	assert.Contains(t, ts, `  tags: z.array(z.string()).nullable().optional(),`)
+	assert.Contains(t, ts, `  labels: z.record(z.string()).optional(),`)
+
+	cmd := humacli.TypeScriptCommand(func() huma.API { return api })
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{})
+	assert.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "export interface TSThing {")
+
+	path := t.TempDir() + "/types.ts"
+	cmd.SetArgs([]string{"--zod", "-o", path})
+	assert.NoError(t, cmd.Execute())
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "TSThingSchema")
+}
@@ -0,0 +1,127 @@
+package humacli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Snapshotter writes an API's OpenAPI document to disk and keeps it up to date
+// as operations are added, which lets doc tooling and local code generators
+// watch a file instead of polling the HTTP endpoint. It is meant for use
+// during development, e.g. behind a `--watch` option.
+//
+//	type Options struct {
+//		Watch bool `doc:"Write openapi.yaml whenever the API changes"`
+//	}
+//
+//	cli := humacli.New(func(hooks humacli.Hooks, opts *Options) {
+//		api := humachi.New(router, huma.DefaultConfig("My API", "1.0.0"))
+//		addRoutes(api)
+//
+//		if opts.Watch {
+//			snap := humacli.NewSnapshotter(api, "openapi.yaml")
+//			snap.OnSpecChange(func(spec []byte) {
+//				log.Println("OpenAPI updated")
+//			})
+//			if err := snap.Snapshot(); err != nil {
+//				log.Fatal(err)
+//			}
+//		}
+//
+//		// ...
+//	})
+type Snapshotter struct {
+	// Path is the file the OpenAPI is written to. Files ending in `.yaml` or
+	// `.yml` are written as YAML, otherwise JSON is written.
+	Path string
+
+	api      huma.API
+	mu       sync.Mutex
+	watching bool
+	last     []byte
+	onChange []func(spec []byte)
+}
+
+// NewSnapshotter creates a new snapshotter for the API which writes to the
+// given path. Nothing is written until the first call to `Snapshot`, after
+// which every operation added to the API triggers a new snapshot. This avoids
+// writing the file repeatedly while operations are first being registered.
+func NewSnapshotter(api huma.API, path string) *Snapshotter {
+	s := &Snapshotter{Path: path, api: api}
+	oapi := api.OpenAPI()
+	oapi.OnAddOperation = append(oapi.OnAddOperation, func(oapi *huma.OpenAPI, op *huma.Operation) {
+		s.mu.Lock()
+		watching := s.watching
+		s.mu.Unlock()
+		if watching {
+			// There is nowhere to report the error from a hook, but the file is
+			// retried on the next change.
+			_ = s.Snapshot()
+		}
+	})
+	return s
+}
+
+// OnSpecChange registers a callback which is called with the serialized
+// OpenAPI after it has been written to disk. Callbacks are only called when
+// the document has actually changed, and never concurrently.
+func (s *Snapshotter) OnSpecChange(fn func(spec []byte)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = append(s.onChange, fn)
+}
+
+// Snapshot writes the current OpenAPI to disk if it has changed since the
+// last snapshot and enables writing new snapshots as operations are added.
+// The file is replaced atomically so readers never see a partial document.
+func (s *Snapshotter) Snapshot() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watching = true
+
+	var spec []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(s.Path)) {
+	case ".yaml", ".yml":
+		spec, err = s.api.OpenAPI().YAML()
+	default:
+		spec, err = json.MarshalIndent(s.api.OpenAPI(), "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+
+	if s.last != nil && bytes.Equal(spec, s.last) {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), "."+filepath.Base(s.Path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(spec); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.Path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	s.last = spec
+
+	for _, fn := range s.onChange {
+		fn(spec)
+	}
+	return nil
+}
@@ -0,0 +1,344 @@
+package humacli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/spf13/cobra"
+)
+
+// TypeScriptOptions configures the generated TypeScript code.
+type TypeScriptOptions struct {
+	// Zod additionally generates a `zod` schema named like `ThingSchema` for
+	// each type, which can be used to validate data at runtime.
+	Zod bool
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// TypeScript generates TypeScript type definitions for all of the schemas in
+// the registry, e.g. from `api.OpenAPI().Components.Schemas`. Object schemas
+// become interfaces with optional properties for fields which are not
+// required, nullable schemas allow `null`, and enums become unions of their
+// values.
+func TypeScript(registry huma.Registry, options ...TypeScriptOptions) string {
+	var opts TypeScriptOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	schemas := registry.Map()
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString("// Code generated by huma. DO NOT EDIT.\n")
+	if opts.Zod {
+		sb.WriteString("\nimport { z } from \"zod\";\n")
+	}
+	for _, name := range names {
+		s := schemas[name]
+		sb.WriteString("\n")
+		tsComment(&sb, "", s)
+		if s.Type == huma.TypeObject && s.Properties != nil && !s.Nullable {
+			sb.WriteString("export interface " + name + " ")
+			tsObject(&sb, "", s)
+			sb.WriteString("\n")
+		} else {
+			sb.WriteString("export type " + name + " = " + tsType("", s) + ";\n")
+		}
+		if opts.Zod {
+			sb.WriteString("\nexport const " + name + "Schema: z.ZodType<" + name + "> = " + zodType("", s) + ";\n")
+		}
+	}
+	return sb.String()
+}
+
+// tsRefName returns the type name for a schema reference.
+func tsRefName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// tsPropName returns a property name, quoted if it isn't a valid identifier.
+func tsPropName(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	b, _ := json.Marshal(name)
+	return string(b)
+}
+
+// tsComment writes the schema's description as a doc comment.
+func tsComment(sb *strings.Builder, indent string, s *huma.Schema) {
+	if s.Description == "" && !s.Deprecated {
+		return
+	}
+	sb.WriteString(indent + "/**\n")
+	for _, line := range strings.Split(s.Description, "\n") {
+		if line == "" && s.Description == "" {
+			continue
+		}
+		sb.WriteString(strings.TrimRight(indent+" * "+strings.ReplaceAll(line, "*/", "*\\/"), " ") + "\n")
+	}
+	if s.Deprecated {
+		sb.WriteString(indent + " * @deprecated\n")
+	}
+	sb.WriteString(indent + " */\n")
+}
+
+// tsObject writes the body of an object type.
+func tsObject(sb *strings.Builder, indent string, s *huma.Schema) {
+	props := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		props = append(props, name)
+	}
+	sort.Strings(props)
+
+	sb.WriteString("{\n")
+	for _, name := range props {
+		prop := s.Properties[name]
+		tsComment(sb, indent+"  ", prop)
+		sb.WriteString(indent + "  ")
+		if prop.ReadOnly {
+			sb.WriteString("readonly ")
+		}
+		sb.WriteString(tsPropName(name))
+		if !tsRequired(s, name) {
+			sb.WriteString("?")
+		}
+		sb.WriteString(": " + tsType(indent+"  ", prop) + ";\n")
+	}
+	if ap, ok := s.AdditionalProperties.(*huma.Schema); ok {
+		sb.WriteString(indent + "  [key: string]: " + tsType(indent+"  ", ap) + ";\n")
+	}
+	sb.WriteString(indent + "}")
+}
+
+func tsRequired(s *huma.Schema, name string) bool {
+	for _, r := range s.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// tsType returns the TypeScript type expression for a schema.
+func tsType(indent string, s *huma.Schema) string {
+	t := tsBaseType(indent, s)
+	if s.Nullable && t != "null" && t != "unknown" {
+		t += " | null"
+	}
+	return t
+}
+
+func tsBaseType(indent string, s *huma.Schema) string {
+	if s.Ref != "" {
+		return tsRefName(s.Ref)
+	}
+	if len(s.Enum) > 0 {
+		values := make([]string, len(s.Enum))
+		for i, v := range s.Enum {
+			b, _ := json.Marshal(v)
+			values[i] = string(b)
+		}
+		return strings.Join(values, " | ")
+	}
+	for _, group := range []struct {
+		schemas []*huma.Schema
+		sep     string
+	}{{s.OneOf, " | "}, {s.AnyOf, " | "}, {s.AllOf, " & "}} {
+		if len(group.schemas) > 0 {
+			types := make([]string, len(group.schemas))
+			for i, sub := range group.schemas {
+				types[i] = tsGroup(tsType(indent, sub))
+			}
+			return strings.Join(types, group.sep)
+		}
+	}
+
+	switch s.Type {
+	case huma.TypeString:
+		return "string"
+	case huma.TypeInteger, huma.TypeNumber:
+		return "number"
+	case huma.TypeBoolean:
+		return "boolean"
+	case "null":
+		return "null"
+	case huma.TypeArray:
+		if s.Items == nil {
+			return "unknown[]"
+		}
+		return tsGroup(tsType(indent, s.Items)) + "[]"
+	case huma.TypeObject:
+		if len(s.Properties) > 0 {
+			var sb strings.Builder
+			tsObject(&sb, indent, s)
+			return sb.String()
+		}
+		if ap, ok := s.AdditionalProperties.(*huma.Schema); ok {
+			return "Record<string, " + tsType(indent, ap) + ">"
+		}
+		return "Record<string, unknown>"
+	}
+	return "unknown"
+}
+
+// tsGroup wraps unions and intersections in parentheses so they can be
+// combined with other types.
+func tsGroup(t string) string {
+	if strings.Contains(t, " | ") || strings.Contains(t, " & ") {
+		if !strings.HasPrefix(t, "{") {
+			return "(" + t + ")"
+		}
+	}
+	return t
+}
+
+// zodType returns the zod schema expression for a schema.
+func zodType(indent string, s *huma.Schema) string {
+	t := zodBaseType(indent, s)
+	if s.Nullable {
+		t += ".nullable()"
+	}
+	return t
+}
+
+func zodBaseType(indent string, s *huma.Schema) string {
+	if s.Ref != "" {
+		return "z.lazy(() => " + tsRefName(s.Ref) + "Schema)"
+	}
+	if len(s.Enum) > 0 {
+		allStrings := true
+		values := make([]string, len(s.Enum))
+		for i, v := range s.Enum {
+			if _, ok := v.(string); !ok {
+				allStrings = false
+			}
+			b, _ := json.Marshal(v)
+			values[i] = string(b)
+		}
+		if allStrings {
+			return "z.enum([" + strings.Join(values, ", ") + "])"
+		}
+		if len(values) == 1 {
+			return "z.literal(" + values[0] + ")"
+		}
+		for i, v := range values {
+			values[i] = "z.literal(" + v + ")"
+		}
+		return "z.union([" + strings.Join(values, ", ") + "])"
+	}
+	if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		group := s.OneOf
+		if len(group) == 0 {
+			group = s.AnyOf
+		}
+		if len(group) == 1 {
+			return zodType(indent, group[0])
+		}
+		types := make([]string, len(group))
+		for i, sub := range group {
+			types[i] = zodType(indent, sub)
+		}
+		return "z.union([" + strings.Join(types, ", ") + "])"
+	}
+	if len(s.AllOf) > 0 {
+		t := zodType(indent, s.AllOf[0])
+		for _, sub := range s.AllOf[1:] {
+			t = "z.intersection(" + t + ", " + zodType(indent, sub) + ")"
+		}
+		return t
+	}
+
+	switch s.Type {
+	case huma.TypeString:
+		return "z.string()"
+	case huma.TypeInteger:
+		return "z.number().int()"
+	case huma.TypeNumber:
+		return "z.number()"
+	case huma.TypeBoolean:
+		return "z.boolean()"
+	case "null":
+		return "z.null()"
+	case huma.TypeArray:
+		if s.Items == nil {
+			return "z.array(z.unknown())"
+		}
+		return "z.array(" + zodType(indent, s.Items) + ")"
+	case huma.TypeObject:
+		if len(s.Properties) > 0 {
+			props := make([]string, 0, len(s.Properties))
+			for name := range s.Properties {
+				props = append(props, name)
+			}
+			sort.Strings(props)
+
+			var sb strings.Builder
+			sb.WriteString("z.object({\n")
+			for _, name := range props {
+				sb.WriteString(fmt.Sprintf("%s  %s: %s", indent, tsPropName(name), zodType(indent+"  ", s.Properties[name])))
+				if !tsRequired(s, name) {
+					sb.WriteString(".optional()")
+				}
+				sb.WriteString(",\n")
+			}
+			sb.WriteString(indent + "})")
+			if ap, ok := s.AdditionalProperties.(*huma.Schema); ok {
+				sb.WriteString(".catchall(" + zodType(indent, ap) + ")")
+			}
+			return sb.String()
+		}
+		if ap, ok := s.AdditionalProperties.(*huma.Schema); ok {
+			return "z.record(" + zodType(indent, ap) + ")"
+		}
+		return "z.record(z.unknown())"
+	}
+	return "z.unknown()"
+}
+
+// TypeScriptCommand returns a command which writes the TypeScript type
+// definitions for the API's schemas (see `TypeScript`) to stdout or a file.
+// The `api` function is called when the command runs, after the options have
+// been parsed and the API created.
+//
+//	var api huma.API
+//
+//	cli := humacli.New(func(hooks humacli.Hooks, opts *Options) {
+//		api = humachi.New(router, huma.DefaultConfig("My API", "1.0.0"))
+//		addRoutes(api)
+//	})
+//
+//	cli.Root().AddCommand(humacli.TypeScriptCommand(func() huma.API {
+//		return api
+//	}))
+func TypeScriptCommand(api func() huma.API) *cobra.Command {
+	var opts TypeScriptOptions
+	var output string
+	cmd := &cobra.Command{
+		Use:   "typescript",
+		Short: "Print TypeScript type definitions for the API's schemas",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ts := TypeScript(api().OpenAPI().Components.Schemas, opts)
+			if output == "" {
+				_, err := fmt.Fprint(cmd.OutOrStdout(), ts)
+				return err
+			}
+			return os.WriteFile(output, []byte(ts), 0o644)
+		},
+	}
+	cmd.Flags().BoolVar(&opts.Zod, "zod", false, "Also generate zod schemas")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write instead of stdout")
+	return cmd
+}
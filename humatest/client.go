@@ -0,0 +1,151 @@
+package humatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Do performs a typed request against the API. The input, if not nil, is
+// marshaled as the JSON request body (or sent as-is if it is an `io.Reader`)
+// and the response body is unmarshaled into the output type. The response is
+// also validated against the schema documented for the operation & status
+// code, so that handlers returning data which does not match their own
+// OpenAPI are caught. Headers like `Authorization: Bearer abc123` may be
+// passed and take precedence over the defaults. The raw response recorder is
+// returned for checking the status code & headers.
+//
+//	thing, resp := humatest.Do[Thing](t, api, http.MethodPost, "/things", &Thing{Name: "foo"})
+//	assert.Equal(t, http.StatusCreated, resp.Code)
+//	assert.Equal(t, "foo", thing.Name)
+func Do[Out any](t testing.TB, api huma.API, method, path string, in any, headers ...string) (Out, *httptest.ResponseRecorder) {
+	t.Helper()
+
+	args := []any{"Accept: application/json"}
+	if in != nil {
+		if reader, ok := in.(io.Reader); ok {
+			args = append(args, reader)
+		} else {
+			b, err := json.Marshal(in)
+			if err != nil {
+				t.Fatalf("unable to marshal request body: %v", err)
+			}
+			args = append(args, "Content-Type: application/json", bytes.NewReader(b))
+		}
+	}
+	for _, h := range headers {
+		args = append(args, h)
+	}
+
+	var out Out
+	resp := Wrap(t, api).Do(method, path, args...)
+	if resp.Body.Len() == 0 {
+		return out, resp
+	}
+
+	if err := json.Unmarshal(resp.Body.Bytes(), &out); err != nil {
+		t.Errorf("unable to unmarshal response body: %v", err)
+		return out, resp
+	}
+
+	if s := responseSchema(api, method, path, resp); s != nil {
+		var value any
+		if err := json.Unmarshal(resp.Body.Bytes(), &value); err == nil {
+			pb := huma.NewPathBuffer([]byte{}, 0)
+			res := &huma.ValidateResult{}
+			huma.Validate(api.OpenAPI().Components.Schemas, s, pb, huma.ModeReadFromServer, value, res)
+			for _, err := range res.Errors {
+				t.Errorf("response does not match schema: %v", err)
+			}
+		}
+	}
+
+	return out, resp
+}
+
+// responseSchema returns the documented schema for a response, if any.
+func responseSchema(api huma.API, method, path string, resp *httptest.ResponseRecorder) *huma.Schema {
+	op := findOperation(api.OpenAPI(), method, path)
+	if op == nil {
+		return nil
+	}
+
+	r := op.Responses[strconv.Itoa(resp.Code)]
+	if r == nil {
+		r = op.Responses["default"]
+	}
+	if r == nil || r.Content == nil {
+		return nil
+	}
+
+	ct, _, _ := mime.ParseMediaType(resp.Header().Get("Content-Type"))
+	if mt := r.Content[ct]; mt != nil {
+		return mt.Schema
+	}
+	if mt := r.Content["application/json"]; mt != nil {
+		return mt.Schema
+	}
+	return nil
+}
+
+// findOperation finds the operation whose path template matches the given
+// request path, preferring templates with the most literal segments.
+func findOperation(oapi *huma.OpenAPI, method, path string) *huma.Operation {
+	path, _, _ = strings.Cut(path, "?")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	var found *huma.Operation
+	best := -1
+	for template, item := range oapi.Paths {
+		tparts := strings.Split(strings.Trim(template, "/"), "/")
+		if len(tparts) != len(parts) {
+			continue
+		}
+		score := 0
+		for i, tp := range tparts {
+			if strings.HasPrefix(tp, "{") && strings.HasSuffix(tp, "}") && parts[i] != "" {
+				continue
+			}
+			if tp != parts[i] {
+				score = -1
+				break
+			}
+			score++
+		}
+		if score <= best {
+			continue
+		}
+
+		var op *huma.Operation
+		switch strings.ToUpper(method) {
+		case "GET":
+			op = item.Get
+		case "PUT":
+			op = item.Put
+		case "POST":
+			op = item.Post
+		case "DELETE":
+			op = item.Delete
+		case "PATCH":
+			op = item.Patch
+		case "HEAD":
+			op = item.Head
+		case "OPTIONS":
+			op = item.Options
+		case "TRACE":
+			op = item.Trace
+		}
+		if op != nil {
+			found = op
+			best = score
+		}
+	}
+	return found
+}
@@ -0,0 +1,63 @@
+package humatest
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type ClientThing struct {
+	ID   string `json:"id" readOnly:"true"`
+	Name string `json:"name" maxLength:"10"`
+}
+
+func TestDoTyped(t *testing.T) {
+	_, api := New(t)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-thing",
+		Method:        http.MethodPost,
+		Path:          "/things",
+		DefaultStatus: http.StatusCreated,
+	}, func(ctx context.Context, input *struct {
+		Auth string `header:"Authorization"`
+		Body ClientThing
+	}) (*struct{ Body ClientThing }, error) {
+		if input.Auth != "secret" {
+			return nil, huma.Error401Unauthorized("missing auth")
+		}
+		input.Body.ID = "abc"
+		return &struct{ Body ClientThing }{Body: input.Body}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{ Body ClientThing }, error) {
+		// Violates the documented `maxLength` of the name.
+		return &struct{ Body ClientThing }{Body: ClientThing{ID: input.ID, Name: strings.Repeat("a", 20)}}, nil
+	})
+
+	thing, resp := Do[ClientThing](t, api, http.MethodPost, "/things", ClientThing{Name: "foo"}, "Authorization: secret")
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, ClientThing{ID: "abc", Name: "foo"}, thing)
+
+	// Error responses can be decoded too.
+	errModel, resp := Do[huma.ErrorModel](t, api, http.MethodPost, "/things", strings.NewReader(`{"name": "foo"}`))
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Equal(t, "missing auth", errModel.Detail)
+
+	// Responses are validated against the documented schema.
+	rec := &recordingTB{TB: t}
+	thing, resp = Do[ClientThing](rec, api, http.MethodGet, "/things/abc?foo=bar", nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "abc", thing.ID)
+	assert.Equal(t, []string{"response does not match schema: expected length <= 10 (name: aaaaaaaaaaaaaaaaaaaa)"}, rec.errors)
+}
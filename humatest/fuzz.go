@@ -0,0 +1,633 @@
+package humatest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// FuzzOptions configures `Fuzz`.
+type FuzzOptions struct {
+	// Iterations is the number of valid inputs to generate. Defaults to 10.
+	Iterations int
+
+	// Seed for the random generator, making runs reproducible. Defaults to 1.
+	Seed int64
+
+	// Headers to send with every request, e.g. `Authorization: Bearer abc123`.
+	Headers []string
+
+	// Params sets fixed values for parameters by name instead of generating
+	// them, e.g. to use the ID of a resource which exists.
+	Params map[string]string
+
+	// AllowedStatuses are additional status codes which are acceptable for
+	// valid inputs, e.g. `404` when generated IDs do not exist.
+	AllowedStatuses []int
+}
+
+// fuzzRequest is a generated request for an operation.
+type fuzzRequest struct {
+	params map[string]any
+	body   any
+	raw    []byte
+}
+
+// fuzzCase is a generated invalid request along with a description of how it
+// is invalid.
+type fuzzCase struct {
+	description string
+	request     fuzzRequest
+}
+
+// Fuzz generates valid and boundary-invalid inputs for an operation from its
+// parameter and body schemas and sends them to the API. Valid inputs must
+// result in a `2xx` response (or one of `opts.AllowedStatuses`), while invalid
+// inputs like wrong types, out-of-range values, and missing required fields
+// must result in a `4xx` response. A `5xx` response is always a failure. This
+// helps to catch validation gaps which would otherwise reach your handlers.
+//
+//	func TestCreateThingFuzz(t *testing.T) {
+//		_, api := humatest.New(t)
+//		RegisterRoutes(api)
+//		humatest.Fuzz(t, api, "create-thing", humatest.FuzzOptions{})
+//	}
+func Fuzz(t testing.TB, api huma.API, operationID string, opts FuzzOptions) {
+	t.Helper()
+
+	var op *huma.Operation
+	for _, item := range api.OpenAPI().Paths {
+		for _, candidate := range []*huma.Operation{item.Get, item.Put, item.Post, item.Delete, item.Patch, item.Head, item.Options, item.Trace} {
+			if candidate != nil && candidate.OperationID == operationID {
+				op = candidate
+			}
+		}
+	}
+	if op == nil {
+		t.Fatalf("operation %q not found", operationID)
+		return
+	}
+
+	if opts.Iterations == 0 {
+		opts.Iterations = 10
+	}
+	if opts.Seed == 0 {
+		opts.Seed = 1
+	}
+
+	f := &fuzzer{
+		registry: api.OpenAPI().Components.Schemas,
+		rand:     rand.New(rand.NewSource(opts.Seed)),
+		opts:     opts,
+	}
+	var bodySchema *huma.Schema
+	if op.RequestBody != nil && op.RequestBody.Content["application/json"] != nil {
+		bodySchema = op.RequestBody.Content["application/json"].Schema
+	}
+
+	tapi := Wrap(t, api)
+	send := func(req fuzzRequest) int {
+		t.Helper()
+		target, args := f.build(op, bodySchema, req)
+		return tapi.Do(op.Method, target, args...).Code
+	}
+
+	valid := func() (fuzzRequest, bool) {
+		req := fuzzRequest{params: map[string]any{}}
+		for _, p := range op.Parameters {
+			if _, ok := opts.Params[p.Name]; ok {
+				continue
+			}
+			if !p.Required && f.rand.Intn(2) == 0 {
+				continue
+			}
+			v, ok := f.generate(p.Schema, 0)
+			if !ok {
+				return req, false
+			}
+			req.params[p.Name] = v
+		}
+		if bodySchema != nil {
+			v, ok := f.generate(bodySchema, 0)
+			if !ok {
+				return req, false
+			}
+			req.body = v
+		}
+		return req, true
+	}
+
+	base, ok := valid()
+	if !ok {
+		t.Logf("unable to generate valid input for %s, skipping fuzzing", operationID)
+		return
+	}
+
+	for i := 0; i < opts.Iterations; i++ {
+		req := base
+		if i > 0 {
+			req, _ = valid()
+		}
+		status := send(req)
+		if (status < 200 || status >= 300) && !slices.Contains(opts.AllowedStatuses, status) {
+			t.Errorf("%s: expected success for valid input but got %d", operationID, status)
+		}
+	}
+
+	// Make sure every parameter is present in the base request so that it can
+	// be mutated.
+	for _, p := range op.Parameters {
+		if _, ok := base.params[p.Name]; !ok {
+			if _, fixed := opts.Params[p.Name]; !fixed {
+				base.params[p.Name], _ = f.generate(p.Schema, 0)
+			}
+		}
+	}
+
+	for _, c := range f.invalidCases(op, bodySchema, base) {
+		status := send(c.request)
+		if status < 400 || status >= 500 {
+			t.Errorf("%s: expected client error for %s but got %d", operationID, c.description, status)
+		}
+	}
+}
+
+type fuzzer struct {
+	registry huma.Registry
+	rand     *rand.Rand
+	opts     FuzzOptions
+}
+
+func (f *fuzzer) deref(s *huma.Schema) *huma.Schema {
+	for s != nil && s.Ref != "" {
+		s = f.registry.SchemaFromRef(s.Ref)
+	}
+	return s
+}
+
+// build returns the request path and arguments for `TestAPI.Do`.
+func (f *fuzzer) build(op *huma.Operation, bodySchema *huma.Schema, req fuzzRequest) (string, []any) {
+	path := op.Path
+	query := url.Values{}
+	args := []any{}
+	for _, h := range f.opts.Headers {
+		args = append(args, h)
+	}
+	cookies := []string{}
+
+	for _, p := range op.Parameters {
+		var value string
+		if fixed, ok := f.opts.Params[p.Name]; ok {
+			value = fixed
+		} else if v, ok := req.params[p.Name]; ok {
+			value = paramString(v)
+		} else {
+			continue
+		}
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(value))
+		case "query":
+			query.Set(p.Name, value)
+		case "header":
+			args = append(args, p.Name+": "+value)
+		case "cookie":
+			cookies = append(cookies, p.Name+"="+value)
+		}
+	}
+	if len(cookies) > 0 {
+		args = append(args, "Cookie: "+strings.Join(cookies, "; "))
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	if req.raw != nil {
+		args = append(args, "Content-Type: application/json", bytes.NewReader(req.raw))
+	} else if bodySchema != nil && req.body != nil {
+		b, _ := json.Marshal(req.body)
+		args = append(args, "Content-Type: application/json", bytes.NewReader(b))
+	}
+	return path, args
+}
+
+// paramString converts a generated value into its parameter representation.
+func paramString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = paramString(item)
+		}
+		return strings.Join(parts, ",")
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// generate returns a random value which is valid for the schema, or false if
+// one cannot be generated, e.g. because of an unsupported pattern.
+func (f *fuzzer) generate(s *huma.Schema, depth int) (any, bool) {
+	s = f.deref(s)
+	if s == nil {
+		return nil, false
+	}
+	if len(s.Enum) > 0 {
+		return s.Enum[f.rand.Intn(len(s.Enum))], true
+	}
+	if len(s.Examples) > 0 && (s.Pattern != "" || f.rand.Intn(4) == 0) {
+		return s.Examples[0], true
+	}
+	if s.Default != nil && (s.Pattern != "" || f.rand.Intn(4) == 0) {
+		return s.Default, true
+	}
+	if len(s.OneOf) > 0 {
+		return f.generate(s.OneOf[f.rand.Intn(len(s.OneOf))], depth)
+	}
+	if len(s.AnyOf) > 0 {
+		return f.generate(s.AnyOf[f.rand.Intn(len(s.AnyOf))], depth)
+	}
+	if len(s.AllOf) > 0 || s.Pattern != "" {
+		return nil, false
+	}
+
+	switch s.Type {
+	case huma.TypeBoolean:
+		return f.rand.Intn(2) == 0, true
+	case huma.TypeInteger, huma.TypeNumber:
+		lo, hi := -1000.0, 1000.0
+		if s.Minimum != nil {
+			lo = *s.Minimum
+		}
+		if s.ExclusiveMinimum != nil {
+			lo = *s.ExclusiveMinimum + 1
+		}
+		if s.Maximum != nil {
+			hi = *s.Maximum
+		}
+		if s.ExclusiveMaximum != nil {
+			hi = *s.ExclusiveMaximum - 1
+		}
+		if hi < lo {
+			hi = lo
+		}
+		if s.Minimum != nil && s.Maximum == nil && s.ExclusiveMaximum == nil {
+			hi = lo + 1000
+		} else if s.Maximum != nil && s.Minimum == nil && s.ExclusiveMinimum == nil {
+			lo = hi - 1000
+		}
+		v := lo + f.rand.Float64()*(hi-lo)
+		if s.Type == huma.TypeInteger {
+			v = math.Max(math.Ceil(lo), math.Min(math.Floor(hi), math.Round(v)))
+		}
+		if s.MultipleOf != nil && *s.MultipleOf != 0 {
+			v = math.Ceil(lo / *s.MultipleOf) * *s.MultipleOf
+		}
+		if s.Type == huma.TypeInteger {
+			return int64(v), true
+		}
+		return v, true
+	case huma.TypeString:
+		switch s.Format {
+		case "date-time", "date-time-http":
+			return "2024-01-02T15:04:05Z", true
+		case "date":
+			return "2024-01-02", true
+		case "time":
+			return "15:04:05Z", true
+		case "email", "idn-email":
+			return "user@example.com", true
+		case "hostname", "idn-hostname":
+			return "example.com", true
+		case "ipv4":
+			return "192.0.2.1", true
+		case "ipv6":
+			return "2001:db8::1", true
+		case "uri", "iri", "uri-reference", "iri-reference", "uri-template":
+			return "https://example.com/path", true
+		case "uuid":
+			return "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d", true
+		case "":
+		default:
+			return nil, false
+		}
+		if s.ContentMediaType != "" {
+			// Embedded documents are only generated from examples.
+			return nil, false
+		}
+		if s.ContentEncoding == "base64" {
+			return base64.StdEncoding.EncodeToString([]byte("fuzz")), true
+		}
+		minLen, maxLen := 0, 12
+		if s.MinLength != nil {
+			minLen = *s.MinLength
+			maxLen = minLen + 12
+		}
+		if s.MaxLength != nil && *s.MaxLength < maxLen {
+			maxLen = *s.MaxLength
+		}
+		n := minLen
+		if maxLen > minLen {
+			n += f.rand.Intn(maxLen - minLen + 1)
+		}
+		const letters = "abcdefghijklmnopqrstuvwxyz0123456789"
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = letters[f.rand.Intn(len(letters))]
+		}
+		return string(b), true
+	case huma.TypeArray:
+		minItems, maxItems := 0, 3
+		if s.MinItems != nil {
+			minItems = *s.MinItems
+			maxItems = minItems + 3
+		}
+		if s.MaxItems != nil && *s.MaxItems < maxItems {
+			maxItems = *s.MaxItems
+		}
+		if depth > 3 {
+			maxItems = minItems
+		}
+		n := minItems
+		if maxItems > minItems {
+			n += f.rand.Intn(maxItems - minItems + 1)
+		}
+		items := []any{}
+		for attempts := 0; len(items) < n && attempts < n*10; attempts++ {
+			v, ok := f.generate(s.Items, depth+1)
+			if !ok {
+				return nil, false
+			}
+			if s.UniqueItems && containsJSON(items, v) {
+				continue
+			}
+			items = append(items, v)
+		}
+		if len(items) < minItems {
+			return nil, false
+		}
+		return items, true
+	case huma.TypeObject:
+		obj := map[string]any{}
+		for _, name := range sortedKeys(s.Properties) {
+			prop := s.Properties[name]
+			if prop.ReadOnly || (!slices.Contains(s.Required, name) && (depth > 3 || f.rand.Intn(2) == 0)) {
+				continue
+			}
+			v, ok := f.generate(prop, depth+1)
+			if !ok {
+				if slices.Contains(s.Required, name) {
+					return nil, false
+				}
+				continue
+			}
+			obj[name] = v
+		}
+		return obj, true
+	case "":
+		return "value", true
+	}
+	return nil, false
+}
+
+func containsJSON(items []any, v any) bool {
+	b, _ := json.Marshal(v)
+	for _, item := range items {
+		ib, _ := json.Marshal(item)
+		if bytes.Equal(b, ib) {
+			return true
+		}
+	}
+	return false
+}
+
+// invalidValues returns values which are invalid for the schema, keyed by a
+// description of why.
+func (f *fuzzer) invalidValues(s *huma.Schema, param bool) map[string]any {
+	s = f.deref(s)
+	out := map[string]any{}
+	if s == nil {
+		return out
+	}
+
+	switch s.Type {
+	case huma.TypeInteger, huma.TypeNumber:
+		out["wrong type"] = "not-a-number"
+		if s.Minimum != nil {
+			out["below minimum"] = *s.Minimum - 1
+		}
+		if s.ExclusiveMinimum != nil {
+			out["below exclusive minimum"] = *s.ExclusiveMinimum
+		}
+		if s.Maximum != nil {
+			out["above maximum"] = *s.Maximum + 1
+		}
+		if s.ExclusiveMaximum != nil {
+			out["above exclusive maximum"] = *s.ExclusiveMaximum
+		}
+		if s.Type == huma.TypeInteger {
+			out["fractional integer"] = 1.5
+		}
+	case huma.TypeBoolean:
+		out["wrong type"] = "not-a-boolean"
+	case huma.TypeString:
+		if !param {
+			out["wrong type"] = 12345
+		}
+		if s.MinLength != nil && *s.MinLength > 0 {
+			out["too short"] = strings.Repeat("a", *s.MinLength-1)
+		}
+		if s.MaxLength != nil {
+			out["too long"] = strings.Repeat("a", *s.MaxLength+1)
+		}
+		switch s.Format {
+		case "date-time", "date", "time", "email", "ipv4", "ipv6", "uuid", "uri":
+			out["invalid "+s.Format] = "not valid!"
+		}
+	case huma.TypeArray:
+		if !param {
+			out["wrong type"] = "not-an-array"
+		}
+		if s.MinItems != nil && *s.MinItems > 0 {
+			items := make([]any, 0, *s.MinItems-1)
+			for i := 0; i < *s.MinItems-1; i++ {
+				if v, ok := f.generate(s.Items, 1); ok {
+					items = append(items, v)
+				}
+			}
+			if len(items) == *s.MinItems-1 {
+				out["too few items"] = items
+			}
+		}
+		if s.MaxItems != nil && !s.UniqueItems {
+			if v, ok := f.generate(s.Items, 1); ok {
+				items := make([]any, *s.MaxItems+1)
+				for i := range items {
+					items[i] = v
+				}
+				out["too many items"] = items
+			}
+		}
+		if s.UniqueItems {
+			if v, ok := f.generate(s.Items, 1); ok {
+				out["duplicate items"] = []any{v, v}
+			}
+		}
+	case huma.TypeObject:
+		if !param {
+			out["wrong type"] = "not-an-object"
+		}
+	}
+	if len(s.Enum) > 0 && s.Type == huma.TypeString {
+		out["not in enum"] = "__invalid_enum_value__"
+	}
+	return out
+}
+
+// invalidCases generates requests which are each invalid in exactly one way.
+func (f *fuzzer) invalidCases(op *huma.Operation, bodySchema *huma.Schema, base fuzzRequest) []fuzzCase {
+	cases := []fuzzCase{}
+	withParam := func(name string, value any, remove bool) fuzzRequest {
+		req := fuzzRequest{params: map[string]any{}, body: base.body}
+		for k, v := range base.params {
+			req.params[k] = v
+		}
+		if remove {
+			delete(req.params, name)
+		} else {
+			req.params[name] = value
+		}
+		return req
+	}
+
+	for _, p := range op.Parameters {
+		if _, fixed := f.opts.Params[p.Name]; fixed {
+			continue
+		}
+		if p.Required && p.In != "path" {
+			cases = append(cases, fuzzCase{
+				description: fmt.Sprintf("missing required %s param %s", p.In, p.Name),
+				request:     withParam(p.Name, nil, true),
+			})
+		}
+		invalid := f.invalidValues(p.Schema, true)
+		for _, desc := range sortedKeys(invalid) {
+			value := invalid[desc]
+			if p.In == "path" {
+				if str, ok := value.(string); ok && str == "" {
+					// Empty path segments would not match the route at all.
+					continue
+				}
+			}
+			cases = append(cases, fuzzCase{
+				description: fmt.Sprintf("%s param %s: %s", p.In, p.Name, desc),
+				request:     withParam(p.Name, value, false),
+			})
+		}
+	}
+
+	if bodySchema == nil {
+		return cases
+	}
+
+	cases = append(cases, fuzzCase{
+		description: "malformed JSON body",
+		request:     fuzzRequest{params: base.params, raw: []byte(`{"malformed`)},
+	})
+	if op.RequestBody.Required {
+		cases = append(cases, fuzzCase{
+			description: "missing required body",
+			request:     fuzzRequest{params: base.params},
+		})
+	}
+
+	var walk func(s *huma.Schema, path []string, depth int)
+	walk = func(s *huma.Schema, path []string, depth int) {
+		s = f.deref(s)
+		if s == nil || depth > 3 {
+			return
+		}
+		location := "body"
+		if len(path) > 0 {
+			location += "." + strings.Join(path, ".")
+		}
+		invalid := f.invalidValues(s, false)
+		for _, desc := range sortedKeys(invalid) {
+			value := invalid[desc]
+			body := setPath(base.body, path, value, false)
+			cases = append(cases, fuzzCase{
+				description: fmt.Sprintf("%s: %s", location, desc),
+				request:     fuzzRequest{params: base.params, body: body},
+			})
+		}
+		if s.Type != huma.TypeObject {
+			return
+		}
+		for _, name := range s.Required {
+			prop := s.Properties[name]
+			if prop == nil || prop.ReadOnly {
+				continue
+			}
+			body := setPath(base.body, append(slices.Clone(path), name), nil, true)
+			cases = append(cases, fuzzCase{
+				description: fmt.Sprintf("%s: missing required property %s", location, name),
+				request:     fuzzRequest{params: base.params, body: body},
+			})
+		}
+		for _, name := range sortedKeys(s.Properties) {
+			prop := s.Properties[name]
+			if prop.ReadOnly {
+				continue
+			}
+			walk(prop, append(slices.Clone(path), name), depth+1)
+		}
+	}
+	walk(bodySchema, nil, 0)
+	return cases
+}
+
+// setPath returns a deep copy of the value with the value at the given path of
+// object keys set or removed. Missing intermediate objects are created.
+func setPath(v any, path []string, value any, remove bool) any {
+	if len(path) == 0 {
+		return value
+	}
+	obj := map[string]any{}
+	if m, ok := v.(map[string]any); ok {
+		for k, item := range m {
+			obj[k] = item
+		}
+	}
+	if len(path) == 1 {
+		if remove {
+			delete(obj, path[0])
+		} else {
+			obj[path[0]] = value
+		}
+		return obj
+	}
+	obj[path[0]] = setPath(obj[path[0]], path[1:], value, remove)
+	return obj
+}
+
+// sortedKeys returns the keys of a map in sorted order so that generation is
+// deterministic for a given seed.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,93 @@
+package humatest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingTB records failures instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+type FuzzThing struct {
+	Name  string   `json:"name" minLength:"2" maxLength:"10"`
+	Count int      `json:"count" minimum:"0" maximum:"100"`
+	Kind  string   `json:"kind,omitempty" enum:"a,b"`
+	Tags  []string `json:"tags,omitempty" maxItems:"3" uniqueItems:"true"`
+	Email string   `json:"email,omitempty" format:"email"`
+}
+
+func TestFuzz(t *testing.T) {
+	_, api := New(t)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-thing",
+		Method:      http.MethodPut,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID    int    `path:"id" minimum:"1"`
+		Limit int    `query:"limit" maximum:"50"`
+		Trace string `header:"X-Trace" required:"true"`
+		Body  FuzzThing
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	rec := &recordingTB{TB: t}
+	Fuzz(rec, api, "put-thing", FuzzOptions{Iterations: 20})
+	assert.Empty(t, rec.errors)
+}
+
+func TestFuzzFindsGaps(t *testing.T) {
+	_, api := New(t)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things",
+	}, func(ctx context.Context, input *struct {
+		// Not validated, so the handler must deal with any value.
+		Count int `query:"count"`
+	}) (*struct{}, error) {
+		if input.Count < 0 {
+			return nil, huma.Error500InternalServerError("negative count")
+		}
+		return nil, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "create-thing",
+		Method:      http.MethodPost,
+		Path:        "/things",
+	}, func(ctx context.Context, input *struct {
+		Body struct {
+			Name string `json:"name" minLength:"1"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	rec := &recordingTB{TB: t}
+	Fuzz(rec, api, "get-thing", FuzzOptions{Params: map[string]string{"count": "-1"}, Iterations: 1})
+	assert.Equal(t, []string{"get-thing: expected success for valid input but got 500"}, rec.errors)
+
+	// The allowed statuses can be customized.
+	rec = &recordingTB{TB: t}
+	Fuzz(rec, api, "get-thing", FuzzOptions{Params: map[string]string{"count": "-1"}, Iterations: 1, AllowedStatuses: []int{500}})
+	assert.Empty(t, rec.errors)
+
+	rec = &recordingTB{TB: t}
+	Fuzz(rec, api, "create-thing", FuzzOptions{})
+	assert.Empty(t, rec.errors)
+}
@@ -33,6 +33,20 @@ func NewContext(op *huma.Operation, r *http.Request, w http.ResponseWriter) huma
 	return humaflow.NewContext(op, r, w)
 }
 
+// informationalRecorder ignores informational responses like `103 Early
+// Hints`, which the response recorder would otherwise treat as the final
+// response status.
+type informationalRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (r informationalRecorder) WriteHeader(code int) {
+	if code >= 100 && code < 200 && code != http.StatusSwitchingProtocols {
+		return
+	}
+	r.ResponseRecorder.WriteHeader(code)
+}
+
 // NewAdapter creates a new test adapter from a router.
 func NewAdapter() huma.Adapter {
 	return humaflow.NewAdapter(flow.New())
@@ -194,6 +208,11 @@ type testAPI struct {
 	tb TB
 }
 
+// Unwrap returns the wrapped API.
+func (a *testAPI) Unwrap() huma.API {
+	return a.API
+}
+
 func (a *testAPI) Do(method, path string, args ...any) *httptest.ResponseRecorder {
 	return a.DoCtx(context.Background(), method, path, args...)
 }
@@ -242,7 +261,7 @@ func (a *testAPI) DoCtx(ctx context.Context, method, path string, args ...any) *
 	bytes, _ := DumpRequest(req)
 	a.tb.Log("Making request:\n" + strings.TrimSpace(string(bytes)))
 
-	a.Adapter().ServeHTTP(resp, req)
+	a.Adapter().ServeHTTP(informationalRecorder{resp}, req)
 
 	bytes, _ = DumpResponse(resp.Result())
 	a.tb.Log("Got response:\n" + strings.TrimSpace(string(bytes)))
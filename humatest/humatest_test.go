@@ -128,6 +128,23 @@ func TestContext(t *testing.T) {
 	assert.Equal(t, "baz", w.Header().Get("Baz"))
 }
 
+func TestEarlyHints(t *testing.T) {
+	_, api := New(t)
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		// Wrapped contexts are unwrapped to find the adapter's context.
+		huma.EarlyHints(huma.WithValue(ctx, "key", "value"), []string{"</style.css>; rel=preload; as=style"})
+		next(ctx)
+	})
+	huma.Get(api, "/hints", func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: "done"}, nil
+	})
+
+	// The informational response is not recorded as the final status.
+	resp := api.Get("/hints")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "</style.css>; rel=preload; as=style", resp.Header().Get("Link"))
+}
+
 func TestAdapter(t *testing.T) {
 	var _ huma.Adapter = NewAdapter()
 }
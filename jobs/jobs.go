@@ -0,0 +1,298 @@
+// Package jobs implements the long-running operation pattern. Instead of
+// making clients wait for slow work to finish, an operation starts a job and
+// returns `202 Accepted` with a `Location` to poll for the job's status,
+// which includes the result once it has succeeded.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// ErrNotFound is returned by a store when a job does not exist.
+var ErrNotFound = errors.New("job not found")
+
+// Status is the state of a job.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// Info describes a job as it is saved in a store.
+type Info struct {
+	// ID uniquely identifies the job and is set by the store.
+	ID string
+
+	// Status is the current state of the job.
+	Status Status
+
+	// Result is the value returned by a succeeded job. Stores which serialize
+	// jobs may return it as any value which can be marshaled to JSON and back
+	// into the result type, e.g. `json.RawMessage`.
+	Result any
+
+	// Error is the message of the error returned by a failed job.
+	Error string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Store is a storage backend for jobs.
+type Store interface {
+	// Create stores a new job and returns its ID.
+	Create(ctx context.Context, info Info) (string, error)
+
+	// Get returns the job or `ErrNotFound`.
+	Get(ctx context.Context, id string) (Info, error)
+
+	// Update replaces the stored job with the same ID.
+	Update(ctx context.Context, info Info) error
+}
+
+// Job is the status of a job returned to clients, with a typed result.
+type Job[T any] struct {
+	ID        string    `json:"id" doc:"Job ID"`
+	Status    Status    `json:"status" enum:"pending,succeeded,failed" doc:"Current state of the job"`
+	Result    *T        `json:"result,omitempty" doc:"Result of the job once it has succeeded"`
+	Error     string    `json:"error,omitempty" doc:"Why the job failed"`
+	CreatedAt time.Time `json:"createdAt" doc:"When the job was started"`
+	UpdatedAt time.Time `json:"updatedAt" doc:"When the job's status last changed"`
+}
+
+// Accepted is the response to an operation which has started a job. Set
+// `DefaultStatus: http.StatusAccepted` on the operation returning it.
+type Accepted[T any] struct {
+	Location string `header:"Location" doc:"URL to poll for the status of the job"`
+	Body     *Job[T]
+}
+
+// StatusInput identifies a job.
+type StatusInput struct {
+	ID string `path:"id" doc:"Job ID"`
+}
+
+// StatusOutput is the current status of a job.
+type StatusOutput[T any] struct {
+	CacheControl string `header:"Cache-Control"`
+	RetryAfter   string `header:"Retry-After" doc:"Seconds to wait before polling a pending job again"`
+	Body         *Job[T]
+}
+
+// Config configures the job status operation.
+type Config struct {
+	// Path is the path of the collection of jobs, like `/jobs`. The status of
+	// each job is available at a sub-path like `/jobs/{id}`.
+	Path string
+
+	// Store saves the jobs and is required.
+	Store Store
+
+	// OperationID of the status operation. Defaults to `get-job`, and must be
+	// set when registering multiple job types.
+	OperationID string
+
+	// RetryAfter is sent to clients polling a pending job to tell them how
+	// long to wait before trying again, or zero to not send it.
+	RetryAfter time.Duration
+
+	// Tags are added to the status operation.
+	Tags []string
+}
+
+// Jobs starts jobs with results of type `T` and reports their status.
+type Jobs[T any] struct {
+	path  string
+	store Store
+}
+
+// Register registers the operation to poll the status of jobs with results
+// of type `T` under the configured path, and returns a value used by other
+// operations to start new jobs.
+//
+//	reports := jobs.Register[Report](api, jobs.Config{
+//		Path:  "/report-jobs",
+//		Store: jobs.NewMemoryStore(),
+//	})
+//
+//	huma.Register(api, huma.Operation{
+//		Method:        http.MethodPost,
+//		Path:          "/reports",
+//		DefaultStatus: http.StatusAccepted,
+//	}, func(ctx context.Context, input *ReportInput) (*jobs.Accepted[Report], error) {
+//		return reports.Start(ctx, func(ctx context.Context) (Report, error) {
+//			return generateReport(ctx, input.Body)
+//		})
+//	})
+func Register[T any](api huma.API, config Config) *Jobs[T] {
+	j := &Jobs[T]{
+		path:  strings.TrimSuffix(config.Path, "/"),
+		store: config.Store,
+	}
+
+	operationID := config.OperationID
+	if operationID == "" {
+		operationID = "get-job"
+	}
+
+	huma.Register(api, huma.Operation{
+		OperationID: operationID,
+		Method:      http.MethodGet,
+		Path:        j.path + "/{id}",
+		Summary:     "Get job status",
+		Description: "Get the status of a job, including its result once it has succeeded.",
+		Tags:        config.Tags,
+		Errors:      []int{http.StatusNotFound},
+	}, func(ctx context.Context, input *StatusInput) (*StatusOutput[T], error) {
+		info, err := j.store.Get(ctx, input.ID)
+		if errors.Is(err, ErrNotFound) {
+			return nil, huma.Error404NotFound("job not found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		job, err := toJob[T](info)
+		if err != nil {
+			return nil, err
+		}
+		out := &StatusOutput[T]{CacheControl: "no-store", Body: job}
+		if info.Status == StatusPending && config.RetryAfter > 0 {
+			out.RetryAfter = strconv.Itoa(int(config.RetryAfter.Round(time.Second) / time.Second))
+		}
+		return out, nil
+	})
+
+	return j
+}
+
+// Start saves a new pending job and runs `fn` in the background, saving its
+// result or error once it returns. The job's context is not canceled when
+// the request finishes, so use it to stop the work during shutdown.
+func (j *Jobs[T]) Start(ctx context.Context, fn func(ctx context.Context) (T, error)) (*Accepted[T], error) {
+	now := time.Now().UTC()
+	info := Info{Status: StatusPending, CreatedAt: now, UpdatedAt: now}
+	id, err := j.store.Create(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	info.ID = id
+
+	go j.run(context.WithoutCancel(ctx), info, fn)
+
+	job, _ := toJob[T](info)
+	return &Accepted[T]{Location: j.path + "/" + id, Body: job}, nil
+}
+
+// run calls the job function and saves its outcome.
+func (j *Jobs[T]) run(ctx context.Context, info Info, fn func(ctx context.Context) (T, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			info.Status = StatusFailed
+			info.Error = fmt.Sprintf("job panicked: %v", r)
+			info.UpdatedAt = time.Now().UTC()
+			_ = j.store.Update(ctx, info)
+		}
+	}()
+
+	result, err := fn(ctx)
+	if err != nil {
+		info.Status = StatusFailed
+		info.Error = err.Error()
+	} else {
+		info.Status = StatusSucceeded
+		info.Result = result
+	}
+	info.UpdatedAt = time.Now().UTC()
+	_ = j.store.Update(ctx, info)
+}
+
+// toJob converts stored job info into a typed job status.
+func toJob[T any](info Info) (*Job[T], error) {
+	job := &Job[T]{
+		ID:        info.ID,
+		Status:    info.Status,
+		Error:     info.Error,
+		CreatedAt: info.CreatedAt,
+		UpdatedAt: info.UpdatedAt,
+	}
+	switch result := info.Result.(type) {
+	case nil:
+	case T:
+		job.Result = &result
+	case *T:
+		job.Result = result
+	default:
+		// The store has serialized the result, so convert it back.
+		b, err := json.Marshal(result)
+		if err != nil {
+			return nil, err
+		}
+		job.Result = new(T)
+		if err := json.Unmarshal(b, job.Result); err != nil {
+			return nil, err
+		}
+	}
+	return job, nil
+}
+
+// MemoryStore is an in-memory job store, useful for testing and
+// development. Jobs are lost when the process exits.
+type MemoryStore struct {
+	mu   sync.Mutex
+	jobs map[string]Info
+}
+
+// NewMemoryStore creates a new empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{jobs: map[string]Info{}}
+}
+
+// Create implements `Store`.
+func (s *MemoryStore) Create(ctx context.Context, info Info) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	info.ID = hex.EncodeToString(b)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[info.ID] = info
+	return info.ID, nil
+}
+
+// Get implements `Store`.
+func (s *MemoryStore) Get(ctx context.Context, id string) (Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, ok := s.jobs[id]
+	if !ok {
+		return Info{}, ErrNotFound
+	}
+	return info, nil
+}
+
+// Update implements `Store`.
+func (s *MemoryStore) Update(ctx context.Context, info Info) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[info.ID]; !ok {
+		return ErrNotFound
+	}
+	s.jobs[info.ID] = info
+	return nil
+}
@@ -0,0 +1,133 @@
+package jobs_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/danielgtaylor/huma/v2/jobs"
+)
+
+type Report struct {
+	Count int `json:"count"`
+}
+
+func TestJobs(t *testing.T) {
+	_, api := humatest.New(t)
+
+	reports := jobs.Register[Report](api, jobs.Config{
+		Path:       "/jobs",
+		Store:      jobs.NewMemoryStore(),
+		RetryAfter: 2 * time.Second,
+	})
+
+	release := make(chan struct{})
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-report",
+		Method:        http.MethodPost,
+		Path:          "/reports",
+		DefaultStatus: http.StatusAccepted,
+	}, func(ctx context.Context, input *struct {
+		Fail bool `query:"fail"`
+	}) (*jobs.Accepted[Report], error) {
+		return reports.Start(ctx, func(ctx context.Context) (Report, error) {
+			<-release
+			if input.Fail {
+				return Report{}, errors.New("out of paper")
+			}
+			return Report{Count: 5}, nil
+		})
+	})
+
+	// The typed job status is documented.
+	get := api.OpenAPI().Paths["/jobs/{id}"].Get
+	require.NotNil(t, get)
+	assert.Contains(t, get.Responses["200"].Headers, "Retry-After")
+	assert.Contains(t, get.Responses, "404")
+	assert.NotNil(t, api.OpenAPI().Components.Schemas.Map()["JobReport"])
+
+	resp := api.Post("/reports")
+	require.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+	location := resp.Header().Get("Location")
+	assert.True(t, strings.HasPrefix(location, "/jobs/"))
+	assert.Contains(t, resp.Body.String(), `"status":"pending"`)
+
+	resp = api.Get(location)
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Equal(t, "2", resp.Header().Get("Retry-After"))
+	assert.Equal(t, "no-store", resp.Header().Get("Cache-Control"))
+
+	close(release)
+	var job jobs.Job[Report]
+	require.Eventually(t, func() bool {
+		resp = api.Get(location)
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &job))
+		return job.Status != jobs.StatusPending
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, jobs.StatusSucceeded, job.Status)
+	require.NotNil(t, job.Result)
+	assert.Equal(t, 5, job.Result.Count)
+	assert.Empty(t, resp.Header().Get("Retry-After"))
+
+	// Failed jobs include the error.
+	resp = api.Post("/reports?fail=true")
+	require.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+	location = resp.Header().Get("Location")
+	require.Eventually(t, func() bool {
+		resp = api.Get(location)
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &job))
+		return job.Status != jobs.StatusPending
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, jobs.StatusFailed, job.Status)
+	assert.Equal(t, "out of paper", job.Error)
+
+	resp = api.Get("/jobs/missing")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+// serializingStore stores results as JSON, like a database-backed store.
+type serializingStore struct {
+	*jobs.MemoryStore
+}
+
+func (s serializingStore) Update(ctx context.Context, info jobs.Info) error {
+	if info.Result != nil {
+		b, err := json.Marshal(info.Result)
+		if err != nil {
+			return err
+		}
+		info.Result = json.RawMessage(b)
+	}
+	return s.MemoryStore.Update(ctx, info)
+}
+
+func TestJobsSerializedResult(t *testing.T) {
+	_, api := humatest.New(t)
+
+	reports := jobs.Register[Report](api, jobs.Config{
+		Path:  "/jobs",
+		Store: serializingStore{jobs.NewMemoryStore()},
+	})
+
+	accepted, err := reports.Start(context.Background(), func(ctx context.Context) (Report, error) {
+		return Report{Count: 3}, nil
+	})
+	require.NoError(t, err)
+
+	var job jobs.Job[Report]
+	require.Eventually(t, func() bool {
+		resp := api.Get(accepted.Location)
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &job))
+		return job.Status == jobs.StatusSucceeded
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 3, job.Result.Count)
+}
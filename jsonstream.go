@@ -0,0 +1,110 @@
+package huma
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// jsonArrayFlushInterval is how often streamed JSON arrays are flushed to
+// the client while items are being written.
+const jsonArrayFlushInterval = 100 * time.Millisecond
+
+// jsonArrayStreamer is implemented by `JSONArrayResponse` so that its item
+// type can be documented.
+type jsonArrayStreamer interface {
+	jsonArrayItemType() reflect.Type
+}
+
+// JSONArrayResponse is a response which streams a JSON array of `T` items,
+// documented as an `array` of `T`. Create it via `StreamJSONArray`.
+type JSONArrayResponse[T any] struct {
+	Body func(ctx Context)
+}
+
+func (r *JSONArrayResponse[T]) jsonArrayItemType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+// StreamJSONArray returns a response which writes a JSON array incrementally
+// as the function yields items, so large exports can be sent without first
+// building a giant slice. The array is flushed to the client periodically.
+//
+//	huma.Get(api, "/export", func(ctx context.Context, input *struct{}) (*huma.JSONArrayResponse[Item], error) {
+//		return huma.StreamJSONArray(func(yield func(Item) error) error {
+//			for rows.Next() {
+//				// ... scan the item ...
+//				if err := yield(item); err != nil {
+//					return err
+//				}
+//			}
+//			return rows.Err()
+//		}), nil
+//	})
+//
+// Yield returns an error if the item could not be marshaled or written, e.g.
+// because the client went away. If the function returns an error before any
+// items are yielded, an error response is sent instead. Otherwise the status
+// has already been sent, so the array is left unterminated to signal to the
+// client that the response is incomplete.
+func StreamJSONArray[T any](fn func(yield func(T) error) error) *JSONArrayResponse[T] {
+	return &JSONArrayResponse[T]{
+		Body: func(ctx Context) {
+			w := ctx.BodyWriter()
+			flusher, _ := w.(http.Flusher)
+			started := false
+			lastFlush := time.Now()
+
+			start := func() {
+				started = true
+				status := http.StatusOK
+				if op := ctx.Operation(); op != nil && op.DefaultStatus != 0 {
+					status = op.DefaultStatus
+				}
+				ctx.SetHeader("Content-Type", "application/json")
+				ctx.SetStatus(status)
+			}
+
+			err := fn(func(item T) error {
+				b, err := json.Marshal(item)
+				if err != nil {
+					return err
+				}
+				sep := ","
+				if !started {
+					start()
+					sep = "["
+				}
+				if _, err := w.Write([]byte(sep)); err != nil {
+					return err
+				}
+				if _, err := w.Write(b); err != nil {
+					return err
+				}
+				if flusher != nil && time.Since(lastFlush) >= jsonArrayFlushInterval {
+					flusher.Flush()
+					lastFlush = time.Now()
+				}
+				return nil
+			})
+
+			if err != nil {
+				if !started {
+					e := newErrorForContext(ctx, http.StatusInternalServerError, "error streaming response", err)
+					ctx.SetHeader("Content-Type", "application/problem+json")
+					ctx.SetStatus(e.GetStatus())
+					json.NewEncoder(w).Encode(e)
+				}
+				return
+			}
+
+			if !started {
+				start()
+				w.Write([]byte("[]"))
+				return
+			}
+			w.Write([]byte("]"))
+		},
+	}
+}
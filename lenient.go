@@ -0,0 +1,79 @@
+package huma
+
+import (
+	"strings"
+	"time"
+)
+
+// LenientTimeFormats are the date/time formats accepted by `time.Time` params
+// with the `lenient:"true"` tag, in addition to their `timeFormat`. They are
+// tried in order, so put the formats your clients are most likely to send
+// first. Note that e.g. `01/02/2024` is the 2nd of January with the default
+// formats, which match the US style for slashes and the European style for
+// dots. Replace or reorder them to change this:
+//
+//	huma.LenientTimeFormats = []string{"02/01/2006", "2006-01-02"}
+var LenientTimeFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"2006/01/02",
+	"01/02/2006",
+	"01/02/2006 15:04:05",
+	"02.01.2006",
+	"02.01.2006 15:04:05",
+	time.RFC1123,
+	time.RFC1123Z,
+}
+
+// normalizeDecimal converts a localized number like `1,5`, `1.234,5`, or
+// `1 234.5` into the `1.5` / `1234.5` form expected by `strconv`. When both
+// `.` and `,` are present the last one is the decimal separator. Otherwise a
+// single `,` or `.` is the decimal separator, while repeated ones are
+// grouping separators. Spaces and apostrophes are also grouping separators.
+func normalizeDecimal(value string) string {
+	dots, commas := strings.Count(value, "."), strings.Count(value, ",")
+	if commas == 0 && dots <= 1 && !strings.ContainsAny(value, " '\u00a0\u202f") {
+		return value
+	}
+
+	decimal := '.'
+	switch {
+	case dots > 0 && commas > 0:
+		if strings.LastIndexByte(value, ',') > strings.LastIndexByte(value, '.') {
+			decimal = ','
+		}
+	case commas == 1:
+		decimal = ','
+	case commas > 1 || dots > 1:
+		decimal = 0
+	}
+
+	var b strings.Builder
+	b.Grow(len(value))
+	for _, r := range value {
+		switch r {
+		case ' ', '\'', '\u00a0', '\u202f':
+			// Grouping separators.
+		case '.', ',':
+			if r == decimal {
+				b.WriteByte('.')
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// parseLenientTime parses a time using the first matching format in
+// `LenientTimeFormats`.
+func parseLenientTime(value string) (time.Time, bool) {
+	for _, format := range LenientTimeFormats {
+		if t, err := time.Parse(format, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,226 @@
+package huma
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LintIssue is a single documentation quality problem found by `Lint`.
+type LintIssue struct {
+	// Rule is the name of the rule which found the issue.
+	Rule string
+
+	// Location identifies what the issue is about, like `GET /things` or
+	// `#/components/schemas/Thing`.
+	Location string
+
+	// Message describes the issue.
+	Message string
+}
+
+// Error satisfies the `error` interface.
+func (i *LintIssue) Error() string {
+	return i.Rule + ": " + i.Location + ": " + i.Message
+}
+
+// LintRule checks the OpenAPI document for a documentation quality problem,
+// calling `report` for each issue that it finds.
+type LintRule struct {
+	Name  string
+	Check func(oapi *OpenAPI, report func(location, message string))
+}
+
+var operationIDCasing = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+// LintOperationDescription reports operations without a description.
+var LintOperationDescription = LintRule{
+	Name: "operation-description",
+	Check: func(oapi *OpenAPI, report func(location, message string)) {
+		eachOperation(oapi, func(location string, op *Operation) {
+			if op.Description == "" {
+				report(location, "operation should have a description")
+			}
+		})
+	},
+}
+
+// LintOperation4xx reports operations which document no client error
+// responses, i.e. no `4xx` or `default` response.
+var LintOperation4xx = LintRule{
+	Name: "operation-4xx-response",
+	Check: func(oapi *OpenAPI, report func(location, message string)) {
+		eachOperation(oapi, func(location string, op *Operation) {
+			for status := range op.Responses {
+				if status == "default" || strings.HasPrefix(status, "4") {
+					return
+				}
+			}
+			report(location, "operation should document at least one 4xx response")
+		})
+	},
+}
+
+// LintOperationIDCasing reports operation IDs which are not kebab-case, like
+// `list-things`.
+var LintOperationIDCasing = LintRule{
+	Name: "operation-id-casing",
+	Check: func(oapi *OpenAPI, report func(location, message string)) {
+		eachOperation(oapi, func(location string, op *Operation) {
+			if !operationIDCasing.MatchString(op.OperationID) {
+				report(location, "operation ID "+op.OperationID+" should be kebab-case")
+			}
+		})
+	},
+}
+
+// LintOperationTags reports operations without any tags.
+var LintOperationTags = LintRule{
+	Name: "operation-tags",
+	Check: func(oapi *OpenAPI, report func(location, message string)) {
+		eachOperation(oapi, func(location string, op *Operation) {
+			if len(op.Tags) == 0 {
+				report(location, "operation should have at least one tag")
+			}
+		})
+	},
+}
+
+// LintSchemaExamples reports object schemas in the components which have no
+// examples, either on the schema itself or on any of its properties.
+var LintSchemaExamples = LintRule{
+	Name: "schema-examples",
+	Check: func(oapi *OpenAPI, report func(location, message string)) {
+		if oapi.Components == nil || oapi.Components.Schemas == nil {
+			return
+		}
+		schemas := oapi.Components.Schemas.Map()
+		names := make([]string, 0, len(schemas))
+		for name := range schemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+	outer:
+		for _, name := range names {
+			s := schemas[name]
+			if s.Type != TypeObject || len(s.Examples) > 0 {
+				continue
+			}
+			for propName, prop := range s.Properties {
+				// The `$schema` link added by `SchemaLinkTransformer` always
+				// has an example, so don't count it.
+				if propName != "$schema" && len(prop.Examples) > 0 {
+					continue outer
+				}
+			}
+			report("#/components/schemas/"+name, "schema should have examples")
+		}
+	},
+}
+
+// moneyFieldName matches property names which usually hold money.
+var moneyFieldName = regexp.MustCompile(`(?i)(amount|price|cost|total|balance|fee|tax|salary|payment|money)`)
+
+// LintFloatMoney reports floating point properties in the component schemas
+// whose names suggest money, like `price` or `totalAmount`, which should use
+// `huma.Cents`, `huma.Micros`, or `huma.Decimal` instead to avoid rounding
+// errors.
+var LintFloatMoney = LintRule{
+	Name: "float-money",
+	Check: func(oapi *OpenAPI, report func(location, message string)) {
+		if oapi.Components == nil || oapi.Components.Schemas == nil {
+			return
+		}
+		schemas := oapi.Components.Schemas.Map()
+		names := make([]string, 0, len(schemas))
+		for name := range schemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			props := schemas[name].Properties
+			propNames := make([]string, 0, len(props))
+			for propName := range props {
+				propNames = append(propNames, propName)
+			}
+			sort.Strings(propNames)
+			for _, propName := range propNames {
+				if props[propName].Type == TypeNumber && moneyFieldName.MatchString(propName) {
+					report("#/components/schemas/"+name+"/properties/"+propName, "money should not be a float, use huma.Cents, huma.Micros, or huma.Decimal")
+				}
+			}
+		}
+	},
+}
+
+// DefaultLintRules are the rules used by `Lint` when none are passed.
+var DefaultLintRules = []LintRule{
+	LintOperationDescription,
+	LintOperation4xx,
+	LintOperationIDCasing,
+	LintOperationTags,
+	LintSchemaExamples,
+	LintFloatMoney,
+}
+
+// Lint checks the API's OpenAPI document for documentation quality problems
+// using the given rules, or `DefaultLintRules` if none are passed. Call it
+// after registering all operations, e.g. at startup or in a test, to catch
+// issues before deployment. All issues are returned joined into a single
+// error, each one a `*LintIssue`, or nil if none were found.
+//
+//	if err := huma.Lint(api); err != nil {
+//		log.Printf("API docs need work:\n%v", err)
+//	}
+func Lint(api API, rules ...LintRule) error {
+	if len(rules) == 0 {
+		rules = DefaultLintRules
+	}
+
+	var errs []error
+	for _, rule := range rules {
+		rule.Check(api.OpenAPI(), func(location, message string) {
+			errs = append(errs, &LintIssue{
+				Rule:     rule.Name,
+				Location: location,
+				Message:  message,
+			})
+		})
+	}
+	return errors.Join(errs...)
+}
+
+// eachOperation calls `cb` for each operation in the document, ordered by
+// path and then method.
+func eachOperation(oapi *OpenAPI, cb func(location string, op *Operation)) {
+	paths := make([]string, 0, len(oapi.Paths))
+	for p := range oapi.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		item := oapi.Paths[p]
+		for _, entry := range []struct {
+			method string
+			op     *Operation
+		}{
+			{http.MethodGet, item.Get},
+			{http.MethodPut, item.Put},
+			{http.MethodPost, item.Post},
+			{http.MethodDelete, item.Delete},
+			{http.MethodOptions, item.Options},
+			{http.MethodHead, item.Head},
+			{http.MethodPatch, item.Patch},
+			{http.MethodTrace, item.Trace},
+		} {
+			if entry.op != nil {
+				cb(entry.method+" "+p, entry.op)
+			}
+		}
+	}
+}
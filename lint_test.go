@@ -0,0 +1,69 @@
+package huma_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+type LintThing struct {
+	ID    string  `json:"id"`
+	Price float64 `json:"price"`
+	Ratio float64 `json:"ratio"`
+}
+
+type LintDocumented struct {
+	ID string `json:"id" example:"abc123"`
+}
+
+func TestLint(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "getThing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{ Body LintThing }, error) {
+		return nil, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-documented",
+		Method:      http.MethodGet,
+		Path:        "/documented",
+		Description: "List documented things.",
+		Tags:        []string{"Things"},
+		Errors:      []int{http.StatusBadRequest},
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body []LintDocumented }, error) {
+		return nil, nil
+	})
+
+	err := huma.Lint(api)
+	require.Error(t, err)
+
+	var issues []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var issue *huma.LintIssue
+		require.True(t, errors.As(e, &issue))
+		issues = append(issues, issue.Error())
+	}
+	assert.Equal(t, []string{
+		"operation-description: GET /things/{id}: operation should have a description",
+		"operation-id-casing: GET /things/{id}: operation ID getThing should be kebab-case",
+		"operation-tags: GET /things/{id}: operation should have at least one tag",
+		"schema-examples: #/components/schemas/LintThing: schema should have examples",
+		"float-money: #/components/schemas/LintThing/properties/price: money should not be a float, use huma.Cents, huma.Micros, or huma.Decimal",
+	}, issues)
+
+	// Only the given rules are checked.
+	assert.NoError(t, huma.Lint(api, huma.LintOperation4xx))
+}
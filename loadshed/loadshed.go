@@ -0,0 +1,126 @@
+// Package loadshed provides per-operation concurrency limits which shed load
+// when an operation is overloaded. Requests beyond the limit wait in a bounded
+// queue, and once the queue is full or the wait times out a
+// `503 Service Unavailable` error with a `Retry-After` header is returned.
+// The 503 response is documented in the OpenAPI.
+//
+//	huma.Get(api, "/reports/{id}", getReport, loadshed.Limit(api, loadshed.Config{
+//		MaxInFlight:  10,
+//		MaxQueue:     50,
+//		QueueTimeout: 2 * time.Second,
+//	}))
+package loadshed
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// DefaultRetryAfter is the `Retry-After` duration used when none is set.
+var DefaultRetryAfter = time.Second
+
+// Config configures the concurrency limit for an operation.
+type Config struct {
+	// MaxInFlight is the maximum number of requests which may be handled at
+	// the same time. Required.
+	MaxInFlight int
+
+	// MaxQueue is the maximum number of requests which may wait for an
+	// in-flight request to finish. When zero, requests over the limit are
+	// rejected immediately.
+	MaxQueue int
+
+	// QueueTimeout is how long a queued request may wait before it is
+	// rejected. When zero, queued requests wait until they are canceled.
+	QueueTimeout time.Duration
+
+	// RetryAfter is sent to rejected clients via the `Retry-After` header,
+	// rounded up to whole seconds. Defaults to `DefaultRetryAfter`.
+	RetryAfter time.Duration
+}
+
+// Limit returns an operation handler which limits the number of concurrent
+// requests to the operation. Each call creates a separate limit, so use a
+// new one for each operation.
+func Limit(api huma.API, config Config) func(o *huma.Operation) {
+	if config.MaxInFlight < 1 {
+		panic("loadshed: MaxInFlight must be at least 1")
+	}
+	if config.RetryAfter <= 0 {
+		config.RetryAfter = DefaultRetryAfter
+	}
+	retryAfter := strconv.Itoa(int(math.Ceil(config.RetryAfter.Seconds())))
+
+	slots := make(chan struct{}, config.MaxInFlight)
+	queue := make(chan struct{}, config.MaxQueue)
+
+	reject := func(ctx huma.Context) {
+		ctx.SetHeader("Retry-After", retryAfter)
+		huma.WriteErr(api, ctx, http.StatusServiceUnavailable, "server is overloaded, try again later")
+	}
+
+	return func(o *huma.Operation) {
+		o.Middlewares = append(o.Middlewares, func(ctx huma.Context, next func(huma.Context)) {
+			select {
+			case slots <- struct{}{}:
+			default:
+				if !wait(ctx, slots, queue, config.QueueTimeout) {
+					reject(ctx)
+					return
+				}
+			}
+			defer func() { <-slots }()
+			next(ctx)
+		})
+
+		if o.Responses == nil {
+			o.Responses = map[string]*huma.Response{}
+		}
+		status := strconv.Itoa(http.StatusServiceUnavailable)
+		if o.Responses[status] == nil {
+			o.Responses[status] = &huma.Response{}
+		}
+		if o.Responses[status].Headers == nil {
+			o.Responses[status].Headers = map[string]*huma.Header{}
+		}
+		o.Responses[status].Headers["Retry-After"] = &huma.Header{
+			Description: "Seconds to wait before retrying the request.",
+			Schema:      &huma.Schema{Type: huma.TypeInteger},
+		}
+		if !slices.Contains(o.Errors, http.StatusServiceUnavailable) {
+			o.Errors = append(o.Errors, http.StatusServiceUnavailable)
+		}
+	}
+}
+
+// wait queues the request until a slot is available, returning false if the
+// queue is full, the timeout expires, or the request is canceled.
+func wait(ctx huma.Context, slots, queue chan struct{}, timeout time.Duration) bool {
+	select {
+	case queue <- struct{}{}:
+	default:
+		return false
+	}
+	defer func() { <-queue }()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-expired:
+		return false
+	case <-ctx.Context().Done():
+		return false
+	}
+}
@@ -0,0 +1,83 @@
+package loadshed
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+func register(api huma.API, config Config) (started, release chan struct{}) {
+	started = make(chan struct{}, 1)
+	release = make(chan struct{})
+	huma.Get(api, "/slow", func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		started <- struct{}{}
+		<-release
+		return nil, nil
+	}, Limit(api, config))
+	return started, release
+}
+
+func TestLimit(t *testing.T) {
+	_, api := humatest.New(t)
+	started, release := register(api, Config{
+		MaxInFlight: 1,
+		RetryAfter:  1500 * time.Millisecond,
+	})
+
+	resp := api.OpenAPI().Paths["/slow"].Get.Responses["503"]
+	require.NotNil(t, resp)
+	assert.NotNil(t, resp.Headers["Retry-After"])
+	assert.NotNil(t, resp.Content["application/problem+json"])
+
+	done := make(chan int)
+	go func() {
+		done <- api.Get("/slow").Code
+	}()
+	<-started
+
+	// The limit is reached and there is no queue, so the request is shed.
+	shed := api.Get("/slow")
+	assert.Equal(t, http.StatusServiceUnavailable, shed.Code)
+	assert.Equal(t, "2", shed.Header().Get("Retry-After"))
+
+	close(release)
+	assert.Equal(t, http.StatusNoContent, <-done)
+}
+
+func TestLimitQueue(t *testing.T) {
+	_, api := humatest.New(t)
+	started, release := register(api, Config{
+		MaxInFlight:  1,
+		MaxQueue:     1,
+		QueueTimeout: 10 * time.Millisecond,
+	})
+
+	done := make(chan int)
+	go func() {
+		done <- api.Get("/slow").Code
+	}()
+	<-started
+
+	// Queued, but the in-flight request does not finish in time.
+	shed := api.Get("/slow")
+	assert.Equal(t, http.StatusServiceUnavailable, shed.Code)
+	assert.Equal(t, "1", shed.Header().Get("Retry-After"))
+
+	close(release)
+	assert.Equal(t, http.StatusNoContent, <-done)
+	assert.Equal(t, http.StatusNoContent, api.Get("/slow").Code)
+}
+
+func TestLimitInvalid(t *testing.T) {
+	_, api := humatest.New(t)
+	assert.Panics(t, func() {
+		Limit(api, Config{})
+	})
+}
@@ -0,0 +1,298 @@
+package huma
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// MirrorResponse is a response captured by `Mirror` for comparison.
+type MirrorResponse struct {
+	// Status is the response status code.
+	Status int
+
+	// Header contains the response headers.
+	Header http.Header
+
+	// Body is the response body, truncated to `MirrorConfig.MaxBodyBytes`.
+	Body []byte
+
+	// Latency is the time it took to handle the request.
+	Latency time.Duration
+
+	// Err is set if the mirrored request failed, in which case there is no
+	// response.
+	Err error
+}
+
+// MirrorConfig configures which requests `Mirror` copies and where to.
+type MirrorConfig struct {
+	// Percent of matching requests to mirror, from 0 to 100.
+	Percent float64
+
+	// Match optionally limits mirroring to requests for which it returns true,
+	// e.g. based on the method, URL, or `ctx.Operation()`.
+	Match func(ctx Context) bool
+
+	// Target is the upstream to send mirrored requests to. The request path is
+	// appended to the path of the target URL, like for `Proxy`. Either
+	// `Target` or `Handler` must be set.
+	Target *url.URL
+
+	// Handler handles mirrored requests in-process instead of sending them to
+	// `Target`, e.g. for a rewritten implementation of the same endpoint.
+	Handler http.Handler
+
+	// Client sends requests to `Target`. Defaults to `http.DefaultClient`.
+	Client *http.Client
+
+	// Timeout limits how long a mirrored request may take. Defaults to 10
+	// seconds.
+	Timeout time.Duration
+
+	// MaxBodyBytes limits the size of request bodies which are mirrored, as
+	// well as the size of response bodies captured for `Compare`. Requests
+	// with larger bodies are not mirrored. Defaults to 1MB.
+	MaxBodyBytes int64
+
+	// Compare is optionally called with the primary and mirrored responses
+	// once both have completed, e.g. to log or count differences. It runs in
+	// its own goroutine and must be safe for concurrent use.
+	Compare func(req *http.Request, primary, mirror *MirrorResponse)
+}
+
+// mirrorHeader marks mirrored requests so the receiver can e.g. skip side
+// effects like sending emails.
+const mirrorHeader = "X-Mirrored-Request"
+
+// Mirror returns a middleware which copies a percentage of requests to a
+// secondary upstream or handler, e.g. to safely verify a rewrite of a legacy
+// endpoint against real traffic. The client always gets the response of the
+// primary handler. Mirrored requests are sent asynchronously once the primary
+// response has been written, without the request's cancellation, and their
+// responses are discarded after being passed to `Compare`. They include the
+// `X-Mirrored-Request: true` header.
+//
+//	target, _ := url.Parse("http://users-v2.internal")
+//	huma.UseMiddlewareIf(api, huma.OperationPathPrefix("/users"), huma.Mirror(huma.MirrorConfig{
+//		Percent: 5,
+//		Target:  target,
+//		Compare: func(req *http.Request, primary, mirror *huma.MirrorResponse) {
+//			if mirror.Err != nil || primary.Status != mirror.Status || !bytes.Equal(primary.Body, mirror.Body) {
+//				log.Printf("mirror mismatch for %s %s", req.Method, req.URL)
+//			}
+//		},
+//	}))
+func Mirror(config MirrorConfig) func(ctx Context, next func(Context)) {
+	if config.Target == nil && config.Handler == nil {
+		panic("mirror requires a target or handler")
+	}
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.MaxBodyBytes == 0 {
+		config.MaxBodyBytes = 1024 * 1024
+	}
+
+	return func(ctx Context, next func(Context)) {
+		if rand.Float64()*100 >= config.Percent || (config.Match != nil && !config.Match(ctx)) {
+			next(ctx)
+			return
+		}
+
+		// Buffer the body so it can be sent twice. Large bodies are streamed
+		// to the primary handler as usual and not mirrored.
+		var body []byte
+		if reader := ctx.BodyReader(); reader != nil {
+			var err error
+			body, err = io.ReadAll(io.LimitReader(reader, config.MaxBodyBytes+1))
+			if err != nil || int64(len(body)) > config.MaxBodyBytes {
+				next(&mirrorContext{humaContext: ctx, body: io.MultiReader(bytes.NewReader(body), reader)})
+				return
+			}
+		}
+
+		req := config.newRequest(ctx, body)
+		mctx := &mirrorContext{humaContext: ctx, body: bytes.NewReader(body)}
+		if config.Compare != nil {
+			mctx.capture = &MirrorResponse{Header: http.Header{}}
+			mctx.limit = config.MaxBodyBytes
+		}
+		start := time.Now()
+		next(mctx)
+
+		var primary *MirrorResponse
+		if mctx.capture != nil {
+			primary = mctx.capture
+			primary.Latency = time.Since(start)
+			primary.Status = ctx.Status()
+			if primary.Status == 0 {
+				primary.Status = http.StatusOK
+			}
+		}
+		go config.send(req, body, primary)
+	}
+}
+
+// newRequest creates the mirrored request. It is not bound to the incoming
+// request's context so that it is not canceled when the primary response has
+// been sent.
+func (c *MirrorConfig) newRequest(ctx Context, body []byte) *http.Request {
+	u := ctx.URL()
+	target := u.String()
+	if c.Target != nil {
+		target = upstreamURL(c.Target, u.Path, u.RawQuery)
+	}
+	req, _ := http.NewRequest(ctx.Method(), target, bytes.NewReader(body))
+	copyHeaders(ctx, req.Header)
+	req.Header.Set(mirrorHeader, "true")
+	if c.Target != nil {
+		setForwardedHeaders(ctx, req.Header)
+	} else {
+		req.Host = ctx.Host()
+		req.RemoteAddr = ctx.RemoteAddr()
+	}
+	return req
+}
+
+// send sends the mirrored request and compares its response to the primary
+// one, if needed.
+func (c *MirrorConfig) send(req *http.Request, body []byte, primary *MirrorResponse) {
+	tctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+	req = req.WithContext(tctx)
+
+	mirror := &MirrorResponse{}
+	start := time.Now()
+	if c.Handler != nil {
+		rec := &mirrorRecorder{header: http.Header{}, limit: c.MaxBodyBytes}
+		c.Handler.ServeHTTP(rec, req)
+		mirror.Status, mirror.Header, mirror.Body = rec.status, rec.header, rec.body.Bytes()
+		if mirror.Status == 0 {
+			mirror.Status = http.StatusOK
+		}
+	} else {
+		resp, err := c.Client.Do(req)
+		if err != nil {
+			mirror.Err = err
+		} else {
+			mirror.Status, mirror.Header = resp.StatusCode, resp.Header
+			mirror.Body, mirror.Err = io.ReadAll(io.LimitReader(resp.Body, c.MaxBodyBytes))
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+	}
+	mirror.Latency = time.Since(start)
+
+	if c.Compare != nil {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		c.Compare(req, primary, mirror)
+	}
+}
+
+// mirrorContext wraps the request context to replay the buffered request
+// body and, if needed, capture the primary response.
+type mirrorContext struct {
+	humaContext
+	body    io.Reader
+	capture *MirrorResponse
+	limit   int64
+	w       *mirrorWriter
+}
+
+func (c *mirrorContext) Unwrap() Context {
+	return c.humaContext
+}
+
+func (c *mirrorContext) BodyReader() io.Reader {
+	return c.body
+}
+
+func (c *mirrorContext) SetHeader(name, value string) {
+	if c.capture != nil {
+		c.capture.Header.Set(name, value)
+	}
+	c.humaContext.SetHeader(name, value)
+}
+
+func (c *mirrorContext) AppendHeader(name, value string) {
+	if c.capture != nil {
+		c.capture.Header.Add(name, value)
+	}
+	c.humaContext.AppendHeader(name, value)
+}
+
+func (c *mirrorContext) BodyWriter() io.Writer {
+	if c.capture == nil {
+		return c.humaContext.BodyWriter()
+	}
+	if c.w == nil {
+		c.w = &mirrorWriter{w: c.humaContext.BodyWriter(), capture: c.capture, limit: c.limit}
+	}
+	return c.w
+}
+
+// mirrorWriter copies the primary response body into the captured response,
+// up to a limit. It can be unwrapped so that streaming responses can still
+// flush the underlying writer.
+type mirrorWriter struct {
+	w       io.Writer
+	capture *MirrorResponse
+	limit   int64
+}
+
+func (m *mirrorWriter) Write(p []byte) (int, error) {
+	if remaining := m.limit - int64(len(m.capture.Body)); remaining > 0 {
+		m.capture.Body = append(m.capture.Body, p[:min(int64(len(p)), remaining)]...)
+	}
+	return m.w.Write(p)
+}
+
+func (m *mirrorWriter) Flush() {
+	if f, ok := m.w.(http.Flusher); ok {
+		f.Flush()
+	} else if rw, ok := m.w.(http.ResponseWriter); ok {
+		http.NewResponseController(rw).Flush()
+	}
+}
+
+func (m *mirrorWriter) Unwrap() http.ResponseWriter {
+	rw, _ := m.w.(http.ResponseWriter)
+	return rw
+}
+
+// mirrorRecorder is an `http.ResponseWriter` which records the response of
+// an in-process mirror handler, up to a limit.
+type mirrorRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+	limit  int64
+}
+
+func (r *mirrorRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *mirrorRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *mirrorRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	if remaining := r.limit - int64(r.body.Len()); remaining > 0 {
+		r.body.Write(p[:min(int64(len(p)), remaining)])
+	}
+	return len(p), nil
+}
@@ -0,0 +1,274 @@
+package huma
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"os"
+	"sync"
+)
+
+// DefaultMultipartMaxMemory is the default number of bytes of file contents
+// kept in memory by `MultipartConfig` before spooling them to disk.
+const DefaultMultipartMaxMemory = 8 << 20
+
+// maxMultipartValueBytes limits the total size of non-file form values, which
+// are always kept in memory.
+const maxMultipartValueBytes = 10 << 20
+
+// MultipartConfig controls how `huma.MultipartFormFiles` request bodies are
+// read. Without it, the router adapter parses the form using its own
+// defaults.
+//
+// File contents are kept in memory up to `MaxMemory` bytes in total, after
+// which files are spooled to temporary files in `TempDir`. Temporary files
+// are removed once the handler returns or panics, and the response has been
+// written, unless `KeepFiles` is set.
+//
+//	config.Multipart = &huma.MultipartConfig{
+//		MaxMemory: 1 << 20,
+//		TempDir:   "/var/spool/uploads",
+//		Sink: func(ctx huma.Context, field string, fh *multipart.FileHeader) (io.Writer, error) {
+//			if field != "video" {
+//				return nil, nil
+//			}
+//			return storage.NewWriter(ctx.Context(), fh.Filename)
+//		},
+//	}
+type MultipartConfig struct {
+	// MaxMemory is the total number of bytes of file contents kept in memory
+	// before files are spooled to disk. Defaults to
+	// `DefaultMultipartMaxMemory`. Set to a negative value to always spool.
+	MaxMemory int64
+
+	// TempDir is the directory for spooled files. Defaults to `os.TempDir()`.
+	TempDir string
+
+	// KeepFiles disables removing spooled files after the handler returns,
+	// e.g. so they can be moved into place instead of copied. The handler is
+	// then responsible for removing them, using the name of the `*os.File`
+	// in `huma.FormFile`.
+	KeepFiles bool
+
+	// Sink is called for each file part as it is read, and can return a
+	// writer to stream the file contents to directly, e.g. object storage,
+	// instead of buffering them. Return a nil writer to read the file as
+	// usual. Streamed files are available in `huma.FormFile` with their size
+	// and filename but without contents. If the writer is an `io.Closer`, it
+	// is closed once the file has been written. Returning an error rejects
+	// the request.
+	Sink func(ctx Context, field string, fh *multipart.FileHeader) (io.Writer, error)
+}
+
+// spooledFile is the stored contents of a file part.
+type spooledFile struct {
+	content []byte
+	path    string
+	sunk    bool
+}
+
+// memFile is an in-memory `multipart.File`.
+type memFile struct {
+	*io.SectionReader
+}
+
+func (memFile) Close() error {
+	return nil
+}
+
+// multipartSpool is a multipart form read using a `MultipartConfig`, which
+// tracks its files so they can be opened and cleaned up.
+type multipartSpool struct {
+	form   *multipart.Form
+	keep   bool
+	mu     sync.Mutex
+	files  map[*multipart.FileHeader]*spooledFile
+	opened []*os.File
+}
+
+// open opens the stored contents of the file. Files which were streamed to a
+// sink have no contents and return a nil file.
+func (s *multipartSpool) open(fh *multipart.FileHeader) (multipart.File, error) {
+	f := s.files[fh]
+	switch {
+	case f == nil:
+		return fh.Open()
+	case f.sunk:
+		return nil, nil
+	case f.path != "":
+		file, err := os.Open(f.path)
+		if err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		s.opened = append(s.opened, file)
+		s.mu.Unlock()
+		return file, nil
+	}
+	return memFile{io.NewSectionReader(bytes.NewReader(f.content), 0, int64(len(f.content)))}, nil
+}
+
+// cleanup closes opened files and removes spooled files, unless they should
+// be kept.
+func (s *multipartSpool) cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, f := range s.opened {
+		f.Close()
+	}
+	s.opened = nil
+	if s.keep {
+		return
+	}
+	for _, f := range s.files {
+		if f.path != "" {
+			os.Remove(f.path)
+		}
+	}
+}
+
+// readMultipart reads the multipart form from the request body using the
+// config. Spooled files are removed if reading fails. Files are only read up
+// to their `maxFileSize` limit, if any, after which a `fileTooLargeError` is
+// returned.
+func (c *MultipartConfig) readMultipart(ctx Context, limits map[string]fileLimits) (s *multipartSpool, err error) {
+	_, params, err := mime.ParseMediaType(ctx.Header("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	boundary := params["boundary"]
+	if boundary == "" {
+		return nil, errors.New("missing multipart boundary")
+	}
+
+	s = &multipartSpool{
+		form: &multipart.Form{
+			Value: map[string][]string{},
+			File:  map[string][]*multipart.FileHeader{},
+		},
+		files: map[*multipart.FileHeader]*spooledFile{},
+	}
+	defer func() {
+		if err != nil {
+			s.cleanup()
+			s = nil
+		}
+	}()
+
+	memory := c.MaxMemory
+	if memory == 0 {
+		memory = DefaultMultipartMaxMemory
+	}
+	valueBytes := int64(maxMultipartValueBytes)
+
+	reader := multipart.NewReader(ctx.BodyReader(), boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return s, err
+		}
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+
+		if part.FileName() == "" {
+			// Regular form values are kept in memory.
+			b, err := io.ReadAll(io.LimitReader(part, valueBytes+1))
+			if err != nil {
+				return s, err
+			}
+			valueBytes -= int64(len(b))
+			if valueBytes < 0 {
+				return s, multipart.ErrMessageTooLarge
+			}
+			s.form.Value[name] = append(s.form.Value[name], string(b))
+			continue
+		}
+
+		fh := &multipart.FileHeader{
+			Filename: part.FileName(),
+			Header:   part.Header,
+		}
+		f := &spooledFile{}
+		s.files[fh] = f
+		s.form.File[name] = append(s.form.File[name], fh)
+
+		// Stop reading one byte past the limit, which is enough to know that
+		// the file is too large.
+		var src io.Reader = part
+		limit := limits[name]
+		if limit.maxSize > 0 {
+			src = io.LimitReader(part, limit.maxSize+1)
+		}
+		tooLarge := func() bool {
+			return limit.maxSize > 0 && fh.Size > limit.maxSize
+		}
+		location := name
+		if limit.multiple {
+			location = fmt.Sprintf("%s[%d]", name, len(s.form.File[name])-1)
+		}
+
+		if c.Sink != nil {
+			w, err := c.Sink(ctx, name, fh)
+			if err != nil {
+				return s, err
+			}
+			if w != nil {
+				f.sunk = true
+				fh.Size, err = io.Copy(w, src)
+				if closer, ok := w.(io.Closer); ok {
+					if cErr := closer.Close(); err == nil {
+						err = cErr
+					}
+				}
+				if err != nil {
+					return s, err
+				}
+				if tooLarge() {
+					return s, newFileTooLargeError(fh, location, limit.maxSize)
+				}
+				continue
+			}
+		}
+
+		// Keep the file in memory if it fits, otherwise spool it to disk.
+		var buf bytes.Buffer
+		n, err := io.CopyN(&buf, src, max(memory, 0)+1)
+		if err != nil && err != io.EOF {
+			return s, err
+		}
+		if n <= memory {
+			memory -= n
+			f.content = buf.Bytes()
+			fh.Size = n
+			if tooLarge() {
+				return s, newFileTooLargeError(fh, location, limit.maxSize)
+			}
+			continue
+		}
+		tmp, err := os.CreateTemp(c.TempDir, "multipart-")
+		if err != nil {
+			return s, fmt.Errorf("unable to spool file: %w", err)
+		}
+		f.path = tmp.Name()
+		size, err := io.Copy(tmp, io.MultiReader(&buf, src))
+		if cErr := tmp.Close(); err == nil {
+			err = cErr
+		}
+		if err != nil {
+			return s, err
+		}
+		fh.Size = size
+		if tooLarge() {
+			return s, newFileTooLargeError(fh, location, limit.maxSize)
+		}
+	}
+	return s, nil
+}
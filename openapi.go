@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/danielgtaylor/huma/v2/yaml"
@@ -95,6 +96,43 @@ func marshalJSON(fields []jsonFieldInfo, extensions map[string]any) ([]byte, err
 	return json.Marshal(value)
 }
 
+// unmarshalJSON unmarshals JSON into `v`, which must be a pointer to an alias
+// type without its own `UnmarshalJSON` method. The `$ref` and inlined
+// extensions are set separately as they cannot be matched to struct fields.
+func unmarshalJSON(data []byte, v any, ref *string, extensions *map[string]any) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	for k, value := range raw {
+		switch {
+		case k == "$ref" && ref != nil:
+			if err := json.Unmarshal(value, ref); err != nil {
+				return err
+			}
+		case strings.HasPrefix(k, "x-") && extensions != nil:
+			var ext any
+			if err := json.Unmarshal(value, &ext); err != nil {
+				return err
+			}
+			if *extensions == nil {
+				*extensions = map[string]any{}
+			}
+			(*extensions)[k] = ext
+		default:
+			continue
+		}
+		delete(raw, k)
+	}
+
+	rest, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(rest, v)
+}
+
 // Contact information to get support for the API.
 //
 //	name: API Support
@@ -312,6 +350,11 @@ func (e *Example) MarshalJSON() ([]byte, error) {
 	}, e.Extensions)
 }
 
+func (e *Example) UnmarshalJSON(data []byte) error {
+	type alias Example
+	return unmarshalJSON(data, (*alias)(e), &e.Ref, &e.Extensions)
+}
+
 // Encoding is a single encoding definition applied to a single schema property.
 //
 //	requestBody:
@@ -548,6 +591,11 @@ type Param struct {
 	// override the example provided by the schema.
 	Examples map[string]*Example `yaml:"examples,omitempty"`
 
+	// Content is a map containing the representations for the parameter. The
+	// key is the media type and the value describes it. The map MUST only
+	// contain one entry, and is mutually exclusive with the schema field.
+	Content map[string]*MediaType `yaml:"content,omitempty"`
+
 	// Extensions (user-defined properties), if any. Values in this map will
 	// be marshalled as siblings of the other properties above.
 	Extensions map[string]any `yaml:",inline"`
@@ -568,9 +616,15 @@ func (p *Param) MarshalJSON() ([]byte, error) {
 		{"schema", p.Schema, omitEmpty},
 		{"example", p.Example, omitNil},
 		{"examples", p.Examples, omitEmpty},
+		{"content", p.Content, omitEmpty},
 	}, p.Extensions)
 }
 
+func (p *Param) UnmarshalJSON(data []byte) error {
+	type alias Param
+	return unmarshalJSON(data, (*alias)(p), &p.Ref, &p.Extensions)
+}
+
 // Header object follows the structure of the Parameter Object with the
 // following changes:
 //
@@ -632,6 +686,11 @@ func (r *RequestBody) MarshalJSON() ([]byte, error) {
 	}, r.Extensions)
 }
 
+func (r *RequestBody) UnmarshalJSON(data []byte) error {
+	type alias RequestBody
+	return unmarshalJSON(data, (*alias)(r), &r.Ref, &r.Extensions)
+}
+
 // Link object represents a possible design-time link for a response. The
 // presence of a link does not guarantee the caller’s ability to successfully
 // invoke it, rather it provides a known relationship and traversal mechanism
@@ -740,6 +799,11 @@ func (l *Link) MarshalJSON() ([]byte, error) {
 	}, l.Extensions)
 }
 
+func (l *Link) UnmarshalJSON(data []byte) error {
+	type alias Link
+	return unmarshalJSON(data, (*alias)(l), &l.Ref, &l.Extensions)
+}
+
 // Response describes a single response from an API Operation, including
 // design-time, static links to operations based on the response.
 //
@@ -790,6 +854,11 @@ func (r *Response) MarshalJSON() ([]byte, error) {
 	}, r.Extensions)
 }
 
+func (r *Response) UnmarshalJSON(data []byte) error {
+	type alias Response
+	return unmarshalJSON(data, (*alias)(r), &r.Ref, &r.Extensions)
+}
+
 // Operation describes a single API operation on a path.
 //
 //	tags:
@@ -858,15 +927,52 @@ type Operation struct {
 	// of -1 can unset the server's timeout.
 	BodyReadTimeout time.Duration `yaml:"-"`
 
+	// MaxDecompressedBodyBytes is the maximum number of bytes of a compressed
+	// request body after it has been decompressed, see
+	// `Config.DecompressRequests`. If not specified, `MaxBodyBytes` is used.
+	// Use -1 for unlimited. If the limit is reached, then an HTTP 413 error is
+	// returned.
+	MaxDecompressedBodyBytes int64 `yaml:"-"`
+
 	// Errors is a list of HTTP status codes that the handler may return. If
 	// not specified, then a default error response is added to the OpenAPI.
 	// This is a convenience for handlers that return a fixed set of errors
 	// where you do not wish to provide each one as an OpenAPI response object.
 	// Each error specified here is expanded into a response object with the
-	// schema generated from the type returned by `huma.NewError()`
-	// or `huma.NewErrorWithContext`.
+	// schema generated from the type returned by `Operation.NewError`,
+	// `Config.NewError`, or `huma.NewError()`.
 	Errors []int `yaml:"-"`
 
+	// ErrorCodes documents the machine-readable error codes (see
+	// `ErrorWithCode`) which may be returned for each error status code. The
+	// statuses are added to `Errors` and the codes are listed as an enum on
+	// the error response schema.
+	//
+	//	ErrorCodes: map[int][]string{
+	//		http.StatusNotFound: {"USER_NOT_FOUND", "ORG_NOT_FOUND"},
+	//	}
+	ErrorCodes map[int][]string `yaml:"-"`
+
+	// ErrorBodies maps error status codes to custom error types, which are
+	// documented as the response schema for that status instead of the
+	// default error model. The statuses are added to `Errors`. Each value must
+	// implement `error` so that handlers can return it directly, and it is
+	// then written with the mapped status code.
+	//
+	//	ErrorBodies: map[int]any{
+	//		http.StatusNotFound: &NotFoundError{},
+	//		http.StatusConflict: &ConflictError{},
+	//	}
+	ErrorBodies map[int]any `yaml:"-"`
+
+	// NewError creates the errors written for this operation, overriding
+	// `Config.NewError` and the global `huma.NewError`. Errors built with the
+	// default `*ErrorModel`, e.g. via `huma.Error404NotFound`, which are
+	// returned by the handler are converted with it too, keeping their
+	// status, detail, and error details. It is called with a
+	// `nil` context and zero status when generating the error schema.
+	NewError func(ctx Context, status int, msg string, errs ...error) StatusError `yaml:"-"`
+
 	// SkipValidateParams disables validation of path, query, and header
 	// parameters. This can speed up request processing if you want to handle
 	// your own validation. Use with caution!
@@ -877,6 +983,86 @@ type Operation struct {
 	// caution!
 	SkipValidateBody bool `yaml:"-"`
 
+	// ApplyResponseDefaults sets the `default` tag value on each zero-valued
+	// field of the response body before it is serialized, which is useful when
+	// handlers return sparse structs. Note that this modifies the returned body.
+	ApplyResponseDefaults bool `yaml:"-"`
+
+	// Enabled optionally decides for each request whether the operation is
+	// available, e.g. by checking a feature flag, so that operations can be
+	// dark-launched. It runs after the API's middleware (e.g. authentication)
+	// but before the operation's own middleware. When it returns false, a
+	// `DisabledStatus` error is returned instead of calling the handler.
+	Enabled func(ctx Context) bool `yaml:"-"`
+
+	// DisabledStatus is the error status code returned when `Enabled` returns
+	// false. Defaults to `404 Not Found`, making the operation indistinguishable
+	// from a missing one. `501 Not Implemented` is another common choice.
+	DisabledStatus int `yaml:"-"`
+
+	// ServerTiming sends a `Server-Timing` header with the time spent in each
+	// phase of handling the request for this operation. See
+	// `Config.ServerTiming` to enable it for all operations.
+	ServerTiming bool `yaml:"-"`
+
+	// Prefer enables handling of the `Prefer` request header (RFC 7240), which
+	// is documented as a parameter. Handlers can read the parsed preferences
+	// via `huma.PreferencesFromContext`. When a client sends
+	// `return=minimal`, successful responses are sent without a body, with
+	// `200 OK` becoming `204 No Content`, and the `Preference-Applied` header
+	// is set. Streaming responses are always sent in full.
+	Prefer bool `yaml:"-"`
+
+	// MaxRequestTimeout enables deadline propagation when set. Clients can send
+	// an `X-Request-Timeout` header with a Go duration or number of seconds, or
+	// a gRPC-style `grpc-timeout` header, to set a deadline on the handler's
+	// context. The timeout is bounded by this maximum, which is also used when
+	// neither header is sent. If the deadline is exceeded and the handler
+	// returns an error, an HTTP 504 error is returned.
+	MaxRequestTimeout time.Duration `yaml:"-"`
+
+	// VerifyDigest checks the request body as received against the checksums
+	// sent in the `Content-MD5`, `Digest` (RFC 3230), or `Content-Digest`
+	// (RFC 9530) headers before it is decompressed, parsed, or validated,
+	// returning a `400 Bad Request` error on mismatch. Requests without these
+	// headers are not checked. The headers are documented as parameters.
+	VerifyDigest bool `yaml:"-"`
+
+	// Trailers lists the names of trailer headers sent after streamed response
+	// bodies, e.g. checksums set via `huma.SetTrailer`. They are announced in
+	// the `Trailer` response header and documented on successful responses via
+	// an `x-trailers` extension.
+	Trailers []string `yaml:"-"`
+
+	// Consumes lists the request body content types, replacing the default of
+	// `application/json`. Each is documented with the body's schema. A format
+	// must be registered for each type so the body can be unmarshaled, see
+	// `Config.Formats`.
+	Consumes []string `yaml:"-"`
+
+	// Produces lists the response body content types, replacing the default of
+	// `application/json`. Each is documented with the body's schema and the
+	// response content type is negotiated between them using the `Accept`
+	// header, defaulting to the first. A format must be registered for each
+	// type so the body can be marshaled, see `Config.Formats`.
+	Produces []string `yaml:"-"`
+
+	// ResponseVersions are alternative versions of the success response body,
+	// keyed by the value of the `version` media type parameter a client sends
+	// in the `Accept` header, e.g. `application/json; version=2`. Each is
+	// documented as a separate content entry. Requests without a matching
+	// version get the handler's unversioned response body.
+	ResponseVersions map[string]ResponseVersion `yaml:"-"`
+
+	// SelectVersion optionally returns the response version for a request,
+	// e.g. from a custom header, replacing the default of reading the
+	// `version` parameter from the `Accept` header.
+	SelectVersion func(ctx Context) string `yaml:"-"`
+
+	// HideWhenDisabled removes the operation from the OpenAPI document served
+	// to clients for which `Enabled` returns false.
+	HideWhenDisabled bool `yaml:"-"`
+
 	// Hidden will skip documenting this operation in the OpenAPI. This is
 	// useful for operations that are not intended to be used by clients but
 	// you'd still like the benefits of using Huma. Generally not recommended.
@@ -892,6 +1078,18 @@ type Operation struct {
 	// authentication, or rate limiting.
 	Middlewares Middlewares `yaml:"-"`
 
+	// Transformers is a list of response transformers to run for this
+	// operation only. They run after the API's global transformers (or those
+	// listed in `TransformerOrder`, if set).
+	Transformers []Transformer `yaml:"-"`
+
+	// TransformerOrder overrides the API's global transformers for this
+	// operation with the named transformers from `Config.NamedTransformers`,
+	// run in the given order. Set it to an empty slice to skip all global
+	// transformers. When using `huma.DefaultConfig`, the schema link
+	// transformer is available as `schemaLink`.
+	TransformerOrder []string `yaml:"-"`
+
 	// --- OpenAPI fields ---
 
 	// Tags is a list of tags for API documentation control. Tags can be used for
@@ -970,6 +1168,14 @@ type Operation struct {
 	// declaration, an empty array can be used.
 	Security []map[string][]string `yaml:"security,omitempty"`
 
+	// RequiredScopes is a list of scopes (e.g. OAuth2 scopes) which a caller must
+	// have been granted to call this operation. They are added to each of the
+	// operation's security requirements (or the API's, if none are set) in the
+	// generated OpenAPI and a 403 error response is documented. Auth middleware
+	// can read them via `huma.ScopesFromContext` and enforce them via
+	// `huma.EnforceScopes`.
+	RequiredScopes []string `yaml:"-"`
+
 	// Servers is an alternative server array to service this operation. If an
 	// alternative server object is specified at the Path Item Object or Root
 	// level, it will be overridden by this value.
@@ -997,6 +1203,11 @@ func (o *Operation) MarshalJSON() ([]byte, error) {
 	}, o.Extensions)
 }
 
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	type alias Operation
+	return unmarshalJSON(data, (*alias)(o), nil, &o.Extensions)
+}
+
 // PathItem describes the operations available on a single path. A Path Item MAY
 // be empty, due to ACL constraints. The path itself is still exposed to the
 // documentation viewer but they will not know which operations and parameters
@@ -1104,6 +1315,11 @@ func (p *PathItem) MarshalJSON() ([]byte, error) {
 	}, p.Extensions)
 }
 
+func (p *PathItem) UnmarshalJSON(data []byte) error {
+	type alias PathItem
+	return unmarshalJSON(data, (*alias)(p), &p.Ref, &p.Extensions)
+}
+
 // OAuthFlow stores configuration details for a supported OAuth Flow.
 //
 //	type: oauth2
@@ -1372,6 +1588,25 @@ func (c *Components) MarshalJSON() ([]byte, error) {
 	}, c.Extensions)
 }
 
+// UnmarshalJSON unmarshals the components, loading the schemas into a new
+// registry so they can be looked up by reference.
+func (c *Components) UnmarshalJSON(data []byte) error {
+	type alias Components
+	var v struct {
+		alias
+		Schemas map[string]*Schema `json:"schemas"`
+	}
+	if err := unmarshalJSON(data, &v, nil, &v.Extensions); err != nil {
+		return err
+	}
+	*c = Components(v.alias)
+	c.Schemas = NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+	for name, s := range v.Schemas {
+		c.Schemas.Map()[name] = s
+	}
+	return nil
+}
+
 // ExternalDocs allows referencing an external resource for extended
 // documentation.
 //
@@ -1534,6 +1769,54 @@ func (o *OpenAPI) AddOperation(op *Operation) {
 	}
 }
 
+// FilterOperations returns a shallow copy of the document containing only the
+// operations for which `keep` returns true. Paths without any remaining
+// operations are removed, as are tags which were only used by removed
+// operations. The original document is not modified.
+//
+//	public := api.OpenAPI().FilterOperations(func(op *huma.Operation) bool {
+//		return !slices.Contains(op.Tags, "Internal")
+//	})
+func (o *OpenAPI) FilterOperations(keep func(op *Operation) bool) *OpenAPI {
+	c := *o
+	c.Paths = make(map[string]*PathItem, len(o.Paths))
+	used := map[string]bool{}
+	removed := map[string]bool{}
+	for p, item := range o.Paths {
+		ic := *item
+		empty := true
+		for _, op := range []**Operation{&ic.Get, &ic.Put, &ic.Post, &ic.Delete, &ic.Options, &ic.Head, &ic.Patch, &ic.Trace} {
+			if *op == nil {
+				continue
+			}
+			if !keep(*op) {
+				for _, tag := range (*op).Tags {
+					removed[tag] = true
+				}
+				*op = nil
+				continue
+			}
+			for _, tag := range (*op).Tags {
+				used[tag] = true
+			}
+			empty = false
+		}
+		if !empty {
+			c.Paths[p] = &ic
+		}
+	}
+
+	if len(o.Tags) > 0 {
+		c.Tags = make([]*Tag, 0, len(o.Tags))
+		for _, tag := range o.Tags {
+			if used[tag.Name] || !removed[tag.Name] {
+				c.Tags = append(c.Tags, tag)
+			}
+		}
+	}
+	return &c
+}
+
 func (o *OpenAPI) MarshalJSON() ([]byte, error) {
 	return marshalJSON([]jsonFieldInfo{
 		{"openapi", o.OpenAPI, omitNever},
@@ -1549,6 +1832,11 @@ func (o *OpenAPI) MarshalJSON() ([]byte, error) {
 	}, o.Extensions)
 }
 
+func (o *OpenAPI) UnmarshalJSON(data []byte) error {
+	type alias OpenAPI
+	return unmarshalJSON(data, (*alias)(o), nil, &o.Extensions)
+}
+
 // YAML returns the OpenAPI represented as YAML without needing to include a
 // library to serialize YAML.
 func (o *OpenAPI) YAML() ([]byte, error) {
@@ -0,0 +1,60 @@
+package huma
+
+import "context"
+
+// Params holds the parsed parameter values of the current request, keyed by
+// location (`path`, `query`, `header`, or `cookie`) and then name. Values
+// have the Go type of their input struct field, e.g. `int` or `time.Time`.
+type Params map[string]map[string]any
+
+// Get returns the parsed value of the parameter with the given location and
+// name, and whether it was present in the request (or had a default).
+func (p Params) Get(in, name string) (any, bool) {
+	v, ok := p[in][name]
+	return v, ok
+}
+
+func (p Params) set(in, name string, value any) {
+	if p[in] == nil {
+		p[in] = map[string]any{}
+	}
+	p[in][name] = value
+}
+
+type paramsKey struct{}
+
+// ParamsFromContext returns the parsed parameters of the current request
+// within resolvers, or nil elsewhere. Together with `ctx.Operation()` this
+// allows cross-field validation involving e.g. path params without needing
+// to parse the URL again.
+//
+//	func (b *MyBody) Resolve(ctx huma.Context) []error {
+//		id, _ := huma.ParamsFromContext(ctx.Context()).Get("path", "id")
+//		if b.ID != id {
+//			return []error{&huma.ErrorDetail{
+//				Location: "body.id",
+//				Message:  "must match the path ID",
+//				Value:    b.ID,
+//			}}
+//		}
+//		return nil
+//	}
+func ParamsFromContext(ctx context.Context) Params {
+	p, _ := ctx.Value(paramsKey{}).(Params)
+	return p
+}
+
+// ParamValue returns the parsed value of the parameter with the given
+// location and name from the context, and whether it was present with the
+// requested type. See `ParamsFromContext`.
+//
+//	id, ok := huma.ParamValue[int](ctx.Context(), "path", "id")
+func ParamValue[T any](ctx context.Context, in, name string) (T, bool) {
+	v, ok := ParamsFromContext(ctx).Get(in, name)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	t, ok := v.(T)
+	return t, ok
+}
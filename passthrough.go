@@ -0,0 +1,67 @@
+package huma
+
+import (
+	"net/url"
+	"reflect"
+)
+
+// passthroughParams captures the query params which are not declared by the
+// input struct into its `PassthroughParams` field.
+type passthroughParams struct {
+	index    []int
+	typ      reflect.Type
+	declared map[string]bool
+}
+
+// findPassthroughParams returns how to capture undeclared query params for
+// the input type, or nil if it has no `PassthroughParams` field. The field
+// is documented as a free-form query param object.
+func findPassthroughParams(inputType reflect.Type, op *Operation, params *findResult[*paramFieldInfo]) *passthroughParams {
+	f, ok := inputType.FieldByName("PassthroughParams")
+	if !ok {
+		return nil
+	}
+	if f.Type.Kind() != reflect.Map || !reflect.TypeOf(url.Values{}).ConvertibleTo(f.Type) {
+		panic("PassthroughParams must be a map[string][]string")
+	}
+
+	pt := &passthroughParams{index: f.Index, typ: f.Type, declared: map[string]bool{}}
+	for _, p := range params.Paths {
+		if p.Value.Loc == "query" {
+			pt.declared[p.Value.Name] = true
+			for _, alias := range p.Value.Aliases {
+				pt.declared[alias] = true
+			}
+		}
+	}
+
+	description := f.Tag.Get("doc")
+	if description == "" {
+		description = "Additional query params which are passed through as-is."
+	}
+	explode := true
+	op.Parameters = append(op.Parameters, &Param{
+		Name:        "params",
+		In:          "query",
+		Description: description,
+		Style:       "form",
+		Explode:     &explode,
+		Schema: &Schema{
+			Type:                 TypeObject,
+			AdditionalProperties: &Schema{Type: TypeString},
+		},
+	})
+	return pt
+}
+
+// set captures the undeclared query params of the request into the input.
+func (pt *passthroughParams) set(ctx Context, v reflect.Value) {
+	u := ctx.URL()
+	values, _ := url.ParseQuery(u.RawQuery)
+	for name := range values {
+		if pt.declared[name] {
+			delete(values, name)
+		}
+	}
+	v.FieldByIndex(pt.index).Set(reflect.ValueOf(values).Convert(pt.typ))
+}
@@ -0,0 +1,149 @@
+package huma
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Precompute does work which would otherwise happen lazily on the first
+// requests, so that latency-sensitive services can pay for it at startup
+// instead. It should be called once all operations have been registered and
+// before the server starts. It:
+//
+//   - Checks that all schema refs resolve, loading any external schemas.
+//   - Compiles schema patterns and precomputes validation messages.
+//   - Compiles the path patterns used for `405 Method Not Allowed` responses.
+//   - Renders the cached OpenAPI documents and their compressed variants.
+//   - Locks the default schema registry, so registering new types panics.
+//
+// Errors which would otherwise only surface when a request comes in, like
+// an invalid pattern, are returned together.
+//
+//	if err := huma.Precompute(api); err != nil {
+//		log.Fatal(err)
+//	}
+//	http.ListenAndServe(":8888", router)
+func Precompute(api API) error {
+	a := apiOf(api)
+	oapi := api.OpenAPI()
+	registry := oapi.Components.Schemas
+	var errs []error
+
+	if registry != nil {
+		schemas := registry.Map()
+		names := make([]string, 0, len(schemas))
+		for name := range schemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			errs = append(errs, precomputeSchema(registry, "schema "+name, schemas[name])...)
+		}
+	}
+
+	templates := make([]string, 0, len(oapi.Paths))
+	for template := range oapi.Paths {
+		templates = append(templates, template)
+	}
+	sort.Strings(templates)
+	for _, op := range a.routes {
+		// Includes hidden operations, which are served but not documented.
+		pathPattern(a.config.BasePath + op.Path)
+	}
+	for _, template := range templates {
+		item := oapi.Paths[template]
+		for _, m := range []struct {
+			method string
+			op     *Operation
+		}{
+			{http.MethodGet, item.Get},
+			{http.MethodHead, item.Head},
+			{http.MethodPost, item.Post},
+			{http.MethodPut, item.Put},
+			{http.MethodPatch, item.Patch},
+			{http.MethodDelete, item.Delete},
+			{http.MethodOptions, item.Options},
+			{http.MethodTrace, item.Trace},
+		} {
+			if m.op != nil {
+				errs = append(errs, precomputeOperation(registry, m.method+" "+template, m.op)...)
+			}
+		}
+	}
+
+	for _, warm := range a.warmers {
+		if err := warm(); err != nil {
+			errs = append(errs, fmt.Errorf("unable to render cached document: %w", err))
+		}
+	}
+
+	if r, ok := registry.(*mapRegistry); ok {
+		r.locked = true
+	}
+	return errors.Join(errs...)
+}
+
+// precomputeOperation precomputes the schemas of the operation's params,
+// request body, and responses.
+func precomputeOperation(registry Registry, where string, op *Operation) []error {
+	var errs []error
+	for _, p := range op.Parameters {
+		errs = append(errs, precomputeSchema(registry, where+" "+p.In+" param "+p.Name, p.Schema)...)
+	}
+	if op.RequestBody != nil {
+		for ct, mt := range op.RequestBody.Content {
+			errs = append(errs, precomputeSchema(registry, where+" request body "+ct, mt.Schema)...)
+		}
+	}
+	for status, resp := range op.Responses {
+		for ct, mt := range resp.Content {
+			errs = append(errs, precomputeSchema(registry, where+" response "+status+" "+ct, mt.Schema)...)
+		}
+		for name, h := range resp.Headers {
+			if h != nil {
+				errs = append(errs, precomputeSchema(registry, where+" response "+status+" header "+name, h.Schema)...)
+			}
+		}
+	}
+	// Map iteration order is random, so sort for stable output.
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errs
+}
+
+// precomputeSchema checks the refs & patterns of the schema and precomputes
+// its validation messages, returning any errors instead of panicking.
+func precomputeSchema(registry Registry, where string, s *Schema) []error {
+	if s == nil {
+		return nil
+	}
+	var errs []error
+	r, _ := registry.(*mapRegistry)
+	copySchema(s, func(c *Schema) {
+		if c.Pattern != "" {
+			if _, err := regexp.Compile(c.Pattern); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid pattern %q: %w", where, c.Pattern, err))
+			}
+		}
+		switch {
+		case c.Ref == "" || r == nil:
+		case isExternalRef(c.Ref):
+			if r.external == nil {
+				errs = append(errs, fmt.Errorf("%s: unresolved external ref %s", where, c.Ref))
+			} else if _, err := r.external.Resolve(c.Ref); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", where, err))
+			}
+		case strings.HasPrefix(c.Ref, r.prefix):
+			if r.SchemaFromRef(c.Ref) == nil {
+				errs = append(errs, fmt.Errorf("%s: unresolved ref %s", where, c.Ref))
+			}
+		}
+	})
+	if errs == nil {
+		s.PrecomputeMessages()
+	}
+	return errs
+}
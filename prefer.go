@@ -0,0 +1,90 @@
+package huma
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Preferences are the parsed preferences from the `Prefer` request header,
+// see RFC 7240. Keys are lowercase preference names like `return`, `wait`,
+// or `respond-async`, and values are empty for preferences without one.
+// Parameters on each preference are ignored.
+type Preferences map[string]string
+
+// Return returns the `return` preference, which is either `minimal`,
+// `representation`, or empty if not sent.
+func (p Preferences) Return() string {
+	return p["return"]
+}
+
+type preferencesKey struct{}
+
+// PreferencesFromContext returns the preferences sent by the client for
+// operations with `Prefer` enabled, or nil otherwise.
+//
+//	if huma.PreferencesFromContext(ctx).Return() == "minimal" {
+//		// Skip loading the related items for the response.
+//	}
+func PreferencesFromContext(ctx context.Context) Preferences {
+	p, _ := ctx.Value(preferencesKey{}).(Preferences)
+	return p
+}
+
+// ParsePreferences parses the values of one or more `Prefer` headers. When a
+// preference is sent more than once the first value is used.
+func ParsePreferences(headers ...string) Preferences {
+	prefs := Preferences{}
+	for _, header := range headers {
+		for _, pref := range strings.Split(header, ",") {
+			pref, _, _ = strings.Cut(pref, ";")
+			name, value, _ := strings.Cut(pref, "=")
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if _, ok := prefs[name]; ok {
+				continue
+			}
+			prefs[name] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	return prefs
+}
+
+// readPreferences parses all of the request's `Prefer` headers.
+func readPreferences(ctx Context) Preferences {
+	var headers []string
+	ctx.EachHeader(func(name, value string) {
+		if strings.EqualFold(name, "Prefer") {
+			headers = append(headers, value)
+		}
+	})
+	return ParsePreferences(headers...)
+}
+
+// applyReturnPreference sets the `Preference-Applied` header for successful
+// responses when the client sent a `return` preference, and returns whether
+// the body should be omitted because `return=minimal` was requested.
+func applyReturnPreference(ctx Context, prefs Preferences, status int) bool {
+	if status < 200 || status >= 300 {
+		return false
+	}
+	switch prefs.Return() {
+	case "minimal":
+		ctx.SetHeader("Preference-Applied", "return=minimal")
+		return true
+	case "representation":
+		ctx.SetHeader("Preference-Applied", "return=representation")
+	}
+	return false
+}
+
+// writeMinimal writes a response without its body for `return=minimal`. As
+// there is no content, `200 OK` becomes `204 No Content`.
+func writeMinimal(ctx Context, status int) {
+	if status == http.StatusOK {
+		status = http.StatusNoContent
+	}
+	ctx.SetStatus(status)
+}
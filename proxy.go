@@ -0,0 +1,250 @@
+package huma
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ProxyOptions configures how `Proxy` forwards requests to the upstream.
+type ProxyOptions struct {
+	// Client sends the upstream requests. Defaults to `http.DefaultClient`.
+	Client *http.Client
+
+	// Rewrite returns the upstream path for the request path, e.g. to strip
+	// a prefix or map to a legacy route. The result is appended to the path of
+	// the target URL. Defaults to the request path as-is.
+	Rewrite func(path string) string
+
+	// ModifyRequest optionally modifies the upstream request before it is
+	// sent, e.g. to add credentials. Returning an error responds with a
+	// `502 Bad Gateway` error.
+	ModifyRequest func(ctx Context, req *http.Request) error
+}
+
+// proxyBodyKey stores the raw request body in the request context so that
+// it can be forwarded as sent.
+type proxyBodyKey struct{}
+
+// proxyBodyContext replays a request body which was already read.
+type proxyBodyContext struct {
+	humaContext
+	body []byte
+}
+
+func (c proxyBodyContext) Unwrap() Context {
+	return c.humaContext
+}
+
+func (c proxyBodyContext) BodyReader() io.Reader {
+	return bytes.NewReader(c.body)
+}
+
+// hopHeaders are hop-by-hop headers which only apply to a single connection
+// and must not be forwarded, see RFC 9110 section 7.6.1. Headers listed in
+// the `Connection` header are hop-by-hop as well.
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// removeHopHeaders removes hop-by-hop headers, including any which are listed
+// in the `Connection` header.
+func removeHopHeaders(h http.Header) {
+	for _, v := range h.Values("Connection") {
+		for _, name := range strings.Split(v, ",") {
+			if name = textproto.TrimString(name); name != "" {
+				h.Del(name)
+			}
+		}
+	}
+	for _, name := range hopHeaders {
+		h.Del(name)
+	}
+}
+
+// upstreamURL returns the URL to forward a request to, which is the path
+// appended to the target path, with the query merged into the target query.
+func upstreamURL(target *url.URL, path, rawQuery string) string {
+	u := *target
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+	u.RawPath = ""
+	if rawQuery != "" {
+		if u.RawQuery != "" {
+			u.RawQuery += "&"
+		}
+		u.RawQuery += rawQuery
+	}
+	return u.String()
+}
+
+// copyHeaders copies the incoming request headers to h, except for
+// hop-by-hop headers.
+func copyHeaders(ctx Context, h http.Header) {
+	ctx.EachHeader(func(name, value string) {
+		h.Add(name, value)
+	})
+	removeHopHeaders(h)
+}
+
+// setForwardedHeaders replaces the `Host` header with `X-Forwarded-*` headers
+// for a request sent to an upstream.
+func setForwardedHeaders(ctx Context, h http.Header) {
+	h.Del("Host")
+	if host := ctx.Host(); host != "" {
+		h.Set("X-Forwarded-Host", host)
+	}
+	if ip, _, err := net.SplitHostPort(ctx.RemoteAddr()); err == nil {
+		h.Add("X-Forwarded-For", ip)
+	}
+}
+
+// Proxy registers an operation which forwards requests to an upstream
+// service, e.g. for a façade API wrapping a legacy backend. The input type
+// `I` is parsed and validated like for any other operation, so invalid
+// requests are rejected without reaching the upstream, and valid ones are
+// forwarded as sent. The upstream response is streamed back as-is, while the
+// output type `O` is only used to document it.
+//
+//	target, _ := url.Parse("http://legacy.internal/api")
+//	huma.Proxy[GetUserInput, GetUserOutput](api, huma.Operation{
+//		OperationID: "get-user",
+//		Method:      http.MethodGet,
+//		Path:        "/users/{id}",
+//	}, target, &huma.ProxyOptions{
+//		Rewrite: func(path string) string {
+//			return strings.Replace(path, "/users/", "/v1/user/", 1)
+//		},
+//	})
+//
+// Upstream errors result in a `502 Bad Gateway` error.
+func Proxy[I, O any](api API, op Operation, target *url.URL, opts *ProxyOptions) {
+	if opts == nil {
+		opts = &ProxyOptions{}
+	}
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	// Document the declared output. The actual response is streamed.
+	outputType := reflect.TypeOf((*O)(nil)).Elem()
+	if outputType.Kind() != reflect.Struct {
+		panic("output must be a struct")
+	}
+	initResponses(&op)
+	processOutputType(outputType, &op, api.OpenAPI().Components.Schemas)
+	if !slices.Contains(op.Errors, http.StatusBadGateway) {
+		op.Errors = append(op.Errors, http.StatusBadGateway)
+	}
+
+	// Read the body before it gets parsed, so it can be forwarded as sent
+	// even if it was compressed or the input has no body.
+	maxBytes := op.MaxBodyBytes
+	if maxBytes == 0 {
+		maxBytes = 1024 * 1024
+	}
+	op.Middlewares = append(Middlewares{func(ctx Context, next func(Context)) {
+		reader := ctx.BodyReader()
+		if reader == nil {
+			reader = bytes.NewReader(nil)
+		}
+		if maxBytes > 0 {
+			reader = io.LimitReader(reader, maxBytes+1)
+		}
+		body, err := io.ReadAll(reader)
+		if err != nil {
+			WriteErr(api, ctx, http.StatusBadRequest, "cannot read request body", err)
+			return
+		}
+		if maxBytes > 0 && int64(len(body)) > maxBytes {
+			WriteErr(api, ctx, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body is too large limit=%d bytes", maxBytes))
+			return
+		}
+		next(proxyBodyContext{humaContext: WithValue(ctx, proxyBodyKey{}, body), body: body})
+	}}, op.Middlewares...)
+
+	Register(api, op, func(c context.Context, input *I) (*StreamResponse, error) {
+		body, _ := c.Value(proxyBodyKey{}).([]byte)
+		return &StreamResponse{
+			Body: func(ctx Context) {
+				forwardRequest(api, ctx, client, target, opts, body)
+			},
+		}, nil
+	})
+}
+
+// forwardRequest sends the request to the upstream and streams back its
+// response.
+func forwardRequest(api API, ctx Context, client *http.Client, target *url.URL, opts *ProxyOptions, body []byte) {
+	reqURL := ctx.URL()
+	path := reqURL.Path
+	if opts.Rewrite != nil {
+		path = opts.Rewrite(path)
+	}
+	u := upstreamURL(target, path, reqURL.RawQuery)
+
+	req, err := http.NewRequestWithContext(ctx.Context(), ctx.Method(), u, bytes.NewReader(body))
+	if err != nil {
+		WriteErr(api, ctx, http.StatusBadGateway, "cannot create upstream request", err)
+		return
+	}
+	copyHeaders(ctx, req.Header)
+	setForwardedHeaders(ctx, req.Header)
+	if opts.ModifyRequest != nil {
+		if err := opts.ModifyRequest(ctx, req); err != nil {
+			WriteErr(api, ctx, http.StatusBadGateway, "cannot create upstream request", err)
+			return
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		WriteErr(api, ctx, http.StatusBadGateway, "upstream request failed", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	removeHopHeaders(resp.Header)
+	for name, values := range resp.Header {
+		for _, v := range values {
+			ctx.AppendHeader(name, v)
+		}
+	}
+	ctx.SetStatus(resp.StatusCode)
+
+	// Flush as data arrives so that streaming upstream responses like
+	// server-sent events are not buffered.
+	w := ctx.BodyWriter()
+	flusher, _ := w.(http.Flusher)
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
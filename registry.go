@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -25,10 +26,16 @@ type Registry interface {
 // DefaultSchemaNamer provides schema names for types. It uses the type name
 // when possible, ignoring the package name. If the type is generic, e.g.
 // `MyType[SubType]`, then the brackets are removed like `MyTypeSubType`.
+// Type parameters are named recursively, so `Page[map[string][]*User]`
+// becomes `PageMapStringListUser`, while anonymous structs, interfaces, and
+// funcs become `Struct`, `Any` (or `Interface`), and `Func`.
 // If the type is unnamed, then the name hint is used.
 // Note: if you plan to use types with the same name from different packages,
-// you should implement your own namer function to prevent issues. Nested
-// anonymous types can also present naming issues.
+// you should implement your own namer function to prevent issues. When using
+// this namer, the registry qualifies the type parameters of colliding generic
+// types with their package name, e.g. `PageModelsUser`. The first type to be
+// registered keeps the unqualified name, so names only change if the order of
+// registration does. Nested anonymous types can also present naming issues.
 func DefaultSchemaNamer(t reflect.Type, hint string) string {
 	name := deref(t).Name()
 
@@ -36,11 +43,16 @@ func DefaultSchemaNamer(t reflect.Type, hint string) string {
 		name = hint
 	}
 
-	// Better support for lists, so e.g. `[]int` becomes `ListInt`.
-	name = strings.ReplaceAll(name, "[]", "List[")
+	return genericSchemaName(name, false)
+}
 
+// genericSchemaName converts a Go type name, which may include type
+// parameters, into a schema name. When `qualified` is true the package name
+// is included for each type parameter to disambiguate types with the same
+// name from different packages.
+func genericSchemaName(name string, qualified bool) string {
 	result := ""
-	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+	for i, part := range strings.FieldsFunc(simplifyTypeName(name), func(r rune) bool {
 		// Split on special characters. Note that `,` is used when there are
 		// multiple inputs to a generic type.
 		return r == '[' || r == ']' || r == '*' || r == ','
@@ -49,14 +61,103 @@ func DefaultSchemaNamer(t reflect.Type, hint string) string {
 		fqn := strings.Split(part, ".")
 		base := fqn[len(fqn)-1]
 
+		if qualified && i > 0 && len(fqn) > 1 {
+			// Use the last path component of the package, e.g. `bar`.
+			pkg := part[:strings.LastIndex(part, ".")]
+			base = pkg[strings.LastIndex(pkg, "/")+1:] + upperFirst(base)
+		}
+
+		// Remove the suffix Go adds to types declared in functions, like
+		// `Thing·1`, and any other non-identifier characters.
+		if idx := strings.IndexRune(base, '·'); idx >= 0 {
+			base = base[:idx]
+		}
+		base = strings.Map(func(r rune) rune {
+			if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+			return -1
+		}, base)
+
 		// Add to result, and uppercase for better scalar support (`int` -> `Int`).
 		// Use unicode-aware uppercase to support non-ASCII characters.
-		r, size := utf8.DecodeRuneInString(base)
-		result += strings.ToUpper(string(r)) + base[size:]
+		result += upperFirst(base)
 	}
-	name = result
 
-	return name
+	return result
+}
+
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return strings.ToUpper(string(r)) + s[size:]
+}
+
+// simplifyTypeName rewrites the parts of a type name which are not simple
+// identifiers. Slices and arrays become `List[...`, and anonymous structs,
+// interfaces and funcs, whose names may contain spaces, braces, and quoted
+// struct tags, are replaced by a single identifier.
+func simplifyTypeName(name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); {
+		rest := name[i:]
+		switch {
+		case strings.HasPrefix(rest, "[]"):
+			b.WriteString("List[")
+			i += 2
+		case rest[0] == '[' && len(rest) > 1 && rest[1] >= '0' && rest[1] <= '9':
+			// Fixed-size array like `[3]int`.
+			b.WriteString("List[")
+			i += strings.IndexByte(rest, ']') + 1
+		case strings.HasPrefix(rest, "interface {}"):
+			b.WriteString("Any")
+			i += len("interface {}")
+		case strings.HasPrefix(rest, "interface {"):
+			b.WriteString("Interface")
+			i += skipBalanced(rest, len("interface "))
+		case strings.HasPrefix(rest, "struct {"):
+			b.WriteString("Struct")
+			i += skipBalanced(rest, len("struct "))
+		case strings.HasPrefix(rest, "func("):
+			b.WriteString("Func")
+			i += skipBalanced(rest, len("func"))
+			// Skip the results, which end at the next type parameter.
+			for i < len(name) && name[i] != ',' && name[i] != ']' {
+				if name[i] == '(' {
+					i += skipBalanced(name[i:], 0)
+					continue
+				}
+				i++
+			}
+		default:
+			b.WriteByte(name[i])
+			i++
+		}
+	}
+	return b.String()
+}
+
+// skipBalanced returns the offset just past the brace or paren group which
+// starts at `start`, skipping over quoted strings like struct tags.
+func skipBalanced(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			for i++; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+		case '{', '(':
+			depth++
+		case '}', ')':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(s)
 }
 
 type mapRegistry struct {
@@ -66,6 +167,16 @@ type mapRegistry struct {
 	seen    map[reflect.Type]bool
 	namer   func(reflect.Type, string) string
 	aliases map[reflect.Type]reflect.Type
+
+	// defaultNamer is set when using `DefaultSchemaNamer`, in which case
+	// colliding generic types are disambiguated. Custom namers are expected
+	// to produce unique names themselves.
+	defaultNamer bool
+
+	flattenAllOf bool
+	examples     bool
+	external     *ExternalSchemas
+	locked       bool
 }
 
 func (r *mapRegistry) Schema(t reflect.Type, allowRef bool, hint string) *Schema {
@@ -102,6 +213,13 @@ func (r *mapRegistry) Schema(t reflect.Type, allowRef bool, hint string) *Schema
 	name := r.namer(origType, hint)
 
 	if getsRef {
+		if r.defaultNamer && r.types[name] != nil && r.types[name] != t && strings.Contains(t.Name(), "[") {
+			// Generic types like `Page[a.User]` and `Page[b.User]` get the same
+			// name, so qualify the type parameters with their package names.
+			if qualified := genericSchemaName(t.Name(), true); r.types[qualified] == nil || r.types[qualified] == t {
+				name = qualified
+			}
+		}
 		if s, ok := r.schemas[name]; ok {
 			if _, ok := r.seen[t]; !ok {
 				// Name matches but type is different, so we have a dupe.
@@ -117,11 +235,22 @@ func (r *mapRegistry) Schema(t reflect.Type, allowRef bool, hint string) *Schema
 
 	// First, register the type so refs can be created above for recursive types.
 	if getsRef {
+		if r.locked {
+			panic(fmt.Errorf("schema registry is locked, cannot add %s for type %s", name, t))
+		}
 		r.schemas[name] = &Schema{}
 		r.types[name] = t
 		r.seen[t] = true
 	}
 	s := SchemaFromType(r, origType)
+	if r.flattenAllOf {
+		s = r.flatten(s)
+	}
+	if r.examples && getsRef && len(s.Examples) == 0 {
+		if example, ok := r.example(s, map[string]bool{}).(map[string]any); ok {
+			s.Examples = []any{example}
+		}
+	}
 	if getsRef {
 		r.schemas[name] = s
 	}
@@ -134,12 +263,18 @@ func (r *mapRegistry) Schema(t reflect.Type, allowRef bool, hint string) *Schema
 
 func (r *mapRegistry) SchemaFromRef(ref string) *Schema {
 	if !strings.HasPrefix(ref, r.prefix) {
+		if r.external != nil && isExternalRef(ref) {
+			return r.external.Get(ref)
+		}
 		return nil
 	}
 	return r.schemas[ref[len(r.prefix):]]
 }
 
 func (r *mapRegistry) TypeFromRef(ref string) reflect.Type {
+	if !strings.HasPrefix(ref, r.prefix) {
+		return nil
+	}
 	return r.types[ref[len(r.prefix):]]
 }
 
@@ -155,20 +290,231 @@ func (r *mapRegistry) MarshalYAML() (interface{}, error) {
 	return r.schemas, nil
 }
 
+// SchemaBundle returns a self-contained copy of the named registry schema,
+// with all of the registry schemas it references copied into its `$defs` and
+// refs rewritten to point at them. Returns `nil` if the schema does not exist.
+//
+//	bundle := huma.SchemaBundle(api.OpenAPI().Components.Schemas, "Note")
+func SchemaBundle(r Registry, name string) *Schema {
+	schemas := r.Map()
+	root := schemas[name]
+	if root == nil {
+		return nil
+	}
+
+	defs := map[string]*Schema{}
+	var rewrite func(s *Schema) *Schema
+	rewrite = func(s *Schema) *Schema {
+		return copySchema(s, func(c *Schema) {
+			if c.Ref == "" || strings.HasPrefix(c.Ref, "#/$defs/") {
+				return
+			}
+			ref := c.Ref[strings.LastIndex(c.Ref, "/")+1:]
+			target := schemas[ref]
+			if target == nil || r.SchemaFromRef(c.Ref) != target {
+				// Not a registry schema, e.g. an external ref.
+				return
+			}
+			if ref == name {
+				// Recursive reference back to the root document.
+				c.Ref = "#"
+				return
+			}
+			c.Ref = "#/$defs/" + ref
+			if _, ok := defs[ref]; !ok {
+				// Reserve the name first so recursive types terminate.
+				defs[ref] = nil
+				defs[ref] = rewrite(target)
+			}
+		})
+	}
+
+	bundle := rewrite(root)
+	if len(defs) > 0 {
+		bundle.Defs = defs
+	}
+	return bundle
+}
+
+// copySchema returns a deep copy of the schema's structure, calling `fn` on
+// each copied (sub)schema so it can be modified without affecting the
+// original. Precomputed validation data is shared with the original.
+func copySchema(s *Schema, fn func(*Schema)) *Schema {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	c.Items = copySchema(s.Items, fn)
+	c.Not = copySchema(s.Not, fn)
+	c.PropertyNames = copySchema(s.PropertyNames, fn)
+	if ap, ok := s.AdditionalProperties.(*Schema); ok {
+		c.AdditionalProperties = copySchema(ap, fn)
+	}
+	if s.Properties != nil {
+		c.Properties = make(map[string]*Schema, len(s.Properties))
+		for k, v := range s.Properties {
+			c.Properties[k] = copySchema(v, fn)
+		}
+	}
+	if s.Defs != nil {
+		c.Defs = make(map[string]*Schema, len(s.Defs))
+		for k, v := range s.Defs {
+			c.Defs[k] = copySchema(v, fn)
+		}
+	}
+	for _, list := range []*[]*Schema{&c.OneOf, &c.AnyOf, &c.AllOf} {
+		if *list == nil {
+			continue
+		}
+		copied := make([]*Schema, len(*list))
+		for i, v := range *list {
+			copied[i] = copySchema(v, fn)
+		}
+		*list = copied
+	}
+	fn(&c)
+	return &c
+}
+
 // RegisterTypeAlias(t, alias) makes the schema generator use the `alias` type instead of `t`.
 func (r *mapRegistry) RegisterTypeAlias(t reflect.Type, alias reflect.Type) {
 	r.aliases[t] = alias
 }
 
+// flatten returns a copy of the schema with each `allOf` composition merged
+// into a single schema via `MergeSchemas`. References to registry schemas
+// are resolved first. Compositions which reference unknown schemas, or
+// schemas still being generated (i.e. recursive types), are left as-is.
+func (r *mapRegistry) flatten(s *Schema) *Schema {
+	return copySchema(s, func(c *Schema) {
+		if len(c.AllOf) == 0 {
+			return
+		}
+		subs := make([]*Schema, 0, len(c.AllOf))
+		for _, sub := range c.AllOf {
+			if sub.Ref != "" {
+				sub = r.SchemaFromRef(sub.Ref)
+				if sub == nil || (sub.Type == "" && sub.Properties == nil) {
+					return
+				}
+			}
+			subs = append(subs, sub)
+		}
+		// Merge in order, with the composing schema's own fields last so that
+		// they take precedence, e.g. for its title and description.
+		var merged *Schema
+		for _, sub := range subs {
+			merged = MergeSchemas(merged, sub)
+		}
+		base := *c
+		base.AllOf = nil
+		*c = *MergeSchemas(merged, &base)
+	})
+}
+
+// example synthesizes an example value for the schema from the examples and
+// defaults of it and its properties & items, or returns nil if there are none.
+// Refs are followed, except for recursive ones.
+func (r *mapRegistry) example(s *Schema, visited map[string]bool) any {
+	if s.Ref != "" {
+		if visited[s.Ref] {
+			return nil
+		}
+		resolved := r.SchemaFromRef(s.Ref)
+		if resolved == nil {
+			return nil
+		}
+		visited[s.Ref] = true
+		defer delete(visited, s.Ref)
+		s = resolved
+	}
+	if len(s.Examples) > 0 {
+		return s.Examples[0]
+	}
+	if s.Default != nil {
+		return s.Default
+	}
+	switch s.Type {
+	case TypeObject:
+		if ap, ok := s.AdditionalProperties.(*Schema); ok && len(s.Properties) == 0 {
+			if v := r.example(ap, visited); v != nil {
+				return map[string]any{"key": v}
+			}
+			return nil
+		}
+		example := map[string]any{}
+		for name, prop := range s.Properties {
+			if prop.hidden {
+				continue
+			}
+			if v := r.example(prop, visited); v != nil {
+				example[name] = v
+			}
+		}
+		if len(example) > 0 {
+			return example
+		}
+	case TypeArray:
+		if s.Items != nil {
+			if v := r.example(s.Items, visited); v != nil {
+				return []any{v}
+			}
+		}
+	}
+	return nil
+}
+
+// MapRegistryOption configures a registry created by `NewMapRegistry`.
+type MapRegistryOption func(r *mapRegistry)
+
+// MapRegistryFlattenAllOf makes the registry flatten `allOf` compositions in
+// generated schemas, e.g. from a `SchemaProvider` or `SchemaTransformer`,
+// into a single schema with deterministically merged properties & required
+// fields. See `MergeSchemas` for how the schemas are combined.
+//
+//	registry := huma.NewMapRegistry("#/components/schemas/",
+//		huma.DefaultSchemaNamer, huma.MapRegistryFlattenAllOf())
+func MapRegistryFlattenAllOf() MapRegistryOption {
+	return func(r *mapRegistry) {
+		r.flattenAllOf = true
+	}
+}
+
+// MapRegistryExamples makes the registry synthesize an example for each
+// named object schema which doesn't have one, combining the `example` and
+// `default` tags of its fields. Nested structs, slices, and maps are
+// included, so e.g. `[]Item` fields get an example item. The result is
+// available as `Schema.Examples[0]`.
+func MapRegistryExamples() MapRegistryOption {
+	return func(r *mapRegistry) {
+		r.examples = true
+	}
+}
+
+// MapRegistryExternalSchemas makes the registry look up external refs like
+// `https://example.com/schemas/thing.json` in the given external schemas, so
+// that request bodies using them can be validated. See `ExternalSchemas`.
+func MapRegistryExternalSchemas(external *ExternalSchemas) MapRegistryOption {
+	return func(r *mapRegistry) {
+		r.external = external
+	}
+}
+
 // NewMapRegistry creates a new registry that stores schemas in a map and
 // returns references to them using the given prefix.
-func NewMapRegistry(prefix string, namer func(t reflect.Type, hint string) string) Registry {
-	return &mapRegistry{
+func NewMapRegistry(prefix string, namer func(t reflect.Type, hint string) string, options ...MapRegistryOption) Registry {
+	r := &mapRegistry{
 		prefix:  prefix,
 		schemas: map[string]*Schema{},
 		types:   map[string]reflect.Type{},
 		seen:    map[reflect.Type]bool{},
 		aliases: map[reflect.Type]reflect.Type{},
 		namer:   namer,
+
+		defaultNamer: namer != nil && reflect.ValueOf(namer).Pointer() == reflect.ValueOf(DefaultSchemaNamer).Pointer(),
+	}
+	for _, option := range options {
+		option(r)
 	}
+	return r
 }
@@ -1,8 +1,10 @@
 package huma
 
 import (
+	"bytes"
 	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -46,6 +48,15 @@ func TestDefaultSchemaNamer(t *testing.T) {
 		{Output[Embedded[*time.Time]]{}, "OutputEmbeddedTime", ""},
 		{Output[*[]Embedded[time.Time]]{}, "OutputListEmbeddedTime", ""},
 		{Output[EmbeddedTwo[[]time.Time, **url.URL]]{}, "OutputEmbeddedTwoListTimeURL", ""},
+		{Output[[3]int]{}, "OutputListInt", ""},
+		{Output[any]{}, "OutputAny", ""},
+		{Output[error]{}, "OutputError", ""},
+		{Output[interface{ M() }]{}, "OutputInterface", ""},
+		{Output[struct {
+			A int `json:"a,omitempty" doc:"Some [doc], with {braces}"`
+		}]{}, "OutputStruct", ""},
+		{EmbeddedTwo[func(int) (string, error), S]{}, "EmbeddedTwoFuncS", ""},
+		{EmbeddedTwo[int, map[S][]Output[any]]{}, "EmbeddedTwoIntMapSListOutputAny", ""},
 		{Renamed{}, "Renamed", ""},
 		{struct{}{}, "SomeGenericThing", "Some[pkg.Generic]Thing"},
 		{struct{}{}, "Type1Type2Type3", "pkg1.Type1[path/to/pkg2.Type2]pkg3.Type3"},
@@ -57,6 +68,41 @@ func TestDefaultSchemaNamer(t *testing.T) {
 	}
 }
 
+func TestSchemaGenericCollision(t *testing.T) {
+	type Local struct{}
+
+	registry := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+
+	// Same type name from different packages.
+	assert.Equal(t, "#/components/schemas/OutputReader", registry.Schema(reflect.TypeOf(Output[bytes.Reader]{}), true, "").Ref)
+	assert.Equal(t, "#/components/schemas/OutputStringsReader", registry.Schema(reflect.TypeOf(Output[strings.Reader]{}), true, "").Ref)
+
+	// Lookups are stable.
+	assert.Equal(t, "#/components/schemas/OutputReader", registry.Schema(reflect.TypeOf(Output[bytes.Reader]{}), true, "").Ref)
+	assert.Equal(t, "#/components/schemas/OutputStringsReader", registry.Schema(reflect.TypeOf(Output[strings.Reader]{}), true, "").Ref)
+
+	// Types declared in functions drop the Go-generated suffix.
+	assert.Equal(t, "#/components/schemas/OutputLocal", registry.Schema(reflect.TypeOf(Output[Local]{}), true, "").Ref)
+
+	assert.Equal(t, reflect.TypeOf(Output[strings.Reader]{}), registry.TypeFromRef("#/components/schemas/OutputStringsReader"))
+
+	// Non-generic collisions still panic.
+	registry.Schema(reflect.TypeOf(Local{}), true, "")
+	assert.Panics(t, func() {
+		type Local struct{}
+		registry.Schema(reflect.TypeOf(Local{}), true, "")
+	})
+
+	// Custom namers are not overridden, so their collisions panic.
+	custom := NewMapRegistry("#/components/schemas/", func(t reflect.Type, hint string) string {
+		return "Custom" + DefaultSchemaNamer(t, hint)
+	})
+	assert.Equal(t, "#/components/schemas/CustomOutputReader", custom.Schema(reflect.TypeOf(Output[bytes.Reader]{}), true, "").Ref)
+	assert.PanicsWithError(t, "duplicate name: CustomOutputReader, new type: huma.Output[strings.Reader], existing type: huma.Output[bytes.Reader]", func() {
+		custom.Schema(reflect.TypeOf(Output[strings.Reader]{}), true, "")
+	})
+}
+
 func TestSchemaAlias(t *testing.T) {
 	type StringContainer struct {
 		Value string
@@ -73,3 +119,136 @@ func TestSchemaAlias(t *testing.T) {
 	schemaWithString := registry.Schema(reflect.TypeOf(StructWithString{}), false, "")
 	assert.Equal(t, schemaWithString, schemaWithContainer)
 }
+
+func TestSchemaBundle(t *testing.T) {
+	type Child struct {
+		Name string `json:"name"`
+	}
+	type Parent struct {
+		Child    Child   `json:"child"`
+		Children []Child `json:"children"`
+		Self     *Parent `json:"self,omitempty"`
+	}
+
+	registry := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+	registry.Schema(reflect.TypeOf(Parent{}), true, "")
+
+	bundle := SchemaBundle(registry, "Parent")
+	assert.Equal(t, "#/$defs/Child", bundle.Properties["child"].Ref)
+	assert.Equal(t, "#/$defs/Child", bundle.Properties["children"].Items.Ref)
+	assert.Equal(t, "#", bundle.Properties["self"].Ref)
+	assert.Contains(t, bundle.Defs, "Child")
+	assert.Len(t, bundle.Defs, 1)
+
+	// The original registry schema must be left untouched.
+	orig := registry.Map()["Parent"]
+	assert.Equal(t, "#/components/schemas/Child", orig.Properties["child"].Ref)
+	assert.Nil(t, orig.Defs)
+
+	assert.Nil(t, SchemaBundle(registry, "Missing"))
+}
+
+func TestMergeSchemas(t *testing.T) {
+	minLength := 1
+	a := &Schema{
+		Type:        TypeObject,
+		Description: "a",
+		Required:    []string{"id", "name"},
+		Properties: map[string]*Schema{
+			"id":   {Type: TypeString},
+			"name": {Type: TypeString},
+		},
+		Extensions: map[string]any{"x-a": true},
+	}
+	b := &Schema{
+		Description: "b",
+		Required:    []string{"name", "age"},
+		Properties: map[string]*Schema{
+			"name": {MinLength: &minLength},
+			"age":  {Type: TypeInteger},
+		},
+		Extensions: map[string]any{"x-b": true},
+	}
+
+	merged := MergeSchemas(a, b)
+	assert.Equal(t, TypeObject, merged.Type)
+	assert.Equal(t, "b", merged.Description)
+	assert.Equal(t, []string{"id", "name", "age"}, merged.Required)
+	assert.Len(t, merged.Properties, 3)
+	assert.Equal(t, TypeString, merged.Properties["name"].Type)
+	assert.Equal(t, 1, *merged.Properties["name"].MinLength)
+	assert.Equal(t, map[string]any{"x-a": true, "x-b": true}, merged.Extensions)
+	assert.True(t, merged.requiredMap["age"])
+
+	// Inputs must be left untouched.
+	assert.Equal(t, "a", a.Description)
+	assert.Len(t, a.Properties, 2)
+	assert.Nil(t, a.Properties["name"].MinLength)
+	assert.Equal(t, []string{"name", "age"}, b.Required)
+}
+
+type flattenBase struct {
+	ID string `json:"id"`
+}
+
+type flattenComposed struct {
+	Name string `json:"name,omitempty"`
+}
+
+func (flattenComposed) TransformSchema(r Registry, s *Schema) *Schema {
+	return &Schema{
+		Description: "A composed schema",
+		AllOf: []*Schema{
+			r.Schema(reflect.TypeOf(flattenBase{}), true, ""),
+			s,
+		},
+	}
+}
+
+func TestSchemaFlattenAllOf(t *testing.T) {
+	registry := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer, MapRegistryFlattenAllOf())
+	registry.Schema(reflect.TypeOf(flattenComposed{}), true, "")
+
+	s := registry.Map()["FlattenComposed"]
+	assert.Nil(t, s.AllOf)
+	assert.Equal(t, TypeObject, s.Type)
+	assert.Equal(t, "A composed schema", s.Description)
+	assert.Equal(t, []string{"id"}, s.Required)
+	assert.Contains(t, s.Properties, "id")
+	assert.Contains(t, s.Properties, "name")
+
+	// Without the option, the composition is left as-is.
+	registry = NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+	registry.Schema(reflect.TypeOf(flattenComposed{}), true, "")
+	assert.Len(t, registry.Map()["FlattenComposed"].AllOf, 2)
+}
+
+func TestSchemaExamples(t *testing.T) {
+	type Tag struct {
+		Name string `json:"name" example:"blue"`
+	}
+	type Thing struct {
+		ID       string         `json:"id" example:"abc123"`
+		Count    int            `json:"count" default:"5"`
+		Tags     []Tag          `json:"tags"`
+		Labels   map[string]Tag `json:"labels"`
+		Parent   *Thing         `json:"parent,omitempty"`
+		Internal string         `json:"internal"`
+	}
+
+	registry := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer, MapRegistryExamples())
+	registry.Schema(reflect.TypeOf(Thing{}), true, "")
+
+	assert.Equal(t, []any{map[string]any{"name": "blue"}}, registry.Map()["Tag"].Examples)
+	assert.Equal(t, []any{map[string]any{
+		"id":     "abc123",
+		"count":  5,
+		"tags":   []any{map[string]any{"name": "blue"}},
+		"labels": map[string]any{"key": map[string]any{"name": "blue"}},
+	}}, registry.Map()["Thing"].Examples)
+
+	// Without the option, no examples are synthesized.
+	registry = NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+	registry.Schema(reflect.TypeOf(Thing{}), true, "")
+	assert.Nil(t, registry.Map()["Thing"].Examples)
+}
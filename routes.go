@@ -0,0 +1,67 @@
+package huma
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// pathParamRe matches path params like `{id}` or `{id:[0-9]+}` so that paths
+// which only differ in param names are treated as the same route.
+var pathParamRe = regexp.MustCompile(`\{[^}]*\}`)
+
+// RouteConflictError is the panic value used by `Register` when an operation
+// would conflict with one which has already been registered, either because
+// both use the same method and path, or because both use the same operation
+// ID. Routers handle conflicts differently, so they are rejected up front.
+type RouteConflictError struct {
+	// Existing is the previously registered operation.
+	Existing *Operation
+
+	// Operation is the operation which failed to register.
+	Operation *Operation
+}
+
+func (e *RouteConflictError) Error() string {
+	if e.Existing.OperationID != "" && e.Existing.OperationID == e.Operation.OperationID {
+		return fmt.Sprintf("duplicate operation ID %q: %s conflicts with existing %s", e.Operation.OperationID, describeRoute(e.Operation), describeRoute(e.Existing))
+	}
+	return fmt.Sprintf("route conflict: %s conflicts with existing %s", describeRoute(e.Operation), describeRoute(e.Existing))
+}
+
+// describeRoute returns a human-readable description of an operation for use
+// in conflict errors.
+func describeRoute(op *Operation) string {
+	s := op.Method + " " + op.Path
+	if op.OperationID != "" {
+		s += fmt.Sprintf(" (operation %q)", op.OperationID)
+	}
+	return s
+}
+
+// routeKey returns the method and path with param names removed, e.g.
+// `GET /items/{}` for `GET /items/{id}`.
+func routeKey(op *Operation) string {
+	return strings.ToUpper(op.Method) + " " + pathParamRe.ReplaceAllString(op.Path, "{}")
+}
+
+// checkConflicts records the operation's route and ID, panicking with a
+// `*RouteConflictError` if either is already in use.
+func (a *api) checkConflicts(op *Operation) {
+	if a.routes == nil {
+		a.routes = map[string]*Operation{}
+	}
+
+	key := routeKey(op)
+	if existing := a.routes[key]; existing != nil {
+		panic(&RouteConflictError{Existing: existing, Operation: op})
+	}
+	if op.OperationID != "" {
+		idKey := "id " + op.OperationID
+		if existing := a.routes[idKey]; existing != nil {
+			panic(&RouteConflictError{Existing: existing, Operation: op})
+		}
+		a.routes[idKey] = op
+	}
+	a.routes[key] = op
+}
@@ -28,6 +28,17 @@ var ErrSchemaInvalid = errors.New("schema is invalid")
 // https://pkg.go.dev/encoding/json#Marshal.
 var DefaultArrayNullable = true
 
+// SchemaTagExtensions maps custom struct field tags to the OpenAPI extension
+// set on the field's schema with the tag's value, e.g. `unit:"seconds"`
+// results in `x-unit: seconds`. The `visibility` tag is used by the
+// `VisibilityTransformer`. Add entries to standardize your own annotations:
+//
+//	huma.SchemaTagExtensions["owner"] = "x-owner"
+var SchemaTagExtensions = map[string]string{
+	"unit":       "x-unit",
+	"visibility": "x-visibility",
+}
+
 // JSON Schema type constants
 const (
 	TypeBoolean = "boolean"
@@ -40,11 +51,12 @@ const (
 
 // Special JSON Schema formats.
 var (
-	timeType       = reflect.TypeOf(time.Time{})
-	ipType         = reflect.TypeOf(net.IP{})
-	ipAddrType     = reflect.TypeOf(netip.Addr{})
-	urlType        = reflect.TypeOf(url.URL{})
-	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	timeType            = reflect.TypeOf(time.Time{})
+	ipType              = reflect.TypeOf(net.IP{})
+	ipAddrType          = reflect.TypeOf(netip.Addr{})
+	urlType             = reflect.TypeOf(url.URL{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	rawMessageType      = reflect.TypeOf(json.RawMessage{})
 )
 
 func deref(t reflect.Type) reflect.Type {
@@ -98,11 +110,13 @@ type Schema struct {
 	Ref                  string              `yaml:"$ref,omitempty"`
 	Format               string              `yaml:"format,omitempty"`
 	ContentEncoding      string              `yaml:"contentEncoding,omitempty"`
+	ContentMediaType     string              `yaml:"contentMediaType,omitempty"`
 	Default              any                 `yaml:"default,omitempty"`
 	Examples             []any               `yaml:"examples,omitempty"`
 	Items                *Schema             `yaml:"items,omitempty"`
 	AdditionalProperties any                 `yaml:"additionalProperties,omitempty"`
 	Properties           map[string]*Schema  `yaml:"properties,omitempty"`
+	PropertyNames        *Schema             `yaml:"propertyNames,omitempty"`
 	Enum                 []any               `yaml:"enum,omitempty"`
 	Minimum              *float64            `yaml:"minimum,omitempty"`
 	ExclusiveMinimum     *float64            `yaml:"exclusiveMinimum,omitempty"`
@@ -130,6 +144,11 @@ type Schema struct {
 	AllOf []*Schema `yaml:"allOf,omitempty"`
 	Not   *Schema   `yaml:"not,omitempty"`
 
+	// Defs holds schemas which can be referenced via `#/$defs/{name}` from
+	// within this schema. It is typically only set on self-contained schemas
+	// produced by `SchemaBundle`.
+	Defs map[string]*Schema `yaml:"$defs,omitempty"`
+
 	// OpenAPI specific fields
 	Discriminator *Discriminator `yaml:"discriminator,omitempty"`
 
@@ -138,6 +157,18 @@ type Schema struct {
 	propertyNames []string        `yaml:"-"`
 	hidden        bool            `yaml:"-"`
 
+	// aliases maps alternative property names from `altNames` tags to the
+	// declared property names, see `Config.FieldAliases`.
+	aliases map[string]string `yaml:"-"`
+
+	// fileLimits are the `maxFileSize` & `maxFiles` limits of the file fields
+	// of a `huma.MultipartFormFiles` form schema, by form field name.
+	fileLimits map[string]fileLimits `yaml:"-"`
+
+	// enveloped is the original body schema when this schema is a response
+	// envelope created by the `EnvelopeTransformer`.
+	enveloped *Schema `yaml:"-"`
+
 	// Precomputed validation messages. These prevent allocations during
 	// validation and are known at schema creation time.
 	msgEnum              string                       `yaml:"-"`
@@ -165,8 +196,8 @@ func (s *Schema) MarshalJSON() ([]byte, error) {
 		typ = []string{s.Type, "null"}
 	}
 
-	var contentMediaType string
-	if s.Format == "binary" {
+	contentMediaType := s.ContentMediaType
+	if contentMediaType == "" && s.Format == "binary" {
 		contentMediaType = "application/octet-stream"
 	}
 
@@ -197,6 +228,7 @@ func (s *Schema) MarshalJSON() ([]byte, error) {
 		{"items", s.Items, omitEmpty},
 		{"additionalProperties", s.AdditionalProperties, omitNil},
 		{"properties", props, omitEmpty},
+		{"propertyNames", s.PropertyNames, omitEmpty},
 		{"enum", s.Enum, omitEmpty},
 		{"minimum", s.Minimum, omitEmpty},
 		{"exclusiveMinimum", s.ExclusiveMinimum, omitEmpty},
@@ -222,15 +254,71 @@ func (s *Schema) MarshalJSON() ([]byte, error) {
 		{"allOf", s.AllOf, omitEmpty},
 		{"not", s.Not, omitEmpty},
 		{"discriminator", s.Discriminator, omitEmpty},
+		{"$defs", s.Defs, omitEmpty},
 	}, s.Extensions)
 }
 
+// UnmarshalJSON unmarshals the schema from JSON, e.g. when loading an existing
+// OpenAPI document. Types like `["string", "null"]` set `Nullable`, and boolean
+// `additionalProperties` are kept as a `bool`.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type alias Schema
+	var v struct {
+		*alias
+		Type                 json.RawMessage    `json:"type"`
+		AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+		Defs                 map[string]*Schema `json:"$defs"`
+	}
+	v.alias = (*alias)(s)
+	if err := unmarshalJSON(data, &v, &s.Ref, &s.Extensions); err != nil {
+		return err
+	}
+	s.Defs = v.Defs
+
+	if len(v.Type) > 0 {
+		var types []string
+		if err := json.Unmarshal(v.Type, &types); err != nil {
+			types = []string{""}
+			if err := json.Unmarshal(v.Type, &types[0]); err != nil {
+				return err
+			}
+		}
+		for _, t := range types {
+			if t == "null" && len(types) > 1 {
+				s.Nullable = true
+				continue
+			}
+			s.Type = t
+		}
+	}
+
+	if len(v.AdditionalProperties) > 0 {
+		var b bool
+		if err := json.Unmarshal(v.AdditionalProperties, &b); err == nil {
+			s.AdditionalProperties = b
+		} else {
+			var ap *Schema
+			if err := json.Unmarshal(v.AdditionalProperties, &ap); err != nil {
+				return err
+			}
+			s.AdditionalProperties = ap
+		}
+	}
+	return nil
+}
+
+// enumString returns the enum values as a comma-separated string for use in
+// validation messages.
+func enumString(values []any) string {
+	return strings.Join(mapTo(values, func(v any) string {
+		return fmt.Sprintf("%v", v)
+	}), ", ")
+}
+
 // PrecomputeMessages tries to precompute as many validation error messages
 // as possible so that new strings aren't allocated during request validation.
 func (s *Schema) PrecomputeMessages() {
-	s.msgEnum = ErrorFormatter(validation.MsgExpectedOneOf, strings.Join(mapTo(s.Enum, func(v any) string {
-		return fmt.Sprintf("%v", v)
-	}), ", "))
+	s.msgEnum = ErrorFormatter(validation.MsgExpectedOneOf, enumString(s.Enum))
 	if s.Minimum != nil {
 		s.msgMinimum = ErrorFormatter(validation.MsgExpectedMinimumNumber, *s.Minimum)
 	}
@@ -330,6 +418,89 @@ func (s *Schema) PrecomputeMessages() {
 	if sub := s.Not; sub != nil {
 		sub.PrecomputeMessages()
 	}
+
+	if sub := s.PropertyNames; sub != nil {
+		sub.PrecomputeMessages()
+	}
+}
+
+// MergeSchemas returns a new schema combining `a` and `b`, e.g. to flatten
+// an `allOf` composition of several object schemas into one. Properties from
+// both schemas are included, with properties present in both merged
+// recursively. Required fields are combined in order with duplicates removed,
+// and extensions & dependent required fields are combined by key. For all
+// other fields, values set in `b` take precedence over those in `a`. Neither
+// input schema is modified.
+//
+//	merged := huma.MergeSchemas(registry.Schema(reflect.TypeOf(Base{}), false, ""), &huma.Schema{
+//		Type:     huma.TypeObject,
+//		Required: []string{"extra"},
+//		Properties: map[string]*huma.Schema{
+//			"extra": {Type: huma.TypeString},
+//		},
+//	})
+func MergeSchemas(a, b *Schema) *Schema {
+	merged := &Schema{}
+	dst := reflect.ValueOf(merged).Elem()
+
+	var properties map[string]*Schema
+	var required []string
+	var extensions map[string]any
+	var dependentRequired map[string][]string
+	seenRequired := map[string]bool{}
+
+	for _, s := range []*Schema{a, b} {
+		if s == nil {
+			continue
+		}
+
+		// Copy set exported fields, leaving precomputed data to be regenerated.
+		src := reflect.ValueOf(s).Elem()
+		for i := 0; i < src.NumField(); i++ {
+			if !src.Type().Field(i).IsExported() || src.Field(i).IsZero() {
+				continue
+			}
+			dst.Field(i).Set(src.Field(i))
+		}
+
+		for name, prop := range s.Properties {
+			if properties == nil {
+				properties = map[string]*Schema{}
+			}
+			if existing, ok := properties[name]; ok {
+				prop = MergeSchemas(existing, prop)
+			}
+			properties[name] = prop
+		}
+
+		for _, name := range s.Required {
+			if !seenRequired[name] {
+				seenRequired[name] = true
+				required = append(required, name)
+			}
+		}
+
+		for k, v := range s.Extensions {
+			if extensions == nil {
+				extensions = map[string]any{}
+			}
+			extensions[k] = v
+		}
+
+		for k, v := range s.DependentRequired {
+			if dependentRequired == nil {
+				dependentRequired = map[string][]string{}
+			}
+			dependentRequired[k] = v
+		}
+	}
+
+	merged.Properties = properties
+	merged.Required = required
+	merged.Extensions = extensions
+	merged.DependentRequired = dependentRequired
+	merged.PrecomputeMessages()
+	return merged
 }
 
 func boolTag(f reflect.StructField, tag string, def bool) bool {
@@ -435,6 +606,17 @@ func ensureType(r Registry, fieldName string, s *Schema, value string, v any) {
 func convertType(fieldName string, t reflect.Type, v any) any {
 	vv := reflect.ValueOf(v)
 	tv := reflect.TypeOf(v)
+	if str, ok := v.(string); ok && tv != t && reflect.PointerTo(deref(t)).Implements(textUnmarshalerType) {
+		// Special case: types like `huma.Duration` are loaded from text.
+		tmp := reflect.New(deref(t))
+		if err := tmp.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str)); err != nil {
+			panic(fmt.Errorf("unable to convert %v to %v for field '%s': %w", v, t, fieldName, ErrSchemaInvalid))
+		}
+		if t.Kind() == reflect.Ptr {
+			return tmp.Interface()
+		}
+		return tmp.Elem().Interface()
+	}
 	if v != nil && tv != t {
 		if tv.Kind() == reflect.Slice {
 			// Slices can't be cast due to the different layouts. Instead, we make a
@@ -554,6 +736,8 @@ func SchemaFromField(registry Registry, f reflect.StructField, hint string) *Sch
 		}
 	}
 	fs.ContentEncoding = stringTag(f, "encoding", fs.ContentEncoding)
+	fs.ContentEncoding = stringTag(f, "contentEncoding", fs.ContentEncoding)
+	fs.ContentMediaType = stringTag(f, "contentMediaType", fs.ContentMediaType)
 	if defaultValue := jsonTag(registry, f, fs, "default"); defaultValue != nil {
 		fs.Default = defaultValue
 	}
@@ -563,6 +747,12 @@ func SchemaFromField(registry Registry, f reflect.StructField, hint string) *Sch
 			fs.Examples = []any{e}
 		}
 	}
+	if value := f.Tag.Get("docExample"); value != "" {
+		// Additional example, e.g. to show an edge case alongside `example`.
+		if e := jsonTagValue(registry, f.Name, fs, value); e != nil {
+			fs.Examples = append(fs.Examples, e)
+		}
+	}
 
 	if enum := f.Tag.Get("enum"); enum != "" {
 		s := fs
@@ -599,15 +789,75 @@ func SchemaFromField(registry Registry, f reflect.StructField, hint string) *Sch
 	fs.MinLength = intTag(f, "minLength", fs.MinLength)
 	fs.MaxLength = intTag(f, "maxLength", fs.MaxLength)
 	fs.Pattern = stringTag(f, "pattern", fs.Pattern)
+	if fs.Format == "decimal" {
+		if precision, scale := intTag(f, "precision", nil), intTag(f, "scale", nil); precision != nil || scale != nil {
+			// Limit the number of digits, see `huma.Decimal`.
+			fs.Pattern, fs.PatternDescription = decimalPattern(precision, scale)
+		}
+	}
 	fs.PatternDescription = stringTag(f, "patternDescription", fs.PatternDescription)
 	fs.MinItems = intTag(f, "minItems", fs.MinItems)
 	fs.MaxItems = intTag(f, "maxItems", fs.MaxItems)
 	fs.UniqueItems = boolTag(f, "uniqueItems", fs.UniqueItems)
 	fs.MinProperties = intTag(f, "minProperties", fs.MinProperties)
+	if keyPattern := f.Tag.Get("keyPattern"); keyPattern != "" {
+		if deref(f.Type).Kind() != reflect.Map {
+			panic(fmt.Errorf("keyPattern is only supported for maps, but field '%s' is type '%s'", f.Name, f.Type))
+		}
+		fs.PropertyNames = &Schema{Type: TypeString, Pattern: keyPattern}
+	}
 	fs.MaxProperties = intTag(f, "maxProperties", fs.MaxProperties)
 	fs.ReadOnly = boolTag(f, "readOnly", fs.ReadOnly)
 	fs.WriteOnly = boolTag(f, "writeOnly", fs.WriteOnly)
 	fs.Deprecated = boolTag(f, "deprecated", fs.Deprecated)
+	if removedAfter := f.Tag.Get("removedAfter"); removedAfter != "" {
+		if _, err := time.Parse(time.DateOnly, removedAfter); err != nil {
+			panic(fmt.Errorf("invalid date tag 'removedAfter' for field '%s': %v (%w)", f.Name, removedAfter, err))
+		}
+		// Fields scheduled for removal are implicitly deprecated.
+		fs.Deprecated = true
+		if fs.Extensions == nil {
+			fs.Extensions = map[string]any{}
+		}
+		fs.Extensions["x-removal-date"] = removedAfter
+	}
+	if boolTag(f, "redact", false) {
+		// Let consumers know the value may be masked, see `RedactTransformer`.
+		if fs.Extensions == nil {
+			fs.Extensions = map[string]any{}
+		}
+		fs.Extensions["x-redact"] = true
+	}
+	for tag, ext := range SchemaTagExtensions {
+		if value, ok := f.Tag.Lookup(tag); ok {
+			if fs.Extensions == nil {
+				fs.Extensions = map[string]any{}
+			}
+			fs.Extensions[ext] = value
+		}
+	}
+	if exts := f.Tag.Get("extensions"); exts != "" {
+		if fs.Extensions == nil {
+			fs.Extensions = map[string]any{}
+		}
+		for _, ext := range strings.Split(exts, ",") {
+			name, value, found := strings.Cut(ext, "=")
+			name = strings.TrimSpace(name)
+			if !strings.HasPrefix(name, "x-") {
+				panic(fmt.Errorf("invalid extensions tag for field '%s': %s must start with x-", f.Name, name))
+			}
+			var v any = true
+			if found {
+				// Numbers, bools, and JSON values are parsed, otherwise the value is
+				// used as a string.
+				value = strings.TrimSpace(value)
+				if err := json.Unmarshal([]byte(value), &v); err != nil {
+					v = value
+				}
+			}
+			fs.Extensions[name] = v
+		}
+	}
 	fs.PrecomputeMessages()
 
 	fs.hidden = boolTag(f, "hidden", fs.hidden)
@@ -676,6 +926,12 @@ type SchemaTransformer interface {
 	TransformSchema(r Registry, s *Schema) *Schema
 }
 
+// SchemaExtensionsProvider is an interface that can be implemented by types
+// to add OpenAPI extensions like `x-internal` to their generated schema.
+type SchemaExtensionsProvider interface {
+	SchemaExtensions() map[string]any
+}
+
 // SchemaFromType returns a schema for a given type, using the registry to
 // possibly create references for nested structs. The schema that is returned
 // can then be passed to `huma.Validate` to efficiently validate incoming
@@ -690,6 +946,16 @@ func SchemaFromType(r Registry, t reflect.Type) *Schema {
 
 	// Transform generated schema if type implements SchemaTransformer
 	v := reflect.New(t).Interface()
+	if ep, ok := v.(SchemaExtensionsProvider); ok {
+		if exts := ep.SchemaExtensions(); len(exts) > 0 {
+			if s.Extensions == nil {
+				s.Extensions = map[string]any{}
+			}
+			for k, ext := range exts {
+				s.Extensions[k] = ext
+			}
+		}
+	}
 	if st, ok := v.(SchemaTransformer); ok {
 		s = st.TransformSchema(r, s)
 
@@ -804,6 +1070,7 @@ func schemaFromType(r Registry, t reflect.Type) *Schema {
 		fieldSet := map[string]struct{}{}
 		props := map[string]*Schema{}
 		dependentRequiredMap := map[string][]string{}
+		var aliases map[string]string
 		for _, info := range getFields(t, make(map[reflect.Type]struct{})) {
 			f := info.Field
 
@@ -847,6 +1114,15 @@ func schemaFromType(r Registry, t reflect.Type) *Schema {
 				props[name] = fs
 				propNames = append(propNames, name)
 
+				if alt := f.Tag.Get("altNames"); alt != "" {
+					if aliases == nil {
+						aliases = map[string]string{}
+					}
+					for _, a := range strings.Split(alt, ",") {
+						aliases[strings.TrimSpace(a)] = name
+					}
+				}
+
 				if fs.hidden {
 					// This field is deliberately ignored. It may still exist, but won't
 					// be documented as a required field.
@@ -905,6 +1181,7 @@ func schemaFromType(r Registry, t reflect.Type) *Schema {
 		s.Required = required
 		s.DependentRequired = dependentRequiredMap
 		s.requiredMap = requiredMap
+		s.aliases = aliases
 		s.PrecomputeMessages()
 	case reflect.Interface:
 		// Interfaces mean any object.
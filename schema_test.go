@@ -277,6 +277,60 @@ func TestSchema(t *testing.T) {
 				"additionalProperties": false
 			}`,
 		},
+		{
+			name: "field-string-content",
+			input: struct {
+				Value string `json:"value" contentEncoding:"base64" contentMediaType:"application/json"`
+			}{},
+			expected: `{
+				"type": "object",
+				"properties": {
+					"value": {
+						"type": "string",
+						"contentMediaType": "application/json",
+						"contentEncoding": "base64"
+					}
+				},
+				"required": ["value"],
+				"additionalProperties": false
+			}`,
+		},
+		{
+			name: "field-unit-examples",
+			input: struct {
+				Value int `json:"value" unit:"seconds" example:"30" docExample:"0"`
+			}{},
+			expected: `{
+				"type": "object",
+				"properties": {
+					"value": {
+						"type": "integer",
+						"format": "int64",
+						"examples": [30, 0],
+						"x-unit": "seconds"
+					}
+				},
+				"required": ["value"],
+				"additionalProperties": false
+			}`,
+		},
+		{
+			name: "field-removed-after",
+			input: struct {
+				Value string `json:"value,omitempty" removedAfter:"2025-01-01"`
+			}{},
+			expected: `{
+				"type": "object",
+				"properties": {
+					"value": {
+						"type": "string",
+						"deprecated": true,
+						"x-removal-date": "2025-01-01"
+					}
+				},
+				"additionalProperties": false
+			}`,
+		},
 		{
 			name: "field-array",
 			input: struct {
@@ -297,6 +351,30 @@ func TestSchema(t *testing.T) {
 				"additionalProperties": false
 			}`,
 		},
+		{
+			name: "field-map-key-pattern",
+			input: struct {
+				Value map[string]int `json:"value" keyPattern:"^[a-z0-9_]+$"`
+			}{},
+			expected: `{
+				"type": "object",
+				"properties": {
+					"value": {
+						"type": "object",
+						"propertyNames": {
+							"type": "string",
+							"pattern": "^[a-z0-9_]+$"
+						},
+						"additionalProperties": {
+							"type": "integer",
+							"format": "int64"
+						}
+					}
+				},
+				"required": ["value"],
+				"additionalProperties": false
+			}`,
+		},
 		{
 			name: "field-map",
 			input: struct {
@@ -824,6 +902,20 @@ func TestSchema(t *testing.T) {
 			}{},
 			panics: "invalid bool tag 'readOnly' for field 'Value': bad",
 		},
+		{
+			name: "panic-key-pattern",
+			input: struct {
+				Value string `json:"value" keyPattern:"^[a-z]+$"`
+			}{},
+			panics: `keyPattern is only supported for maps, but field 'Value' is type 'string'`,
+		},
+		{
+			name: "panic-date",
+			input: struct {
+				Value string `json:"value" removedAfter:"soon"`
+			}{},
+			panics: `invalid date tag 'removedAfter' for field 'Value': soon (parsing time "soon" as "2006-01-02": cannot parse "soon" as "2006")`,
+		},
 		{
 			name: "panic-int",
 			input: struct {
@@ -1375,6 +1467,54 @@ func TestSchemaArrayNotNullable(t *testing.T) {
 	assert.Equal(t, "array", s.Properties["field"].Type)
 }
 
+func TestSchemaTagExtensions(t *testing.T) {
+	huma.SchemaTagExtensions["owner"] = "x-owner"
+	defer func() {
+		delete(huma.SchemaTagExtensions, "owner")
+	}()
+
+	type Value struct {
+		Field string `json:"field" owner:"billing" unit:"USD"`
+	}
+
+	r := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	s := r.Schema(reflect.TypeOf(Value{}), false, "")
+
+	assert.Equal(t, map[string]any{"x-owner": "billing", "x-unit": "USD"}, s.Properties["field"].Extensions)
+}
+
+type ExtensionsType struct {
+	Value string `json:"value"`
+}
+
+func (ExtensionsType) SchemaExtensions() map[string]any {
+	return map[string]any{"x-internal": true}
+}
+
+func TestSchemaExtensions(t *testing.T) {
+	type Value struct {
+		Field  string         `json:"field" extensions:"x-order=3, x-internal, x-group=billing, x-tags=[\"a\"]"`
+		Nested ExtensionsType `json:"nested"`
+	}
+
+	r := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	s := r.Schema(reflect.TypeOf(Value{}), false, "")
+
+	assert.Equal(t, map[string]any{
+		"x-order":    3.0,
+		"x-internal": true,
+		"x-group":    "billing",
+		"x-tags":     []any{"a"},
+	}, s.Properties["field"].Extensions)
+	assert.Equal(t, map[string]any{"x-internal": true}, r.Map()["ExtensionsType"].Extensions)
+
+	assert.PanicsWithError(t, "invalid extensions tag for field 'Field': order must start with x-", func() {
+		r.Schema(reflect.TypeOf(struct {
+			Field string `json:"field" extensions:"order=3"`
+		}{}), false, "")
+	})
+}
+
 type BenchSub struct {
 	Visible bool      `json:"visible" default:"true"`
 	Metrics []float64 `json:"metrics" maxItems:"31"`
@@ -0,0 +1,298 @@
+package huma
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+)
+
+// APIKeyVerifier verifies an API key read from the location declared by an
+// `apiKey` security scheme, returning whether it is valid.
+type APIKeyVerifier func(ctx Context, key string) bool
+
+// MutualTLSVerifier verifies the verified client certificate chains of a
+// `mutualTLS` security scheme, returning whether the client is allowed. The
+// server's `tls.Config` is responsible for validating the chains themselves.
+type MutualTLSVerifier func(ctx Context, chains [][]*x509.Certificate) bool
+
+// SecurityVerifiers maps security scheme names from
+// `OpenAPI.Components.SecuritySchemes` to functions which verify the
+// credentials for that scheme. See `SecurityMiddleware`.
+type SecurityVerifiers struct {
+	APIKey    map[string]APIKeyVerifier
+	MutualTLS map[string]MutualTLSVerifier
+}
+
+// SecurityMiddleware returns a middleware which enforces the security
+// requirements of each operation (or the API's global requirements if the
+// operation sets none) using the declared security schemes. Credentials are
+// read from the location given by the scheme, e.g. the header, query param, or
+// cookie named by an `apiKey` scheme, and passed to the matching verifier.
+//
+// A request is allowed if any of the requirements is satisfied, otherwise a
+// `401 Unauthorized` error is written. Schemes without a verifier, e.g. a JWT
+// bearer scheme, are not checked here and are treated as satisfied so that
+// other middleware can handle them. This means an alternative made up only of
+// such schemes lets the request through, e.g. for `[{jwt}, {apiKey}]` the
+// request is passed on without an API key and the JWT middleware must reject
+// it if the token is missing or invalid.
+//
+//	api.UseMiddleware(huma.SecurityMiddleware(api, huma.SecurityVerifiers{
+//		APIKey: map[string]huma.APIKeyVerifier{
+//			"apiKey": func(ctx huma.Context, key string) bool {
+//				return key == os.Getenv("API_KEY")
+//			},
+//		},
+//	}))
+func SecurityMiddleware(api API, verifiers SecurityVerifiers) func(ctx Context, next func(Context)) {
+	oapi := api.OpenAPI()
+	return func(ctx Context, next func(Context)) {
+		requirements := oapi.Security
+		if op := ctx.Operation(); op != nil && op.Security != nil {
+			requirements = op.Security
+		}
+
+		if len(requirements) == 0 {
+			next(ctx)
+			return
+		}
+		for _, req := range requirements {
+			satisfied := true
+			for name := range req {
+				ok, known := verifySecurityScheme(oapi, verifiers, ctx, name)
+				satisfied = satisfied && (ok || !known)
+			}
+			if satisfied {
+				next(ctx)
+				return
+			}
+		}
+
+		WriteErr(api, ctx, http.StatusUnauthorized, "missing or invalid credentials")
+	}
+}
+
+// verifySecurityScheme checks the credentials for a single named scheme. It
+// returns whether they are valid and whether a verifier exists for the scheme.
+func verifySecurityScheme(oapi *OpenAPI, verifiers SecurityVerifiers, ctx Context, name string) (ok bool, known bool) {
+	var scheme *SecurityScheme
+	if oapi.Components != nil {
+		scheme = oapi.Components.SecuritySchemes[name]
+	}
+	if scheme == nil {
+		return false, false
+	}
+
+	switch scheme.Type {
+	case "apiKey":
+		verify := verifiers.APIKey[name]
+		if verify == nil {
+			return false, false
+		}
+		key := ""
+		switch scheme.In {
+		case "header":
+			key = ctx.Header(scheme.Name)
+		case "query":
+			key = ctx.Query(scheme.Name)
+		case "cookie":
+			if c, err := ReadCookie(ctx, scheme.Name); err == nil {
+				key = c.Value
+			}
+		}
+		return key != "" && verify(ctx, key), true
+	case "mutualTLS":
+		verify := verifiers.MutualTLS[name]
+		if verify == nil {
+			return false, false
+		}
+		state := ctx.TLS()
+		return state != nil && len(state.VerifiedChains) > 0 && verify(ctx, state.VerifiedChains), true
+	}
+	return false, false
+}
+
+// withScopes returns a copy of the security requirements (or the API's default
+// requirements if none are set) with the given scopes added to each scheme.
+// The input is never modified as it may be shared between operations.
+func withScopes(security, defaults []map[string][]string, scopes []string) []map[string][]string {
+	if security == nil {
+		security = defaults
+	}
+	result := make([]map[string][]string, 0, len(security))
+	for _, req := range security {
+		c := make(map[string][]string, len(req))
+		for name, existing := range req {
+			merged := slices.Clone(existing)
+			for _, scope := range scopes {
+				if !slices.Contains(merged, scope) {
+					merged = append(merged, scope)
+				}
+			}
+			c[name] = merged
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// ScopesFromContext returns the scopes required by the current operation. If
+// the operation sets `RequiredScopes` those are returned, otherwise all scopes
+// from the operation's security requirements are returned.
+//
+//	func AuthMiddleware(ctx huma.Context, next func(huma.Context)) {
+//		required := huma.ScopesFromContext(ctx)
+//		// ...
+//	}
+func ScopesFromContext(ctx Context) []string {
+	op := ctx.Operation()
+	if op == nil {
+		return nil
+	}
+	if op.RequiredScopes != nil {
+		return op.RequiredScopes
+	}
+	var scopes []string
+	for _, req := range op.Security {
+		for _, reqScopes := range req {
+			for _, scope := range reqScopes {
+				if !slices.Contains(scopes, scope) {
+					scopes = append(scopes, scope)
+				}
+			}
+		}
+	}
+	return scopes
+}
+
+// EnforceScopes checks that all of the scopes required by the current
+// operation (see `ScopesFromContext`) have been granted to the caller. If any
+// are missing, a 403 Forbidden error listing each missing scope is written and
+// `false` is returned.
+//
+//	func AuthMiddleware(ctx huma.Context, next func(huma.Context)) {
+//		granted := getScopesFromToken(ctx.Header("Authorization"))
+//		if huma.EnforceScopes(api, ctx, granted) {
+//			next(ctx)
+//		}
+//	}
+func EnforceScopes(api API, ctx Context, granted []string) bool {
+	var errs []error
+	for _, scope := range ScopesFromContext(ctx) {
+		if !slices.Contains(granted, scope) {
+			errs = append(errs, &ErrorDetail{
+				Message:  "missing required scope",
+				Location: "scopes",
+				Value:    scope,
+			})
+		}
+	}
+	if len(errs) > 0 {
+		WriteErr(api, ctx, http.StatusForbidden, "insufficient scope", errs...)
+		return false
+	}
+	return true
+}
+
+// PolicyFunc decides whether the caller may run the operation, which requires
+// the permissions returned by `PermissionsFromContext`. Return nil to allow
+// the request. Returning a `StatusError` writes that error, while any other
+// error results in a `403 Forbidden`.
+type PolicyFunc func(ctx Context, op *Operation) error
+
+// RequirePermission is an operation handler which declares the permissions
+// required to call the operation. They are stored in the operation's
+// `permissions` metadata, documented via the `x-permissions` OpenAPI
+// extension for client tooling, and enforced by `PolicyMiddleware`.
+//
+//	huma.Get(api, "/things", listThings, huma.RequirePermission("things:read"))
+func RequirePermission(permissions ...string) func(o *Operation) {
+	return func(o *Operation) {
+		existing, _ := o.Metadata["permissions"].([]string)
+		merged := slices.Clone(existing)
+		for _, p := range permissions {
+			if !slices.Contains(merged, p) {
+				merged = append(merged, p)
+			}
+		}
+
+		if o.Metadata == nil {
+			o.Metadata = map[string]any{}
+		}
+		o.Metadata["permissions"] = merged
+
+		if o.Extensions == nil {
+			o.Extensions = map[string]any{}
+		}
+		o.Extensions["x-permissions"] = merged
+
+		if !slices.Contains(o.Errors, http.StatusForbidden) {
+			o.Errors = append(o.Errors, http.StatusForbidden)
+		}
+	}
+}
+
+// PermissionsFromContext returns the permissions required by the current
+// operation, see `RequirePermission`.
+func PermissionsFromContext(ctx Context) []string {
+	op := ctx.Operation()
+	if op == nil {
+		return nil
+	}
+	permissions, _ := op.Metadata["permissions"].([]string)
+	return permissions
+}
+
+// PolicyMiddleware returns a middleware which evaluates the policy for each
+// operation which requires permissions (see `RequirePermission`). Operations
+// without required permissions are passed through without calling the
+// policy.
+//
+//	api.UseMiddleware(huma.PolicyMiddleware(api, func(ctx huma.Context, op *huma.Operation) error {
+//		granted := getPermissionsFromToken(ctx.Header("Authorization"))
+//		for _, p := range huma.PermissionsFromContext(ctx) {
+//			if !slices.Contains(granted, p) {
+//				return fmt.Errorf("missing permission %s", p)
+//			}
+//		}
+//		return nil
+//	}))
+func PolicyMiddleware(api API, policy PolicyFunc) func(ctx Context, next func(Context)) {
+	return func(ctx Context, next func(Context)) {
+		if len(PermissionsFromContext(ctx)) == 0 {
+			next(ctx)
+			return
+		}
+
+		if err := policy(ctx, ctx.Operation()); err != nil {
+			var se StatusError
+			if errors.As(err, &se) {
+				if err := writeResponse(api, ctx, se.GetStatus(), "", se); err != nil {
+					fmt.Fprintf(os.Stderr, "could not write error: %v\n", err)
+				}
+				return
+			}
+			WriteErr(api, ctx, http.StatusForbidden, "insufficient permissions", err)
+			return
+		}
+		next(ctx)
+	}
+}
+
+// Audited is an operation handler which marks the operation as auditable
+// using the given action name, like `resource.update`. The action is stored in
+// the operation's `audit` metadata and used by the `audit` package to emit an
+// event after each request.
+//
+//	huma.Put(api, "/things/{id}", updateThing, huma.Audited("thing.update"))
+func Audited(action string) func(o *Operation) {
+	return func(o *Operation) {
+		if o.Metadata == nil {
+			o.Metadata = map[string]any{}
+		}
+		o.Metadata["audit"] = action
+	}
+}
@@ -0,0 +1,248 @@
+package huma_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+func TestRequiredScopes(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Components.SecuritySchemes = map[string]*huma.SecurityScheme{
+		"bearer": {Type: "http", Scheme: "bearer"},
+	}
+	global := []map[string][]string{{"bearer": {"read"}}}
+	config.Security = global
+	_, api := humatest.New(t, config)
+
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		granted := strings.Fields(ctx.Header("X-Scopes"))
+		if huma.EnforceScopes(api, ctx, granted) {
+			next(ctx)
+		}
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:    "delete-item",
+		Method:         http.MethodDelete,
+		Path:           "/items/{id}",
+		RequiredScopes: []string{"read", "write"},
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-item",
+		Method:      http.MethodGet,
+		Path:        "/items/{id}",
+		Security:    []map[string][]string{{"bearer": {"read"}}},
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	// Scopes are documented on top of the global requirements, which must not
+	// be modified.
+	op := api.OpenAPI().Paths["/items/{id}"].Delete
+	assert.Equal(t, []map[string][]string{{"bearer": {"read", "write"}}}, op.Security)
+	assert.Contains(t, op.Responses, "403")
+	assert.Equal(t, []map[string][]string{{"bearer": {"read"}}}, global)
+
+	resp := api.Delete("/items/1", "X-Scopes: read")
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"value":"write"`)
+	assert.NotContains(t, resp.Body.String(), `"value":"read"`)
+
+	resp = api.Delete("/items/1", "X-Scopes: read write")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	// Scopes are read from the security requirements if not set explicitly.
+	resp = api.Get("/items/1")
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"value":"read"`)
+
+	resp = api.Get("/items/1", "X-Scopes: read")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+}
+
+func TestSecurityMiddleware(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Components.SecuritySchemes = map[string]*huma.SecurityScheme{
+		"header": {Type: "apiKey", In: "header", Name: "X-API-Key"},
+		"query":  {Type: "apiKey", In: "query", Name: "api_key"},
+		"cookie": {Type: "apiKey", In: "cookie", Name: "key"},
+		"mtls":   {Type: "mutualTLS"},
+		"bearer": {Type: "http", Scheme: "bearer"},
+	}
+	config.Security = []map[string][]string{{"header": {}}, {"query": {}}, {"cookie": {}}}
+	_, api := humatest.New(t, config)
+
+	valid := func(ctx huma.Context, key string) bool { return key == "secret" }
+	mw := huma.SecurityMiddleware(api, huma.SecurityVerifiers{
+		APIKey: map[string]huma.APIKeyVerifier{
+			"header": valid,
+			"query":  valid,
+			"cookie": valid,
+		},
+		MutualTLS: map[string]huma.MutualTLSVerifier{
+			"mtls": func(ctx huma.Context, chains [][]*x509.Certificate) bool {
+				return chains[0][0].Subject.CommonName == "client"
+			},
+		},
+	})
+	api.UseMiddleware(mw)
+
+	handler := func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	}
+	huma.Register(api, huma.Operation{
+		OperationID: "get-keyed",
+		Method:      http.MethodGet,
+		Path:        "/keyed",
+	}, handler)
+	huma.Register(api, huma.Operation{
+		OperationID: "get-public",
+		Method:      http.MethodGet,
+		Path:        "/public",
+		Security:    []map[string][]string{},
+	}, handler)
+	huma.Register(api, huma.Operation{
+		OperationID: "get-bearer",
+		Method:      http.MethodGet,
+		Path:        "/bearer",
+		Security:    []map[string][]string{{"bearer": {}}},
+	}, handler)
+	huma.Register(api, huma.Operation{
+		OperationID: "get-bearer-or-key",
+		Method:      http.MethodGet,
+		Path:        "/bearer-or-key",
+		Security:    []map[string][]string{{"bearer": {}}, {"header": {}}},
+	}, handler)
+	huma.Register(api, huma.Operation{
+		OperationID: "get-bearer-and-key",
+		Method:      http.MethodGet,
+		Path:        "/bearer-and-key",
+		Security:    []map[string][]string{{"bearer": {}, "header": {}}},
+	}, handler)
+
+	resp := api.Get("/keyed")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = api.Get("/keyed", "X-API-Key: wrong")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = api.Get("/keyed", "X-API-Key: secret")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	resp = api.Get("/keyed?api_key=secret")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	resp = api.Get("/keyed", "Cookie: key=secret")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	// No requirements means no auth needed.
+	resp = api.Get("/public")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	// Schemes without verifiers are left to other middleware.
+	resp = api.Get("/bearer")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	// An alternative without verifiers is not checked here, so the request is
+	// passed on even without an API key.
+	resp = api.Get("/bearer-or-key")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	resp = api.Get("/bearer-or-key", "X-API-Key: wrong")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	// Schemes with verifiers are still enforced when combined with others.
+	resp = api.Get("/bearer-and-key")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = api.Get("/bearer-and-key", "X-API-Key: secret")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	// Mutual TLS uses the verified client certificate chains.
+	op := &huma.Operation{Security: []map[string][]string{{"mtls": {}}}}
+	for _, item := range []struct {
+		name     string
+		state    *tls.ConnectionState
+		expected int
+	}{
+		{"no-tls", nil, http.StatusUnauthorized},
+		{"no-cert", &tls.ConnectionState{}, http.StatusUnauthorized},
+		{"wrong-cert", &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "other"}}}}}, http.StatusUnauthorized},
+		{"valid", &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "client"}}}}}, http.StatusNoContent},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.TLS = item.state
+			w := httptest.NewRecorder()
+			mw(humatest.NewContext(op, r, w), func(ctx huma.Context) {
+				ctx.SetStatus(http.StatusNoContent)
+			})
+			assert.Equal(t, item.expected, w.Code)
+		})
+	}
+}
+
+func TestPolicyMiddleware(t *testing.T) {
+	_, api := humatest.New(t)
+
+	var policyOp *huma.Operation
+	api.UseMiddleware(huma.PolicyMiddleware(api, func(ctx huma.Context, op *huma.Operation) error {
+		policyOp = op
+		granted := strings.Split(ctx.Header("X-Permissions"), ",")
+		for _, p := range huma.PermissionsFromContext(ctx) {
+			if !slices.Contains(granted, p) {
+				return fmt.Errorf("missing permission %s", p)
+			}
+		}
+		if ctx.Header("X-Banned") != "" {
+			return huma.Error401Unauthorized("banned")
+		}
+		return nil
+	}))
+
+	handler := func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	}
+	huma.Get(api, "/public", handler)
+	huma.Get(api, "/things", handler, huma.RequirePermission("things:read"), huma.RequirePermission("things:read", "things:list"))
+
+	op := api.OpenAPI().Paths["/things"].Get
+	assert.Equal(t, []string{"things:read", "things:list"}, op.Metadata["permissions"])
+	assert.Equal(t, []string{"things:read", "things:list"}, op.Extensions["x-permissions"])
+	assert.Contains(t, op.Responses, "403")
+
+	resp := api.Get("/public")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Nil(t, policyOp)
+
+	resp = api.Get("/things", "X-Permissions: things:read")
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	assert.Contains(t, resp.Body.String(), "missing permission things:list")
+	assert.Equal(t, "/things", policyOp.Path)
+
+	resp = api.Get("/things", "X-Permissions: things:read,things:list")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	resp = api.Get("/things", "X-Permissions: things:read,things:list", "X-Banned: true")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
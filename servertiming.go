@@ -0,0 +1,130 @@
+package huma
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// serverTiming wraps the request context to measure the phases of handling a
+// request and report them via the `Server-Timing` header. Since headers must
+// be sent before the body, the response is buffered so that the time spent
+// marshaling it can be included. Streaming responses are not buffered and
+// omit the marshal phase.
+type serverTiming struct {
+	humaContext
+	buffered bool
+
+	start        time.Time
+	validate     time.Duration
+	handlerStart time.Time
+	handlerEnd   time.Time
+	writeStart   time.Time
+
+	status int
+	buf    *bytes.Buffer
+}
+
+func newServerTiming(ctx Context, buffered bool) *serverTiming {
+	return &serverTiming{humaContext: ctx, buffered: buffered, start: time.Now()}
+}
+
+// now returns the current time, or the zero time when timing is disabled to
+// avoid the overhead of reading the clock.
+func (t *serverTiming) now() time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// trackValidate adds the time since start to the validation phase. It is a
+// no-op when timing is disabled.
+func (t *serverTiming) trackValidate(start time.Time) {
+	if t != nil {
+		t.validate += time.Since(start)
+	}
+}
+
+func (t *serverTiming) Unwrap() Context {
+	return t.humaContext
+}
+
+func (t *serverTiming) SetStatus(status int) {
+	if t.writeStart.IsZero() {
+		t.writeStart = time.Now()
+	}
+	if t.buffered {
+		t.status = status
+		return
+	}
+	t.humaContext.SetHeader("Server-Timing", t.header(false))
+	t.humaContext.SetStatus(status)
+}
+
+func (t *serverTiming) Status() int {
+	if t.buffered && t.status != 0 {
+		return t.status
+	}
+	return t.humaContext.Status()
+}
+
+func (t *serverTiming) BodyWriter() io.Writer {
+	if !t.buffered {
+		return t.humaContext.BodyWriter()
+	}
+	if t.buf == nil {
+		t.buf = bufPool.Get().(*bytes.Buffer)
+	}
+	return t.buf
+}
+
+// finish writes the `Server-Timing` header and any buffered response.
+func (t *serverTiming) finish() {
+	if !t.buffered {
+		return
+	}
+	t.humaContext.SetHeader("Server-Timing", t.header(true))
+	if t.status != 0 {
+		t.humaContext.SetStatus(t.status)
+	}
+	if t.buf != nil {
+		t.humaContext.BodyWriter().Write(t.buf.Bytes())
+		t.buf.Reset()
+		bufPool.Put(t.buf)
+		t.buf = nil
+	}
+}
+
+// header formats the measured phases, in milliseconds.
+func (t *serverTiming) header(marshal bool) string {
+	end := t.writeStart
+	if end.IsZero() {
+		end = time.Now()
+	}
+	parseEnd := end
+	if !t.handlerStart.IsZero() {
+		parseEnd = t.handlerStart
+	}
+
+	var sb strings.Builder
+	metric := func(name string, d time.Duration) {
+		if sb.Len() > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(name)
+		sb.WriteString(";dur=")
+		sb.WriteString(strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64))
+	}
+	metric("parse", parseEnd.Sub(t.start)-t.validate)
+	metric("validate", t.validate)
+	if !t.handlerStart.IsZero() {
+		metric("handler", t.handlerEnd.Sub(t.handlerStart))
+	}
+	if marshal {
+		metric("marshal", time.Since(end))
+	}
+	return sb.String()
+}
@@ -0,0 +1,27 @@
+package huma
+
+// serverVariable returns the first server variable with the given name
+// declared in the OpenAPI servers, or nil if there is none.
+func (o *OpenAPI) serverVariable(name string) *ServerVariable {
+	for _, server := range o.Servers {
+		if v := server.Variables[name]; v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// serverVariableValue returns the value of a server variable for the request,
+// using `Config.ServerVariableFunc` if set, falling back to the declared
+// default.
+func (o *OpenAPI) serverVariableValue(ctx Context, name string, fn func(ctx Context, name string) string) string {
+	if fn != nil {
+		if v := fn(ctx, name); v != "" {
+			return v
+		}
+	}
+	if v := o.serverVariable(name); v != nil {
+		return v.Default
+	}
+	return ""
+}
@@ -0,0 +1,304 @@
+package huma
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/danielgtaylor/huma/v2/negotiation"
+)
+
+// specCache lazily renders a document like the OpenAPI spec and caches it
+// along with its ETag and any compressed variants, so that it does not need to
+// be serialized for every request. Call `invalidate` whenever the underlying
+// document changes, e.g. when a new operation is added.
+type specCache struct {
+	contentType  string
+	cacheControl string
+	render       func() ([]byte, error)
+	encoders     map[string]func(io.Writer) io.WriteCloser
+	encodings    []string
+
+	mu       sync.Mutex
+	variants map[string]*specVariant
+}
+
+// specVariant is a single rendered and optionally compressed representation.
+type specVariant struct {
+	body []byte
+	etag string
+}
+
+func newSpecCache(contentType, cacheControl string, encoders map[string]func(io.Writer) io.WriteCloser, render func() ([]byte, error)) *specCache {
+	c := &specCache{
+		contentType:  contentType,
+		cacheControl: cacheControl,
+		render:       render,
+		encoders:     encoders,
+	}
+	for name := range encoders {
+		c.encodings = append(c.encodings, name)
+	}
+	// Sort for deterministic negotiation when the client has no preference.
+	sort.Strings(c.encodings)
+	return c
+}
+
+func (c *specCache) invalidate() {
+	c.mu.Lock()
+	c.variants = nil
+	c.mu.Unlock()
+}
+
+// variant returns the cached representation for the given content encoding,
+// rendering and compressing it if needed. An empty encoding means the
+// uncompressed document.
+func (c *specCache) variant(encoding string) (*specVariant, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if v := c.variants[encoding]; v != nil {
+		return v, nil
+	}
+
+	identity := c.variants[""]
+	if identity == nil {
+		body, err := c.render()
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(body)
+		identity = &specVariant{
+			body: body,
+			etag: base64.RawURLEncoding.EncodeToString(sum[:16]),
+		}
+		c.variants = map[string]*specVariant{"": identity}
+	}
+	if encoding == "" {
+		return identity, nil
+	}
+
+	buf := &bytes.Buffer{}
+	w := c.encoders[encoding](buf)
+	if _, err := w.Write(identity.body); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	v := &specVariant{
+		body: buf.Bytes(),
+		etag: identity.etag + "-" + encoding,
+	}
+	c.variants[encoding] = v
+	return v, nil
+}
+
+// warm renders the document and each of its compressed variants so that
+// they are cached before the first request, see `Precompute`.
+func (c *specCache) warm() error {
+	if _, err := c.variant(""); err != nil {
+		return err
+	}
+	for _, encoding := range c.encodings {
+		if _, err := c.variant(encoding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// serve writes the best representation for the request, or a
+// `304 Not Modified` if the client already has it.
+func (c *specCache) serve(ctx Context) {
+	encoding := ""
+	if len(c.encodings) > 0 {
+		ctx.AppendHeader("Vary", "Accept-Encoding")
+		if accept := ctx.Header("Accept-Encoding"); accept != "" {
+			encoding = negotiation.SelectQValueFast(accept, c.encodings)
+		}
+	}
+
+	v, err := c.variant(encoding)
+	if err != nil {
+		ctx.SetStatus(http.StatusInternalServerError)
+		return
+	}
+
+	etag := `"` + v.etag + `"`
+	ctx.SetHeader("ETag", etag)
+	if c.cacheControl != "" {
+		ctx.SetHeader("Cache-Control", c.cacheControl)
+	}
+	if etagMatches(ctx.Header("If-None-Match"), etag) {
+		ctx.SetStatus(http.StatusNotModified)
+		return
+	}
+
+	ctx.SetHeader("Content-Type", c.contentType)
+	if encoding != "" {
+		ctx.SetHeader("Content-Encoding", encoding)
+	}
+	ctx.BodyWriter().Write(v.body)
+}
+
+// etagMatches returns whether any of the comma-separated entity tags in an
+// `If-None-Match` header match the given ETag, using weak comparison.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// specSuffixes are the paths, relative to `Config.OpenAPIPath`, at which the
+// renderings returned by `newSpecDocs` are served.
+var specSuffixes = []string{".json", "-3.0.json", ".yaml", "-3.0.yaml"}
+
+// newSpecDocs returns caches for each rendering of the OpenAPI document, in
+// the same order as `specSuffixes`.
+func newSpecDocs(config Config, doc func() *OpenAPI) []*specCache {
+	return []*specCache{
+		newSpecCache("application/vnd.oai.openapi+json", config.OpenAPICacheControl, config.OpenAPIEncoders, func() ([]byte, error) {
+			return json.Marshal(doc())
+		}),
+		newSpecCache("application/vnd.oai.openapi+json", config.OpenAPICacheControl, config.OpenAPIEncoders, func() ([]byte, error) {
+			return doc().Downgrade()
+		}),
+		newSpecCache("application/vnd.oai.openapi+yaml", config.OpenAPICacheControl, config.OpenAPIEncoders, func() ([]byte, error) {
+			return doc().YAML()
+		}),
+		newSpecCache("application/vnd.oai.openapi+yaml", config.OpenAPICacheControl, config.OpenAPIEncoders, func() ([]byte, error) {
+			return doc().DowngradeYAML()
+		}),
+	}
+}
+
+// filteredSpecs serves copies of the OpenAPI document filtered for the
+// current request via `Config.SpecFilter`, without the operations which are
+// disabled for it (see `Operation.HideWhenDisabled`), and with servers for
+// its base path (see `Config.BasePathFunc`). Each distinct audience and set
+// of hidden operations is rendered and cached separately. Base paths which
+// depend on the request, e.g. a tenant, are controlled by the client, so for
+// those only the filtered document is cached and its servers are filled in
+// for each response.
+type filteredSpecs struct {
+	config      Config
+	doc         func() *OpenAPI
+	basePath    func(ctx Context) string
+	dynamicBase bool
+
+	mu       sync.Mutex
+	ops      []*Operation
+	docs     map[string][]*specCache
+	filtered map[string]*OpenAPI
+}
+
+func newFilteredSpecs(config Config, doc func() *OpenAPI) *filteredSpecs {
+	return &filteredSpecs{
+		config:      config,
+		doc:         doc,
+		basePath:    config.basePathFunc(),
+		dynamicBase: config.BasePathFunc != nil || strings.Contains(config.BasePath, "{"),
+	}
+}
+
+// add tracks a newly added operation and invalidates the cached documents.
+func (f *filteredSpecs) add(op *Operation) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if op.HideWhenDisabled && op.Enabled != nil {
+		f.ops = append(f.ops, op)
+	}
+	f.docs = nil
+	f.filtered = nil
+}
+
+// get returns the documents for the request, or nil if the full document
+// should be served.
+func (f *filteredSpecs) get(ctx Context) []*specCache {
+	f.mu.Lock()
+	ops := f.ops
+	f.mu.Unlock()
+
+	var hidden []*Operation
+	var key strings.Builder
+	for _, op := range ops {
+		if !op.Enabled(ctx) {
+			hidden = append(hidden, op)
+			key.WriteString(op.Method + " " + op.Path + "\n")
+		}
+	}
+	base := ""
+	if f.basePath != nil {
+		base = f.basePath(ctx)
+	}
+	filter := f.config.SpecFilter
+	if filter == nil && len(hidden) == 0 && base == "" {
+		return nil
+	}
+
+	if filter != nil {
+		if f.config.SpecAudience == nil {
+			// Without an audience the result cannot be cached.
+			doc := withBasePath(f.filter(ctx, hidden), base)
+			return newSpecDocs(f.config, func() *OpenAPI { return doc })
+		}
+		key.WriteString("audience:" + f.config.SpecAudience(ctx) + "\n")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.dynamicBase {
+		doc := f.filtered[key.String()]
+		if doc == nil {
+			doc = f.filter(ctx, hidden)
+			if f.filtered == nil {
+				f.filtered = map[string]*OpenAPI{}
+			}
+			f.filtered[key.String()] = doc
+		}
+		return newSpecDocs(f.config, func() *OpenAPI { return withBasePath(doc, base) })
+	}
+	if docs := f.docs[key.String()]; docs != nil {
+		return docs
+	}
+	if f.docs == nil {
+		f.docs = map[string][]*specCache{}
+	}
+	doc := withBasePath(f.filter(ctx, hidden), base)
+	docs := newSpecDocs(f.config, func() *OpenAPI { return doc })
+	f.docs[key.String()] = docs
+	return docs
+}
+
+// filter returns the document for the request via `Config.SpecFilter`,
+// without the given hidden operations.
+func (f *filteredSpecs) filter(ctx Context, hidden []*Operation) *OpenAPI {
+	doc := f.doc()
+	if f.config.SpecFilter != nil {
+		doc = f.config.SpecFilter(ctx, doc)
+	}
+	return withoutOperations(doc, hidden)
+}
+
+// withoutOperations returns a copy of the document with the given operations
+// removed, or the document itself if there are none.
+func withoutOperations(doc *OpenAPI, hidden []*Operation) *OpenAPI {
+	if len(hidden) == 0 {
+		return doc
+	}
+	return doc.FilterOperations(func(op *Operation) bool {
+		return !slices.Contains(hidden, op)
+	})
+}
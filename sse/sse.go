@@ -4,11 +4,13 @@ package sse
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"reflect"
 	"runtime/debug"
+	"sort"
 	"time"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -33,6 +35,12 @@ type writeDeadliner interface {
 	SetWriteDeadline(time.Time) error
 }
 
+// ErrClientDisconnected is returned by a `Sender` when the client has gone
+// away, either because the request context was canceled or because writing
+// the message failed. Handlers can check for it via `errors.Is` to stop
+// sending and clean up, as opposed to the server ending the stream.
+var ErrClientDisconnected = errors.New("client disconnected")
+
 // Message is a single SSE message. There is no `event` field as this is
 // handled by the `eventTypeMap` when registering the operation.
 type Message struct {
@@ -69,11 +77,21 @@ func Register[I any](api huma.API, op huma.Operation, eventTypeMap map[string]an
 		op.Responses["200"].Content = map[string]*huma.MediaType{}
 	}
 
+	// Sort the event names so the generated OpenAPI is deterministic.
+	events := make([]string, 0, len(eventTypeMap))
+	for k := range eventTypeMap {
+		events = append(events, k)
+	}
+	sort.Strings(events)
+
 	typeToEvent := make(map[reflect.Type]string, len(eventTypeMap))
 	dataSchemas := make([]*huma.Schema, 0, len(eventTypeMap))
-	for k, v := range eventTypeMap {
-		vt := deref(reflect.TypeOf(v))
+	eventSchemas := make(map[string]*huma.Schema, len(eventTypeMap))
+	for _, k := range events {
+		vt := deref(reflect.TypeOf(eventTypeMap[k]))
 		typeToEvent[vt] = k
+		dataSchema := api.OpenAPI().Components.Schemas.Schema(vt, true, k)
+		eventSchemas[k] = dataSchema
 		required := []string{"data"}
 		if k != "" && k != "message" {
 			required = append(required, "event")
@@ -93,7 +111,7 @@ func Register[I any](api huma.API, op huma.Operation, eventTypeMap map[string]an
 						"const": k,
 					},
 				},
-				"data": api.OpenAPI().Components.Schemas.Schema(vt, true, k),
+				"data": dataSchema,
 				"retry": {
 					Type:        huma.TypeInteger,
 					Description: "The retry time in milliseconds.",
@@ -110,13 +128,16 @@ func Register[I any](api huma.API, op huma.Operation, eventTypeMap map[string]an
 		Description: "Each oneOf object in the array represents one possible Server Sent Events (SSE) message, serialized as UTF-8 text according to the SSE specification.",
 		Type:        huma.TypeArray,
 		Items: &huma.Schema{
-			Extensions: map[string]interface{}{
-				"oneOf": dataSchemas,
-			},
+			OneOf: dataSchemas,
 		},
 	}
 	op.Responses["200"].Content["text/event-stream"] = &huma.MediaType{
 		Schema: schema,
+		Extensions: map[string]any{
+			// Map each event name to the schema of its data so that generated
+			// clients can decode the payloads without parsing the `oneOf`.
+			"x-huma-sse-events": eventSchemas,
+		},
 	}
 
 	// Register the operation with the API, using the built-in streaming
@@ -159,6 +180,10 @@ func Register[I any](api huma.API, op huma.Operation, eventTypeMap map[string]an
 				}
 
 				send := func(msg Message) error {
+					if err := ctx.Context().Err(); err != nil {
+						return fmt.Errorf("%w: %w", ErrClientDisconnected, err)
+					}
+
 					if deadliner != nil {
 						if err := deadliner.SetWriteDeadline(time.Now().Add(WriteTimeout)); err != nil {
 							fmt.Fprintf(os.Stderr, "warning: unable to set write deadline: %v\n", err)
@@ -187,7 +212,7 @@ func Register[I any](api huma.API, op huma.Operation, eventTypeMap map[string]an
 
 					// Write the message data.
 					if _, err := bw.Write([]byte("data: ")); err != nil {
-						return err
+						return fmt.Errorf("%w: %w", ErrClientDisconnected, err)
 					}
 					if err := encoder.Encode(msg.Data); err != nil {
 						bw.Write([]byte(`{"error": "encode error: `))
@@ -2,6 +2,7 @@ package sse_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"testing"
@@ -121,3 +122,61 @@ data: {"error": "encode error: json: unsupported type: chan int"}
 	req, _ = http.NewRequest(http.MethodGet, "/sse", nil)
 	api.Adapter().ServeHTTP(w, req)
 }
+
+func TestSSEOpenAPI(t *testing.T) {
+	_, api := humatest.New(t)
+
+	sse.Register(api, huma.Operation{
+		OperationID: "sse",
+		Method:      http.MethodGet,
+		Path:        "/sse",
+	}, map[string]any{
+		"userDelete": UserDeletedEvent{},
+		"message":    &DefaultMessage{},
+		"userCreate": UserCreatedEvent{},
+	}, func(ctx context.Context, input *struct{}, send sse.Sender) {})
+
+	content := api.OpenAPI().Paths["/sse"].Get.Responses["200"].Content["text/event-stream"]
+	require.NotNil(t, content)
+
+	// Events are sorted by name for a stable document.
+	titles := []string{}
+	for _, s := range content.Schema.Items.OneOf {
+		titles = append(titles, s.Title)
+	}
+	assert.Equal(t, []string{"Event message", "Event userCreate", "Event userDelete"}, titles)
+
+	b, err := json.Marshal(content.Extensions["x-huma-sse-events"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"message": {"$ref": "#/components/schemas/DefaultMessage"},
+		"userCreate": {"$ref": "#/components/schemas/UserCreatedEvent"},
+		"userDelete": {"$ref": "#/components/schemas/UserDeletedEvent"}
+	}`, string(b))
+}
+
+func TestSSEClientDisconnected(t *testing.T) {
+	_, api := humatest.New(t)
+
+	var sendErr error
+	sse.Register(api, huma.Operation{
+		OperationID: "sse",
+		Method:      http.MethodGet,
+		Path:        "/sse",
+	}, map[string]any{
+		"message": &DefaultMessage{},
+	}, func(ctx context.Context, input *struct{}, send sse.Sender) {
+		sendErr = send.Data(DefaultMessage{Message: "Hello, world!"})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/sse", nil)
+	api.Adapter().ServeHTTP(&DummyWriter{}, req)
+	assert.ErrorIs(t, sendErr, sse.ErrClientDisconnected)
+	assert.ErrorIs(t, sendErr, context.Canceled)
+
+	req, _ = http.NewRequest(http.MethodGet, "/sse", nil)
+	api.Adapter().ServeHTTP(&DummyWriter{writeErr: errors.New("broken pipe")}, req)
+	assert.ErrorIs(t, sendErr, sse.ErrClientDisconnected)
+}
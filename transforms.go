@@ -2,10 +2,14 @@ package huma
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path"
 	"reflect"
+	"slices"
+	"strings"
+	"sync"
 )
 
 type schemaField struct {
@@ -18,7 +22,21 @@ type schemaField struct {
 // to understand the structure of the response and enables things like
 // as-you-type validation & completion of HTTP resources in editors like
 // VSCode.
+//
+// The link host is derived from the request's `Host` by default. Set
+// `BaseURL` when running behind a proxy or gateway that changes the public
+// scheme or host. Injection can be disabled for a specific operation by
+// setting its `schemaLink` metadata field to `false`.
 type SchemaLinkTransformer struct {
+	// BaseURL returns the scheme and host (e.g. `https://api.example.com`) to
+	// use when generating absolute `$schema` links and `Link` headers for the
+	// current request. If nil, the request host is used.
+	BaseURL func(ctx Context) string
+
+	// BasePath returns the base path of the current request, e.g. `/t/acme`,
+	// which is inserted before the schemas path. See `Config.BasePathFunc`.
+	BasePath func(ctx Context) string
+
 	prefix      string
 	schemasPath string
 	types       map[any]struct {
@@ -49,11 +67,12 @@ func NewSchemaLinkTransformer(prefix, schemasPath string) *SchemaLinkTransformer
 }
 
 func (t *SchemaLinkTransformer) addSchemaField(oapi *OpenAPI, content *MediaType) bool {
-	if content == nil || content.Schema == nil || content.Schema.Ref == "" {
+	ref := bodySchemaRef(content)
+	if ref == "" {
 		return true
 	}
 
-	schema := oapi.Components.Schemas.SchemaFromRef(content.Schema.Ref)
+	schema := oapi.Components.Schemas.SchemaFromRef(ref)
 	if schema.Type != TypeObject || (schema.Properties != nil && schema.Properties["$schema"] != nil) {
 		return true
 	}
@@ -73,11 +92,23 @@ func (t *SchemaLinkTransformer) addSchemaField(oapi *OpenAPI, content *MediaType
 		Format:      "uri",
 		Description: "A URL to the JSON Schema for this object.",
 		ReadOnly:    true,
-		Examples:    []any{server + t.schemasPath + "/" + path.Base(content.Schema.Ref) + ".json"},
+		Examples:    []any{server + t.schemasPath + "/" + path.Base(ref) + ".json"},
 	}
 	return false
 }
 
+// bodySchemaRef returns the schema reference of the body described by the
+// media type, looking inside response envelopes.
+func bodySchemaRef(content *MediaType) string {
+	if content == nil || content.Schema == nil {
+		return ""
+	}
+	if content.Schema.enveloped != nil {
+		return content.Schema.enveloped.Ref
+	}
+	return content.Schema.Ref
+}
+
 // OnAddOperation is triggered whenever a new operation is added to the API,
 // enabling this transformer to precompute & cache information about the
 // response and schema.
@@ -105,10 +136,11 @@ func (t *SchemaLinkTransformer) OnAddOperation(oapi *OpenAPI, op *Operation) {
 			}
 
 			// Then, create the wrapper Go type that has the $schema field.
-			typ := deref(registry.TypeFromRef(content.Schema.Ref))
+			ref := bodySchemaRef(content)
+			typ := deref(registry.TypeFromRef(ref))
 
 			extra := schemaField{
-				Schema: schemasPath + "/" + path.Base(content.Schema.Ref) + ".json",
+				Schema: schemasPath + "/" + path.Base(ref) + ".json",
 			}
 
 			fieldIndexes := []int{}
@@ -151,6 +183,13 @@ func (t *SchemaLinkTransformer) OnAddOperation(oapi *OpenAPI, op *Operation) {
 // Transform is called for every response to add the `$schema` field and/or
 // the Link header pointing to the JSON Schema.
 func (t *SchemaLinkTransformer) Transform(ctx Context, status string, v any) (any, error) {
+	if e, ok := v.(*envelope); ok {
+		// The link describes the wrapped body.
+		data, err := t.Transform(ctx, status, e.Data)
+		e.Data = data
+		return e, err
+	}
+
 	vv := reflect.ValueOf(v)
 	if vv.Kind() == reflect.Pointer && vv.IsNil() {
 		return v, nil
@@ -167,24 +206,57 @@ func (t *SchemaLinkTransformer) Transform(ctx Context, status string, v any) (an
 		return v, nil
 	}
 
-	host := ctx.Host()
-	ctx.AppendHeader("Link", info.header)
-
-	tmp := reflect.New(info.t).Elem()
+	if op := ctx.Operation(); op != nil && op.Metadata != nil {
+		if b, ok := op.Metadata["schemaLink"].(bool); ok && !b {
+			// Special case: explicitly disabled for this operation.
+			return v, nil
+		}
+	}
 
 	// Set the `$schema` field.
 	buf := bufPool.Get().(*bytes.Buffer)
-	if len(host) >= 9 && (host[:9] == "localhost" || host[:9] == "127.0.0.1") {
-		buf.WriteString("http://")
+	ref, header := info.ref, info.header
+	if t.BasePath != nil {
+		ref = strings.TrimSuffix(t.BasePath(ctx), "/") + ref
+		header = "<" + ref + ">; rel=\"describedBy\""
+	}
+	if t.BaseURL != nil {
+		base := strings.TrimSuffix(t.BaseURL(ctx), "/")
+		ctx.AppendHeader("Link", "<"+base+ref+">; rel=\"describedBy\"")
+		buf.WriteString(base)
 	} else {
-		buf.WriteString("https://")
+		ctx.AppendHeader("Link", header)
+		host := ctx.Host()
+		if len(host) >= 9 && (host[:9] == "localhost" || host[:9] == "127.0.0.1") {
+			buf.WriteString("http://")
+		} else {
+			buf.WriteString("https://")
+		}
+		buf.WriteString(host)
 	}
-	buf.WriteString(host)
-	buf.WriteString(info.ref)
-	tmp.Field(0).SetString(buf.String())
+	buf.WriteString(ref)
+	schemaURL := buf.String()
 	buf.Reset()
 	bufPool.Put(buf)
 
+	if em, ok := v.(*ErrorModel); ok && len(em.Extensions) > 0 {
+		// Copying the fields would drop the extension members, so marshal the
+		// error and add the field to the result instead.
+		b, err := json.Marshal(em)
+		if err != nil {
+			return v, err
+		}
+		var m map[string]any
+		if err := json.Unmarshal(b, &m); err != nil || m == nil {
+			return v, nil
+		}
+		m["$schema"] = schemaURL
+		return m, nil
+	}
+
+	tmp := reflect.New(info.t).Elem()
+	tmp.Field(0).SetString(schemaURL)
+
 	// Copy over all the exported fields.
 	vv = reflect.Indirect(vv)
 	for i, j := range info.fields {
@@ -198,3 +270,661 @@ func (t *SchemaLinkTransformer) Transform(ctx Context, status string, v any) (an
 
 	return tmp.Addr().Interface(), nil
 }
+
+// responseSchema returns the documented body schema for the response status,
+// preferring JSON if multiple content types are available. Envelopes are
+// skipped so the schema describes the body returned by the handler.
+func responseSchema(op *Operation, status string) *Schema {
+	s := documentedResponseSchema(op, status)
+	if s != nil && s.enveloped != nil {
+		return s.enveloped
+	}
+	return s
+}
+
+func documentedResponseSchema(op *Operation, status string) *Schema {
+	resp := op.Responses[status]
+	if resp == nil {
+		resp = op.Responses["default"]
+	}
+	if resp == nil || resp.Content == nil {
+		return nil
+	}
+	if mt := resp.Content["application/json"]; mt != nil {
+		return mt.Schema
+	}
+	for _, mt := range resp.Content {
+		return mt.Schema
+	}
+	return nil
+}
+
+// derefSchema follows any references to return the actual schema.
+func derefSchema(r Registry, s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		s = r.SchemaFromRef(s.Ref)
+	}
+	return s
+}
+
+// schemaContains returns whether the schema or any of its property, item, or
+// additional property schemas match.
+func schemaContains(r Registry, s *Schema, match func(*Schema) bool, visited map[*Schema]bool) bool {
+	if match(s) {
+		return true
+	}
+	s = derefSchema(r, s)
+	if s == nil || visited[s] {
+		return false
+	}
+	visited[s] = true
+
+	if s.Items != nil && schemaContains(r, s.Items, match, visited) {
+		return true
+	}
+	if ap, ok := s.AdditionalProperties.(*Schema); ok && schemaContains(r, ap, match, visited) {
+		return true
+	}
+	for _, prop := range s.Properties {
+		if schemaContains(r, prop, match, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaFilter finds the properties of response data whose schema matches a
+// predicate, like write-only or hidden properties. It is shared by the
+// transformers which strip or mask such properties, which only need to supply
+// the predicate and what to do with each property.
+type schemaFilter struct {
+	registry Registry
+	match    func(*Schema) bool
+
+	// checks caches whether a schema (or any of its children) contains
+	// matching properties.
+	checks sync.Map
+}
+
+// contains returns whether the schema or any of its children contains a
+// matching property.
+func (f *schemaFilter) contains(s *Schema) bool {
+	if cached, ok := f.checks.Load(s); ok {
+		return cached.(bool)
+	}
+	found := schemaContains(f.registry, s, f.match, map[*Schema]bool{})
+	f.checks.Store(s, found)
+	return found
+}
+
+// propAction tells `schemaFilter.walk` what to do with a property.
+type propAction int
+
+const (
+	// propDescend walks the property's value.
+	propDescend propAction = iota
+
+	// propReplace replaces the property's value without walking it.
+	propReplace
+
+	// propRemove removes the property.
+	propRemove
+)
+
+// walk walks generic JSON data along with its schema, calling `visit` for
+// each object property with its schema (nil if undocumented), path, and
+// value. Data without a schema is only walked if `untyped` is set.
+func (f *schemaFilter) walk(s *Schema, pb *PathBuffer, data any, untyped bool, visit func(prop *Schema, pb *PathBuffer, value any) (any, propAction)) any {
+	s = derefSchema(f.registry, s)
+	if s == nil && !untyped {
+		return data
+	}
+
+	switch value := data.(type) {
+	case map[string]any:
+		for k, item := range value {
+			var prop *Schema
+			if s != nil {
+				if prop = s.Properties[k]; prop == nil {
+					prop, _ = s.AdditionalProperties.(*Schema)
+				}
+			}
+			pb.Push(k)
+			switch replaced, action := visit(prop, pb, item); action {
+			case propRemove:
+				delete(value, k)
+			case propReplace:
+				value[k] = replaced
+			default:
+				value[k] = f.walk(prop, pb, item, untyped, visit)
+			}
+			pb.Pop()
+		}
+	case []any:
+		var items *Schema
+		if s != nil {
+			items = s.Items
+		}
+		for i, item := range value {
+			pb.PushIndex(i)
+			value[i] = f.walk(items, pb, item, untyped, visit)
+			pb.Pop()
+		}
+	}
+	return data
+}
+
+// genericData converts the value to generic JSON data, e.g. `map[string]any`,
+// so that its properties can be modified.
+func genericData(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var data any
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// stripIndexes removes array indexes from a path, e.g. `items[0].email`
+// becomes `items.email` and `[0].email` becomes `email`.
+func stripIndexes(path string) string {
+	if !strings.Contains(path, "[") {
+		return path
+	}
+	var sb strings.Builder
+	depth := 0
+	for _, r := range path {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case depth == 0:
+			sb.WriteRune(r)
+		}
+	}
+	return strings.TrimPrefix(sb.String(), ".")
+}
+
+// envelope is the wrapper written by the `EnvelopeTransformer`.
+type envelope struct {
+	Data any            `json:"data"`
+	Meta map[string]any `json:"meta,omitempty"`
+}
+
+// EnvelopeTransformer is a transform that wraps successful JSON response
+// bodies in a standard envelope like `{"data": ..., "meta": {...}}`, for APIs
+// whose guidelines require one, without changing every output struct. The
+// documented response schemas are wrapped to match. Error responses are not
+// wrapped. Enveloping can be disabled for a specific operation by setting its
+// `envelope` metadata field to `false`.
+//
+// Add the transformer after any others so that they operate on the response
+// body returned by the handler rather than on the envelope. The `$schema`
+// link, if enabled, describes the wrapped body.
+//
+//	config := huma.DefaultConfig("My API", "1.0.0")
+//	envelope := huma.NewEnvelopeTransformer()
+//	config.OnAddOperation = append(config.OnAddOperation, envelope.OnAddOperation)
+//	config.Transformers = append(config.Transformers, envelope.Transform)
+type EnvelopeTransformer struct {
+	// Meta returns the metadata to include in the envelope for the current
+	// response, e.g. a request ID or pagination info. If nil or empty, the
+	// `meta` field is omitted.
+	Meta func(ctx Context, status string) map[string]any
+}
+
+// NewEnvelopeTransformer creates a new transformer that wraps successful
+// response bodies in an envelope.
+func NewEnvelopeTransformer() *EnvelopeTransformer {
+	return &EnvelopeTransformer{}
+}
+
+// OnAddOperation is triggered whenever a new operation is added to the API,
+// wrapping the schemas of its successful JSON responses in the envelope.
+func (t *EnvelopeTransformer) OnAddOperation(oapi *OpenAPI, op *Operation) {
+	if b, ok := op.Metadata["envelope"].(bool); ok && !b {
+		return
+	}
+
+	for status, resp := range op.Responses {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		for ct, content := range resp.Content {
+			if content == nil || content.Schema == nil || content.Schema.enveloped != nil {
+				continue
+			}
+			if ct != "application/json" && !strings.HasSuffix(ct, "+json") {
+				continue
+			}
+			wrapper := &Schema{
+				Type: TypeObject,
+				Properties: map[string]*Schema{
+					"data": content.Schema,
+					"meta": {
+						Type:                 TypeObject,
+						Description:          "Additional information about the response.",
+						AdditionalProperties: true,
+					},
+				},
+				Required:             []string{"data"},
+				AdditionalProperties: false,
+				enveloped:            content.Schema,
+			}
+			wrapper.PrecomputeMessages()
+			content.Schema = wrapper
+		}
+	}
+}
+
+// Transform is called for every response to wrap the body in the envelope
+// when its documented schema is enveloped.
+func (t *EnvelopeTransformer) Transform(ctx Context, status string, v any) (any, error) {
+	op := ctx.Operation()
+	if op == nil || !strings.HasPrefix(status, "2") {
+		return v, nil
+	}
+
+	if s := documentedResponseSchema(op, status); s == nil || s.enveloped == nil {
+		return v, nil
+	}
+
+	e := &envelope{Data: v}
+	if t.Meta != nil {
+		e.Meta = t.Meta(ctx, status)
+	}
+	return e, nil
+}
+
+// WriteOnlyMode controls how a `WriteOnlyTransformer` handles write-only
+// fields which are set in response bodies.
+type WriteOnlyMode int
+
+const (
+	// WriteOnlyStrip removes write-only fields from response bodies before
+	// they are serialized.
+	WriteOnlyStrip WriteOnlyMode = iota
+
+	// WriteOnlyError fails the response with an error describing the leaked
+	// fields. This is useful to catch mistakes during development & testing.
+	WriteOnlyError
+)
+
+// WriteOnlyTransformer is a transform that ensures fields marked as
+// `writeOnly` (e.g. passwords) do not leak into response bodies. Validation
+// of requests already ignores read-only fields, and this provides the
+// symmetric check for responses using the operation's documented response
+// schema. Responses without any write-only fields set are passed through
+// unmodified. When stripping, the body is converted to generic data so field
+// order may change.
+//
+//	config := huma.DefaultConfig("My API", "1.0.0")
+//	wo := huma.NewWriteOnlyTransformer(config.Components.Schemas, huma.WriteOnlyError)
+//	config.Transformers = append(config.Transformers, wo.Transform)
+type WriteOnlyTransformer struct {
+	mode   WriteOnlyMode
+	filter schemaFilter
+}
+
+// NewWriteOnlyTransformer creates a new transformer which uses the given
+// registry to resolve schema references.
+func NewWriteOnlyTransformer(registry Registry, mode WriteOnlyMode) *WriteOnlyTransformer {
+	return &WriteOnlyTransformer{
+		mode: mode,
+		filter: schemaFilter{
+			registry: registry,
+			match:    func(s *Schema) bool { return s.WriteOnly },
+		},
+	}
+}
+
+// Transform is called for every response to check for write-only fields.
+func (t *WriteOnlyTransformer) Transform(ctx Context, status string, v any) (any, error) {
+	op := ctx.Operation()
+	if op == nil || v == nil {
+		return v, nil
+	}
+
+	s := responseSchema(op, status)
+	if s == nil || !t.filter.contains(s) {
+		return v, nil
+	}
+
+	data, err := genericData(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove any non-zero write-only properties, recording their paths.
+	var leaked []string
+	data = t.filter.walk(s, NewPathBuffer([]byte{}, 0), data, false, func(prop *Schema, pb *PathBuffer, value any) (any, propAction) {
+		if prop != nil && prop.WriteOnly && value != nil && !reflect.ValueOf(value).IsZero() {
+			leaked = append(leaked, pb.String())
+			return nil, propRemove
+		}
+		return nil, propDescend
+	})
+	if len(leaked) == 0 {
+		return v, nil
+	}
+	if t.mode == WriteOnlyError {
+		slices.Sort(leaked)
+		return nil, fmt.Errorf("write only fields in response: %s", strings.Join(leaked, ", "))
+	}
+	return data, nil
+}
+
+// RedactTransformer is a transform that masks sensitive values like emails or
+// tokens in response bodies. Fields tagged with `redact:"true"` are masked
+// everywhere and are annotated with an `x-redact` schema extension so
+// consumers know the value may be masked. Additional fields can be masked for
+// a single operation by listing their dot-separated paths (ignoring array
+// indexes) in the operation's `redact` metadata field, e.g.
+// `[]string{"owner.email"}`.
+//
+// The values echoed back in error details are also masked when they come from
+// a redacted request body field or parameter, so that e.g. a password which
+// fails validation is not returned to the client or written to logs.
+//
+//	config := huma.DefaultConfig("My API", "1.0.0")
+//	redact := huma.NewRedactTransformer(config.Components.Schemas)
+//	config.Transformers = append(config.Transformers, redact.Transform)
+type RedactTransformer struct {
+	// Mask returns the replacement for a redacted value at the given path. If
+	// nil, values are replaced with `***`.
+	Mask func(path string, value any) any
+
+	filter schemaFilter
+}
+
+// NewRedactTransformer creates a new transformer which uses the given registry
+// to resolve schema references.
+func NewRedactTransformer(registry Registry) *RedactTransformer {
+	return &RedactTransformer{
+		filter: schemaFilter{registry: registry, match: isRedacted},
+	}
+}
+
+func isRedacted(s *Schema) bool {
+	return s != nil && s.Extensions["x-redact"] == true
+}
+
+func (t *RedactTransformer) mask(path string, value any) any {
+	if t.Mask != nil {
+		return t.Mask(path, value)
+	}
+	return "***"
+}
+
+// Transform is called for every response to mask redacted fields.
+func (t *RedactTransformer) Transform(ctx Context, status string, v any) (any, error) {
+	op := ctx.Operation()
+	if op == nil || v == nil {
+		return v, nil
+	}
+
+	var fields []string
+	if op.Metadata != nil {
+		fields, _ = op.Metadata["redact"].([]string)
+	}
+
+	if em, ok := v.(*ErrorModel); ok {
+		return t.redactErrors(op, fields, em), nil
+	}
+
+	s := responseSchema(op, status)
+	if s == nil {
+		return v, nil
+	}
+	if len(fields) == 0 && !t.filter.contains(s) {
+		return v, nil
+	}
+
+	data, err := genericData(v)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := false
+	data = t.walk(s, "", data, fields, &changed)
+	if !changed {
+		return v, nil
+	}
+	return data, nil
+}
+
+// walk masks redacted values in the data, which has the given path.
+func (t *RedactTransformer) walk(s *Schema, path string, data any, fields []string, changed *bool) any {
+	// Fields listed in the metadata may be within undocumented data.
+	return t.filter.walk(s, NewPathBuffer([]byte(path), len(path)), data, true, func(prop *Schema, pb *PathBuffer, value any) (any, propAction) {
+		if value == nil {
+			return nil, propDescend
+		}
+		p := stripIndexes(pb.String())
+		if isRedacted(prop) || slices.Contains(fields, p) {
+			*changed = true
+			return t.mask(p, value), propReplace
+		}
+		return nil, propDescend
+	})
+}
+
+// redactErrors returns a copy of the error model with values from redacted
+// request fields masked.
+func (t *RedactTransformer) redactErrors(op *Operation, fields []string, em *ErrorModel) *ErrorModel {
+	var copied *ErrorModel
+	for i, detail := range em.Errors {
+		if detail == nil || detail.Value == nil {
+			continue
+		}
+
+		var value any
+		s, path, redacted := t.locate(op, fields, detail.Location)
+		if redacted {
+			value = t.mask(detail.Location, detail.Value)
+		} else if s != nil {
+			// The value may be an object or array containing redacted fields.
+			switch detail.Value.(type) {
+			case map[string]any, []any:
+				b, err := json.Marshal(detail.Value)
+				if err != nil {
+					continue
+				}
+				if err := json.Unmarshal(b, &value); err != nil {
+					continue
+				}
+				changed := false
+				value = t.walk(s, path, value, fields, &changed)
+				if !changed {
+					continue
+				}
+			default:
+				continue
+			}
+		} else {
+			continue
+		}
+
+		if copied == nil {
+			tmp := *em
+			tmp.Errors = slices.Clone(em.Errors)
+			copied = &tmp
+		}
+		d := *detail
+		d.Value = value
+		copied.Errors[i] = &d
+	}
+	if copied == nil {
+		return em
+	}
+	return copied
+}
+
+// locate finds the request body schema and body-relative path for an error
+// location like `body.items[0].email`, and whether the location refers to a
+// redacted field or parameter like `query.token`.
+func (t *RedactTransformer) locate(op *Operation, fields []string, location string) (*Schema, string, bool) {
+	location = stripIndexes(location)
+
+	in, path, _ := strings.Cut(location, ".")
+	if in != "body" {
+		if slices.Contains(fields, location) {
+			return nil, "", true
+		}
+		for _, p := range op.Parameters {
+			if p.In == in && p.Name == path {
+				return nil, "", isRedacted(p.Schema)
+			}
+		}
+		return nil, "", false
+	}
+
+	if path != "" && slices.Contains(fields, path) {
+		return nil, path, true
+	}
+	if op.RequestBody == nil || op.RequestBody.Content["application/json"] == nil {
+		return nil, path, false
+	}
+	s := op.RequestBody.Content["application/json"].Schema
+	if path == "" {
+		return s, path, false
+	}
+	for _, part := range strings.Split(path, ".") {
+		s = derefSchema(t.filter.registry, s)
+		for s != nil && s.Type == TypeArray {
+			s = derefSchema(t.filter.registry, s.Items)
+		}
+		if s == nil {
+			return nil, path, false
+		}
+		next := s.Properties[part]
+		if next == nil {
+			next, _ = s.AdditionalProperties.(*Schema)
+		}
+		if isRedacted(next) {
+			return nil, path, true
+		}
+		s = next
+	}
+	return s, path, false
+}
+
+// VisibilityTransformer hides fields tagged with a `visibility` like
+// `visibility:"internal"` from an API, so that e.g. public and internal API
+// instances can share the same structs. Matching properties are removed from
+// the API's OpenAPI document, are no longer required in request bodies, and
+// are stripped from response bodies. Each API instance must use its own
+// config & registry, as the registry's schemas are modified. When stripping,
+// the body is converted to generic data so field order may change.
+//
+//	config := huma.DefaultConfig("My API", "1.0.0")
+//	vis := huma.NewVisibilityTransformer(config.Components.Schemas, "internal")
+//	config.OnAddOperation = append(config.OnAddOperation, vis.OnAddOperation)
+//	config.Transformers = append(config.Transformers, vis.Transform)
+type VisibilityTransformer struct {
+	hidden map[string]bool
+	filter schemaFilter
+}
+
+// NewVisibilityTransformer creates a new transformer which hides fields with
+// any of the given visibilities, using the registry to resolve references.
+func NewVisibilityTransformer(registry Registry, hidden ...string) *VisibilityTransformer {
+	t := &VisibilityTransformer{hidden: map[string]bool{}}
+	for _, v := range hidden {
+		t.hidden[v] = true
+	}
+	t.filter.registry = registry
+	t.filter.match = t.isHidden
+	return t
+}
+
+func (t *VisibilityTransformer) isHidden(s *Schema) bool {
+	v, ok := s.Extensions["x-visibility"].(string)
+	return ok && t.hidden[v]
+}
+
+// OnAddOperation is triggered whenever a new operation is added to the API,
+// hiding the matching properties of the registry's and operation's schemas.
+func (t *VisibilityTransformer) OnAddOperation(oapi *OpenAPI, op *Operation) {
+	visited := map[*Schema]bool{}
+	for _, s := range t.filter.registry.Map() {
+		t.hide(s, visited)
+	}
+	if op.RequestBody != nil {
+		for _, content := range op.RequestBody.Content {
+			t.hide(content.Schema, visited)
+		}
+	}
+	for _, resp := range op.Responses {
+		for _, content := range resp.Content {
+			t.hide(content.Schema, visited)
+		}
+	}
+}
+
+// hide marks the matching properties of the schema and its children as hidden
+// and removes them from the required properties.
+func (t *VisibilityTransformer) hide(s *Schema, visited map[*Schema]bool) {
+	if s == nil || visited[s] {
+		return
+	}
+	visited[s] = true
+
+	changed := false
+	for name, prop := range s.Properties {
+		if t.isHidden(prop) {
+			prop.hidden = true
+			if i := slices.Index(s.Required, name); i != -1 {
+				s.Required = slices.Delete(s.Required, i, i+1)
+				changed = true
+			}
+			continue
+		}
+		t.hide(prop, visited)
+	}
+	if changed {
+		s.PrecomputeMessages()
+	}
+
+	t.hide(s.Items, visited)
+	if ap, ok := s.AdditionalProperties.(*Schema); ok {
+		t.hide(ap, visited)
+	}
+	for _, sub := range s.AllOf {
+		t.hide(sub, visited)
+	}
+	for _, sub := range s.AnyOf {
+		t.hide(sub, visited)
+	}
+	for _, sub := range s.OneOf {
+		t.hide(sub, visited)
+	}
+}
+
+// Transform is called for every response to strip hidden fields.
+func (t *VisibilityTransformer) Transform(ctx Context, status string, v any) (any, error) {
+	op := ctx.Operation()
+	if op == nil || v == nil {
+		return v, nil
+	}
+
+	s := responseSchema(op, status)
+	if s == nil || !t.filter.contains(s) {
+		return v, nil
+	}
+
+	data, err := genericData(v)
+	if err != nil {
+		return nil, err
+	}
+	return t.filter.walk(s, NewPathBuffer([]byte{}, 0), data, false, func(prop *Schema, pb *PathBuffer, value any) (any, propAction) {
+		if prop != nil && t.isHidden(prop) {
+			return nil, propRemove
+		}
+		return nil, propDescend
+	}), nil
+}
@@ -0,0 +1,677 @@
+package huma
+
+import (
+	"bytes"
+	"cmp"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is a `time.Duration` which is represented as a human-friendly
+// string like `5s` or `1h30m` in request parameters as well as request and
+// response bodies. It can be used anywhere you would use a `time.Duration`:
+//
+//	type MyInput struct {
+//		Timeout huma.Duration `query:"timeout" default:"30s"`
+//	}
+//
+//	func handler(ctx context.Context, input *MyInput) (*MyOutput, error) {
+//		ctx, cancel := context.WithTimeout(ctx, input.Timeout.Duration())
+//		defer cancel()
+//		// ...
+//	}
+type Duration time.Duration
+
+// Duration returns the value as a standard library `time.Duration`.
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// String returns the duration formatted like `1h30m0s`.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalText implements `encoding.TextMarshaler`, which is also used when
+// marshaling to JSON.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements `encoding.TextUnmarshaler`, which is also used when
+// unmarshaling from JSON. See `time.ParseDuration` for the supported format.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Schema implements `huma.SchemaProvider`.
+func (d Duration) Schema(r Registry) *Schema {
+	return &Schema{
+		Type:        TypeString,
+		Description: "Duration with a unit suffix, e.g. `300ms`, `5s`, or `1h30m`.",
+		Pattern:     `^[-+]?(0|(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$`,
+		Examples:    []any{"5s"},
+	}
+}
+
+// ByteSize is a number of bytes which is represented as a human-friendly string
+// like `512KB` or `10MiB` in request parameters as well as request and response
+// bodies. Decimal (`KB`, `MB`, ...) units are powers of 1000 while binary
+// (`KiB`, `MiB`, ...) units are powers of 1024. A number without a unit is
+// treated as bytes.
+//
+//	type MyInput struct {
+//		MaxSize huma.ByteSize `query:"max-size" default:"10MB"`
+//	}
+type ByteSize int64
+
+// Common byte sizes, e.g. `10 * huma.Megabyte`.
+const (
+	Byte     ByteSize = 1
+	Kilobyte          = 1000 * Byte
+	Megabyte          = 1000 * Kilobyte
+	Gigabyte          = 1000 * Megabyte
+	Terabyte          = 1000 * Gigabyte
+	Petabyte          = 1000 * Terabyte
+	Exabyte           = 1000 * Petabyte
+	Kibibyte          = 1024 * Byte
+	Mebibyte          = 1024 * Kibibyte
+	Gibibyte          = 1024 * Mebibyte
+	Tebibyte          = 1024 * Gibibyte
+	Pebibyte          = 1024 * Tebibyte
+	Exbibyte          = 1024 * Pebibyte
+)
+
+// byteSizeUnits are ordered from largest to smallest so the most compact
+// representation is picked when formatting.
+var byteSizeUnits = []struct {
+	name string
+	size ByteSize
+}{
+	{"EiB", Exbibyte}, {"EB", Exabyte},
+	{"PiB", Pebibyte}, {"PB", Petabyte},
+	{"TiB", Tebibyte}, {"TB", Terabyte},
+	{"GiB", Gibibyte}, {"GB", Gigabyte},
+	{"MiB", Mebibyte}, {"MB", Megabyte},
+	{"KiB", Kibibyte}, {"KB", Kilobyte},
+}
+
+// ParseByteSize parses a string like `10MB`, `1.5GiB`, or `512` into a
+// `ByteSize`.
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i == -1 {
+		i = len(s)
+	}
+	num, unit := s[:i], strings.TrimSpace(s[i:])
+	if num == "" {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+
+	multiplier := Byte
+	switch strings.TrimSuffix(strings.ToUpper(unit), "B") {
+	case "":
+	case "K":
+		multiplier = Kilobyte
+	case "M":
+		multiplier = Megabyte
+	case "G":
+		multiplier = Gigabyte
+	case "T":
+		multiplier = Terabyte
+	case "P":
+		multiplier = Petabyte
+	case "E":
+		multiplier = Exabyte
+	case "KI":
+		multiplier = Kibibyte
+	case "MI":
+		multiplier = Mebibyte
+	case "GI":
+		multiplier = Gibibyte
+	case "TI":
+		multiplier = Tebibyte
+	case "PI":
+		multiplier = Pebibyte
+	case "EI":
+		multiplier = Exbibyte
+	default:
+		return 0, fmt.Errorf("invalid byte size unit %q", unit)
+	}
+
+	if !strings.Contains(num, ".") {
+		// Use integer math where possible to avoid rounding errors.
+		n, err := strconv.ParseInt(num, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid byte size %q", s)
+		}
+		if n > math.MaxInt64/int64(multiplier) {
+			return 0, fmt.Errorf("byte size %q is too large", s)
+		}
+		return ByteSize(n) * multiplier, nil
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+	f *= float64(multiplier)
+	if f >= math.MaxInt64 {
+		return 0, fmt.Errorf("byte size %q is too large", s)
+	}
+	return ByteSize(f), nil
+}
+
+// String returns the size using the largest unit which represents it exactly,
+// e.g. `10MB`, `1536KiB`, or `100B`.
+func (b ByteSize) String() string {
+	if b != 0 {
+		for _, u := range byteSizeUnits {
+			if b%u.size == 0 {
+				return strconv.FormatInt(int64(b/u.size), 10) + u.name
+			}
+		}
+	}
+	return strconv.FormatInt(int64(b), 10) + "B"
+}
+
+// MarshalText implements `encoding.TextMarshaler`, which is also used when
+// marshaling to JSON.
+func (b ByteSize) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText implements `encoding.TextUnmarshaler`, which is also used when
+// unmarshaling from JSON. See `ParseByteSize` for the supported format.
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	parsed, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*b = parsed
+	return nil
+}
+
+// Schema implements `huma.SchemaProvider`.
+func (b ByteSize) Schema(r Registry) *Schema {
+	return &Schema{
+		Type:        TypeString,
+		Description: "Size with an optional decimal (`KB`, `MB`, ...) or binary (`KiB`, `MiB`, ...) unit suffix, e.g. `512KB` or `10MiB`.",
+		Pattern:     `^[0-9]+(\.[0-9]+)? ?([kKmMgGtTpPeE][iI]?)?[bB]?$`,
+		Examples:    []any{"10MB"},
+	}
+}
+
+// Decimal is an exact decimal number like `-12.34` which is represented as a
+// string in request parameters as well as request and response bodies, so no
+// precision is lost to floating point conversions. This makes it suitable for
+// e.g. money. The number of digits can be limited with the `precision` (total
+// digits) and `scale` (digits after the decimal point) field tags:
+//
+//	type Payment struct {
+//		Amount huma.Decimal `json:"amount" precision:"10" scale:"2"`
+//	}
+//
+// Trailing zeros are significant and preserved, so `1.50` round-trips as-is.
+type Decimal struct {
+	value string
+}
+
+// ParseDecimal parses a string like `12`, `-0.5`, or `+12.340` into a
+// `Decimal`. Exponents are not supported.
+func ParseDecimal(s string) (Decimal, error) {
+	digits := strings.TrimLeft(s, "+-")
+	if len(s)-len(digits) > 1 {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	intPart, fracPart, hasFrac := strings.Cut(digits, ".")
+	if intPart == "" || (hasFrac && fracPart == "") {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	for _, part := range []string{intPart, fracPart} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+			}
+		}
+	}
+
+	intPart = strings.TrimLeft(intPart, "0")
+	if intPart == "" {
+		intPart = "0"
+	}
+	value := intPart
+	if hasFrac {
+		value += "." + fracPart
+	}
+	if strings.HasPrefix(s, "-") && strings.Trim(value, "0.") != "" {
+		value = "-" + value
+	}
+	return Decimal{value: value}, nil
+}
+
+// String returns the decimal formatted like `-12.34`. The zero value is `0`.
+func (d Decimal) String() string {
+	if d.value == "" {
+		return "0"
+	}
+	return d.value
+}
+
+// IsZero returns whether the decimal is equal to zero.
+func (d Decimal) IsZero() bool {
+	return strings.Trim(d.value, "0.") == ""
+}
+
+// Rat returns the decimal as an exact `*big.Rat` for calculations.
+func (d Decimal) Rat() *big.Rat {
+	r, _ := new(big.Rat).SetString(d.String())
+	return r
+}
+
+// Cmp compares two decimals and returns -1, 0, or 1.
+func (d Decimal) Cmp(other Decimal) int {
+	return d.Rat().Cmp(other.Rat())
+}
+
+// MarshalText implements `encoding.TextMarshaler`, which is also used when
+// marshaling to JSON.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements `encoding.TextUnmarshaler`, which is also used when
+// unmarshaling from JSON. See `ParseDecimal` for the supported format.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Schema implements `huma.SchemaProvider`.
+func (d Decimal) Schema(r Registry) *Schema {
+	return &Schema{
+		Type:        TypeString,
+		Format:      "decimal",
+		Description: "Exact decimal number, e.g. `12.34`.",
+		Pattern:     `^[-+]?[0-9]+(\.[0-9]+)?$`,
+		Examples:    []any{"12.34"},
+	}
+}
+
+// Cents is an amount of money in cents, i.e. hundredths of the currency unit,
+// so `1234` is `12.34`. It is represented as an integer in request parameters
+// as well as request and response bodies, which avoids the rounding errors of
+// floating point money without requiring clients to parse decimal strings:
+//
+//	type Order struct {
+//		Total huma.Cents `json:"total" minimum:"0"`
+//	}
+//
+// The schema documents the unit via the `x-unit` and `x-scale` extensions.
+// Use `String()` to format the amount like `12.34`.
+type Cents int64
+
+// ParseCents parses a decimal string like `12.34` or `-5` into `Cents`. At most
+// two decimal places are allowed.
+func ParseCents(s string) (Cents, error) {
+	v, err := parseScaled(s, 2)
+	return Cents(v), err
+}
+
+// String returns the amount formatted like `12.34`.
+func (c Cents) String() string {
+	return formatScaled(int64(c), 2)
+}
+
+// Decimal returns the amount as an exact `huma.Decimal` like `12.34`.
+func (c Cents) Decimal() Decimal {
+	return Decimal{value: c.String()}
+}
+
+// Schema implements `huma.SchemaProvider`.
+func (c Cents) Schema(r Registry) *Schema {
+	return scaledSchema("cents", 2, "Amount in cents, i.e. hundredths of the currency unit, e.g. `1234` for `12.34`.")
+}
+
+// Micros is an amount in micros, i.e. millionths of the currency unit, so
+// `12345678` is `12.345678`. This is useful for prices below a cent, like
+// per-request billing or exchange rates. It is represented as an integer like
+// `huma.Cents`.
+type Micros int64
+
+// ParseMicros parses a decimal string like `0.000125` into `Micros`. At most
+// six decimal places are allowed.
+func ParseMicros(s string) (Micros, error) {
+	v, err := parseScaled(s, 6)
+	return Micros(v), err
+}
+
+// String returns the amount formatted like `12.345678`.
+func (m Micros) String() string {
+	return formatScaled(int64(m), 6)
+}
+
+// Decimal returns the amount as an exact `huma.Decimal` like `12.345678`.
+func (m Micros) Decimal() Decimal {
+	return Decimal{value: m.String()}
+}
+
+// Schema implements `huma.SchemaProvider`.
+func (m Micros) Schema(r Registry) *Schema {
+	return scaledSchema("micros", 6, "Amount in micros, i.e. millionths of the currency unit, e.g. `12345678` for `12.345678`.")
+}
+
+// scaledSchema returns the integer schema of a fixed-point amount with the
+// given unit and number of decimal places.
+func scaledSchema(unit string, scale int, description string) *Schema {
+	one := 1.0
+	return &Schema{
+		Type:        TypeInteger,
+		Format:      "int64",
+		Description: description,
+		MultipleOf:  &one,
+		Examples:    []any{1234},
+		Extensions: map[string]any{
+			"x-unit":  unit,
+			"x-scale": scale,
+		},
+	}
+}
+
+// formatScaled formats the integer with the given number of decimal places,
+// e.g. `-5` with a scale of 2 is `-0.05`.
+func formatScaled(v int64, scale int) string {
+	digits := strconv.FormatInt(v, 10)
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
+
+// parseScaled parses a decimal string into an integer with the given number of
+// decimal places, e.g. `12.3` with a scale of 2 is `1230`.
+func parseScaled(s string, scale int) (int64, error) {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		return 0, err
+	}
+	intPart, fracPart, _ := strings.Cut(d.String(), ".")
+	if len(fracPart) > scale {
+		return 0, fmt.Errorf("invalid amount %q: at most %d decimal places allowed", s, scale)
+	}
+	v, err := strconv.ParseInt(intPart+fracPart+strings.Repeat("0", scale-len(fracPart)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: out of range", s)
+	}
+	return v, nil
+}
+
+// decimalPattern returns a pattern which limits decimals to the given total
+// number of digits (precision) and digits after the decimal point (scale),
+// either of which may be nil.
+func decimalPattern(precision, scale *int) (pattern, description string) {
+	switch {
+	case precision == nil:
+		return fmt.Sprintf(`^[-+]?[0-9]+(\.[0-9]{1,%d})?$`, *scale),
+			fmt.Sprintf("decimal with at most %d decimal places", *scale)
+	case scale == nil || *scale == 0:
+		return fmt.Sprintf(`^[-+]?0*[0-9]{1,%d}$`, *precision),
+			fmt.Sprintf("integer with at most %d digits", *precision)
+	}
+	intPattern := "0+"
+	if *precision > *scale {
+		intPattern = fmt.Sprintf("0*[0-9]{1,%d}", *precision-*scale)
+	}
+	return fmt.Sprintf(`^[-+]?%s(\.[0-9]{1,%d})?$`, intPattern, *scale),
+		fmt.Sprintf("decimal with at most %d digits and %d decimal places", *precision, *scale)
+}
+
+// Nullable is a value which may be `null`, represented on the wire as the
+// wrapped type or `null`. The schema is that of the wrapped type with `null`
+// added to its allowed types, which is downgraded to `nullable: true` for
+// OpenAPI 3.0. It implements `sql.Scanner` so it can be read directly from
+// nullable database columns, while `Ptr()` can be used to write them.
+//
+//	type User struct {
+//		Nickname huma.Nullable[string] `json:"nickname" maxLength:"20"`
+//	}
+//
+// Objects cannot be made nullable, the same as the `nullable` field tag.
+type Nullable[T any] struct {
+	Null  bool
+	Value T
+}
+
+// NewNullable returns a non-null value.
+func NewNullable[T any](v T) Nullable[T] {
+	return Nullable[T]{Value: v}
+}
+
+// Ptr returns a pointer to the value, or nil if it is null. This is useful
+// as a `database/sql` query argument.
+func (n Nullable[T]) Ptr() *T {
+	if n.Null {
+		return nil
+	}
+	return &n.Value
+}
+
+// MarshalJSON marshals the value or `null`.
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if n.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+// UnmarshalJSON unmarshals the value or `null`.
+func (n *Nullable[T]) UnmarshalJSON(b []byte) error {
+	*n = Nullable[T]{}
+	if bytes.Equal(b, []byte("null")) {
+		n.Null = true
+		return nil
+	}
+	return json.Unmarshal(b, &n.Value)
+}
+
+// Scan implements `sql.Scanner`.
+func (n *Nullable[T]) Scan(src any) error {
+	var v sql.Null[T]
+	if err := v.Scan(src); err != nil {
+		return err
+	}
+	*n = Nullable[T]{Null: !v.Valid, Value: v.V}
+	return nil
+}
+
+// Schema implements `huma.SchemaProvider`.
+func (n Nullable[T]) Schema(r Registry) *Schema {
+	return nullableSchema(r, reflect.TypeFor[T]())
+}
+
+// Omittable is a value which may be omitted, set to `null`, or set to a value.
+// Each state is tracked so handlers can tell them apart, e.g. for partial
+// updates where omitting a field leaves it unchanged while `null` clears it.
+// Use `omitempty` to make the field optional. The schema is the same as for
+// `Nullable`.
+//
+//	type UpdateUser struct {
+//		Nickname huma.Omittable[string] `json:"nickname,omitempty"`
+//	}
+//
+// When marshaling, an omitted value is written as `null` unless the field
+// uses the `omitzero` JSON option (Go 1.24+).
+type Omittable[T any] struct {
+	Sent  bool
+	Null  bool
+	Value T
+}
+
+// IsZero returns whether the value was omitted.
+func (o Omittable[T]) IsZero() bool {
+	return !o.Sent
+}
+
+// MarshalJSON marshals the value or `null`.
+func (o Omittable[T]) MarshalJSON() ([]byte, error) {
+	if !o.Sent || o.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+// UnmarshalJSON unmarshals the value or `null`. It is only called when the
+// field is present in the input.
+func (o *Omittable[T]) UnmarshalJSON(b []byte) error {
+	*o = Omittable[T]{Sent: true}
+	if bytes.Equal(b, []byte("null")) {
+		o.Null = true
+		return nil
+	}
+	return json.Unmarshal(b, &o.Value)
+}
+
+// Schema implements `huma.SchemaProvider`.
+func (o Omittable[T]) Schema(r Registry) *Schema {
+	return nullableSchema(r, reflect.TypeFor[T]())
+}
+
+// nullableSchema returns a copy of the schema for the type which allows `null`.
+func nullableSchema(r Registry, t reflect.Type) *Schema {
+	s := r.Schema(t, true, "")
+	if s.Ref != "" {
+		// See the `nullable` field tag for why objects are not supported.
+		panic(fmt.Errorf("nullable is not supported for type '%s' which is an object", t))
+	}
+	c := *s
+	c.Nullable = true
+	return &c
+}
+
+// AcceptLanguage is a parsed `Accept-Language` header, with the requested
+// language tags ranked from most to least preferred by their quality values.
+// Use it as a header input field to avoid parsing the header by hand:
+//
+//	type MyInput struct {
+//		Locales huma.AcceptLanguage `header:"Accept-Language"`
+//	}
+//
+//	func handler(ctx context.Context, input *MyInput) (*MyOutput, error) {
+//		locale := input.Locales.Best("en-US", "de", "fr")
+//		// ...
+//	}
+type AcceptLanguage struct {
+	// Tags are the requested BCP 47 language tags like `en-US`, most preferred
+	// first. Tags with a quality value of zero are omitted. The wildcard `*`
+	// may be present to indicate any language is acceptable.
+	Tags []string
+}
+
+// UnmarshalText implements `encoding.TextUnmarshaler`.
+func (a *AcceptLanguage) UnmarshalText(text []byte) error {
+	type ranked struct {
+		tag string
+		q   float64
+	}
+	parsed := []ranked{}
+	for _, part := range strings.Split(string(text), ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if params = strings.TrimSpace(params); params != "" {
+			value, ok := strings.CutPrefix(params, "q=")
+			if !ok {
+				return fmt.Errorf("invalid parameter %q for language %q", params, tag)
+			}
+			var err error
+			if q, err = strconv.ParseFloat(value, 64); err != nil || q < 0 || q > 1 {
+				return fmt.Errorf("invalid quality value %q for language %q", value, tag)
+			}
+		}
+		if q > 0 {
+			parsed = append(parsed, ranked{tag, q})
+		}
+	}
+	// Stable so that equal quality values keep the client's order.
+	slices.SortStableFunc(parsed, func(a, b ranked) int {
+		return cmp.Compare(b.q, a.q)
+	})
+	a.Tags = make([]string, len(parsed))
+	for i, p := range parsed {
+		a.Tags[i] = p.tag
+	}
+	return nil
+}
+
+// Best returns the supported language which best matches the requested
+// languages, preferring earlier supported languages for the wildcard `*`. A
+// requested tag matches a supported tag which is equal to it, more specific
+// than it (`en` matches `en-US`), or less specific than it (`de-AT` matches
+// `de`), compared case-insensitively. If nothing matches, the first supported
+// language is returned as the default.
+func (a AcceptLanguage) Best(supported ...string) string {
+	if len(supported) == 0 {
+		return ""
+	}
+	for _, tag := range a.Tags {
+		if tag == "*" {
+			return supported[0]
+		}
+		// Exact match, then more specific, then progressively less specific.
+		for _, s := range supported {
+			if strings.EqualFold(s, tag) {
+				return s
+			}
+		}
+		for _, s := range supported {
+			if len(s) > len(tag) && s[len(tag)] == '-' && strings.EqualFold(s[:len(tag)], tag) {
+				return s
+			}
+		}
+		for prefix := tag; strings.Contains(prefix, "-"); {
+			prefix = prefix[:strings.LastIndex(prefix, "-")]
+			for _, s := range supported {
+				if strings.EqualFold(s, prefix) {
+					return s
+				}
+			}
+		}
+	}
+	return supported[0]
+}
+
+// Schema implements `huma.SchemaProvider`.
+func (a AcceptLanguage) Schema(r Registry) *Schema {
+	return &Schema{
+		Type:        TypeString,
+		Description: "Preferred languages as BCP 47 tags with optional quality values, like `de-AT, de;q=0.9, en;q=0.5`.",
+		Examples:    []any{"en-US, en;q=0.9"},
+	}
+}
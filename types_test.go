@@ -0,0 +1,433 @@
+package huma_test
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+func TestParseByteSize(t *testing.T) {
+	for _, item := range []struct {
+		input    string
+		expected huma.ByteSize
+		err      bool
+	}{
+		{input: "0", expected: 0},
+		{input: "512", expected: 512},
+		{input: "512B", expected: 512},
+		{input: "10KB", expected: 10 * huma.Kilobyte},
+		{input: "10kb", expected: 10 * huma.Kilobyte},
+		{input: "10 MB", expected: 10 * huma.Megabyte},
+		{input: "10M", expected: 10 * huma.Megabyte},
+		{input: "10MiB", expected: 10 * huma.Mebibyte},
+		{input: "1.5GiB", expected: 1536 * huma.Mebibyte},
+		{input: "2EiB", expected: 2 * huma.Exbibyte},
+		{input: "", err: true},
+		{input: "MB", err: true},
+		{input: "-1MB", err: true},
+		{input: "10XB", err: true},
+		{input: "1.2.3KB", err: true},
+		{input: "9999EB", err: true},
+		{input: "9999.5EB", err: true},
+	} {
+		t.Run(item.input, func(t *testing.T) {
+			size, err := huma.ParseByteSize(item.input)
+			if item.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, item.expected, size)
+		})
+	}
+}
+
+func TestByteSizeString(t *testing.T) {
+	assert.Equal(t, "0B", huma.ByteSize(0).String())
+	assert.Equal(t, "100B", huma.ByteSize(100).String())
+	assert.Equal(t, "10MB", (10 * huma.Megabyte).String())
+	assert.Equal(t, "1536KiB", (1536 * huma.Kibibyte).String())
+	assert.Equal(t, "3GiB", (3 * huma.Gibibyte).String())
+}
+
+func TestFriendlyScalarTypes(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	type Limits struct {
+		Timeout huma.Duration `json:"timeout"`
+		MaxSize huma.ByteSize `json:"max_size,omitempty" default:"1KiB"`
+	}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-limits",
+		Method:      http.MethodPut,
+		Path:        "/limits",
+	}, func(ctx context.Context, input *struct {
+		Wait huma.Duration `query:"wait" default:"5s"`
+		Body Limits
+	}) (*struct{ Body Limits }, error) {
+		assert.Equal(t, 5*time.Second, input.Wait.Duration())
+		input.Body.Timeout += huma.Duration(time.Second)
+		return &struct{ Body Limits }{Body: input.Body}, nil
+	})
+
+	// Documented as strings.
+	b, _ := json.Marshal(api.OpenAPI().Paths["/limits"].Put.Parameters[0].Schema)
+	assert.Contains(t, string(b), `"type":"string"`)
+	assert.Contains(t, string(b), `"default":"5s"`)
+
+	resp := api.Put("/limits", strings.NewReader(`{"timeout": "1m30s"}`))
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), `"timeout":"1m31s"`)
+	assert.Contains(t, resp.Body.String(), `"max_size":"1KiB"`)
+
+	resp = api.Put("/limits?wait=5000ms", strings.NewReader(`{"timeout": "1s", "max_size": "10MB"}`))
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), `"max_size":"10MB"`)
+
+	resp = api.Put("/limits?wait=bad", strings.NewReader(`{"timeout": "1s"}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "query.wait")
+
+	resp = api.Put("/limits", strings.NewReader(`{"timeout": "soon", "max_size": "lots"}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "body.timeout")
+	assert.Contains(t, resp.Body.String(), "body.max_size")
+}
+
+func TestParseDecimal(t *testing.T) {
+	for _, item := range []struct {
+		input    string
+		expected string
+		err      bool
+	}{
+		{input: "0", expected: "0"},
+		{input: "12", expected: "12"},
+		{input: "-12.340", expected: "-12.340"},
+		{input: "+0012.5", expected: "12.5"},
+		{input: "-0.00", expected: "0.00"},
+		{input: "123456789012345678901234567890.123456789", expected: "123456789012345678901234567890.123456789"},
+		{input: "", err: true},
+		{input: "-", err: true},
+		{input: "--1", err: true},
+		{input: ".5", err: true},
+		{input: "5.", err: true},
+		{input: "1e3", err: true},
+		{input: "1.2.3", err: true},
+	} {
+		t.Run(item.input, func(t *testing.T) {
+			d, err := huma.ParseDecimal(item.input)
+			if item.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, item.expected, d.String())
+		})
+	}
+
+	a, _ := huma.ParseDecimal("1.50")
+	b, _ := huma.ParseDecimal("1.5")
+	assert.Equal(t, 0, a.Cmp(b))
+	assert.Equal(t, "3/2", a.Rat().String())
+	assert.True(t, huma.Decimal{}.IsZero())
+	assert.Equal(t, "0", huma.Decimal{}.String())
+}
+
+func TestDecimal(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	type Payment struct {
+		Amount huma.Decimal  `json:"amount" precision:"6" scale:"2"`
+		Fee    *huma.Decimal `json:"fee,omitempty" scale:"3"`
+		Count  huma.Decimal  `json:"count,omitempty" precision:"3"`
+	}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-payment",
+		Method:      http.MethodPut,
+		Path:        "/payment",
+	}, func(ctx context.Context, input *struct {
+		Min  huma.Decimal `query:"min"`
+		Body Payment
+	}) (*struct{ Body Payment }, error) {
+		if input.Body.Amount.Cmp(input.Min) < 0 {
+			return nil, huma.Error400BadRequest("amount too small")
+		}
+		return &struct{ Body Payment }{Body: input.Body}, nil
+	})
+
+	b, _ := json.Marshal(api.OpenAPI().Components.Schemas.Map()["Payment"])
+	assert.Contains(t, string(b), `"format":"decimal"`)
+	assert.Contains(t, string(b), `"pattern":"^[-+]?0*[0-9]{1,4}(\\.[0-9]{1,2})?$"`)
+	assert.Contains(t, string(b), `"pattern":"^[-+]?[0-9]+(\\.[0-9]{1,3})?$"`)
+	assert.Contains(t, string(b), `"pattern":"^[-+]?0*[0-9]{1,3}$"`)
+
+	resp := api.Put("/payment?min=10.5", strings.NewReader(`{"amount": "1234.50", "fee": "0.125"}`))
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), `"amount":"1234.50"`)
+	assert.Contains(t, resp.Body.String(), `"fee":"0.125"`)
+
+	resp = api.Put("/payment?min=2000", strings.NewReader(`{"amount": "1234.50"}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+
+	resp = api.Put("/payment?min=bad", strings.NewReader(`{"amount": "12345.5", "fee": "0.1234", "count": "1.5"}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "query.min")
+	assert.Contains(t, resp.Body.String(), "expected string to be decimal with at most 6 digits and 2 decimal places")
+	assert.Contains(t, resp.Body.String(), "body.fee")
+	assert.Contains(t, resp.Body.String(), "body.count")
+
+	// Floats are not accepted.
+	resp = api.Put("/payment", strings.NewReader(`{"amount": 12.5}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "expected string")
+}
+
+func TestCents(t *testing.T) {
+	for _, item := range []struct {
+		input  string
+		cents  huma.Cents
+		micros huma.Micros
+		err    bool
+	}{
+		{input: "0", cents: 0, micros: 0},
+		{input: "12.34", cents: 1234, micros: 12340000},
+		{input: "-0.05", cents: -5, micros: -50000},
+		{input: "+7", cents: 700, micros: 7000000},
+		{input: "0.001", err: true},
+		{input: "abc", err: true},
+	} {
+		t.Run(item.input, func(t *testing.T) {
+			c, err := huma.ParseCents(item.input)
+			if item.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, item.cents, c)
+
+			m, err := huma.ParseMicros(item.input)
+			require.NoError(t, err)
+			assert.Equal(t, item.micros, m)
+		})
+	}
+
+	c, err := huma.ParseCents("92233720368547758.07")
+	require.NoError(t, err)
+	assert.Equal(t, huma.Cents(math.MaxInt64), c)
+	_, err = huma.ParseCents("92233720368547758.08")
+	assert.ErrorContains(t, err, "out of range")
+
+	assert.Equal(t, "12.34", huma.Cents(1234).String())
+	assert.Equal(t, "-0.05", huma.Cents(-5).String())
+	assert.Equal(t, "0.00", huma.Cents(0).String())
+	assert.Equal(t, "-92233720368547758.08", huma.Cents(math.MinInt64).String())
+	assert.Equal(t, "0.000125", huma.Micros(125).String())
+	assert.Equal(t, "12.34", huma.Cents(1234).Decimal().String())
+	assert.Equal(t, "12.345678", huma.Micros(12345678).Decimal().String())
+
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	type Price struct {
+		Amount huma.Cents  `json:"amount" minimum:"0"`
+		Rate   huma.Micros `json:"rate,omitempty"`
+	}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-price",
+		Method:      http.MethodPut,
+		Path:        "/price",
+	}, func(ctx context.Context, input *struct {
+		Max  huma.Cents `query:"max"`
+		Body Price
+	}) (*struct{ Body Price }, error) {
+		if input.Max > 0 && input.Body.Amount > input.Max {
+			return nil, huma.Error400BadRequest("amount exceeds " + input.Max.String())
+		}
+		return &struct{ Body Price }{Body: input.Body}, nil
+	})
+
+	b, _ := json.Marshal(api.OpenAPI().Components.Schemas.Map()["Price"])
+	assert.Contains(t, string(b), `"type":"integer"`)
+	assert.Contains(t, string(b), `"multipleOf":1`)
+	assert.Contains(t, string(b), `"x-unit":"cents"`)
+	assert.Contains(t, string(b), `"x-scale":2`)
+	assert.Contains(t, string(b), `"x-unit":"micros"`)
+	assert.Contains(t, string(b), `"minimum":0`)
+
+	resp := api.Put("/price?max=5000", strings.NewReader(`{"amount": 1234, "rate": 125}`))
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), `"amount":1234`)
+	assert.Contains(t, resp.Body.String(), `"rate":125`)
+
+	resp = api.Put("/price?max=1000", strings.NewReader(`{"amount": 1234}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), "amount exceeds 10.00")
+
+	// Floats & negative amounts are rejected.
+	resp = api.Put("/price", strings.NewReader(`{"amount": 12.34}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "body.amount")
+
+	resp = api.Put("/price", strings.NewReader(`{"amount": -1}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
+
+func TestNullable(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	type User struct {
+		Name     string                  `json:"name"`
+		Nickname huma.Nullable[string]   `json:"nickname" maxLength:"5"`
+		Tags     huma.Nullable[[]string] `json:"tags,omitempty"`
+	}
+
+	type UpdateUser struct {
+		Nickname huma.Omittable[string] `json:"nickname,omitempty" maxLength:"5"`
+	}
+
+	user := User{Name: "alice", Nickname: huma.NewNullable("al")}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-user",
+		Method:      http.MethodPut,
+		Path:        "/user",
+	}, func(ctx context.Context, input *struct{ Body User }) (*struct{ Body User }, error) {
+		user = input.Body
+		return &struct{ Body User }{Body: user}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "patch-user",
+		Method:      http.MethodPatch,
+		Path:        "/user",
+	}, func(ctx context.Context, input *struct{ Body UpdateUser }) (*struct{ Body User }, error) {
+		if input.Body.Nickname.Sent {
+			user.Nickname = huma.Nullable[string]{Null: input.Body.Nickname.Null, Value: input.Body.Nickname.Value}
+		}
+		return &struct{ Body User }{Body: user}, nil
+	})
+
+	schema := api.OpenAPI().Components.Schemas.Map()["User"]
+	b, _ := json.Marshal(schema.Properties["nickname"])
+	assert.JSONEq(t, `{"type": ["string", "null"], "maxLength": 5}`, string(b))
+	assert.Contains(t, schema.Required, "nickname")
+	b, _ = json.Marshal(schema.Properties["tags"])
+	assert.Contains(t, string(b), `"type":["array","null"]`)
+
+	// OpenAPI 3.0 uses `nullable: true` instead.
+	b, _ = api.OpenAPI().Downgrade()
+	assert.Contains(t, string(b), `"nickname":{"maxLength":5,"nullable":true,"type":"string"}`)
+
+	resp := api.Put("/user", map[string]any{"name": "bob", "nickname": nil})
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.True(t, user.Nickname.Null)
+	assert.Nil(t, user.Nickname.Ptr())
+	assert.Contains(t, resp.Body.String(), `"nickname":null`)
+
+	resp = api.Put("/user", map[string]any{"name": "bob", "nickname": "toolong"})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "body.nickname")
+
+	resp = api.Put("/user", map[string]any{"name": "bob", "nickname": "b", "tags": []string{"x"}})
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Equal(t, "b", *user.Nickname.Ptr())
+	assert.Contains(t, resp.Body.String(), `"nickname":"b"`)
+
+	// Omitted values are left alone, null values are cleared.
+	resp = api.Patch("/user", map[string]any{})
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Equal(t, "b", user.Nickname.Value)
+
+	resp = api.Patch("/user", map[string]any{"nickname": nil})
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.True(t, user.Nickname.Null)
+
+	resp = api.Patch("/user", map[string]any{"nickname": "toolong"})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	// Objects are not supported.
+	type Object struct {
+		Value string `json:"value"`
+	}
+	assert.Panics(t, func() {
+		huma.Put(api, "/object", func(ctx context.Context, input *struct {
+			Body struct {
+				Value huma.Nullable[Object] `json:"value"`
+			}
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
+
+func TestNullableScan(t *testing.T) {
+	var n huma.Nullable[int64]
+	require.NoError(t, n.Scan(nil))
+	assert.True(t, n.Null)
+	require.NoError(t, n.Scan(int64(5)))
+	assert.Equal(t, huma.NewNullable(int64(5)), n)
+
+	var o huma.Omittable[int]
+	assert.True(t, o.IsZero())
+	b, _ := json.Marshal(o)
+	assert.Equal(t, "null", string(b))
+	require.NoError(t, json.Unmarshal([]byte("5"), &o))
+	assert.Equal(t, huma.Omittable[int]{Sent: true, Value: 5}, o)
+	b, _ = json.Marshal(o)
+	assert.Equal(t, "5", string(b))
+}
+
+func TestAcceptLanguage(t *testing.T) {
+	var a huma.AcceptLanguage
+	require.NoError(t, a.UnmarshalText([]byte("fr;q=0.5, de-AT, en;q=0.5, *;q=0.1, es;q=0")))
+	assert.Equal(t, []string{"de-AT", "fr", "en", "*"}, a.Tags)
+
+	assert.Equal(t, "de", a.Best("en", "de"))
+	assert.Equal(t, "de-AT", a.Best("de-de", "de-AT"))
+	assert.Equal(t, "fr-FR", a.Best("en-US", "fr-FR"))
+	assert.Equal(t, "ja", a.Best("ja", "zh"))
+	assert.Equal(t, "", a.Best())
+
+	a.Tags = nil
+	assert.Equal(t, "en", a.Best("en", "de"))
+
+	assert.Error(t, a.UnmarshalText([]byte("en;q=2")))
+	assert.Error(t, a.UnmarshalText([]byte("en;level=1")))
+
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-greeting",
+		Method:      http.MethodGet,
+		Path:        "/greeting",
+	}, func(ctx context.Context, input *struct {
+		Locales huma.AcceptLanguage `header:"Accept-Language"`
+	}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: input.Locales.Best("en", "de")}, nil
+	})
+
+	param := api.OpenAPI().Paths["/greeting"].Get.Parameters[0]
+	assert.Equal(t, huma.TypeString, param.Schema.Type)
+
+	resp := api.Get("/greeting", "Accept-Language: de-CH, en;q=0.8")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `"de"`, strings.TrimSpace(resp.Body.String()))
+
+	resp = api.Get("/greeting")
+	assert.Equal(t, `"en"`, strings.TrimSpace(resp.Body.String()))
+
+	resp = api.Get("/greeting", "Accept-Language: de;q=x")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
@@ -0,0 +1,426 @@
+// Package uploads provides resumable uploads based on the core tus protocol
+// (https://tus.io/protocols/resumable-upload). Clients create an upload with
+// its total length, then send its content in one or more `PATCH` requests
+// which each start at the current offset. If a connection drops, the client
+// asks for the current offset via `HEAD` and continues from there.
+package uploads
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// ContentType is the required content type of `PATCH` requests.
+const ContentType = "application/offset+octet-stream"
+
+// ErrNotFound is returned by a store when an upload does not exist.
+var ErrNotFound = errors.New("upload not found")
+
+// Info describes an upload.
+type Info struct {
+	// ID uniquely identifies the upload and is set by the store.
+	ID string
+
+	// Length is the total size of the upload in bytes.
+	Length int64
+
+	// Offset is the number of bytes which have been received so far.
+	Offset int64
+
+	// Metadata is optional key/value data sent by the client when creating the
+	// upload, like a filename.
+	Metadata map[string]string
+
+	// ExpiresAt is when an incomplete upload expires. Zero means never.
+	ExpiresAt time.Time
+}
+
+// Complete returns whether all of the upload's content has been received.
+func (i Info) Complete() bool {
+	return i.Offset == i.Length
+}
+
+// Store is a storage backend for uploads.
+type Store interface {
+	// Create stores a new empty upload and returns its ID.
+	Create(ctx context.Context, info Info) (string, error)
+
+	// Get returns the upload or `ErrNotFound`.
+	Get(ctx context.Context, id string) (Info, error)
+
+	// Write appends data to the upload at the given offset, which always
+	// matches the upload's current offset, and returns the number of bytes
+	// written. Data written before an error must be kept so that the client can
+	// resume the upload from the new offset.
+	Write(ctx context.Context, id string, offset int64, r io.Reader) (int64, error)
+
+	// Delete removes the upload and its content.
+	Delete(ctx context.Context, id string) error
+}
+
+// Config configures the upload operations.
+type Config struct {
+	// Path is the path of the collection of uploads, like `/uploads`. Each
+	// upload is available at a sub-path like `/uploads/{id}`.
+	Path string
+
+	// Store saves the uploads and is required.
+	Store Store
+
+	// MaxSize is the maximum upload size in bytes, or zero for no limit.
+	MaxSize int64
+
+	// Expiration is how long an incomplete upload is kept after it is
+	// created, or zero to keep it forever. Expired uploads are deleted when
+	// they are next accessed.
+	Expiration time.Duration
+
+	// OnComplete is called once all of the content for an upload has been
+	// received, e.g. to move it to permanent storage. Returning an error
+	// fails the final `PATCH` request.
+	OnComplete func(ctx context.Context, info Info) error
+
+	// Tags are added to each of the generated operations.
+	Tags []string
+}
+
+// CreateInput creates a new upload.
+type CreateInput struct {
+	Length   int64  `header:"Upload-Length" minimum:"0" required:"true" doc:"Total size of the upload in bytes"`
+	Metadata string `header:"Upload-Metadata" doc:"Comma-separated key/value pairs, where each key is followed by a space and the base64-encoded value"`
+}
+
+// CreateOutput is the response to creating an upload.
+type CreateOutput struct {
+	Location string    `header:"Location" doc:"URL of the new upload"`
+	Expires  time.Time `header:"Upload-Expires" doc:"When the incomplete upload expires"`
+}
+
+// IDInput identifies an upload.
+type IDInput struct {
+	ID string `path:"id" doc:"Upload ID"`
+}
+
+// OffsetOutput describes the progress of an upload.
+type OffsetOutput struct {
+	CacheControl string    `header:"Cache-Control"`
+	Offset       int64     `header:"Upload-Offset" doc:"Number of bytes received so far"`
+	Length       int64     `header:"Upload-Length" doc:"Total size of the upload in bytes"`
+	Metadata     string    `header:"Upload-Metadata" doc:"Metadata sent when the upload was created"`
+	Expires      time.Time `header:"Upload-Expires" doc:"When the incomplete upload expires"`
+}
+
+// PatchInput sends the next chunk of an upload's content.
+type PatchInput struct {
+	ID          string `path:"id" doc:"Upload ID"`
+	Offset      int64  `header:"Upload-Offset" minimum:"0" required:"true" doc:"Offset of the chunk, which must match the current offset of the upload"`
+	ContentType string `header:"Content-Type" doc:"Must be application/offset+octet-stream"`
+	body        io.Reader
+}
+
+// Resolve captures the request body so that it can be streamed into the
+// store rather than read into memory.
+func (i *PatchInput) Resolve(ctx huma.Context) []error {
+	i.body = ctx.BodyReader()
+	return nil
+}
+
+// PatchOutput is the response to sending a chunk.
+type PatchOutput struct {
+	Offset  int64     `header:"Upload-Offset" doc:"Number of bytes received so far"`
+	Expires time.Time `header:"Upload-Expires" doc:"When the incomplete upload expires"`
+}
+
+// EncodeMetadata encodes metadata for the `Upload-Metadata` header.
+func EncodeMetadata(metadata map[string]string) string {
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + " " + base64.StdEncoding.EncodeToString([]byte(metadata[k]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// DecodeMetadata decodes the `Upload-Metadata` header.
+func DecodeMetadata(header string) (map[string]string, error) {
+	metadata := map[string]string{}
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(pair, " ")
+		decoded, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, errors.New("invalid base64 value for key " + k)
+		}
+		metadata[k] = string(decoded)
+	}
+	return metadata, nil
+}
+
+// Register registers the operations to create, resume, upload, and delete
+// resumable uploads under the configured path.
+//
+//	uploads.Register(api, uploads.Config{
+//		Path:       "/uploads",
+//		Store:      uploads.NewMemoryStore(),
+//		MaxSize:    1 << 30,
+//		Expiration: 24 * time.Hour,
+//		OnComplete: func(ctx context.Context, info uploads.Info) error {
+//			log.Printf("Received %s", info.Metadata["filename"])
+//			return nil
+//		},
+//	})
+func Register(api huma.API, config Config) {
+	path := strings.TrimSuffix(config.Path, "/")
+	store := config.Store
+
+	// get loads the upload, removing it if it has expired.
+	get := func(ctx context.Context, id string) (Info, error) {
+		info, err := store.Get(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			return info, huma.Error404NotFound("upload not found")
+		}
+		if err != nil {
+			return info, err
+		}
+		if !info.Complete() && !info.ExpiresAt.IsZero() && time.Now().After(info.ExpiresAt) {
+			if err := store.Delete(ctx, id); err != nil {
+				return info, err
+			}
+			return info, huma.Error410Gone("upload expired")
+		}
+		return info, nil
+	}
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-upload",
+		Method:        http.MethodPost,
+		Path:          path,
+		Summary:       "Create upload",
+		Description:   "Create a new resumable upload. Send its content via `PATCH` to the returned `Location`.",
+		Tags:          config.Tags,
+		DefaultStatus: http.StatusCreated,
+		Errors:        []int{http.StatusBadRequest, http.StatusRequestEntityTooLarge},
+	}, func(ctx context.Context, input *CreateInput) (*CreateOutput, error) {
+		if config.MaxSize > 0 && input.Length > config.MaxSize {
+			return nil, huma.Error413RequestEntityTooLarge("upload is too large", &huma.ErrorDetail{
+				Message:  "expected at most " + strconv.FormatInt(config.MaxSize, 10) + " bytes",
+				Location: "header.Upload-Length",
+				Value:    config.MaxSize,
+			})
+		}
+		metadata, err := DecodeMetadata(input.Metadata)
+		if err != nil {
+			return nil, huma.Error400BadRequest("invalid metadata", &huma.ErrorDetail{
+				Message:  err.Error(),
+				Location: "header.Upload-Metadata",
+				Value:    input.Metadata,
+			})
+		}
+		info := Info{Length: input.Length, Metadata: metadata}
+		if config.Expiration > 0 {
+			info.ExpiresAt = time.Now().Add(config.Expiration).UTC()
+		}
+		id, err := store.Create(ctx, info)
+		if err != nil {
+			return nil, err
+		}
+		return &CreateOutput{Location: path + "/" + id, Expires: info.ExpiresAt}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "get-upload-offset",
+		Method:        http.MethodHead,
+		Path:          path + "/{id}",
+		Summary:       "Get upload offset",
+		Description:   "Get the number of bytes received so far, which is the offset to resume the upload from.",
+		Tags:          config.Tags,
+		DefaultStatus: http.StatusOK,
+		Errors:        []int{http.StatusNotFound, http.StatusGone},
+	}, func(ctx context.Context, input *IDInput) (*OffsetOutput, error) {
+		info, err := get(ctx, input.ID)
+		if err != nil {
+			return nil, err
+		}
+		out := &OffsetOutput{
+			CacheControl: "no-store",
+			Offset:       info.Offset,
+			Length:       info.Length,
+		}
+		if len(info.Metadata) > 0 {
+			out.Metadata = EncodeMetadata(info.Metadata)
+		}
+		if !info.Complete() {
+			out.Expires = info.ExpiresAt
+		}
+		return out, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "upload-chunk",
+		Method:      http.MethodPatch,
+		Path:        path + "/{id}",
+		Summary:     "Upload chunk",
+		Description: "Send the next chunk of the upload's content starting at its current offset.",
+		Tags:        config.Tags,
+		RequestBody: &huma.RequestBody{
+			Required: true,
+			Content: map[string]*huma.MediaType{
+				ContentType: {
+					Schema: &huma.Schema{Type: huma.TypeString, Format: "binary"},
+				},
+			},
+		},
+		Errors: []int{http.StatusNotFound, http.StatusConflict, http.StatusGone, http.StatusUnsupportedMediaType},
+	}, func(ctx context.Context, input *PatchInput) (*PatchOutput, error) {
+		if input.ContentType != ContentType {
+			return nil, huma.Error415UnsupportedMediaType("expected content type " + ContentType)
+		}
+		info, err := get(ctx, input.ID)
+		if err != nil {
+			return nil, err
+		}
+		if input.Offset != info.Offset {
+			return nil, huma.Error409Conflict("offset does not match", &huma.ErrorDetail{
+				Message:  "expected offset " + strconv.FormatInt(info.Offset, 10),
+				Location: "header.Upload-Offset",
+				Value:    input.Offset,
+			})
+		}
+
+		// Any extra content beyond the upload's length is ignored.
+		n, err := store.Write(ctx, info.ID, info.Offset, io.LimitReader(input.body, info.Length-info.Offset))
+		info.Offset += n
+		if err != nil {
+			return nil, err
+		}
+
+		if info.Complete() && config.OnComplete != nil {
+			if err := config.OnComplete(ctx, info); err != nil {
+				return nil, err
+			}
+		}
+
+		out := &PatchOutput{Offset: info.Offset}
+		if !info.Complete() {
+			out.Expires = info.ExpiresAt
+		}
+		return out, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-upload",
+		Method:      http.MethodDelete,
+		Path:        path + "/{id}",
+		Summary:     "Delete upload",
+		Description: "Stop and remove an upload.",
+		Tags:        config.Tags,
+		Errors:      []int{http.StatusNotFound},
+	}, func(ctx context.Context, input *IDInput) (*struct{}, error) {
+		if _, err := store.Get(ctx, input.ID); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return nil, huma.Error404NotFound("upload not found")
+			}
+			return nil, err
+		}
+		return nil, store.Delete(ctx, input.ID)
+	})
+}
+
+// MemoryStore is an in-memory upload store, useful for testing and
+// development. Content is lost when the process exits.
+type MemoryStore struct {
+	mu      sync.Mutex
+	uploads map[string]*memoryUpload
+}
+
+type memoryUpload struct {
+	info Info
+	data []byte
+}
+
+// NewMemoryStore creates a new empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{uploads: map[string]*memoryUpload{}}
+}
+
+// Create implements `Store`.
+func (s *MemoryStore) Create(ctx context.Context, info Info) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	info.ID = hex.EncodeToString(b)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.uploads[info.ID] = &memoryUpload{info: info}
+	return info.ID, nil
+}
+
+// Get implements `Store`.
+func (s *MemoryStore) Get(ctx context.Context, id string) (Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.uploads[id]
+	if u == nil {
+		return Info{}, ErrNotFound
+	}
+	return u.info, nil
+}
+
+// Write implements `Store`.
+func (s *MemoryStore) Write(ctx context.Context, id string, offset int64, r io.Reader) (int64, error) {
+	// Read outside of the lock as the client may be slow.
+	chunk, err := io.ReadAll(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.uploads[id]
+	if u == nil {
+		return 0, ErrNotFound
+	}
+	if u.info.Offset != offset {
+		return 0, huma.Error409Conflict("offset does not match")
+	}
+	u.data = append(u.data, chunk...)
+	u.info.Offset += int64(len(chunk))
+	return int64(len(chunk)), err
+}
+
+// Delete implements `Store`.
+func (s *MemoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.uploads, id)
+	return nil
+}
+
+// Bytes returns the content received so far for an upload.
+func (s *MemoryStore) Bytes(id string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u := s.uploads[id]; u != nil {
+		return u.data
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package uploads_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/danielgtaylor/huma/v2/uploads"
+)
+
+func TestUploads(t *testing.T) {
+	_, api := humatest.New(t)
+
+	store := uploads.NewMemoryStore()
+	completed := []uploads.Info{}
+	uploads.Register(api, uploads.Config{
+		Path:       "/uploads",
+		Store:      store,
+		MaxSize:    20,
+		Expiration: time.Hour,
+		OnComplete: func(ctx context.Context, info uploads.Info) error {
+			completed = append(completed, info)
+			return nil
+		},
+	})
+
+	// The protocol headers are documented.
+	patch := api.OpenAPI().Paths["/uploads/{id}"].Patch
+	require.NotNil(t, patch)
+	assert.Contains(t, patch.RequestBody.Content, uploads.ContentType)
+	assert.Contains(t, patch.Responses["204"].Headers, "Upload-Offset")
+	assert.Equal(t, "Upload-Offset", patch.Parameters[1].Name)
+
+	resp := api.Post("/uploads", "Upload-Length: 11", "Upload-Metadata: "+uploads.EncodeMetadata(map[string]string{"filename": "hello.txt"}))
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	location := resp.Header().Get("Location")
+	assert.True(t, strings.HasPrefix(location, "/uploads/"))
+	assert.NotEmpty(t, resp.Header().Get("Upload-Expires"))
+
+	resp = api.Patch(location, "Content-Type: "+uploads.ContentType, "Upload-Offset: 0", strings.NewReader("hello"))
+	assert.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+	assert.Equal(t, "5", resp.Header().Get("Upload-Offset"))
+
+	// Wrong offset or content type.
+	resp = api.Patch(location, "Content-Type: "+uploads.ContentType, "Upload-Offset: 0", strings.NewReader("hello"))
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	resp = api.Patch(location, "Content-Type: application/json", "Upload-Offset: 5", strings.NewReader("{}"))
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.Code)
+
+	// Resume from the current offset.
+	resp = api.Do(http.MethodHead, location)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "5", resp.Header().Get("Upload-Offset"))
+	assert.Equal(t, "11", resp.Header().Get("Upload-Length"))
+	assert.Equal(t, "no-store", resp.Header().Get("Cache-Control"))
+	assert.Equal(t, "filename aGVsbG8udHh0", resp.Header().Get("Upload-Metadata"))
+
+	// Extra content past the length is ignored.
+	resp = api.Patch(location, "Content-Type: "+uploads.ContentType, "Upload-Offset: 5", strings.NewReader(" world!!!"))
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, "11", resp.Header().Get("Upload-Offset"))
+	assert.Empty(t, resp.Header().Get("Upload-Expires"))
+
+	require.Len(t, completed, 1)
+	assert.Equal(t, "hello.txt", completed[0].Metadata["filename"])
+	assert.Equal(t, "hello world", string(store.Bytes(completed[0].ID)))
+
+	resp = api.Delete(location)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	resp = api.Do(http.MethodHead, location)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	resp = api.Delete(location)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	// Limits and validation.
+	resp = api.Post("/uploads", "Upload-Length: 21")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+	resp = api.Post("/uploads", "Upload-Length: 1", "Upload-Metadata: filename !!!")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = api.Post("/uploads")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
+
+func TestUploadExpired(t *testing.T) {
+	_, api := humatest.New(t)
+
+	store := uploads.NewMemoryStore()
+	uploads.Register(api, uploads.Config{
+		Path:  "/uploads",
+		Store: store,
+		OnComplete: func(ctx context.Context, info uploads.Info) error {
+			return errors.New("failed")
+		},
+	})
+
+	id, err := store.Create(context.Background(), uploads.Info{Length: 1, ExpiresAt: time.Now().Add(-time.Second)})
+	require.NoError(t, err)
+
+	resp := api.Do(http.MethodHead, "/uploads/"+id)
+	assert.Equal(t, http.StatusGone, resp.Code)
+	resp = api.Do(http.MethodHead, "/uploads/"+id)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	id, err = store.Create(context.Background(), uploads.Info{Length: 1})
+	require.NoError(t, err)
+	resp = api.Patch("/uploads/"+id, "Content-Type: "+uploads.ContentType, "Upload-Offset: 0", strings.NewReader("a"))
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+}
@@ -1,16 +1,22 @@
 package huma
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"net"
 	"net/mail"
 	"net/url"
 	"reflect"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unicode/utf8"
 	"unsafe"
@@ -163,8 +169,41 @@ func NewPathBuffer(buf []byte, offset int) *PathBuffer {
 // validations as long as `Reset()` is called between uses.
 type ValidateResult struct {
 	Errors []error
+
+	// Deprecated contains the locations of deprecated properties which were
+	// set in the input, like `body.oldName`. Only populated when validating
+	// with `ModeWriteToServer`.
+	Deprecated []string
+
+	// CollectUnknown collects unexpected properties into `Unknown` instead of
+	// reporting them as errors when validating with `ModeWriteToServer`.
+	CollectUnknown bool
+
+	// Unknown contains the locations of unexpected properties which were set
+	// in the input, like `body.removedField`, when `CollectUnknown` is set.
+	Unknown []string
+
+	// MessageFunc, if set, is used to generate built-in validation messages
+	// from their format & arguments instead of the default precomputed
+	// English messages. See `Config.MessageFunc`.
+	MessageFunc func(format string, args ...any) string
 }
 
+// MessageFunc translates a built-in validation message for the current
+// request. The format is one of the `validation.Msg*` values, for example
+// `validation.MsgExpectedMaxLength`, and can be used as a lookup key for the
+// translation. The arguments are the values used to format the message.
+//
+//	config.MessageFunc = func(ctx huma.Context, format string, args ...any) string {
+//		if strings.HasPrefix(ctx.Header("Accept-Language"), "de") {
+//			if translated, ok := german[format]; ok {
+//				format = translated
+//			}
+//		}
+//		return fmt.Sprintf(format, args...)
+//	}
+type MessageFunc func(ctx Context, format string, args ...any) string
+
 // Add an error to the validation result at the given path and with the
 // given value.
 func (r *ValidateResult) Add(path *PathBuffer, v any, msg string) {
@@ -185,9 +224,41 @@ func (r *ValidateResult) Addf(path *PathBuffer, v any, format string, args ...an
 	})
 }
 
+// addMsg adds a built-in validation message, using the precomputed message
+// (if any) unless a custom message function is set.
+func (r *ValidateResult) addMsg(path *PathBuffer, v any, msg string, format string, args ...any) {
+	if r.MessageFunc != nil {
+		msg = r.MessageFunc(format, args...)
+	} else if msg == "" {
+		msg = ErrorFormatter(format, args...)
+	}
+	r.Add(path, v, msg)
+}
+
 // Reset the validation error so it can be used again.
 func (r *ValidateResult) Reset() {
 	r.Errors = r.Errors[:0]
+	r.Deprecated = r.Deprecated[:0]
+	r.Unknown = r.Unknown[:0]
+}
+
+// formatValidators are the custom string formats, see `RegisterFormat`.
+var formatValidators = map[string]func(string) error{}
+
+// RegisterFormat registers a custom string format, e.g. `iban`, so that
+// fields with a `format:"iban"` tag are documented with the format and are
+// validated by calling `validate`, which returns an error describing why the
+// value is invalid. Built-in formats like `email` cannot be replaced. Formats
+// should be registered at startup, before any validation takes place.
+//
+//	huma.RegisterFormat("even-length", func(s string) error {
+//		if len(s)%2 != 0 {
+//			return errors.New("odd length")
+//		}
+//		return nil
+//	})
+func RegisterFormat(name string, validate func(s string) error) {
+	formatValidators[name] = validate
 }
 
 func validateFormat(path *PathBuffer, str string, s *Schema, res *ValidateResult) {
@@ -201,73 +272,116 @@ func validateFormat(path *PathBuffer, str string, s *Schema, res *ValidateResult
 			}
 		}
 		if !found {
-			res.Add(path, str, validation.MsgExpectedRFC3339DateTime)
+			res.addMsg(path, str, validation.MsgExpectedRFC3339DateTime, validation.MsgExpectedRFC3339DateTime)
 		}
 	case "date-time-http":
 		if _, err := time.Parse(time.RFC1123, str); err != nil {
-			res.Add(path, str, validation.MsgExpectedRFC1123DateTime)
+			res.addMsg(path, str, validation.MsgExpectedRFC1123DateTime, validation.MsgExpectedRFC1123DateTime)
 		}
 	case "date":
 		if _, err := time.Parse("2006-01-02", str); err != nil {
-			res.Add(path, str, validation.MsgExpectedRFC3339Date)
+			res.addMsg(path, str, validation.MsgExpectedRFC3339Date, validation.MsgExpectedRFC3339Date)
 		}
 	case "time":
 		if _, err := time.Parse("15:04:05", str); err != nil {
 			if _, err := time.Parse("15:04:05Z07:00", str); err != nil {
-				res.Add(path, str, validation.MsgExpectedRFC3339Time)
+				res.addMsg(path, str, validation.MsgExpectedRFC3339Time, validation.MsgExpectedRFC3339Time)
 			}
 		}
 		// TODO: duration
 	case "email", "idn-email":
 		if _, err := mail.ParseAddress(str); err != nil {
-			res.Add(path, str, ErrorFormatter(validation.MsgExpectedRFC5322Email, err))
+			res.addMsg(path, str, "", validation.MsgExpectedRFC5322Email, err)
 		}
 	case "hostname":
 		if !(rxHostname.MatchString(str) && len(str) < 256) {
-			res.Add(path, str, validation.MsgExpectedRFC5890Hostname)
+			res.addMsg(path, str, validation.MsgExpectedRFC5890Hostname, validation.MsgExpectedRFC5890Hostname)
 		}
 	// TODO: proper idn-hostname support... need to figure out how.
 	case "ipv4":
 		if ip := net.ParseIP(str); ip == nil || ip.To4() == nil {
-			res.Add(path, str, validation.MsgExpectedRFC2673IPv4)
+			res.addMsg(path, str, validation.MsgExpectedRFC2673IPv4, validation.MsgExpectedRFC2673IPv4)
 		}
 	case "ipv6":
 		if ip := net.ParseIP(str); ip == nil || ip.To16() == nil {
-			res.Add(path, str, validation.MsgExpectedRFC2373IPv6)
+			res.addMsg(path, str, validation.MsgExpectedRFC2373IPv6, validation.MsgExpectedRFC2373IPv6)
 		}
 	case "uri", "uri-reference", "iri", "iri-reference":
 		if _, err := url.Parse(str); err != nil {
-			res.Add(path, str, ErrorFormatter(validation.MsgExpectedRFC3986URI, err))
+			res.addMsg(path, str, "", validation.MsgExpectedRFC3986URI, err)
 		}
 		// TODO: check if it's actually a reference?
 	case "uuid":
 		if err := validateUUID(str); err != nil {
-			res.Add(path, str, ErrorFormatter(validation.MsgExpectedRFC4122UUID, err))
+			res.addMsg(path, str, "", validation.MsgExpectedRFC4122UUID, err)
 		}
 	case "uri-template":
 		u, err := url.Parse(str)
 		if err != nil {
-			res.Add(path, str, ErrorFormatter(validation.MsgExpectedRFC3986URI, err))
+			res.addMsg(path, str, "", validation.MsgExpectedRFC3986URI, err)
 			return
 		}
 		if !rxURITemplate.MatchString(u.Path) {
-			res.Add(path, str, validation.MsgExpectedRFC6570URITemplate)
+			res.addMsg(path, str, validation.MsgExpectedRFC6570URITemplate, validation.MsgExpectedRFC6570URITemplate)
 		}
 	case "json-pointer":
 		if !rxJSONPointer.MatchString(str) {
-			res.Add(path, str, validation.MsgExpectedRFC6901JSONPointer)
+			res.addMsg(path, str, validation.MsgExpectedRFC6901JSONPointer, validation.MsgExpectedRFC6901JSONPointer)
 		}
 	case "relative-json-pointer":
 		if !rxRelJSONPointer.MatchString(str) {
-			res.Add(path, str, validation.MsgExpectedRFC6901RelativeJSONPointer)
+			res.addMsg(path, str, validation.MsgExpectedRFC6901RelativeJSONPointer, validation.MsgExpectedRFC6901RelativeJSONPointer)
 		}
 	case "regex":
 		if _, err := regexp.Compile(str); err != nil {
-			res.Add(path, str, ErrorFormatter(validation.MsgExpectedRegexp, err))
+			res.addMsg(path, str, "", validation.MsgExpectedRegexp, err)
+		}
+	default:
+		if validate := formatValidators[s.Format]; validate != nil {
+			if err := validate(str); err != nil {
+				res.addMsg(path, str, "", validation.MsgExpectedFormat, s.Format, err)
+			}
 		}
 	}
 }
 
+// validateContent checks that a string's content, after decoding it using the
+// schema's `contentEncoding` if needed, is valid for its `contentMediaType`.
+// Only JSON media types are checked.
+func validateContent(path *PathBuffer, str string, s *Schema, res *ValidateResult) {
+	mt := s.ContentMediaType
+	if i := strings.IndexByte(mt, ';'); i >= 0 {
+		mt = mt[:i]
+	}
+	if mt != "application/json" && !strings.HasSuffix(mt, "+json") {
+		return
+	}
+
+	content := []byte(str)
+	if s.ContentEncoding == "base64" {
+		decoded, err := decodeBase64(str)
+		if err != nil {
+			res.addMsg(path, str, validation.MsgExpectedBase64String, validation.MsgExpectedBase64String)
+			return
+		}
+		content = decoded
+	}
+
+	if !json.Valid(content) {
+		res.addMsg(path, str, "", validation.MsgExpectedContentMediaType, s.ContentMediaType)
+	}
+}
+
+// decodeBase64 decodes standard or URL-safe base64 with or without padding,
+// matching the strings accepted by `rxBase64`.
+func decodeBase64(str string) ([]byte, error) {
+	str = strings.TrimRight(str, "=")
+	if strings.ContainsAny(str, "-_") {
+		return base64.RawURLEncoding.DecodeString(str)
+	}
+	return base64.RawStdEncoding.DecodeString(str)
+}
+
 func validateOneOf(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any, res *ValidateResult) {
 	found := false
 	subRes := &ValidateResult{}
@@ -275,14 +389,14 @@ func validateOneOf(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v
 		Validate(r, sub, path, mode, v, subRes)
 		if len(subRes.Errors) == 0 {
 			if found {
-				res.Add(path, v, "expected value to match exactly one schema but matched multiple")
+				res.addMsg(path, v, validation.MsgExpectedMatchExactlyOneSchemaMatchedMultiple, validation.MsgExpectedMatchExactlyOneSchemaMatchedMultiple)
 			}
 			found = true
 		}
 		subRes.Reset()
 	}
 	if !found {
-		res.Add(path, v, validation.MsgExpectedMatchExactlyOneSchema)
+		res.addMsg(path, v, validation.MsgExpectedMatchExactlyOneSchema, validation.MsgExpectedMatchExactlyOneSchema)
 	}
 }
 
@@ -298,7 +412,7 @@ func validateAnyOf(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v
 	}
 
 	if matches == 0 {
-		res.Add(path, v, validation.MsgExpectedMatchAtLeastOneSchema)
+		res.addMsg(path, v, validation.MsgExpectedMatchAtLeastOneSchema, validation.MsgExpectedMatchAtLeastOneSchema)
 	}
 }
 
@@ -316,7 +430,7 @@ func validateDiscriminator(r Registry, s *Schema, path *PathBuffer, mode Validat
 
 	if !found {
 		path.Push(s.Discriminator.PropertyName)
-		res.Add(path, v, validation.MsgExpectedPropertyNameInObject)
+		res.addMsg(path, v, validation.MsgExpectedPropertyNameInObject, validation.MsgExpectedPropertyNameInObject)
 		return
 	}
 
@@ -385,7 +499,7 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 		subRes := &ValidateResult{}
 		Validate(r, s.Not, path, mode, v, subRes)
 		if len(subRes.Errors) == 0 {
-			res.Add(path, v, validation.MsgExpectedNotMatchSchema)
+			res.addMsg(path, v, validation.MsgExpectedNotMatchSchema, validation.MsgExpectedNotMatchSchema)
 		}
 	}
 
@@ -396,19 +510,23 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 	switch s.Type {
 	case TypeBoolean:
 		if _, ok := v.(bool); !ok {
-			res.Add(path, v, validation.MsgExpectedBoolean)
+			res.addMsg(path, v, validation.MsgExpectedBoolean, validation.MsgExpectedBoolean)
 			return
 		}
 	case TypeNumber, TypeInteger:
 		var num float64
 
+		// Large 64-bit integers cannot be represented exactly as `float64`, so
+		// keep an exact copy around for comparisons.
+		var exact *big.Int
+
 		switch v := v.(type) {
 		case float64:
 			num = v
 		case float32:
 			num = float64(v)
 		case int:
-			num = float64(v)
+			num, exact = fromInt64(int64(v))
 		case int8:
 			num = float64(v)
 		case int16:
@@ -416,9 +534,9 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 		case int32:
 			num = float64(v)
 		case int64:
-			num = float64(v)
+			num, exact = fromInt64(v)
 		case uint:
-			num = float64(v)
+			num, exact = fromUint64(uint64(v))
 		case uint8:
 			num = float64(v)
 		case uint16:
@@ -426,35 +544,46 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 		case uint32:
 			num = float64(v)
 		case uint64:
-			num = float64(v)
+			num, exact = fromUint64(v)
+		case json.Number:
+			if i, err := v.Int64(); err == nil {
+				num, exact = fromInt64(i)
+			} else if u, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+				num, exact = fromUint64(u)
+			} else if f, err := v.Float64(); err == nil {
+				num = f
+			} else {
+				res.addMsg(path, v, validation.MsgExpectedNumber, validation.MsgExpectedNumber)
+				return
+			}
 		default:
-			res.Add(path, v, validation.MsgExpectedNumber)
+			res.addMsg(path, v, validation.MsgExpectedNumber, validation.MsgExpectedNumber)
 			return
 		}
 
 		if s.Minimum != nil {
-			if num < *s.Minimum {
-				res.Add(path, v, s.msgMinimum)
+			if compareNum(num, exact, *s.Minimum) < 0 {
+				res.addMsg(path, v, s.msgMinimum, validation.MsgExpectedMinimumNumber, *s.Minimum)
 			}
 		}
 		if s.ExclusiveMinimum != nil {
-			if num <= *s.ExclusiveMinimum {
-				res.Add(path, v, s.msgExclusiveMinimum)
+			if compareNum(num, exact, *s.ExclusiveMinimum) <= 0 {
+				res.addMsg(path, v, s.msgExclusiveMinimum, validation.MsgExpectedExclusiveMinimumNumber, *s.ExclusiveMinimum)
 			}
 		}
 		if s.Maximum != nil {
-			if num > *s.Maximum {
-				res.Add(path, v, s.msgMaximum)
+			if compareNum(num, exact, *s.Maximum) > 0 {
+				res.addMsg(path, v, s.msgMaximum, validation.MsgExpectedMaximumNumber, *s.Maximum)
 			}
 		}
 		if s.ExclusiveMaximum != nil {
-			if num >= *s.ExclusiveMaximum {
-				res.Add(path, v, s.msgExclusiveMaximum)
+			if compareNum(num, exact, *s.ExclusiveMaximum) >= 0 {
+				res.addMsg(path, v, s.msgExclusiveMaximum, validation.MsgExpectedExclusiveMaximumNumber, *s.ExclusiveMaximum)
 			}
 		}
 		if s.MultipleOf != nil {
-			if math.Mod(num, *s.MultipleOf) != 0 {
-				res.Add(path, v, s.msgMultipleOf)
+			if !isMultipleOf(num, exact, *s.MultipleOf) {
+				res.addMsg(path, v, s.msgMultipleOf, validation.MsgExpectedNumberBeMultipleOf, *s.MultipleOf)
 			}
 		}
 	case TypeString:
@@ -463,24 +592,28 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 			if b, ok := v.([]byte); ok {
 				str = *(*string)(unsafe.Pointer(&b))
 			} else {
-				res.Add(path, v, validation.MsgExpectedString)
+				res.addMsg(path, v, validation.MsgExpectedString, validation.MsgExpectedString)
 				return
 			}
 		}
 
 		if s.MinLength != nil {
 			if utf8.RuneCountInString(str) < *s.MinLength {
-				res.Add(path, str, s.msgMinLength)
+				res.addMsg(path, str, s.msgMinLength, validation.MsgExpectedMinLength, *s.MinLength)
 			}
 		}
 		if s.MaxLength != nil {
 			if utf8.RuneCountInString(str) > *s.MaxLength {
-				res.Add(path, str, s.msgMaxLength)
+				res.addMsg(path, str, s.msgMaxLength, validation.MsgExpectedMaxLength, *s.MaxLength)
 			}
 		}
 		if s.patternRe != nil {
 			if !s.patternRe.MatchString(str) {
-				res.Add(path, v, s.msgPattern)
+				if s.PatternDescription != "" {
+					res.addMsg(path, v, s.msgPattern, validation.MsgExpectedBePattern, s.PatternDescription)
+				} else {
+					res.addMsg(path, v, s.msgPattern, validation.MsgExpectedMatchPattern, s.Pattern)
+				}
 			}
 		}
 
@@ -490,9 +623,14 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 
 		if s.ContentEncoding == "base64" {
 			if !rxBase64.MatchString(str) {
-				res.Add(path, str, validation.MsgExpectedBase64String)
+				res.addMsg(path, str, validation.MsgExpectedBase64String, validation.MsgExpectedBase64String)
+				return
 			}
 		}
+
+		if s.ContentMediaType != "" {
+			validateContent(path, str, s, res)
+		}
 	case TypeArray:
 		switch arr := v.(type) {
 		case []any:
@@ -523,7 +661,7 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 		case []float64:
 			handleArray(r, s, path, mode, res, arr)
 		default:
-			res.Add(path, v, validation.MsgExpectedArray)
+			res.addMsg(path, v, validation.MsgExpectedArray, validation.MsgExpectedArray)
 			return
 		}
 	case TypeObject:
@@ -533,12 +671,19 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 		case map[any]any:
 			handleMapAny(r, s, path, mode, vv, res)
 		default:
-			res.Add(path, v, validation.MsgExpectedObject)
+			res.addMsg(path, v, validation.MsgExpectedObject, validation.MsgExpectedObject)
 			return
 		}
 	}
 
 	if len(s.Enum) > 0 {
+		if n, ok := v.(json.Number); ok {
+			// Compare precise numbers using the same representation as the enum
+			// values, which are parsed from JSON into `float64`.
+			if f, err := n.Float64(); err == nil {
+				v = f
+			}
+		}
 		found := false
 		for _, e := range s.Enum {
 			if e == v {
@@ -547,20 +692,67 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 			}
 		}
 		if !found {
-			res.Add(path, v, s.msgEnum)
+			res.addMsg(path, v, s.msgEnum, validation.MsgExpectedOneOf, enumString(s.Enum))
 		}
 	}
 }
 
+// maxExactInt is the largest integer magnitude that a `float64` can represent
+// without losing precision.
+const maxExactInt = 1 << 53
+
+// fromInt64 returns the value as a `float64`, along with an exact copy if the
+// conversion loses precision.
+func fromInt64(v int64) (float64, *big.Int) {
+	if v > maxExactInt || v < -maxExactInt {
+		return float64(v), big.NewInt(v)
+	}
+	return float64(v), nil
+}
+
+// fromUint64 returns the value as a `float64`, along with an exact copy if the
+// conversion loses precision.
+func fromUint64(v uint64) (float64, *big.Int) {
+	if v > maxExactInt {
+		return float64(v), new(big.Int).SetUint64(v)
+	}
+	return float64(v), nil
+}
+
+// compareNum compares a number to a schema limit, returning -1, 0, or +1. If
+// `exact` is set it is used instead of `num` for a precise comparison.
+func compareNum(num float64, exact *big.Int, limit float64) int {
+	if exact == nil {
+		switch {
+		case num < limit:
+			return -1
+		case num > limit:
+			return 1
+		}
+		return 0
+	}
+	return new(big.Float).SetInt(exact).Cmp(big.NewFloat(limit))
+}
+
+// isMultipleOf returns whether the number is a multiple of `m`. If `exact` is
+// set and `m` is a whole number, integer math is used to avoid rounding.
+func isMultipleOf(num float64, exact *big.Int, m float64) bool {
+	if exact != nil && m == math.Trunc(m) && m != 0 {
+		bm, _ := big.NewFloat(m).Int(nil)
+		return new(big.Int).Rem(exact, bm).Sign() == 0
+	}
+	return math.Mod(num, m) == 0
+}
+
 func handleArray[T any](r Registry, s *Schema, path *PathBuffer, mode ValidateMode, res *ValidateResult, arr []T) {
 	if s.MinItems != nil {
 		if len(arr) < *s.MinItems {
-			res.Add(path, arr, s.msgMinItems)
+			res.addMsg(path, arr, s.msgMinItems, validation.MsgExpectedMinItems, *s.MinItems)
 		}
 	}
 	if s.MaxItems != nil {
 		if len(arr) > *s.MaxItems {
-			res.Add(path, arr, s.msgMaxItems)
+			res.addMsg(path, arr, s.msgMaxItems, validation.MsgExpectedMaxItems, *s.MaxItems)
 		}
 	}
 
@@ -568,7 +760,7 @@ func handleArray[T any](r Registry, s *Schema, path *PathBuffer, mode ValidateMo
 		seen := make(map[any]struct{}, len(arr))
 		for _, item := range arr {
 			if _, ok := seen[item]; ok {
-				res.Add(path, arr, validation.MsgExpectedArrayItemsUnique)
+				res.addMsg(path, arr, validation.MsgExpectedArrayItemsUnique, validation.MsgExpectedArrayItemsUnique)
 			}
 			seen[item] = struct{}{}
 		}
@@ -581,18 +773,48 @@ func handleArray[T any](r Registry, s *Schema, path *PathBuffer, mode ValidateMo
 	}
 }
 
+// validatePropertyNames validates each of an object's keys against the
+// `propertyNames` schema. Errors are located at the offending key.
+func validatePropertyNames(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, keys []string, res *ValidateResult) {
+	// Sort for a stable error order.
+	sort.Strings(keys)
+	for _, k := range keys {
+		path.Push(k)
+		Validate(r, s, path, mode, k, res)
+		path.Pop()
+	}
+}
+
+// addUnexpected reports an unexpected property, either as an error or by
+// collecting it when `CollectUnknown` is set.
+func addUnexpected(path *PathBuffer, mode ValidateMode, v any, res *ValidateResult) {
+	if res.CollectUnknown && mode == ModeWriteToServer {
+		res.Unknown = append(res.Unknown, path.String())
+		return
+	}
+	res.addMsg(path, v, validation.MsgUnexpectedProperty, validation.MsgUnexpectedProperty)
+}
+
 func handleMapString(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, m map[string]any, res *ValidateResult) {
 	if s.MinProperties != nil {
 		if len(m) < *s.MinProperties {
-			res.Add(path, m, s.msgMinProperties)
+			res.addMsg(path, m, s.msgMinProperties, validation.MsgExpectedMinProperties, *s.MinProperties)
 		}
 	}
 	if s.MaxProperties != nil {
 		if len(m) > *s.MaxProperties {
-			res.Add(path, m, s.msgMaxProperties)
+			res.addMsg(path, m, s.msgMaxProperties, validation.MsgExpectedMaxProperties, *s.MaxProperties)
 		}
 	}
 
+	if s.PropertyNames != nil {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		validatePropertyNames(r, s.PropertyNames, path, mode, keys, res)
+	}
+
 	for _, k := range s.propertyNames {
 		v := s.Properties[k]
 
@@ -602,6 +824,7 @@ func handleMapString(r Registry, s *Schema, path *PathBuffer, mode ValidateMode,
 		// the `for` loop never runs.
 		readOnly := v.ReadOnly
 		writeOnly := v.WriteOnly
+		deprecated := v.Deprecated
 		for v.Ref != "" {
 			v = r.SchemaFromRef(v.Ref)
 		}
@@ -612,7 +835,7 @@ func handleMapString(r Registry, s *Schema, path *PathBuffer, mode ValidateMode,
 
 		// Be stricter for responses, enabling validation of the server if desired.
 		if mode == ModeReadFromServer && writeOnly && m[k] != nil && !reflect.ValueOf(m[k]).IsZero() {
-			res.Add(path, m[k], "write only property is non-zero")
+			res.addMsg(path, m[k], validation.MsgWriteOnlyPropertyNonZero, validation.MsgWriteOnlyPropertyNonZero)
 			continue
 		}
 
@@ -638,7 +861,7 @@ func handleMapString(r Registry, s *Schema, path *PathBuffer, mode ValidateMode,
 				// These are not required for the current mode.
 				continue
 			}
-			res.Add(path, m, s.msgRequired[k])
+			res.addMsg(path, m, s.msgRequired[k], validation.MsgExpectedRequiredProperty, k)
 			continue
 		}
 
@@ -654,11 +877,14 @@ func handleMapString(r Registry, s *Schema, path *PathBuffer, mode ValidateMode,
 					continue
 				}
 
-				res.Add(path, m, s.msgDependentRequired[k][dependent])
+				res.addMsg(path, m, s.msgDependentRequired[k][dependent], validation.MsgExpectedDependentRequiredProperty, dependent, k)
 			}
 		}
 
 		path.Push(k)
+		if deprecated && mode == ModeWriteToServer {
+			res.Deprecated = append(res.Deprecated, path.String())
+		}
 		Validate(r, v, path, mode, m[actualKey], res)
 		path.Pop()
 	}
@@ -678,7 +904,7 @@ func handleMapString(r Registry, s *Schema, path *PathBuffer, mode ValidateMode,
 				}
 
 				path.Push(k)
-				res.Add(path, m, validation.MsgUnexpectedProperty)
+				addUnexpected(path, mode, m, res)
 				path.Pop()
 			}
 		}
@@ -701,15 +927,27 @@ func handleMapString(r Registry, s *Schema, path *PathBuffer, mode ValidateMode,
 func handleMapAny(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, m map[any]any, res *ValidateResult) {
 	if s.MinProperties != nil {
 		if len(m) < *s.MinProperties {
-			res.Add(path, m, s.msgMinProperties)
+			res.addMsg(path, m, s.msgMinProperties, validation.MsgExpectedMinProperties, *s.MinProperties)
 		}
 	}
 	if s.MaxProperties != nil {
 		if len(m) > *s.MaxProperties {
-			res.Add(path, m, s.msgMaxProperties)
+			res.addMsg(path, m, s.msgMaxProperties, validation.MsgExpectedMaxProperties, *s.MaxProperties)
 		}
 	}
 
+	if s.PropertyNames != nil {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			if ks, ok := k.(string); ok {
+				keys = append(keys, ks)
+			} else {
+				keys = append(keys, fmt.Sprint(k))
+			}
+		}
+		validatePropertyNames(r, s.PropertyNames, path, mode, keys, res)
+	}
+
 	for _, k := range s.propertyNames {
 		v := s.Properties[k]
 
@@ -719,6 +957,7 @@ func handleMapAny(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, m
 		// the `for` loop never runs.
 		readOnly := v.ReadOnly
 		writeOnly := v.WriteOnly
+		deprecated := v.Deprecated
 		for v.Ref != "" {
 			v = r.SchemaFromRef(v.Ref)
 		}
@@ -729,7 +968,7 @@ func handleMapAny(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, m
 
 		// Be stricter for responses, enabling validation of the server if desired.
 		if mode == ModeReadFromServer && writeOnly && m[k] != nil && !reflect.ValueOf(m[k]).IsZero() {
-			res.Add(path, m[k], "write only property is non-zero")
+			res.addMsg(path, m[k], validation.MsgWriteOnlyPropertyNonZero, validation.MsgWriteOnlyPropertyNonZero)
 			continue
 		}
 
@@ -742,7 +981,7 @@ func handleMapAny(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, m
 				// These are not required for the current mode.
 				continue
 			}
-			res.Add(path, m, s.msgRequired[k])
+			res.addMsg(path, m, s.msgRequired[k], validation.MsgExpectedRequiredProperty, k)
 			continue
 		}
 
@@ -758,11 +997,14 @@ func handleMapAny(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, m
 					continue
 				}
 
-				res.Add(path, m, s.msgDependentRequired[k][dependent])
+				res.addMsg(path, m, s.msgDependentRequired[k][dependent], validation.MsgExpectedDependentRequiredProperty, dependent, k)
 			}
 		}
 
 		path.Push(k)
+		if deprecated && mode == ModeWriteToServer {
+			res.Deprecated = append(res.Deprecated, path.String())
+		}
 		Validate(r, v, path, mode, m[k], res)
 		path.Pop()
 	}
@@ -778,7 +1020,7 @@ func handleMapAny(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, m
 			}
 			if _, ok := s.Properties[kStr]; !ok {
 				path.Push(kStr)
-				res.Add(path, m, validation.MsgUnexpectedProperty)
+				addUnexpected(path, mode, m, res)
 				path.Pop()
 			}
 		}
@@ -866,6 +1108,110 @@ func (v *ModelValidator) Validate(typ reflect.Type, value any) []error {
 	return nil
 }
 
+// Validator validates values against the schema generated from the Go type
+// `T`, which is compiled once up front. Unlike `Validate`, it manages its own
+// path buffers and results so it is easy to reuse outside of HTTP handlers,
+// e.g. for messages from a queue or files loaded by a CLI. It is safe for
+// concurrent use.
+//
+//	type MyExample struct {
+//		Name string `json:"name" maxLength:"5"`
+//		Age int `json:"age" minimum:"25"`
+//	}
+//
+//	validator := huma.NewValidator[MyExample]()
+//	example, errs := validator.ValidateJSON([]byte(`{"name": "abcdefg", "age": 1}`))
+//	if errs != nil {
+//		fmt.Println("Validation error", errs)
+//	}
+type Validator[T any] struct {
+	registry Registry
+	schema   *Schema
+	pool     sync.Pool
+}
+
+// NewValidator creates a new validator for the Go type `T`. Values are
+// validated the same way as request bodies, so e.g. read-only fields are not
+// required to be present.
+func NewValidator[T any]() *Validator[T] {
+	registry := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+	typ := reflect.TypeFor[T]()
+	return &Validator[T]{
+		registry: registry,
+		schema:   registry.Schema(typ, true, typ.Name()),
+		pool: sync.Pool{
+			New: func() any {
+				return &validateDeps{
+					pb:  &PathBuffer{buf: make([]byte, 0, 128)},
+					res: &ValidateResult{},
+				}
+			},
+		},
+	}
+}
+
+// Schema returns the compiled schema used for validation, with any top-level
+// reference resolved.
+func (v *Validator[T]) Schema() *Schema {
+	if v.schema.Ref != "" {
+		return v.registry.SchemaFromRef(v.schema.Ref)
+	}
+	return v.schema
+}
+
+// Validate the value, which is either generic data like the result of
+// `json.Unmarshal` into an `any`, or a `T` (or `*T`) which gets converted to
+// generic data first. A list of errors is returned if validation failed,
+// otherwise `nil`.
+func (v *Validator[T]) Validate(value any) []error {
+	switch value.(type) {
+	case T, *T:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return []error{&ErrorDetail{Message: err.Error()}}
+		}
+		value = nil
+		if err := json.Unmarshal(b, &value); err != nil {
+			return []error{&ErrorDetail{Message: err.Error()}}
+		}
+	}
+
+	deps := v.pool.Get().(*validateDeps)
+	defer func() {
+		deps.pb.Reset()
+		deps.res.Reset()
+		v.pool.Put(deps)
+	}()
+
+	Validate(v.registry, v.schema, deps.pb, ModeWriteToServer, value, deps.res)
+
+	if len(deps.res.Errors) > 0 {
+		// The result is reused, so the errors must be copied.
+		return slices.Clone(deps.res.Errors)
+	}
+	return nil
+}
+
+// ValidateJSON validates the JSON data and, if it is valid, unmarshals it
+// into a new `T`. A list of errors is returned if the data is not valid JSON
+// or if validation failed, otherwise `nil`.
+func (v *Validator[T]) ValidateJSON(data []byte) (T, []error) {
+	var out T
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return out, []error{&ErrorDetail{Message: err.Error()}}
+	}
+
+	if errs := v.Validate(value); errs != nil {
+		return out, errs
+	}
+
+	if err := json.Unmarshal(data, &out); err != nil {
+		return out, []error{&ErrorDetail{Message: err.Error()}}
+	}
+	return out, nil
+}
+
 // The following is borrowed from the Google UUID package:
 // https://github.com/google/uuid/blob/v1.6.0/uuid.go
 // Copyright (c) 2009,2014 Google Inc. All rights reserved.
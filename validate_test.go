@@ -1,10 +1,14 @@
 package huma_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
 	"github.com/danielgtaylor/huma/v2/validation"
 )
 
@@ -206,6 +211,58 @@ var validateTests = []struct {
 		input: map[string]any{"value": 2},
 		errs:  []string{"expected number to be a multiple of 5"},
 	},
+	{
+		name: "json number success",
+		typ: reflect.TypeOf(struct {
+			Value int64 `json:"value" minimum:"1" maximum:"9007199254740992"`
+		}{}),
+		input: map[string]any{"value": json.Number("9007199254740992")},
+	},
+	{
+		name: "json number maximum precision fail",
+		typ: reflect.TypeOf(struct {
+			Value int64 `json:"value" maximum:"9007199254740992"`
+		}{}),
+		input: map[string]any{"value": json.Number("9007199254740993")},
+		errs:  []string{"expected number <= 9.007199254740992e+15"},
+	},
+	{
+		name: "int64 multiple of precision fail",
+		typ: reflect.TypeOf(struct {
+			Value int64 `json:"value" multipleOf:"2"`
+		}{}),
+		input: map[string]any{"value": int64(9007199254740993)},
+		errs:  []string{"expected number to be a multiple of 2"},
+	},
+	{
+		name: "uint64 json number exclusive minimum success",
+		typ: reflect.TypeOf(struct {
+			Value uint64 `json:"value" exclusiveMinimum:"18446744073709549568"`
+		}{}),
+		input: map[string]any{"value": json.Number("18446744073709549569")},
+	},
+	{
+		name: "json number float success",
+		typ: reflect.TypeOf(struct {
+			Value float64 `json:"value" maximum:"1.5"`
+		}{}),
+		input: map[string]any{"value": json.Number("1.25")},
+	},
+	{
+		name: "json number enum success",
+		typ: reflect.TypeOf(struct {
+			Value int `json:"value" enum:"1,5,9"`
+		}{}),
+		input: map[string]any{"value": json.Number("5")},
+	},
+	{
+		name: "expected json number",
+		typ: reflect.TypeOf(struct {
+			Value int `json:"value"`
+		}{}),
+		input: map[string]any{"value": json.Number("abc")},
+		errs:  []string{"expected number"},
+	},
 	{
 		name:  "string success",
 		typ:   reflect.TypeOf(""),
@@ -601,6 +658,43 @@ var validateTests = []struct {
 		input: map[string]any{"value": []byte("!")},
 		errs:  []string{"expected string to be base64 encoded"},
 	},
+	{
+		name: "content media type success",
+		typ: reflect.TypeOf(struct {
+			Value string `json:"value" contentMediaType:"application/json"`
+		}{}),
+		input: map[string]any{"value": `{"foo": [1, 2]}`},
+	},
+	{
+		name: "content media type fail",
+		typ: reflect.TypeOf(struct {
+			Value string `json:"value" contentMediaType:"application/json"`
+		}{}),
+		input: map[string]any{"value": `{"foo": `},
+		errs:  []string{"expected string content to be valid application/json"},
+	},
+	{
+		name: "content media type ignored",
+		typ: reflect.TypeOf(struct {
+			Value string `json:"value" contentMediaType:"text/html"`
+		}{}),
+		input: map[string]any{"value": `<p>`},
+	},
+	{
+		name: "encoded content media type success",
+		typ: reflect.TypeOf(struct {
+			Value string `json:"value" contentEncoding:"base64" contentMediaType:"application/vnd.api+json"`
+		}{}),
+		input: map[string]any{"value": "eyJmb28iOiB0cnVlfQ"},
+	},
+	{
+		name: "encoded content media type fail",
+		typ: reflect.TypeOf(struct {
+			Value string `json:"value" contentEncoding:"base64" contentMediaType:"application/json"`
+		}{}),
+		input: map[string]any{"value": "eyJmb28iOg=="},
+		errs:  []string{"expected string content to be valid application/json"},
+	},
 	{
 		name:  "array success",
 		typ:   reflect.TypeOf([]any{}),
@@ -712,6 +806,36 @@ var validateTests = []struct {
 		input: map[string]any{"value": []any{1, 2, 1, 3}},
 		errs:  []string{"expected array items to be unique"},
 	},
+	{
+		name: "key pattern success",
+		typ: reflect.TypeOf(struct {
+			Value map[string]int `json:"value" keyPattern:"^[a-z_]+$"`
+		}{}),
+		input: map[string]any{"value": map[string]any{"one": 1, "two_too": 2}},
+	},
+	{
+		name: "expected key pattern",
+		typ: reflect.TypeOf(struct {
+			Value map[string]int `json:"value" keyPattern:"^[a-z_]+$"`
+		}{}),
+		input: map[string]any{"value": map[string]any{"one": 1, "two2": 2}},
+		errs:  []string{"expected string to match pattern ^[a-z_]+$"},
+	},
+	{
+		name: "key pattern any success",
+		typ: reflect.TypeOf(struct {
+			Value map[string]int `json:"value" keyPattern:"^[a-z_]+$"`
+		}{}),
+		input: map[any]any{"value": map[any]any{"one": 1, "two": 2}},
+	},
+	{
+		name: "expected key pattern any",
+		typ: reflect.TypeOf(struct {
+			Value map[string]int `json:"value" keyPattern:"^[a-z_]+$"`
+		}{}),
+		input: map[any]any{"value": map[any]any{"One": 1, "two": 2}},
+		errs:  []string{"expected string to match pattern ^[a-z_]+$"},
+	},
 	{
 		name:  "map success",
 		typ:   reflect.TypeOf(map[string]int{}),
@@ -1443,6 +1567,25 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidatePropertyNamesLocation(t *testing.T) {
+	registry := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	s := registry.Schema(reflect.TypeOf(struct {
+		Labels map[string]string `json:"labels" keyPattern:"^[a-z]+$"`
+	}{}), false, "")
+
+	pb := huma.NewPathBuffer([]byte{}, 0)
+	pb.Push("body")
+	res := &huma.ValidateResult{}
+	huma.Validate(registry, s, pb, huma.ModeWriteToServer, map[string]any{
+		"labels": map[string]any{"ok": "a", "Bad": "b", "no-dash": "c"},
+	}, res)
+
+	require.Len(t, res.Errors, 2)
+	assert.Equal(t, "body.labels.Bad", res.Errors[0].(*huma.ErrorDetail).Location)
+	assert.Equal(t, "Bad", res.Errors[0].(*huma.ErrorDetail).Value)
+	assert.Equal(t, "body.labels.no-dash", res.Errors[1].(*huma.ErrorDetail).Location)
+}
+
 func TestValidateCustomFormatter(t *testing.T) {
 	originalFormatter := huma.ErrorFormatter
 	defer func() {
@@ -1465,6 +1608,73 @@ func TestValidateCustomFormatter(t *testing.T) {
 	assert.Equal(t, "custom: [mail: missing '@' or angle-addr] (value: alice)", res.Errors[0].Error())
 }
 
+func TestValidateRegisterFormat(t *testing.T) {
+	huma.RegisterFormat("even-length", func(s string) error {
+		if len(s)%2 != 0 {
+			return errors.New("odd length")
+		}
+		return nil
+	})
+
+	registry := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	s := registry.Schema(reflect.TypeOf(struct {
+		Value string `json:"value" format:"even-length"`
+	}{}), false, "")
+	assert.Equal(t, "even-length", s.Properties["value"].Format)
+
+	pb := huma.NewPathBuffer([]byte{}, 0)
+	res := &huma.ValidateResult{}
+	huma.Validate(registry, s, pb, huma.ModeWriteToServer, map[string]any{"value": "ab"}, res)
+	assert.Empty(t, res.Errors)
+
+	huma.Validate(registry, s, pb, huma.ModeWriteToServer, map[string]any{"value": "abc"}, res)
+	require.Len(t, res.Errors, 1)
+	assert.Equal(t, "expected string to be even-length: odd length (value: abc)", res.Errors[0].Error())
+}
+
+func TestValidateMessageFunc(t *testing.T) {
+	german := map[string]string{
+		validation.MsgExpectedMaxLength:        "erwartete Länge <= %d",
+		validation.MsgRequiredParameterMissing: "erforderlicher %s-Parameter fehlt",
+	}
+
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.MessageFunc = func(ctx huma.Context, format string, args ...any) string {
+		if strings.HasPrefix(ctx.Header("Accept-Language"), "de") {
+			if translated, ok := german[format]; ok {
+				format = translated
+			}
+		}
+		return fmt.Sprintf(format, args...)
+	}
+	_, api := humatest.New(t, config)
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodPost,
+		Path:   "/test",
+	}, func(ctx context.Context, input *struct {
+		Limit int `query:"limit" required:"true"`
+		Body  struct {
+			Name string `json:"name" maxLength:"3"`
+			Age  int    `json:"age" minimum:"1"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	resp := api.Post("/test", "Accept-Language: de-DE", map[string]any{"name": "abcdef", "age": 0})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "erforderlicher query-Parameter fehlt")
+	assert.Contains(t, resp.Body.String(), `erwartete Länge \u003c= 3`)
+
+	// Untranslated messages fall back to the original format.
+	assert.Contains(t, resp.Body.String(), `expected number \u003e= 1`)
+
+	resp = api.Post("/test", map[string]any{"name": "abcdef", "age": 1})
+	assert.Contains(t, resp.Body.String(), "required query parameter is missing")
+	assert.Contains(t, resp.Body.String(), `expected length \u003c= 3`)
+}
+
 type TransformDeleteField struct {
 	Field1 string `json:"field1"`
 	Field2 string `json:"field2"`
@@ -1517,6 +1727,64 @@ func ExampleModelValidator() {
 	// []
 }
 
+func ExampleNewValidator() {
+	// Define a type you want to validate.
+	type Model struct {
+		Name string `json:"name" maxLength:"5"`
+		Age  int    `json:"age" minimum:"25"`
+	}
+
+	// Compile the validator once and reuse it.
+	validator := huma.NewValidator[Model]()
+
+	_, errs := validator.ValidateJSON([]byte(`{"name": "abcdefg", "age": 1}`))
+	fmt.Println(errs)
+
+	// Try again with valid data!
+	model, errs := validator.ValidateJSON([]byte(`{"name": "foo", "age": 25}`))
+	fmt.Println(model.Name, errs)
+
+	// Output: [expected number >= 25 (age: 1) expected length <= 5 (name: abcdefg)]
+	// foo []
+}
+
+func TestValidator(t *testing.T) {
+	type Model struct {
+		ID   string   `json:"id" readOnly:"true"`
+		Name string   `json:"name" minLength:"1"`
+		Tags []string `json:"tags,omitempty" uniqueItems:"true"`
+	}
+
+	validator := huma.NewValidator[Model]()
+	assert.Equal(t, "object", validator.Schema().Type)
+
+	// Read-only fields are not required.
+	assert.Nil(t, validator.Validate(map[string]any{"name": "foo"}))
+
+	// Go values are supported directly.
+	assert.Nil(t, validator.Validate(Model{Name: "foo"}))
+	errs := validator.Validate(&Model{Tags: []string{"a", "a"}})
+	require.Len(t, errs, 2)
+	assert.Equal(t, "expected length >= 1 (name: )", errs[0].Error())
+	assert.Equal(t, "expected array items to be unique (tags: [a a])", errs[1].Error())
+
+	_, errs = validator.ValidateJSON([]byte(`{"name": `))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "unexpected end of JSON input", errs[0].Error())
+
+	// Validators are safe for concurrent use.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs := validator.ValidateJSON([]byte(`{"name": ""}`))
+			assert.Len(t, errs, 1)
+		}()
+	}
+	wg.Wait()
+}
+
 var BenchValidatePB *huma.PathBuffer
 var BenchValidateRes *huma.ValidateResult
 
@@ -0,0 +1,106 @@
+package huma
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ResponseVersion is an alternative version of an operation's success
+// response body, see `Operation.ResponseVersions`.
+//
+//	huma.Register(api, huma.Operation{
+//		Method: http.MethodGet,
+//		Path:   "/users/{id}",
+//		ResponseVersions: map[string]huma.ResponseVersion{
+//			"2": {
+//				Body: UserV2{},
+//				Convert: func(ctx huma.Context, body any) (any, error) {
+//					return NewUserV2(body.(User)), nil
+//				},
+//			},
+//		},
+//	}, handler)
+type ResponseVersion struct {
+	// Body is a value of the versioned body type, used to generate its schema.
+	Body any
+
+	// Convert returns the versioned body from the handler's response body.
+	Convert func(ctx Context, body any) (any, error)
+}
+
+// documentResponseVersions adds a content entry like
+// `application/json; version=2` for each response version to each of the
+// operation's successful responses.
+func documentResponseVersions(op *Operation, registry Registry) {
+	versions := make([]string, 0, len(op.ResponseVersions))
+	for version := range op.ResponseVersions {
+		versions = append(versions, version)
+	}
+	slices.Sort(versions)
+
+	for status, resp := range op.Responses {
+		if !strings.HasPrefix(status, "2") || len(resp.Content) == 0 {
+			continue
+		}
+		contentTypes := make([]string, 0, len(resp.Content))
+		for ct := range resp.Content {
+			contentTypes = append(contentTypes, ct)
+		}
+		for _, version := range versions {
+			t := reflect.TypeOf(op.ResponseVersions[version].Body)
+			schema := registry.Schema(t, true, getHint(t, "", op.OperationID+"ResponseV"+version))
+			for _, ct := range contentTypes {
+				resp.Content[ct+"; version="+version] = &MediaType{Schema: schema}
+			}
+		}
+	}
+}
+
+// selectResponseVersion returns the response version requested by the client,
+// which is the first `version` media type parameter in the `Accept` header
+// unless the operation provides its own `SelectVersion` function.
+func selectResponseVersion(ctx Context, op *Operation) string {
+	if op.SelectVersion != nil {
+		return op.SelectVersion(ctx)
+	}
+	for _, mediaRange := range strings.Split(ctx.Header("Accept"), ",") {
+		_, params, _ := strings.Cut(mediaRange, ";")
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(param, "=")
+			if strings.EqualFold(strings.TrimSpace(name), "version") {
+				return strings.Trim(strings.TrimSpace(value), `"`)
+			}
+		}
+	}
+	return ""
+}
+
+// applyResponseVersion converts the body to the version requested by the
+// client, if any, setting the versioned `Content-Type` header. It returns the
+// body and the content type to marshal it with.
+func applyResponseVersion(api API, ctx Context, op *Operation, ct string, body any) (any, string, error) {
+	if op.SelectVersion == nil {
+		ctx.AppendHeader("Vary", "Accept")
+	}
+	version := selectResponseVersion(ctx, op)
+	rv, ok := op.ResponseVersions[version]
+	if !ok {
+		return body, ct, nil
+	}
+
+	if ct == "" {
+		var err error
+		if ct, err = api.Negotiate(ctx.Header("Accept")); err != nil {
+			// Let the usual content negotiation handle the error.
+			return body, "", nil
+		}
+	}
+
+	converted, err := rv.Convert(ctx, body)
+	if err != nil {
+		return nil, "", err
+	}
+	ctx.SetHeader("Content-Type", ct+"; version="+version)
+	return converted, ct, nil
+}
@@ -0,0 +1,505 @@
+// Package webhooks provides outgoing webhook delivery for Huma APIs. Event
+// payloads are documented in the OpenAPI `webhooks` section using the same
+// schema generation as operations, subscribers can be managed via optional
+// generated operations, and deliveries are signed and retried with exponential
+// backoff.
+//
+// Deliveries follow the Standard Webhooks conventions: each request includes
+// `webhook-id`, `webhook-timestamp`, and `webhook-signature` headers, where the
+// signature is `v1,` followed by the base64 HMAC-SHA256 of
+// `{id}.{timestamp}.{body}`. Subscription secrets use the `whsec_{base64}`
+// format, and the decoded bytes are used as the signing key.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// ErrNotFound is returned by a `Store` when a subscription does not exist.
+var ErrNotFound = errors.New("subscription not found")
+
+// Subscription is a registered subscriber endpoint.
+type Subscription struct {
+	ID     string   `json:"id" readOnly:"true" doc:"Unique subscription identifier"`
+	URL    string   `json:"url" format:"uri" doc:"URL to deliver events to"`
+	Events []string `json:"events,omitempty" doc:"Event types to deliver, or all events if empty"`
+	Secret string   `json:"secret,omitempty" doc:"Secret used to sign deliveries, in the Standard Webhooks format of whsec_ followed by 24 to 64 base64 encoded bytes. Generated if not provided, and only returned when the subscription is created."`
+}
+
+// wants returns whether the subscription should receive the given event.
+func (s *Subscription) wants(event string) bool {
+	return len(s.Events) == 0 || slices.Contains(s.Events, event)
+}
+
+// Store persists subscriptions. Implementations must be safe for concurrent
+// use. A `MemoryStore` is used by default.
+type Store interface {
+	Add(ctx context.Context, sub *Subscription) error
+	Remove(ctx context.Context, id string) error
+	List(ctx context.Context) ([]*Subscription, error)
+}
+
+// MemoryStore is an in-memory `Store`, suitable for testing or single-instance
+// services which re-register subscribers on startup.
+type MemoryStore struct {
+	mu   sync.RWMutex
+	subs []*Subscription
+}
+
+// Add a subscription to the store.
+func (s *MemoryStore) Add(ctx context.Context, sub *Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subs = append(s.subs, sub)
+	return nil
+}
+
+// Remove a subscription from the store by ID.
+func (s *MemoryStore) Remove(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, sub := range s.subs {
+		if sub.ID == id {
+			s.subs = slices.Delete(s.subs, i, i+1)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// List all subscriptions in the store.
+func (s *MemoryStore) List(ctx context.Context) ([]*Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.subs), nil
+}
+
+// Event is the envelope sent to subscribers for each delivery.
+type Event[T any] struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Data      T         `json:"data"`
+}
+
+// Manager manages subscriptions and delivers events to them.
+type Manager struct {
+	// Store persists subscriptions. Defaults to a `MemoryStore`.
+	Store Store
+
+	// Client is used to send deliveries. Defaults to a client with a 10 second
+	// timeout.
+	Client *http.Client
+
+	// MaxAttempts is the maximum number of delivery attempts per subscriber.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry. It doubles after each
+	// failed attempt up to `MaxBackoff`.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum delay between retries.
+	MaxBackoff time.Duration
+
+	// OnError is called when a delivery has permanently failed.
+	OnError func(sub *Subscription, event string, err error)
+
+	// ValidateURL checks the URL of each subscription created via the
+	// operations from `RegisterOperations`, so callers cannot make the server
+	// send requests to internal services. Defaults to `DefaultValidateURL`.
+	// Set it to nil to allow any URL, e.g. for tests against local servers.
+	ValidateURL func(u *url.URL) error
+
+	api    huma.API
+	mu     sync.RWMutex
+	events map[string]reflect.Type
+	wg     sync.WaitGroup
+}
+
+// New creates a new webhook manager for the API.
+func New(api huma.API) *Manager {
+	return &Manager{
+		Store:          &MemoryStore{},
+		Client:         &http.Client{Timeout: 10 * time.Second},
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Minute,
+		ValidateURL:    DefaultValidateURL,
+		api:            api,
+		events:         map[string]reflect.Type{},
+	}
+}
+
+// RegisterEvent registers an event type along with an example of its payload,
+// and documents it in the OpenAPI `webhooks` section. Only registered events
+// can be sent or subscribed to.
+//
+//	m.RegisterEvent("order.created", "An order was created", Order{})
+func (m *Manager) RegisterEvent(event, description string, payload any) {
+	t := reflect.TypeOf(payload)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	m.mu.Lock()
+	m.events[event] = t
+	m.mu.Unlock()
+
+	oapi := m.api.OpenAPI()
+	registry := oapi.Components.Schemas
+	envelope := &huma.Schema{
+		Title: "Event " + event,
+		Type:  huma.TypeObject,
+		Properties: map[string]*huma.Schema{
+			"id":        {Type: huma.TypeString, Description: "Unique event identifier, also sent as the webhook-id header"},
+			"type":      {Type: huma.TypeString, Enum: []any{event}, Description: "Event type"},
+			"timestamp": {Type: huma.TypeString, Format: "date-time", Description: "Time the event occurred"},
+			"data":      registry.Schema(t, true, event),
+		},
+		Required: []string{"id", "type", "timestamp", "data"},
+	}
+
+	if oapi.Webhooks == nil {
+		oapi.Webhooks = map[string]*huma.PathItem{}
+	}
+	oapi.Webhooks[event] = &huma.PathItem{
+		Post: &huma.Operation{
+			OperationID: "webhook-" + event,
+			Summary:     event,
+			Description: description,
+			Parameters: []*huma.Param{
+				{Name: "webhook-id", In: "header", Required: true, Description: "Unique event identifier", Schema: &huma.Schema{Type: huma.TypeString}},
+				{Name: "webhook-timestamp", In: "header", Required: true, Description: "Unix timestamp in seconds of the delivery attempt", Schema: &huma.Schema{Type: huma.TypeInteger}},
+				{Name: "webhook-signature", In: "header", Required: true, Description: "Signature of the delivery, e.g. `v1,{base64 HMAC-SHA256}`", Schema: &huma.Schema{Type: huma.TypeString}},
+			},
+			RequestBody: &huma.RequestBody{
+				Required: true,
+				Content: map[string]*huma.MediaType{
+					"application/json": {Schema: envelope},
+				},
+			},
+			Responses: map[string]*huma.Response{
+				"2XX": {Description: "Event received. Any other status causes a retry."},
+			},
+		},
+	}
+}
+
+// Send an event to all subscribers which want it. Deliveries happen in the
+// background and are retried on failure; use `Wait` to wait for them.
+// An error is returned if the event is not registered or its data does not
+// match the registered payload type.
+func (m *Manager) Send(ctx context.Context, event string, data any) error {
+	m.mu.RLock()
+	t, ok := m.events[event]
+	m.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown webhook event %q", event)
+	}
+	if dt := reflect.TypeOf(data); dt != t && (dt == nil || dt.Kind() != reflect.Ptr || dt.Elem() != t) {
+		return fmt.Errorf("webhook event %q expects %v but got %v", event, t, dt)
+	}
+
+	id := newID()
+	body, err := json.Marshal(Event[any]{
+		ID:        id,
+		Type:      event,
+		Timestamp: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+
+	subs, err := m.Store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Deliveries outlive the request that triggered them.
+	ctx = context.WithoutCancel(ctx)
+	for _, sub := range subs {
+		if !sub.wants(event) {
+			continue
+		}
+		m.wg.Add(1)
+		go func(sub *Subscription) {
+			defer m.wg.Done()
+			if err := m.deliver(ctx, sub, id, body); err != nil && m.OnError != nil {
+				m.OnError(sub, event, err)
+			}
+		}(sub)
+	}
+	return nil
+}
+
+// Wait blocks until all in-progress deliveries have completed or permanently
+// failed, e.g. during a graceful shutdown.
+func (m *Manager) Wait() {
+	m.wg.Wait()
+}
+
+// deliver sends the body to a subscriber, retrying with exponential backoff.
+func (m *Manager) deliver(ctx context.Context, sub *Subscription, id string, body []byte) error {
+	backoff := m.InitialBackoff
+	var err error
+	for attempt := 1; attempt <= m.MaxAttempts; attempt++ {
+		var retry bool
+		if retry, err = m.attempt(ctx, sub, id, body); err == nil || !retry {
+			return err
+		}
+		if attempt == m.MaxAttempts {
+			break
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff = min(backoff*2, m.MaxBackoff)
+	}
+	return err
+}
+
+// attempt makes a single delivery attempt, returning whether a failure can be
+// retried.
+func (m *Manager) attempt(ctx context.Context, sub *Subscription, id string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("webhook-id", id)
+	req.Header.Set("webhook-timestamp", timestamp)
+	req.Header.Set("webhook-signature", Sign(sub.Secret, id, timestamp, body))
+
+	resp, err := m.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("webhook delivery to %s failed with status %d", sub.URL, resp.StatusCode)
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests
+	return retry, err
+}
+
+// secretPrefix marks secrets in the Standard Webhooks format.
+const secretPrefix = "whsec_"
+
+// secretKey returns the signing key for a secret in the `whsec_{base64}`
+// format. The prefix is optional.
+func secretKey(secret string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, secretPrefix))
+	if err != nil || len(key) < 24 || len(key) > 64 {
+		return nil, errors.New("secret must be whsec_ followed by 24 to 64 base64 encoded bytes")
+	}
+	return key, nil
+}
+
+// newSecret returns a new random secret in the `whsec_{base64}` format.
+func newSecret() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	return secretPrefix + base64.StdEncoding.EncodeToString(b)
+}
+
+// Sign returns the `webhook-signature` header value for a delivery. Receivers
+// can use it to verify that a delivery was sent by the API. The secret is
+// decoded from the Standard Webhooks `whsec_{base64}` format, while secrets
+// in any other format are used as-is, e.g. for stores with legacy secrets.
+func Sign(secret, id, timestamp string, body []byte) string {
+	key, err := secretKey(secret)
+	if err != nil {
+		key = []byte(secret)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id + "." + timestamp + "."))
+	mac.Write(body)
+	return "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// newID returns a new random identifier.
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type listOutput struct {
+	Body []*Subscription
+}
+
+type subscriptionOutput struct {
+	Body *Subscription
+}
+
+// DefaultValidateURL rejects subscription URLs which do not use `http` or
+// `https`, or whose host is or resolves to a loopback, link-local, private,
+// or unspecified address, like `localhost` or `169.254.169.254`.
+func DefaultValidateURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("URL must use http or https")
+	}
+	host := u.Hostname()
+	if host == "" {
+		return errors.New("URL must have a host")
+	}
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		var err error
+		if ips, err = net.LookupIP(host); err != nil {
+			return fmt.Errorf("cannot resolve host %s", host)
+		}
+	}
+	for _, ip := range ips {
+		if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsPrivate() || ip.IsUnspecified() {
+			return errors.New("URL must not point to a loopback, link-local, or private address")
+		}
+	}
+	return nil
+}
+
+// RegisterOperations registers operations to create, list, and delete
+// subscriptions below the given path. The optional modifiers can be used to
+// customize each operation.
+//
+// Anyone able to call these operations can receive your events, so they must
+// be protected. RegisterOperations panics unless each operation has security
+// requirements, either from the API's global `Security` or set by a modifier.
+// Set `op.Security` to an empty slice to explicitly allow anonymous access.
+// Security requirements are only documented, so they must also be enforced,
+// e.g. via `huma.SecurityMiddleware` or your own auth middleware.
+//
+//	m.RegisterOperations(api, "/webhooks", func(op *huma.Operation) {
+//		op.Security = []map[string][]string{{"admin": {}}}
+//	})
+func (m *Manager) RegisterOperations(api huma.API, path string, modifiers ...func(op *huma.Operation)) {
+	modify := func(op huma.Operation) huma.Operation {
+		op.Tags = []string{"Webhooks"}
+		for _, modifier := range modifiers {
+			modifier(&op)
+		}
+		if op.Security == nil && len(api.OpenAPI().Security) == 0 {
+			panic(fmt.Errorf("webhook operation %s has no security requirements, set op.Security via a modifier or to an empty slice to allow anonymous access", op.OperationID))
+		}
+		return op
+	}
+
+	huma.Register(api, modify(huma.Operation{
+		OperationID:   "create-webhook-subscription",
+		Method:        http.MethodPost,
+		Path:          path,
+		Summary:       "Create webhook subscription",
+		DefaultStatus: http.StatusCreated,
+	}), func(ctx context.Context, input *struct {
+		Body Subscription
+	}) (*subscriptionOutput, error) {
+		sub := input.Body
+		m.mu.RLock()
+		var errs []error
+		for i, event := range sub.Events {
+			if _, ok := m.events[event]; !ok {
+				errs = append(errs, &huma.ErrorDetail{
+					Message:  "unknown event",
+					Location: fmt.Sprintf("body.events[%d]", i),
+					Value:    event,
+				})
+			}
+		}
+		m.mu.RUnlock()
+		if m.ValidateURL != nil {
+			u, err := url.Parse(sub.URL)
+			if err == nil {
+				err = m.ValidateURL(u)
+			}
+			if err != nil {
+				errs = append(errs, &huma.ErrorDetail{
+					Message:  err.Error(),
+					Location: "body.url",
+					Value:    sub.URL,
+				})
+			}
+		}
+		if sub.Secret != "" {
+			if _, err := secretKey(sub.Secret); err != nil {
+				errs = append(errs, &huma.ErrorDetail{
+					Message:  err.Error(),
+					Location: "body.secret",
+				})
+			}
+		}
+		if len(errs) > 0 {
+			return nil, huma.Error422UnprocessableEntity("validation failed", errs...)
+		}
+
+		sub.ID = newID()
+		if sub.Secret == "" {
+			sub.Secret = newSecret()
+		}
+		if err := m.Store.Add(ctx, &sub); err != nil {
+			return nil, err
+		}
+		return &subscriptionOutput{Body: &sub}, nil
+	})
+
+	huma.Register(api, modify(huma.Operation{
+		OperationID: "list-webhook-subscriptions",
+		Method:      http.MethodGet,
+		Path:        path,
+		Summary:     "List webhook subscriptions",
+	}), func(ctx context.Context, input *struct{}) (*listOutput, error) {
+		subs, err := m.Store.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out := &listOutput{Body: make([]*Subscription, 0, len(subs))}
+		for _, sub := range subs {
+			// Never return the secret after creation.
+			c := *sub
+			c.Secret = ""
+			out.Body = append(out.Body, &c)
+		}
+		return out, nil
+	})
+
+	huma.Register(api, modify(huma.Operation{
+		OperationID: "delete-webhook-subscription",
+		Method:      http.MethodDelete,
+		Path:        path + "/{id}",
+		Summary:     "Delete webhook subscription",
+	}), func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		if err := m.Store.Remove(ctx, input.ID); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return nil, huma.Error404NotFound(err.Error())
+			}
+			return nil, err
+		}
+		return nil, nil
+	})
+}
@@ -0,0 +1,190 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/danielgtaylor/huma/v2/webhooks"
+)
+
+type Order struct {
+	ID    string `json:"id"`
+	Total int    `json:"total"`
+}
+
+type Refund struct {
+	OrderID string `json:"order_id"`
+}
+
+func TestWebhooks(t *testing.T) {
+	_, api := humatest.New(t)
+
+	var attempts atomic.Int32
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- b
+	}))
+	defer server.Close()
+
+	m := webhooks.New(api)
+	m.InitialBackoff = time.Millisecond
+	m.RegisterEvent("order.created", "An order was created", Order{})
+	m.RegisterEvent("order.refunded", "An order was refunded", Refund{})
+	assert.PanicsWithError(t, "webhook operation create-webhook-subscription has no security requirements, set op.Security via a modifier or to an empty slice to allow anonymous access", func() {
+		m.RegisterOperations(api, "/webhooks")
+	})
+	m.RegisterOperations(api, "/webhooks", func(op *huma.Operation) {
+		op.Security = []map[string][]string{}
+	})
+
+	// Payloads are documented.
+	hook := api.OpenAPI().Webhooks["order.created"]
+	require.NotNil(t, hook)
+	b, _ := json.Marshal(hook.Post.RequestBody)
+	assert.Contains(t, string(b), `"$ref":"#/components/schemas/Order"`)
+
+	resp := api.Post("/webhooks", map[string]any{
+		"url":    server.URL,
+		"events": []string{"order.unknown"},
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), "body.events[0]")
+
+	resp = api.Post("/webhooks", map[string]any{
+		"url":    server.URL,
+		"events": []string{"order.created"},
+		"secret": "s3cret",
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), "body.secret")
+
+	// Internal addresses are rejected by default.
+	resp = api.Post("/webhooks", map[string]any{
+		"url": server.URL,
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), "body.url")
+
+	// Allow the local test server.
+	m.ValidateURL = nil
+
+	secret := "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	resp = api.Post("/webhooks", map[string]any{
+		"url":    server.URL,
+		"events": []string{"order.created"},
+		"secret": secret,
+	})
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	var sub webhooks.Subscription
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &sub))
+	assert.NotEmpty(t, sub.ID)
+
+	resp = api.Get("/webhooks")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), sub.ID)
+	assert.NotContains(t, resp.Body.String(), secret)
+
+	assert.Error(t, m.Send(context.Background(), "order.unknown", Order{}))
+	assert.Error(t, m.Send(context.Background(), "order.created", Refund{}))
+
+	// Not subscribed, so no delivery.
+	require.NoError(t, m.Send(context.Background(), "order.refunded", &Refund{OrderID: "o1"}))
+
+	require.NoError(t, m.Send(context.Background(), "order.created", Order{ID: "o1", Total: 5}))
+	m.Wait()
+	assert.EqualValues(t, 3, attempts.Load())
+
+	r := <-received
+	body := <-bodies
+	assert.Contains(t, string(body), `"type":"order.created"`)
+	assert.Contains(t, string(body), `"data":{"id":"o1","total":5}`)
+	assert.Equal(t, webhooks.Sign(secret, r.Header.Get("webhook-id"), r.Header.Get("webhook-timestamp"), body), r.Header.Get("webhook-signature"))
+
+	resp = api.Delete("/webhooks/" + sub.ID)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	resp = api.Delete("/webhooks/" + sub.ID)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestDefaultValidateURL(t *testing.T) {
+	for _, item := range []struct {
+		url string
+		err string
+	}{
+		{"http://93.184.215.14/hook", ""},
+		{"ftp://93.184.215.14/hook", "URL must use http or https"},
+		{"http:///hook", "URL must have a host"},
+		{"http://localhost:8080/hook", "URL must not point to a loopback, link-local, or private address"},
+		{"http://127.0.0.1/hook", "URL must not point to a loopback, link-local, or private address"},
+		{"http://[::1]/hook", "URL must not point to a loopback, link-local, or private address"},
+		{"http://169.254.169.254/latest/meta-data", "URL must not point to a loopback, link-local, or private address"},
+		{"http://10.0.0.1/hook", "URL must not point to a loopback, link-local, or private address"},
+		{"http://0.0.0.0/hook", "URL must not point to a loopback, link-local, or private address"},
+	} {
+		t.Run(item.url, func(t *testing.T) {
+			u, err := url.Parse(item.url)
+			require.NoError(t, err)
+			err = webhooks.DefaultValidateURL(u)
+			if item.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, item.err)
+			}
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	// Example from the Standard Webhooks specification.
+	sig := webhooks.Sign("whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw", "msg_p5jXN8AQM9LWM0D4loKWxJek", "1614265330", []byte(`{"test": 2432232314}`))
+	assert.Equal(t, "v1,g0hM9SsE+OTPJTGt/tmIKtSyZlE3uFJELVlNIOLJ1OE=", sig)
+}
+
+func TestWebhookPermanentFailure(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	m := webhooks.New(api)
+	m.InitialBackoff = time.Millisecond
+	var failed error
+	m.OnError = func(sub *webhooks.Subscription, event string, err error) {
+		failed = err
+	}
+	m.RegisterEvent("order.created", "", Order{})
+	require.NoError(t, m.Store.Add(context.Background(), &webhooks.Subscription{ID: "1", URL: server.URL}))
+
+	require.NoError(t, m.Send(context.Background(), "order.created", Order{}))
+	m.Wait()
+
+	// Client errors are not retried.
+	assert.EqualValues(t, 1, attempts.Load())
+	require.Error(t, failed)
+	assert.True(t, strings.Contains(failed.Error(), "410"))
+}